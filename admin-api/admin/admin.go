@@ -24,6 +24,9 @@ func main() {
 	defer server.Stop()
 
 	ctx := svc.NewServiceContext(c)
+	// 全局中间件：解析客户端真实IP和User-Agent，再按IP封禁和白名单规则拒绝请求
+	server.Use(ctx.ClientInfo)
+	server.Use(ctx.IPAccess)
 	handler.RegisterHandlers(server, ctx)
 
 	fmt.Printf("Starting server at %s:%d...\n", c.Host, c.Port)
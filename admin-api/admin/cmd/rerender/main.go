@@ -0,0 +1,197 @@
+// rerender 按当前的Markdown渲染和HTML净化规则重新生成已有文章和页面的HTML，同时重新生成文章目录、自动生成的摘要、字数和阅读时间
+//
+// 渲染或净化规则升级后运行一次即可，按作者当前的角色净化，只更新发生变化的文档，不修改更新时间：
+//
+//	cd admin-api/admin && go run ./cmd/rerender -f etc/admin-api.yaml [-dry-run] [-only posts|pages]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/conf"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/heimdall-api/admin-api/admin/internal/config"
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/render"
+)
+
+var (
+	configFile = flag.String("f", "etc/admin-api.yaml", "the config file")
+	dryRun     = flag.Bool("dry-run", false, "only report documents whose html would change")
+	only       = flag.String("only", "", "limit to posts or pages")
+)
+
+// rerenderer 重新渲染文章和页面，缓存作者角色
+type rerenderer struct {
+	renderer *render.Renderer
+	users    *dao.UserDAO
+	roles    map[primitive.ObjectID]string
+}
+
+// authorRole 获取作者当前的角色，作者已不存在时按最严格的策略净化
+func (r *rerenderer) authorRole(ctx context.Context, authorID primitive.ObjectID) string {
+	if role, ok := r.roles[authorID]; ok {
+		return role
+	}
+	role := ""
+	author, err := r.users.GetByID(ctx, authorID.Hex())
+	switch {
+	case err != nil:
+		log.Printf("获取作者失败，按最严格的策略净化: author=%s, error=%v", authorID.Hex(), err)
+	case author == nil:
+		log.Printf("作者已不存在，按最严格的策略净化: author=%s", authorID.Hex())
+	default:
+		role = author.Role
+	}
+	r.roles[authorID] = role
+	return role
+}
+
+// stats 单个集合的处理结果
+type stats struct {
+	total   int
+	changed int
+	failed  int
+}
+
+func (s stats) String() string {
+	return fmt.Sprintf("共%d个，需更新%d个，失败%d个", s.total, s.changed, s.failed)
+}
+
+func main() {
+	flag.Parse()
+	if *only != "" && *only != "posts" && *only != "pages" {
+		log.Fatalf("-only must be posts or pages")
+	}
+
+	var c config.Config
+	conf.MustLoad(*configFile, &c)
+
+	ctx := context.Background()
+	client := connect(ctx, c)
+	defer client.Disconnect(ctx)
+	database := client.Database(c.MongoDB.Database)
+	r := &rerenderer{
+		renderer: svc.NewRenderer(c.Security.ContentSecurity),
+		users:    dao.NewUserDAO(database),
+		roles:    make(map[primitive.ObjectID]string),
+	}
+
+	if *only == "" || *only == "posts" {
+		result, err := r.posts(ctx, dao.NewPostDAO(database))
+		if err != nil {
+			log.Fatalf("Failed to rerender posts: %v", err)
+		}
+		log.Printf("文章: %s", result)
+	}
+
+	if *only == "" || *only == "pages" {
+		result, err := r.pages(ctx, dao.NewPageDAO(database))
+		if err != nil {
+			log.Fatalf("Failed to rerender pages: %v", err)
+		}
+		log.Printf("页面: %s", result)
+	}
+}
+
+// posts 重新渲染全部文章
+func (r *rerenderer) posts(ctx context.Context, posts *dao.PostDAO) (stats, error) {
+	var result stats
+	err := posts.ForEach(ctx, func(post *model.Post) error {
+		result.total++
+		doc, err := r.renderer.Render(post.Markdown, r.authorRole(ctx, post.AuthorID))
+		if err != nil {
+			result.failed++
+			log.Printf("渲染文章失败: id=%s, error=%v", post.ID.Hex(), err)
+			return nil
+		}
+		var toc []model.TOCItem
+		if !post.DisableTOC {
+			toc = render.BuildTOC(doc.Headings, post.TOCMaxDepth)
+		}
+		// 与保存时一致，只重新生成未手动填写的摘要
+		autoExcerpt, excerpt := post.AutoExcerpt || post.Excerpt == "", post.Excerpt
+		if autoExcerpt {
+			excerpt = doc.Excerpt(constants.PostExcerptDefaultLength)
+		}
+		text := doc.Stats()
+		if doc.HTML == post.HTML && reflect.DeepEqual(toc, post.TOC) &&
+			excerpt == post.Excerpt && autoExcerpt == post.AutoExcerpt &&
+			text.WordCount() == post.WordCount && text.ReadingTime() == post.ReadingTime {
+			return nil
+		}
+
+		result.changed++
+		if *dryRun {
+			log.Printf("文章需要更新: id=%s, slug=%s", post.ID.Hex(), post.Slug)
+			return nil
+		}
+		post.HTML, post.TOC = doc.HTML, toc
+		post.Excerpt, post.AutoExcerpt = excerpt, autoExcerpt
+		post.WordCount, post.ReadingTime = text.WordCount(), text.ReadingTime()
+		if err := posts.SetRendered(ctx, post); err != nil {
+			result.failed++
+			log.Printf("保存文章失败: id=%s, error=%v", post.ID.Hex(), err)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// pages 重新渲染全部页面
+func (r *rerenderer) pages(ctx context.Context, pages *dao.PageDAO) (stats, error) {
+	var result stats
+	err := pages.ForEach(ctx, func(page *model.Page) error {
+		result.total++
+		doc, err := r.renderer.Render(page.Content, r.authorRole(ctx, page.AuthorID))
+		if err != nil {
+			result.failed++
+			log.Printf("渲染页面失败: id=%s, error=%v", page.ID.Hex(), err)
+			return nil
+		}
+		if doc.HTML == page.HTML {
+			return nil
+		}
+
+		result.changed++
+		if *dryRun {
+			log.Printf("页面需要更新: id=%s, slug=%s", page.ID.Hex(), page.Slug)
+			return nil
+		}
+		if err := pages.SetHTML(ctx, page.ID, doc.HTML); err != nil {
+			result.failed++
+			log.Printf("保存页面HTML失败: id=%s, error=%v", page.ID.Hex(), err)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// connect 连接MongoDB
+func connect(ctx context.Context, c config.Config) *mongo.Client {
+	connectCtx, cancel := context.WithTimeout(ctx, time.Duration(c.MongoDB.ConnectTimeout)*time.Second)
+	defer cancel()
+
+	clientOptions := options.Client().ApplyURI(c.GetMongoDBURI())
+	clientOptions.SetServerSelectionTimeout(time.Duration(c.MongoDB.ServerSelectionTimeout) * time.Second)
+
+	client, err := mongo.Connect(connectCtx, clientOptions)
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	if err := client.Ping(connectCtx, nil); err != nil {
+		log.Fatalf("Failed to ping MongoDB: %v", err)
+	}
+	return client
+}
@@ -49,6 +49,9 @@ type Config struct {
 
 	// 缓存配置
 	Cache CacheConfig `json:",optional"`
+
+	// GeoIP配置
+	GeoIP GeoIPConfig `json:",optional"`
 }
 
 // JWTBusinessConfig JWT业务扩展配置
@@ -94,10 +97,51 @@ type RedisConfig struct {
 
 // SecurityConfig 安全配置
 type SecurityConfig struct {
-	BcryptCost           int             `json:",default=12"`
-	MaxLoginAttempts     int             `json:",default=5"`
-	LoginLockoutDuration int             `json:",default=1800"` // 30分钟
-	RateLimit            RateLimitConfig `json:",optional"`
+	BcryptCost            int                   `json:",default=12"`
+	MaxLoginAttemptsPerIP int                   `json:",default=20"`    // 同一IP在封禁窗口内允许的登录失败次数，不区分用户名
+	LoginIPBlockDuration  int                   `json:",default=1800"`  // IP登录失败计数窗口及封禁时间（秒）
+	IPAllowlistMode       bool                  `json:",default=false"` // 白名单模式，开启且白名单非空时只允许白名单中的IP访问
+	RateLimit             RateLimitConfig       `json:",optional"`
+	TrustedProxies        []string              `json:",optional"`                                        // 受信任的反向代理（CIDR或IP），仅信任其转发的X-Forwarded-For/X-Real-IP
+	PasswordResetURL      string                `json:",default=http://localhost:3000/reset-password"`    // 重置密码页面地址，令牌以token查询参数附加
+	PasswordResetTTL      int                   `json:",default=1800"`                                    // 重置令牌有效期（秒）
+	InvitationURL         string                `json:",default=http://localhost:3000/accept-invitation"` // 接受邀请页面地址，令牌以token查询参数附加
+	InvitationTTL         int                   `json:",default=604800"`                                  // 邀请链接有效期（秒）
+	MFAIssuer             string                `json:",default=Heimdall"`                                // 两步验证器中显示的签发方名称
+	MFASecretKey          string                // 加密保存TOTP密钥的密钥（Base64编码的32字节）
+	WebAuthn              WebAuthnConfig        `json:",optional"`
+	LoginAnomaly          LoginAnomalyConfig    `json:",optional"`
+	AuditLog              AuditLogConfig        `json:",optional"`
+	ContentSecurity       ContentSecurityConfig `json:",optional"`
+}
+
+// ContentSecurityConfig 内容安全配置，公开服务中的同名配置需保持一致
+type ContentSecurityConfig struct {
+	EnableXSSFilter bool     `json:",default=true"` // 是否按白名单净化渲染后的文章和页面HTML
+	EmbedRoles      []string `json:",optional"`     // 允许嵌入iframe和音视频的角色，为空时只允许所有者
+	EmbedHosts      []string `json:",optional"`     // 允许作为iframe来源的主机，为空时使用内置的常见视频和代码片段站点
+	URLSchemes      []string `json:",optional"`     // 链接和图片允许的URL协议，为空时为 http、https、mailto
+}
+
+// AuditLogConfig 审计日志配置
+type AuditLogConfig struct {
+	Enabled       bool `json:",default=true"` // 是否记录修改数据的管理操作
+	RetentionDays int  `json:",default=180"`  // 保留天数，启动时同步到TTL索引，0为永久保留
+}
+
+// LoginAnomalyConfig 登录异常检测配置
+type LoginAnomalyConfig struct {
+	Enabled        bool `json:",default=true"`  // 是否检测新国家、新设备和异地旅行登录
+	HistorySize    int  `json:",default=20"`    // 与最近多少次成功登录比较
+	MaxTravelSpeed int  `json:",default=900"`   // 正常出行的最大速度（公里/小时），超过视为异地旅行，0为不检测
+	NotifyUser     bool `json:",default=false"` // 是否向账号邮箱发送异常登录提醒
+}
+
+// WebAuthnConfig 通行密钥配置
+type WebAuthnConfig struct {
+	RPID    string   `json:",default=localhost"` // 依赖方ID，必须是管理后台域名或其上级域名
+	RPName  string   `json:",default=Heimdall"`  // 认证器中显示的站点名称
+	Origins []string `json:",optional"`          // 允许发起仪式的前端源，为空时使用 http://localhost:3000
 }
 
 // RateLimitConfig 限流配置
@@ -175,9 +219,8 @@ type BusinessConfig struct {
 
 // CacheConfig 缓存配置
 type CacheConfig struct {
-	JWTBlacklist  CacheItem `json:",optional"`
-	UserSession   CacheItem `json:",optional"`
-	LoginAttempts CacheItem `json:",optional"`
+	JWTBlacklist CacheItem `json:",optional"`
+	UserSession  CacheItem `json:",optional"`
 }
 
 // CacheItem 缓存项配置
@@ -238,3 +281,10 @@ func (c *Config) GetJWTExpireDuration() time.Duration {
 func (c *Config) GetRefreshTokenExpireDuration() time.Duration {
 	return time.Duration(c.JWTBusiness.RefreshExpire) * time.Second
 }
+
+// GeoIPConfig 离线IP地理位置配置
+type GeoIPConfig struct {
+	DatabasePath string `json:",optional"`      // MaxMind格式(.mmdb)数据库路径，为空时不解析地理位置
+	CacheSize    int    `json:",default=10000"` // 查询结果LRU缓存容量
+	Language     string `json:",default=en"`    // 地名语言，如 en、zh-CN
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/heimdall-api/admin-api/admin/internal/logic"
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// 修改当前用户密码
+func ChangePasswordHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ChangePasswordRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := logic.NewChangePasswordLogic(r.Context(), svcCtx)
+		resp, err := l.ChangePassword(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
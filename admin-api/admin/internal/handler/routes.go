@@ -13,135 +13,416 @@ import (
 
 func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 	server.AddRoutes(
-		[]rest.Route{
-			{
-				// 用户登录
-				Method:  http.MethodPost,
-				Path:    "/auth/login",
-				Handler: LoginHandler(serverCtx),
-			},
-		},
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Audit},
+			[]rest.Route{
+				{
+					// 用户登录
+					Method:  http.MethodPost,
+					Path:    "/auth/login",
+					Handler: LoginHandler(serverCtx),
+				},
+				{
+					// 申请重置密码
+					Method:  http.MethodPost,
+					Path:    "/auth/password/forgot",
+					Handler: ForgotPasswordHandler(serverCtx),
+				},
+				{
+					// 完成两步验证登录
+					Method:  http.MethodPost,
+					Path:    "/auth/login/mfa",
+					Handler: LoginMFAHandler(serverCtx),
+				},
+				{
+					// 使用一次性令牌重置密码
+					Method:  http.MethodPost,
+					Path:    "/auth/password/reset",
+					Handler: ResetPasswordHandler(serverCtx),
+				},
+				{
+					// 刷新访问令牌
+					Method:  http.MethodPost,
+					Path:    "/auth/refresh",
+					Handler: RefreshTokenHandler(serverCtx),
+				},
+				{
+					// 接受邀请并创建账号
+					Method:  http.MethodPost,
+					Path:    "/auth/invitations/accept",
+					Handler: AcceptInvitationHandler(serverCtx),
+				},
+				{
+					// 使用通行密钥登录
+					Method:  http.MethodPost,
+					Path:    "/auth/webauthn/login",
+					Handler: WebAuthnLoginHandler(serverCtx),
+				},
+				{
+					// 获取通行密钥登录选项
+					Method:  http.MethodPost,
+					Path:    "/auth/webauthn/login/options",
+					Handler: WebAuthnLoginOptionsHandler(serverCtx),
+				},
+			}...,
+		),
 		rest.WithPrefix("/api/v1/admin"),
 	)
 
 	server.AddRoutes(
-		[]rest.Route{
-			{
-				// 用户登出
-				Method:  http.MethodPost,
-				Path:    "/auth/logout",
-				Handler: LogoutHandler(serverCtx),
-			},
-			{
-				// 获取当前用户信息
-				Method:  http.MethodGet,
-				Path:    "/auth/profile",
-				Handler: ProfileHandler(serverCtx),
-			},
-			{
-				// 获取页面列表
-				Method:  http.MethodGet,
-				Path:    "/pages",
-				Handler: GetPageListHandler(serverCtx),
-			},
-			{
-				// 创建页面
-				Method:  http.MethodPost,
-				Path:    "/pages",
-				Handler: CreatePageHandler(serverCtx),
-			},
-			{
-				// 获取页面详情
-				Method:  http.MethodGet,
-				Path:    "/pages/:id",
-				Handler: GetPageDetailHandler(serverCtx),
-			},
-			{
-				// 更新页面
-				Method:  http.MethodPut,
-				Path:    "/pages/:id",
-				Handler: UpdatePageHandler(serverCtx),
-			},
-			{
-				// 删除页面
-				Method:  http.MethodDelete,
-				Path:    "/pages/:id",
-				Handler: DeletePageHandler(serverCtx),
-			},
-			{
-				// 发布页面
-				Method:  http.MethodPost,
-				Path:    "/pages/:id/publish",
-				Handler: PublishPageHandler(serverCtx),
-			},
-			{
-				// 取消发布页面
-				Method:  http.MethodPost,
-				Path:    "/pages/:id/unpublish",
-				Handler: UnpublishPageHandler(serverCtx),
-			},
-			{
-				// 获取文章列表
-				Method:  http.MethodGet,
-				Path:    "/posts",
-				Handler: GetPostListHandler(serverCtx),
-			},
-			{
-				// 创建文章
-				Method:  http.MethodPost,
-				Path:    "/posts",
-				Handler: CreatePostHandler(serverCtx),
-			},
-			{
-				// 获取文章详情
-				Method:  http.MethodGet,
-				Path:    "/posts/:id",
-				Handler: GetPostDetailHandler(serverCtx),
-			},
-			{
-				// 更新文章
-				Method:  http.MethodPut,
-				Path:    "/posts/:id",
-				Handler: UpdatePostHandler(serverCtx),
-			},
-			{
-				// 删除文章
-				Method:  http.MethodDelete,
-				Path:    "/posts/:id",
-				Handler: DeletePostHandler(serverCtx),
-			},
-			{
-				// 发布文章
-				Method:  http.MethodPost,
-				Path:    "/posts/:id/publish",
-				Handler: PublishPostHandler(serverCtx),
-			},
-			{
-				// 取消发布文章
-				Method:  http.MethodPost,
-				Path:    "/posts/:id/unpublish",
-				Handler: UnpublishPostHandler(serverCtx),
-			},
-			{
-				// 获取登录日志列表
-				Method:  http.MethodGet,
-				Path:    "/security/login-logs",
-				Handler: GetLoginLogsHandler(serverCtx),
-			},
-			{
-				// 获取用户列表
-				Method:  http.MethodGet,
-				Path:    "/users",
-				Handler: GetUserListHandler(serverCtx),
-			},
-			{
-				// 获取用户详情
-				Method:  http.MethodGet,
-				Path:    "/users/:id",
-				Handler: GetUserDetailHandler(serverCtx),
-			},
-		},
-		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.TokenBlacklist, serverCtx.Audit, serverCtx.Permission},
+			[]rest.Route{
+				{
+					// 用户登出
+					Method:  http.MethodPost,
+					Path:    "/auth/logout",
+					Handler: LogoutHandler(serverCtx),
+				},
+				{
+					// 获取当前用户的两步验证状态
+					Method:  http.MethodGet,
+					Path:    "/auth/mfa",
+					Handler: GetMFAStatusHandler(serverCtx),
+				},
+				{
+					// 重新生成两步验证恢复码
+					Method:  http.MethodPost,
+					Path:    "/auth/mfa/recovery-codes",
+					Handler: RegenerateRecoveryCodesHandler(serverCtx),
+				},
+				{
+					// 确认绑定TOTP验证器
+					Method:  http.MethodPost,
+					Path:    "/auth/mfa/totp/confirm",
+					Handler: ConfirmTOTPHandler(serverCtx),
+				},
+				{
+					// 关闭TOTP两步验证
+					Method:  http.MethodPost,
+					Path:    "/auth/mfa/totp/disable",
+					Handler: DisableTOTPHandler(serverCtx),
+				},
+				{
+					// 开始绑定TOTP验证器
+					Method:  http.MethodPost,
+					Path:    "/auth/mfa/totp/setup",
+					Handler: SetupTOTPHandler(serverCtx),
+				},
+				{
+					// 修改当前用户密码
+					Method:  http.MethodPost,
+					Path:    "/auth/password",
+					Handler: ChangePasswordHandler(serverCtx),
+				},
+				{
+					// 获取当前用户信息
+					Method:  http.MethodGet,
+					Path:    "/auth/profile",
+					Handler: ProfileHandler(serverCtx),
+				},
+				{
+					// 更新当前用户个人资料
+					Method:  http.MethodPut,
+					Path:    "/auth/profile",
+					Handler: UpdateProfileHandler(serverCtx),
+				},
+				{
+					// 获取当前用户的登录会话列表
+					Method:  http.MethodGet,
+					Path:    "/auth/sessions",
+					Handler: GetSessionListHandler(serverCtx),
+				},
+				{
+					// 吊销当前用户的指定会话
+					Method:  http.MethodDelete,
+					Path:    "/auth/sessions/:id",
+					Handler: RevokeSessionHandler(serverCtx),
+				},
+				{
+					// 吊销当前用户的其他会话
+					Method:  http.MethodPost,
+					Path:    "/auth/sessions/revoke-others",
+					Handler: RevokeOtherSessionsHandler(serverCtx),
+				},
+				{
+					// 获取当前用户的个人访问令牌列表
+					Method:  http.MethodGet,
+					Path:    "/auth/tokens",
+					Handler: GetAccessTokenListHandler(serverCtx),
+				},
+				{
+					// 创建个人访问令牌
+					Method:  http.MethodPost,
+					Path:    "/auth/tokens",
+					Handler: CreateAccessTokenHandler(serverCtx),
+				},
+				{
+					// 吊销个人访问令牌
+					Method:  http.MethodDelete,
+					Path:    "/auth/tokens/:id",
+					Handler: RevokeAccessTokenHandler(serverCtx),
+				},
+				{
+					// 获取当前用户的通行密钥列表
+					Method:  http.MethodGet,
+					Path:    "/auth/webauthn/credentials",
+					Handler: GetWebAuthnCredentialListHandler(serverCtx),
+				},
+				{
+					// 删除当前用户的通行密钥
+					Method:  http.MethodDelete,
+					Path:    "/auth/webauthn/credentials/:id",
+					Handler: DeleteWebAuthnCredentialHandler(serverCtx),
+				},
+				{
+					// 完成通行密钥注册
+					Method:  http.MethodPost,
+					Path:    "/auth/webauthn/register",
+					Handler: WebAuthnRegisterHandler(serverCtx),
+				},
+				{
+					// 获取通行密钥注册选项
+					Method:  http.MethodPost,
+					Path:    "/auth/webauthn/register/options",
+					Handler: WebAuthnRegisterOptionsHandler(serverCtx),
+				},
+				{
+					// 获取待接受的邀请列表
+					Method:  http.MethodGet,
+					Path:    "/invitations",
+					Handler: GetInvitationListHandler(serverCtx),
+				},
+				{
+					// 邀请用户
+					Method:  http.MethodPost,
+					Path:    "/invitations",
+					Handler: CreateInvitationHandler(serverCtx),
+				},
+				{
+					// 撤销邀请
+					Method:  http.MethodDelete,
+					Path:    "/invitations/:id",
+					Handler: RevokeInvitationHandler(serverCtx),
+				},
+				{
+					// 重新发送邀请
+					Method:  http.MethodPost,
+					Path:    "/invitations/:id/resend",
+					Handler: ResendInvitationHandler(serverCtx),
+				},
+				{
+					// 获取页面列表
+					Method:  http.MethodGet,
+					Path:    "/pages",
+					Handler: GetPageListHandler(serverCtx),
+				},
+				{
+					// 创建页面
+					Method:  http.MethodPost,
+					Path:    "/pages",
+					Handler: CreatePageHandler(serverCtx),
+				},
+				{
+					// 获取页面详情
+					Method:  http.MethodGet,
+					Path:    "/pages/:id",
+					Handler: GetPageDetailHandler(serverCtx),
+				},
+				{
+					// 更新页面
+					Method:  http.MethodPut,
+					Path:    "/pages/:id",
+					Handler: UpdatePageHandler(serverCtx),
+				},
+				{
+					// 删除页面
+					Method:  http.MethodDelete,
+					Path:    "/pages/:id",
+					Handler: DeletePageHandler(serverCtx),
+				},
+				{
+					// 发布页面
+					Method:  http.MethodPost,
+					Path:    "/pages/:id/publish",
+					Handler: PublishPageHandler(serverCtx),
+				},
+				{
+					// 取消发布页面
+					Method:  http.MethodPost,
+					Path:    "/pages/:id/unpublish",
+					Handler: UnpublishPageHandler(serverCtx),
+				},
+				{
+					// 获取slug生成策略
+					Method:  http.MethodGet,
+					Path:    "/settings/slug-strategy",
+					Handler: GetSlugStrategyHandler(serverCtx),
+				},
+				{
+					// 更新slug生成策略
+					Method:  http.MethodPut,
+					Path:    "/settings/slug-strategy",
+					Handler: UpdateSlugStrategyHandler(serverCtx),
+				},
+				{
+					// 获取文章列表
+					Method:  http.MethodGet,
+					Path:    "/posts",
+					Handler: GetPostListHandler(serverCtx),
+				},
+				{
+					// 创建文章
+					Method:  http.MethodPost,
+					Path:    "/posts",
+					Handler: CreatePostHandler(serverCtx),
+				},
+				{
+					// 获取文章详情
+					Method:  http.MethodGet,
+					Path:    "/posts/:id",
+					Handler: GetPostDetailHandler(serverCtx),
+				},
+				{
+					// 更新文章
+					Method:  http.MethodPut,
+					Path:    "/posts/:id",
+					Handler: UpdatePostHandler(serverCtx),
+				},
+				{
+					// 删除文章
+					Method:  http.MethodDelete,
+					Path:    "/posts/:id",
+					Handler: DeletePostHandler(serverCtx),
+				},
+				{
+					// 发布文章
+					Method:  http.MethodPost,
+					Path:    "/posts/:id/publish",
+					Handler: PublishPostHandler(serverCtx),
+				},
+				{
+					// 取消发布文章
+					Method:  http.MethodPost,
+					Path:    "/posts/:id/unpublish",
+					Handler: UnpublishPostHandler(serverCtx),
+				},
+				{
+					// 获取安全告警列表
+					Method:  http.MethodGet,
+					Path:    "/security/alerts",
+					Handler: GetSecurityAlertsHandler(serverCtx),
+				},
+				{
+					// 获取操作审计日志列表
+					Method:  http.MethodGet,
+					Path:    "/security/audit-logs",
+					Handler: GetAuditLogsHandler(serverCtx),
+				},
+				{
+					// 获取IP封禁和白名单列表
+					Method:  http.MethodGet,
+					Path:    "/security/ip-rules",
+					Handler: GetIPRuleListHandler(serverCtx),
+				},
+				{
+					// 添加IP封禁或白名单
+					Method:  http.MethodPost,
+					Path:    "/security/ip-rules",
+					Handler: CreateIPRuleHandler(serverCtx),
+				},
+				{
+					// 删除IP封禁或白名单
+					Method:  http.MethodDelete,
+					Path:    "/security/ip-rules/:id",
+					Handler: DeleteIPRuleHandler(serverCtx),
+				},
+				{
+					// 获取登录日志列表
+					Method:  http.MethodGet,
+					Path:    "/security/login-logs",
+					Handler: GetLoginLogsHandler(serverCtx),
+				},
+				{
+					// 获取登录统计
+					Method:  http.MethodGet,
+					Path:    "/security/login-stats",
+					Handler: GetLoginStatsHandler(serverCtx),
+				},
+				{
+					// 获取两步验证策略
+					Method:  http.MethodGet,
+					Path:    "/security/mfa-policy",
+					Handler: GetMFAPolicyHandler(serverCtx),
+				},
+				{
+					// 更新两步验证策略
+					Method:  http.MethodPut,
+					Path:    "/security/mfa-policy",
+					Handler: UpdateMFAPolicyHandler(serverCtx),
+				},
+				{
+					// 获取用户列表
+					Method:  http.MethodGet,
+					Path:    "/users",
+					Handler: GetUserListHandler(serverCtx),
+				},
+				{
+					// 创建用户
+					Method:  http.MethodPost,
+					Path:    "/users",
+					Handler: CreateUserHandler(serverCtx),
+				},
+				{
+					// 获取用户详情
+					Method:  http.MethodGet,
+					Path:    "/users/:id",
+					Handler: GetUserDetailHandler(serverCtx),
+				},
+				{
+					// 更新用户资料
+					Method:  http.MethodPut,
+					Path:    "/users/:id",
+					Handler: UpdateUserHandler(serverCtx),
+				},
+				{
+					// 删除用户
+					Method:  http.MethodDelete,
+					Path:    "/users/:id",
+					Handler: DeleteUserHandler(serverCtx),
+				},
+				{
+					// 激活用户
+					Method:  http.MethodPost,
+					Path:    "/users/:id/activate",
+					Handler: ActivateUserHandler(serverCtx),
+				},
+				{
+					// 修改用户角色
+					Method:  http.MethodPatch,
+					Path:    "/users/:id/role",
+					Handler: UpdateUserRoleHandler(serverCtx),
+				},
+				{
+					// 吊销指定用户的全部会话
+					Method:  http.MethodDelete,
+					Path:    "/users/:id/sessions",
+					Handler: RevokeUserSessionsHandler(serverCtx),
+				},
+				{
+					// 暂停用户
+					Method:  http.MethodPost,
+					Path:    "/users/:id/suspend",
+					Handler: SuspendUserHandler(serverCtx),
+				},
+				{
+					// 解锁用户
+					Method:  http.MethodPost,
+					Path:    "/users/:id/unlock",
+					Handler: UnlockUserHandler(serverCtx),
+				},
+			}...,
+		),
 		rest.WithPrefix("/api/v1/admin"),
 	)
 
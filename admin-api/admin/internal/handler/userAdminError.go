@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/heimdall-api/admin-api/admin/internal/logic"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/utils"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// writeUserAdminError 将用户管理的业务错误映射为对应的错误码，其他错误按默认方式返回
+func writeUserAdminError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, logic.ErrLastOwner):
+		utils.Error(w, constants.GetHTTPStatusCode(constants.ErrLastOwner),
+			constants.ErrLastOwner, err.Error(), nil)
+	case errors.Is(err, logic.ErrCannotDeleteSelf):
+		utils.Error(w, constants.GetHTTPStatusCode(constants.ErrCannotDeleteSelf),
+			constants.ErrCannotDeleteSelf, err.Error(), nil)
+	default:
+		httpx.ErrorCtx(r.Context(), w, err)
+	}
+}
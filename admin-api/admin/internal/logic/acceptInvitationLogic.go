@@ -0,0 +1,130 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type AcceptInvitationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 接受邀请并创建账号
+func NewAcceptInvitationLogic(ctx context.Context, svcCtx *svc.ServiceContext) *AcceptInvitationLogic {
+	return &AcceptInvitationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *AcceptInvitationLogic) AcceptInvitation(req *types.InvitationAcceptRequest) (resp *types.InvitationAcceptResponse, err error) {
+	// 1. 校验令牌签名并获取邀请
+	if req == nil || req.Token == "" {
+		return nil, errInvalidInvitation
+	}
+	invitation, err := l.getInvitation(req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 验证用户名和显示名
+	username := strings.TrimSpace(req.Username)
+	displayName := strings.TrimSpace(req.DisplayName)
+	if displayName == "" {
+		displayName = username
+	}
+	if username == "" {
+		return nil, errors.New("用户名不能为空")
+	}
+	if err := utils.ValidateUsername(username); err != nil {
+		return nil, errors.New("用户名必须以字母开头，只能包含字母、数字和下划线，长度3-30个字符")
+	}
+	user := model.NewUser(username, invitation.Email, "", displayName, invitation.Role)
+	if err := user.ValidateForCreate(); err != nil {
+		return nil, err
+	}
+
+	// 3. 密码策略检查，密码不能包含用户名或邮箱
+	if err := validateNewPassword(user, req.Password); err != nil {
+		return nil, err
+	}
+
+	// 4. 用户名和邮箱唯一性检查
+	if err := NewCreateUserLogic(l.ctx, l.svcCtx).checkUnique(username, invitation.Email); err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, passwordPolicyError(err)
+	}
+	user.PasswordHash = passwordHash
+	user.PasswordHistory = []string{passwordHash}
+
+	// 5. 先原子占用邀请，保证同一邀请只能创建一个账号
+	accepted, err := l.svcCtx.InvitationDAO.MarkAccepted(l.ctx, invitation.ID, invitation.TokenHash, user.ID)
+	if err != nil {
+		l.Logger.Errorf("标记邀请已接受失败: invitationID=%s, error=%v", invitation.ID.Hex(), err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if !accepted {
+		return nil, errInvalidInvitation
+	}
+
+	// 6. 创建用户，失败时释放邀请以便重试
+	if err := l.svcCtx.UserDAO.Create(l.ctx, user); err != nil {
+		l.Logger.Errorf("创建受邀用户失败: %v", err)
+		if released, releaseErr := l.svcCtx.InvitationDAO.ReleaseAccepted(l.ctx, invitation.ID, user.ID); releaseErr != nil || !released {
+			l.Logger.Errorf("释放邀请失败: invitationID=%s, released=%t, error=%v", invitation.ID.Hex(), released, releaseErr)
+		}
+		return nil, errors.New("用户名或邮箱已存在")
+	}
+	audit.SetActor(l.ctx, audit.Actor{UserID: user.ID.Hex(), Username: user.Username, Role: user.Role})
+	audit.SetTargetID(l.ctx, invitation.ID.Hex())
+
+	l.Logger.Infof("受邀用户已创建账号: invitationID=%s, userID=%s, role=%s", invitation.ID.Hex(), user.ID.Hex(), user.Role)
+	return &types.InvitationAcceptResponse{
+		Code:      200,
+		Message:   "账号创建成功，请登录",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data:      NewGetUserDetailLogic(l.ctx, l.svcCtx).buildUserInfo(user),
+	}, nil
+}
+
+// getInvitation 校验令牌签名和有效期，并确认令牌是该邀请最近一次发送的令牌
+func (l *AcceptInvitationLogic) getInvitation(token string) (*model.Invitation, error) {
+	subject, err := utils.ParseSignedToken(l.svcCtx.Config.Auth.AccessSecret, invitationTokenPurpose, token, time.Now())
+	if err != nil {
+		return nil, errInvalidInvitation
+	}
+	id, err := primitive.ObjectIDFromHex(subject)
+	if err != nil {
+		return nil, errInvalidInvitation
+	}
+
+	invitation, err := l.svcCtx.InvitationDAO.GetByID(l.ctx, id)
+	if err != nil {
+		l.Logger.Errorf("获取邀请失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if invitation == nil || !invitation.IsPending() || invitation.TokenHash != hashInvitationToken(token) {
+		return nil, errInvalidInvitation
+	}
+
+	return invitation, nil
+}
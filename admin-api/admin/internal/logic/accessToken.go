@@ -0,0 +1,40 @@
+package logic
+
+import (
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+)
+
+// availableAccessTokenScopes 角色可以使用的作用域
+func availableAccessTokenScopes(role string) []string {
+	var scopes []string
+	for _, scope := range constants.GetAllAccessTokenScopes() {
+		if constants.RoleHasScope(role, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// toAccessTokenInfo 转换为接口返回的令牌信息
+func toAccessTokenInfo(token *model.AccessToken) types.AccessTokenInfo {
+	info := types.AccessTokenInfo{
+		ID:         token.ID.Hex(),
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     token.Scopes,
+		Expired:    token.IsExpired(),
+		LastUsedIP: token.LastUsedIP,
+		CreatedAt:  token.CreatedAt.Format(time.RFC3339),
+	}
+	if token.ExpiresAt != nil {
+		info.ExpiresAt = token.ExpiresAt.Format(time.RFC3339)
+	}
+	if token.LastUsedAt != nil {
+		info.LastUsedAt = token.LastUsedAt.Format(time.RFC3339)
+	}
+	return info
+}
@@ -0,0 +1,59 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/constants"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ActivateUserLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 激活用户
+func NewActivateUserLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ActivateUserLogic {
+	return &ActivateUserLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ActivateUserLogic) ActivateUser(req *types.UserDetailRequest) (resp *types.UserDetailResponse, err error) {
+	if req == nil {
+		return nil, errors.New("请求不能为空")
+	}
+
+	// 1. 获取目标用户并检查角色层级
+	principal, user, err := loadManagedUser(l.ctx, l.svcCtx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 只有暂停或未激活的用户可以激活，锁定的用户需使用解锁操作
+	switch user.Status {
+	case constants.UserStatusSuspended, constants.UserStatusInactive:
+	case constants.UserStatusLocked:
+		return nil, errors.New("用户已被锁定，请使用解锁操作")
+	default:
+		return nil, errors.New("用户已是正常状态")
+	}
+
+	// 3. 更新状态
+	if err := l.svcCtx.UserDAO.Update(l.ctx, req.ID, map[string]interface{}{"status": constants.UserStatusActive}); err != nil {
+		l.Logger.Errorf("激活用户失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	audit.RecordChange(l.ctx, "status", user.Status, constants.UserStatusActive)
+
+	l.Logger.Infof("管理员激活用户: operator=%s, userID=%s, from=%s", principal.UserID, req.ID, user.Status)
+	return reloadUserDetail(l.ctx, l.svcCtx, req.ID, "用户已激活")
+}
@@ -0,0 +1,95 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ChangePasswordLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 修改当前用户密码
+func NewChangePasswordLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ChangePasswordLogic {
+	return &ChangePasswordLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ChangePasswordLogic) ChangePassword(req *types.ChangePasswordRequest) (resp *types.ChangePasswordResponse, err error) {
+	// 1. 参数验证
+	if req == nil || req.CurrentPassword == "" {
+		return nil, errors.New("当前密码不能为空")
+	}
+	if req.NewPassword == "" {
+		return nil, errors.New("新密码不能为空")
+	}
+
+	// 2. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 获取用户信息
+	user, err := l.svcCtx.UserDAO.GetByID(l.ctx, principal.UserID)
+	if err != nil {
+		l.Logger.Errorf("获取用户信息失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if user == nil {
+		return nil, errors.New("用户不存在")
+	}
+
+	// 4. 校验当前密码
+	if err := utils.VerifyPassword(req.CurrentPassword, user.PasswordHash); err != nil {
+		l.Logger.Infof("修改密码失败，当前密码错误: userID=%s", principal.UserID)
+		return nil, errors.New("当前密码错误")
+	}
+
+	// 5. 校验新密码
+	if err := validateNewPassword(user, req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	// 6. 保存新密码
+	updates, err := buildPasswordUpdates(user, req.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.svcCtx.UserDAO.Update(l.ctx, principal.UserID, updates); err != nil {
+		l.Logger.Errorf("更新密码失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	audit.RecordUpdates(l.ctx, user, updates)
+
+	// 7. 吊销当前会话以外的全部会话
+	revoked, err := revokeUserSessions(l.ctx, l.svcCtx.Redis, principal.UserID, principal.SessionID)
+	if err != nil {
+		// 密码已修改成功，吊销失败只记录日志
+		l.Logger.Errorf("修改密码后吊销其他会话失败: %v", err)
+	}
+
+	l.Logger.Infof("用户修改密码成功: userID=%s, revokedSessions=%d", principal.UserID, revoked)
+	return &types.ChangePasswordResponse{
+		Code:      200,
+		Message:   "密码修改成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.ChangePasswordData{
+			RevokedSessions: revoked,
+		},
+	}, nil
+}
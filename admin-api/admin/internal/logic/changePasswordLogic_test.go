@@ -0,0 +1,125 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bytedance/mockey"
+	"github.com/go-redis/redis/v8"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/config"
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+func TestChangePasswordLogic_ChangePassword(t *testing.T) {
+	mockey.PatchConvey("ChangePasswordLogic ChangePassword Tests", t, func() {
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+
+		cfg := config.Config{
+			Auth: struct {
+				AccessSecret string
+				AccessExpire int64
+			}{
+				AccessSecret: "test-secret",
+				AccessExpire: 7200,
+			},
+		}
+		svcCtx := &svc.ServiceContext{
+			Config:  cfg,
+			UserDAO: &dao.UserDAO{},
+			Redis:   rdb,
+		}
+
+		currentHash, _ := utils.HashPassword("Current#Pass1")
+		previousHash, _ := utils.HashPassword("Previous#Pass1")
+		testUser := &model.User{
+			ID:              primitive.NewObjectID(),
+			Username:        "testuser",
+			Email:           "writer@example.com",
+			PasswordHash:    currentHash,
+			PasswordHistory: []string{currentHash, previousHash},
+			Role:            constants.UserRoleAuthor,
+			Status:          constants.UserStatusActive,
+		}
+		mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+
+		var saved map[string]interface{}
+		mockey.Mock((*dao.UserDAO).Update).To(func(_ *dao.UserDAO, _ context.Context, _ string, updates map[string]interface{}) error {
+			saved = updates
+			return nil
+		}).Build()
+
+		current, err := startUserSession(context.Background(), svcCtx, testUser)
+		So(err, ShouldBeNil)
+		other, err := startUserSession(context.Background(), svcCtx, testUser)
+		So(err, ShouldBeNil)
+
+		ctx := auth.WithPrincipal(context.Background(), &auth.Principal{
+			UserID:    testUser.ID.Hex(),
+			Username:  testUser.Username,
+			Role:      testUser.Role,
+			SessionID: current.FamilyID,
+		})
+		change := func(currentPassword, newPassword string) (*types.ChangePasswordResponse, error) {
+			return NewChangePasswordLogic(ctx, svcCtx).ChangePassword(&types.ChangePasswordRequest{
+				CurrentPassword: currentPassword,
+				NewPassword:     newPassword,
+			})
+		}
+
+		Convey("Should reject wrong current password", func() {
+			_, err := change("Wrong#Pass1", "Brand#NewPass1")
+			So(err.Error(), ShouldEqual, "当前密码错误")
+			So(saved, ShouldBeNil)
+		})
+
+		Convey("Should reject weak passwords and passwords containing user info", func() {
+			_, err := change("Current#Pass1", "short")
+			So(err.Error(), ShouldContainSubstring, "密码长度不能少于")
+
+			_, err = change("Current#Pass1", "alllowercase")
+			So(err.Error(), ShouldContainSubstring, "密码强度不足")
+
+			_, err = change("Current#Pass1", "Testuser#2024")
+			So(err.Error(), ShouldEqual, "密码不能包含用户名或邮箱")
+			So(saved, ShouldBeNil)
+		})
+
+		Convey("Should reject recently used passwords", func() {
+			_, err := change("Current#Pass1", "Previous#Pass1")
+			So(err.Error(), ShouldContainSubstring, "最近")
+
+			_, err = change("Current#Pass1", "Current#Pass1")
+			So(err.Error(), ShouldContainSubstring, "最近")
+			So(saved, ShouldBeNil)
+		})
+
+		Convey("Should save new password and revoke other sessions", func() {
+			resp, err := change("Current#Pass1", "Brand#NewPass1")
+			So(err, ShouldBeNil)
+			So(resp.Data.RevokedSessions, ShouldEqual, 1)
+
+			newHash := saved["passwordHash"].(string)
+			So(utils.VerifyPassword("Brand#NewPass1", newHash), ShouldBeNil)
+			So(saved["passwordHistory"], ShouldResemble, []string{newHash, currentHash, previousHash})
+
+			sessions, _ := listUserSessions(context.Background(), rdb, testUser.ID.Hex())
+			So(len(sessions), ShouldEqual, 1)
+			So(sessions[0].SessionID, ShouldEqual, current.FamilyID)
+
+			family, _ := loadTokenFamily(context.Background(), rdb, other.FamilyID)
+			So(family.Revoked, ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,97 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ConfirmTOTPLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 确认绑定TOTP验证器
+func NewConfirmTOTPLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ConfirmTOTPLogic {
+	return &ConfirmTOTPLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ConfirmTOTPLogic) ConfirmTOTP(req *types.TOTPConfirmRequest) (resp *types.RecoveryCodesResponse, err error) {
+	// 1. 参数验证
+	if req == nil || req.Code == "" {
+		return nil, errors.New("验证码不能为空")
+	}
+
+	// 2. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 获取待确认的密钥
+	sealed, err := l.svcCtx.Redis.Get(l.ctx, mfaEnrollKey(principal.UserID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, errors.New("绑定已过期，请重新开始")
+	}
+	if err != nil {
+		l.Logger.Errorf("查询待确认TOTP密钥失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	secret, err := l.svcCtx.MFASecretBox.Open(sealed, principal.UserID)
+	if err != nil {
+		l.Logger.Errorf("解密待确认TOTP密钥失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	// 4. 校验首个验证码
+	step, ok := utils.ValidateTOTPCode(secret, req.Code, time.Now())
+	if !ok {
+		return nil, errors.New("验证码错误")
+	}
+
+	// 5. 生成恢复码
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		l.Logger.Errorf("生成恢复码失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	// 6. 启用两步验证，数据库中保存加密后的密钥
+	now := time.Now()
+	updates := map[string]interface{}{
+		"twoFactorEnabled":   true,
+		"twoFactorSecret":    sealed,
+		"twoFactorLastStep":  step,
+		"twoFactorEnabledAt": now,
+		"recoveryCodes":      hashes,
+	}
+	if err := l.svcCtx.UserDAO.Update(l.ctx, principal.UserID, updates); err != nil {
+		l.Logger.Errorf("启用两步验证失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	l.svcCtx.Redis.Del(l.ctx, mfaEnrollKey(principal.UserID))
+
+	l.Logger.Infof("用户启用两步验证: userID=%s", principal.UserID)
+	return &types.RecoveryCodesResponse{
+		Code:      200,
+		Message:   "两步验证已启用，请妥善保存恢复码",
+		Timestamp: now.Format(time.RFC3339),
+		Data: types.RecoveryCodesData{
+			RecoveryCodes: codes,
+		},
+	}, nil
+}
@@ -0,0 +1,60 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/render"
+)
+
+// renderContent 渲染文章或页面的Markdown内容，按内容作者的角色净化HTML
+// 使用作者而不是编辑者的角色，与重新渲染命令和公开服务输出时的净化策略保持一致
+func renderContent(ctx context.Context, svcCtx *svc.ServiceContext, source, authorID string) (*render.Document, error) {
+	role, err := contentAuthorRole(ctx, svcCtx, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := svcCtx.Renderer.Render(source, role)
+	if err != nil {
+		return nil, fmt.Errorf("内容渲染失败: %v", err)
+	}
+	return doc, nil
+}
+
+// postTOC 根据文章的目录设置生成目录，关闭目录时返回nil
+func postTOC(doc *render.Document, disable bool, maxDepth int) []model.TOCItem {
+	if disable {
+		return nil
+	}
+	return render.BuildTOC(doc.Headings, maxDepth)
+}
+
+// validateTOCMaxDepth 验证目录层数，0表示使用默认值
+func validateTOCMaxDepth(depth int) error {
+	if depth < 0 || depth > constants.TOCMaxDepthLimit {
+		return fmt.Errorf("目录层数必须在0到%d之间", constants.TOCMaxDepthLimit)
+	}
+	return nil
+}
+
+// contentAuthorRole 获取内容作者的角色，作者本人操作时直接使用认证主体的角色
+// 作者已被删除时返回空角色，按最严格的策略净化
+func contentAuthorRole(ctx context.Context, svcCtx *svc.ServiceContext, authorID string) (string, error) {
+	if principal, err := auth.FromContext(ctx); err == nil && principal.UserID == authorID {
+		return principal.Role, nil
+	}
+
+	author, err := svcCtx.UserDAO.GetByID(ctx, authorID)
+	if err != nil {
+		return "", fmt.Errorf("获取作者失败: %v", err)
+	}
+	if author == nil {
+		return "", nil
+	}
+	return author.Role, nil
+}
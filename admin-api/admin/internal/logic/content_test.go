@@ -0,0 +1,94 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/render"
+)
+
+func TestRenderContent(t *testing.T) {
+	mockey.PatchConvey("renderContent Tests", t, func() {
+		svcCtx := &svc.ServiceContext{
+			UserDAO:  &dao.UserDAO{},
+			Renderer: render.NewRenderer(render.NewSanitizer(render.SanitizeConfig{})),
+		}
+		ownerID, authorID := primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex()
+		source := "视频\n\n<iframe src=\"https://www.youtube.com/embed/abc\"></iframe>\n\n<script>alert(1)</script>"
+		embed := `<iframe src="https://www.youtube.com/embed/abc"></iframe>`
+
+		Convey("Authors editing their own content should use their own policy", func() {
+			doc, err := renderContent(withPrincipal(context.Background(), ownerID, constants.UserRoleOwner), svcCtx, source, ownerID)
+			So(err, ShouldBeNil)
+			So(doc.HTML, ShouldContainSubstring, embed)
+			So(doc.HTML, ShouldNotContainSubstring, "<script>")
+
+			doc, err = renderContent(withPrincipal(context.Background(), authorID, constants.UserRoleAuthor), svcCtx, source, authorID)
+			So(err, ShouldBeNil)
+			So(doc.HTML, ShouldStartWith, "<p>视频</p>")
+			So(doc.HTML, ShouldNotContainSubstring, "<iframe")
+		})
+
+		Convey("Editing someone else's content should use the author's policy", func() {
+			mockey.Mock((*dao.UserDAO).GetByID).To(func(_ *dao.UserDAO, _ context.Context, id string) (*model.User, error) {
+				So(id, ShouldEqual, authorID)
+				return &model.User{Role: constants.UserRoleAuthor}, nil
+			}).Build()
+
+			doc, err := renderContent(withPrincipal(context.Background(), ownerID, constants.UserRoleOwner), svcCtx, source, authorID)
+			So(err, ShouldBeNil)
+			So(doc.HTML, ShouldNotContainSubstring, "<iframe")
+		})
+
+		Convey("Deleted authors should fall back to the strictest policy", func() {
+			mockey.Mock((*dao.UserDAO).GetByID).Return(nil, nil).Build()
+
+			doc, err := renderContent(withPrincipal(context.Background(), ownerID, constants.UserRoleOwner), svcCtx, source, authorID)
+			So(err, ShouldBeNil)
+			So(doc.HTML, ShouldNotContainSubstring, "<iframe")
+			So(doc.HTML, ShouldNotContainSubstring, "<script>")
+		})
+
+		Convey("Should fail when the author cannot be loaded", func() {
+			mockey.Mock((*dao.UserDAO).GetByID).Return(nil, errors.New("user not found")).Build()
+
+			_, err := renderContent(withPrincipal(context.Background(), ownerID, constants.UserRoleOwner), svcCtx, source, authorID)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestPostTOC(t *testing.T) {
+	Convey("postTOC", t, func() {
+		doc, err := render.Parse("## 简介\n\n### 背景\n\n#### 细节\n\n## 简介")
+		So(err, ShouldBeNil)
+
+		Convey("Should build a nested outline limited by max depth", func() {
+			So(postTOC(doc, false, 2), ShouldResemble, []model.TOCItem{
+				{Level: 2, Text: "简介", ID: "简介", Children: []model.TOCItem{{Level: 3, Text: "背景", ID: "背景"}}},
+				{Level: 2, Text: "简介", ID: "简介-1"},
+			})
+			So(postTOC(doc, false, 0)[0].Children[0].Children, ShouldHaveLength, 1)
+		})
+
+		Convey("Should return nil when the post opts out", func() {
+			So(postTOC(doc, true, 0), ShouldBeNil)
+		})
+
+		Convey("Should validate max depth", func() {
+			So(validateTOCMaxDepth(0), ShouldBeNil)
+			So(validateTOCMaxDepth(constants.TOCMaxDepthLimit), ShouldBeNil)
+			So(validateTOCMaxDepth(-1), ShouldNotBeNil)
+			So(validateTOCMaxDepth(constants.TOCMaxDepthLimit+1), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,139 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateAccessTokenLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 创建个人访问令牌
+func NewCreateAccessTokenLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateAccessTokenLogic {
+	return &CreateAccessTokenLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateAccessTokenLogic) CreateAccessToken(req *types.AccessTokenCreateRequest) (resp *types.AccessTokenCreateResponse, err error) {
+	// 1. 参数验证
+	if req == nil || req.Password == "" {
+		return nil, errors.New("密码不能为空")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("令牌名称不能为空")
+	}
+	if len([]rune(name)) > constants.AccessTokenNameMaxLength {
+		return nil, fmt.Errorf("令牌名称长度不能超过%d个字符", constants.AccessTokenNameMaxLength)
+	}
+	if req.ExpiresIn < 0 || req.ExpiresIn > constants.AccessTokenMaxExpireDays {
+		return nil, fmt.Errorf("有效期必须在0到%d天之间", constants.AccessTokenMaxExpireDays)
+	}
+
+	// 2. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 校验作用域，只能申请角色拥有的权限
+	available := availableAccessTokenScopes(principal.Role)
+	var scopes []string
+	for _, scope := range req.Scopes {
+		if !constants.IsValidAccessTokenScope(scope) {
+			return nil, fmt.Errorf("无效的作用域: %s", scope)
+		}
+		if !slices.Contains(available, scope) {
+			return nil, fmt.Errorf("当前角色不能使用作用域: %s", scope)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("至少需要选择一个作用域")
+	}
+
+	// 4. 获取用户并校验密码
+	user, err := l.svcCtx.UserDAO.GetByID(l.ctx, principal.UserID)
+	if err != nil {
+		l.Logger.Errorf("获取用户信息失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if user == nil {
+		return nil, errors.New("用户不存在")
+	}
+	if err := utils.VerifyPassword(req.Password, user.PasswordHash); err != nil {
+		return nil, errors.New("密码错误")
+	}
+
+	// 5. 检查令牌数量上限
+	userID, err := primitive.ObjectIDFromHex(principal.UserID)
+	if err != nil {
+		return nil, errors.New("无效的用户ID")
+	}
+	count, err := l.svcCtx.AccessTokenDAO.CountActiveByUserID(l.ctx, userID)
+	if err != nil {
+		l.Logger.Errorf("统计个人访问令牌失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if count >= constants.AccessTokenMaxPerUser {
+		return nil, fmt.Errorf("最多只能创建%d个有效令牌，请先吊销不用的令牌", constants.AccessTokenMaxPerUser)
+	}
+
+	// 6. 生成令牌，只保存哈希
+	plaintext, prefix, err := utils.GenerateAccessToken()
+	if err != nil {
+		l.Logger.Errorf("生成个人访问令牌失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	token := &model.AccessToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: utils.HashAccessToken(plaintext),
+		Prefix:    prefix,
+		Scopes:    scopes,
+	}
+	if req.ExpiresIn > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresIn)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := l.svcCtx.AccessTokenDAO.Create(l.ctx, token); err != nil {
+		l.Logger.Errorf("保存个人访问令牌失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	audit.SetTargetID(l.ctx, token.ID.Hex())
+
+	l.Logger.Infof("用户创建个人访问令牌: userID=%s, tokenID=%s, scopes=%v", principal.UserID, token.ID.Hex(), scopes)
+	return &types.AccessTokenCreateResponse{
+		Code:      200,
+		Message:   "令牌已创建，请立即复制保存，关闭后将无法再次查看",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.AccessTokenCreateData{
+			Token:       plaintext,
+			AccessToken: toAccessTokenInfo(token),
+		},
+	}, nil
+}
@@ -0,0 +1,127 @@
+package logic
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+func TestAccessTokenLogic(t *testing.T) {
+	mockey.PatchConvey("Personal Access Token Logic Tests", t, func() {
+		svcCtx := &svc.ServiceContext{
+			UserDAO:        &dao.UserDAO{},
+			AccessTokenDAO: &dao.AccessTokenDAO{},
+		}
+
+		passwordHash, _ := utils.HashPassword("Current#Pass1")
+		testUser := &model.User{
+			ID:           primitive.NewObjectID(),
+			Username:     "author",
+			PasswordHash: passwordHash,
+			Role:         constants.UserRoleAuthor,
+			Status:       constants.UserStatusActive,
+		}
+		mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+
+		// 用内存中的数据模拟数据库
+		var tokens []*model.AccessToken
+		mockey.Mock((*dao.AccessTokenDAO).Create).To(func(_ *dao.AccessTokenDAO, _ context.Context, token *model.AccessToken) error {
+			token.ID = primitive.NewObjectID()
+			tokens = append(tokens, token)
+			return nil
+		}).Build()
+		mockey.Mock((*dao.AccessTokenDAO).CountActiveByUserID).To(func(_ *dao.AccessTokenDAO, _ context.Context, _ primitive.ObjectID) (int64, error) {
+			return int64(len(tokens)), nil
+		}).Build()
+		mockey.Mock((*dao.AccessTokenDAO).ListByUserID).To(func(_ *dao.AccessTokenDAO, _ context.Context, _ primitive.ObjectID) ([]*model.AccessToken, error) {
+			return tokens, nil
+		}).Build()
+		mockey.Mock((*dao.AccessTokenDAO).Revoke).To(func(_ *dao.AccessTokenDAO, _ context.Context, id, _ primitive.ObjectID) (bool, error) {
+			for i, token := range tokens {
+				if token.ID == id {
+					tokens = append(tokens[:i], tokens[i+1:]...)
+					return true, nil
+				}
+			}
+			return false, nil
+		}).Build()
+
+		ctx := withPrincipal(context.Background(), testUser.ID.Hex(), testUser.Role)
+		create := func(req *types.AccessTokenCreateRequest) (*types.AccessTokenCreateResponse, error) {
+			return NewCreateAccessTokenLogic(ctx, svcCtx).CreateAccessToken(req)
+		}
+
+		Convey("Create should return the plaintext once and store only its hash", func() {
+			resp, err := create(&types.AccessTokenCreateRequest{
+				Password:  "Current#Pass1",
+				Name:      " deploy script ",
+				Scopes:    []string{constants.ScopeWritePosts, constants.ScopeWritePosts},
+				ExpiresIn: 30,
+			})
+			So(err, ShouldBeNil)
+			So(strings.HasPrefix(resp.Data.Token, constants.AccessTokenPrefix), ShouldBeTrue)
+			So(resp.Data.AccessToken.Name, ShouldEqual, "deploy script")
+			So(resp.Data.AccessToken.Scopes, ShouldResemble, []string{constants.ScopeWritePosts})
+			So(resp.Data.AccessToken.ExpiresAt, ShouldNotBeEmpty)
+
+			So(tokens, ShouldHaveLength, 1)
+			So(tokens[0].TokenHash, ShouldEqual, utils.HashAccessToken(resp.Data.Token))
+			So(strings.HasPrefix(resp.Data.Token, tokens[0].Prefix), ShouldBeTrue)
+
+			Convey("List should not expose the token again", func() {
+				list, err := NewGetAccessTokenListLogic(ctx, svcCtx).GetAccessTokenList()
+				So(err, ShouldBeNil)
+				So(list.Data.List, ShouldHaveLength, 1)
+				So(list.Data.List[0].Prefix, ShouldEqual, tokens[0].Prefix)
+				So(list.Data.AvailableScopes, ShouldNotContain, constants.ScopeAdminUsers)
+			})
+
+			Convey("Revoke should only succeed once", func() {
+				req := &types.AccessTokenRevokeRequest{ID: resp.Data.AccessToken.ID}
+				_, err := NewRevokeAccessTokenLogic(ctx, svcCtx).RevokeAccessToken(req)
+				So(err, ShouldBeNil)
+				So(tokens, ShouldBeEmpty)
+
+				_, err = NewRevokeAccessTokenLogic(ctx, svcCtx).RevokeAccessToken(req)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("Create should reject a wrong password", func() {
+			_, err := create(&types.AccessTokenCreateRequest{Password: "wrong", Name: "ci", Scopes: []string{constants.ScopeReadPosts}})
+			So(err, ShouldNotBeNil)
+			So(tokens, ShouldBeEmpty)
+		})
+
+		Convey("Create should reject unknown scopes and scopes beyond the role", func() {
+			_, err := create(&types.AccessTokenCreateRequest{Password: "Current#Pass1", Name: "ci", Scopes: []string{"write:everything"}})
+			So(err, ShouldNotBeNil)
+
+			_, err = create(&types.AccessTokenCreateRequest{Password: "Current#Pass1", Name: "ci", Scopes: []string{constants.ScopeAdminUsers}})
+			So(err, ShouldNotBeNil)
+			So(tokens, ShouldBeEmpty)
+		})
+
+		Convey("Create should enforce expiry bounds and the per-user limit", func() {
+			_, err := create(&types.AccessTokenCreateRequest{Password: "Current#Pass1", Name: "ci", Scopes: []string{constants.ScopeReadPosts}, ExpiresIn: constants.AccessTokenMaxExpireDays + 1})
+			So(err, ShouldNotBeNil)
+
+			for i := 0; i < constants.AccessTokenMaxPerUser; i++ {
+				tokens = append(tokens, &model.AccessToken{ID: primitive.NewObjectID()})
+			}
+			_, err = create(&types.AccessTokenCreateRequest{Password: "Current#Pass1", Name: "ci", Scopes: []string{constants.ScopeReadPosts}})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
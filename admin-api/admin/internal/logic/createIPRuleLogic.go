@@ -0,0 +1,126 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateIPRuleLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 添加IP封禁或白名单
+func NewCreateIPRuleLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateIPRuleLogic {
+	return &CreateIPRuleLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateIPRuleLogic) CreateIPRule(req *types.IPRuleCreateRequest) (resp *types.IPRuleResponse, err error) {
+	if req == nil {
+		return nil, errors.New("请求不能为空")
+	}
+
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 参数验证
+	if !constants.IsValidIPRuleType(req.Type) {
+		return nil, errors.New("规则类型无效")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errors.New("原因不能为空")
+	}
+	if utf8.RuneCountInString(reason) > constants.IPRuleMaxReasonLength {
+		return nil, fmt.Errorf("原因不能超过%d个字符", constants.IPRuleMaxReasonLength)
+	}
+	ipNet, err := utils.ParseIPNet(req.CIDR)
+	if err != nil {
+		return nil, errors.New("IP地址或网段格式无效")
+	}
+
+	// 2. 防止操作者把自己挡在管理后台之外
+	currentIP := operatorIP(l.ctx)
+	if req.Type == constants.IPRuleTypeBlock && currentIP != nil && ipNet.Contains(currentIP) {
+		return nil, errors.New("不能封禁当前操作者的IP")
+	}
+	if req.Type == constants.IPRuleTypeAllow && l.svcCtx.IPAccessControl.AllowlistMode() && currentIP != nil && !ipNet.Contains(currentIP) {
+		// 白名单为空时添加的第一条规则必须包含当前IP
+		allowRules, err := loadAllowRules(l.ctx, l.svcCtx)
+		if err != nil {
+			return nil, err
+		}
+		if len(allowRules) == 0 {
+			return nil, errIPRuleLockout
+		}
+	}
+
+	// 3. 临时封禁只针对单个IP，保存在Redis中到期自动解除
+	if req.Duration > 0 {
+		if req.Type != constants.IPRuleTypeBlock {
+			return nil, errors.New("只有封禁规则支持设置时长")
+		}
+		if ones, bits := ipNet.Mask.Size(); ones != bits {
+			return nil, errors.New("临时封禁只支持单个IP")
+		}
+
+		rule, err := l.svcCtx.IPAccessControl.BlockTemporarily(l.ctx, ipNet.IP.String(), reason, principal.UserID, principal.Username, time.Duration(req.Duration)*time.Second)
+		if err != nil {
+			l.Logger.Errorf("临时封禁IP失败: %v", err)
+			return nil, errors.New("系统错误，请稍后重试")
+		}
+		audit.SetTargetID(l.ctx, ipRuleID(rule))
+
+		l.Logger.Infof("已临时封禁IP: operator=%s, ip=%s, duration=%ds", principal.UserID, ipNet.IP.String(), req.Duration)
+		return newIPRuleResponse("封禁成功", rule), nil
+	}
+
+	// 4. 永久规则不允许重复
+	existing, err := l.svcCtx.IPRuleDAO.GetByCIDR(l.ctx, req.Type, ipNet.String())
+	if err != nil {
+		l.Logger.Errorf("查询IP规则失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if existing != nil {
+		return nil, errors.New("该规则已存在")
+	}
+
+	// 5. 保存规则并刷新本地缓存
+	rule := &model.IPRule{
+		Type:          req.Type,
+		CIDR:          ipNet.String(),
+		Reason:        reason,
+		CreatedBy:     principal.UserID,
+		CreatedByName: principal.Username,
+	}
+	if err := l.svcCtx.IPRuleDAO.Create(l.ctx, rule); err != nil {
+		l.Logger.Errorf("创建IP规则失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	l.svcCtx.IPAccessControl.Invalidate()
+	audit.SetTargetID(l.ctx, rule.ID.Hex())
+
+	l.Logger.Infof("已添加IP规则: operator=%s, type=%s, cidr=%s", principal.UserID, rule.Type, rule.CIDR)
+	return newIPRuleResponse("添加成功", rule), nil
+}
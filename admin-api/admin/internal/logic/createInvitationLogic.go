@@ -0,0 +1,123 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateInvitationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 邀请用户
+func NewCreateInvitationLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateInvitationLogic {
+	return &CreateInvitationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateInvitationLogic) CreateInvitation(req *types.InvitationCreateRequest) (resp *types.InvitationResponse, err error) {
+	// 1. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 参数验证
+	if req == nil {
+		return nil, errors.New("请求不能为空")
+	}
+	email := strings.TrimSpace(req.Email)
+	if email == "" {
+		return nil, errors.New("邮箱不能为空")
+	}
+	if err := utils.ValidateEmail(email); err != nil {
+		return nil, errors.New("邮箱格式无效")
+	}
+	if !constants.IsValidUserRole(req.Role) {
+		return nil, errors.New("无效的用户角色")
+	}
+
+	// 3. 只能邀请等级低于自己的角色，所有者可以邀请任意角色
+	if !constants.CanManageRole(principal.Role, req.Role) {
+		return nil, errors.New("无权邀请该角色的用户")
+	}
+
+	// 4. 邮箱不能已注册或已有待接受的邀请
+	if err := l.checkEmail(email); err != nil {
+		return nil, err
+	}
+
+	// 5. 签发邀请令牌并保存邀请
+	inviterID, err := primitive.ObjectIDFromHex(principal.UserID)
+	if err != nil {
+		return nil, errors.New("用户ID格式无效")
+	}
+	invitation := &model.Invitation{
+		ID:        primitive.NewObjectID(),
+		Email:     email,
+		Role:      req.Role,
+		InvitedBy: inviterID,
+		SendCount: 1,
+	}
+	token, tokenHash, expiresAt, err := issueInvitationToken(l.svcCtx, invitation.ID)
+	if err != nil {
+		l.Logger.Errorf("签发邀请令牌失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	invitation.TokenHash = tokenHash
+	invitation.ExpiresAt = expiresAt
+	invitation.LastSentAt = time.Now()
+	if err := l.svcCtx.InvitationDAO.Create(l.ctx, invitation); err != nil {
+		l.Logger.Errorf("保存邀请失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	audit.SetTargetID(l.ctx, invitation.ID.Hex())
+
+	// 6. 发送邀请邮件
+	sendInvitationMail(l.ctx, l.svcCtx, invitation, principal.UserID, token)
+
+	l.Logger.Infof("已创建用户邀请: operator=%s, invitationID=%s, role=%s", principal.UserID, invitation.ID.Hex(), invitation.Role)
+	return newInvitationResponse("邀请已发送", invitation), nil
+}
+
+// checkEmail 检查邮箱是否已注册或已有未过期的待接受邀请
+func (l *CreateInvitationLogic) checkEmail(email string) error {
+	user, err := l.svcCtx.UserDAO.GetByEmail(l.ctx, email)
+	if err != nil {
+		l.Logger.Errorf("查询邮箱失败: %v", err)
+		return errors.New("系统错误，请稍后重试")
+	}
+	if user != nil {
+		return errors.New("该邮箱已注册")
+	}
+
+	pending, err := l.svcCtx.InvitationDAO.GetPendingByEmail(l.ctx, email)
+	if err != nil {
+		l.Logger.Errorf("查询待接受邀请失败: %v", err)
+		return errors.New("系统错误，请稍后重试")
+	}
+	if pending != nil {
+		return errors.New("该邮箱已有待接受的邀请，请重新发送或撤销后再邀请")
+	}
+
+	return nil
+}
@@ -3,11 +3,12 @@ package logic
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/heimdall-api/admin-api/admin/internal/svc"
 	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
 	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -37,12 +38,12 @@ func (l *CreatePageLogic) CreatePage(req *types.PageCreateRequest) (resp *types.
 	}
 
 	// 2. 获取当前用户信息
-	userID := l.ctx.Value("userId")
-	if userID == nil {
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
 		return nil, fmt.Errorf("用户未登录")
 	}
 
-	authorID, err := primitive.ObjectIDFromHex(userID.(string))
+	authorID, err := primitive.ObjectIDFromHex(principal.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("无效的用户ID")
 	}
@@ -54,9 +55,9 @@ func (l *CreatePageLogic) CreatePage(req *types.PageCreateRequest) (resp *types.
 	}
 
 	// 3. 处理slug
-	slug := req.Slug
-	if slug == "" {
-		slug = l.generateSlugFromTitle(req.Title)
+	slug, err := l.resolveSlug(req.Slug, req.Title)
+	if err != nil {
+		return nil, err
 	}
 
 	// 检查slug重复并生成唯一slug
@@ -66,12 +67,16 @@ func (l *CreatePageLogic) CreatePage(req *types.PageCreateRequest) (resp *types.
 	}
 
 	// 4. 创建页面模型
-	page := l.buildPageFromRequest(req, authorID, uniqueSlug)
+	page, err := l.buildPageFromRequest(req, authorID, uniqueSlug)
+	if err != nil {
+		return nil, err
+	}
 
 	// 5. 保存到数据库
 	if err := l.svcCtx.PageDAO.Create(l.ctx, page); err != nil {
 		return nil, fmt.Errorf("页面创建失败: %v", err)
 	}
+	audit.SetTargetID(l.ctx, page.ID.Hex())
 
 	// 6. 获取创建后的页面（包含生成的ID）
 	createdPage, err := l.svcCtx.PageDAO.GetByID(l.ctx, page.ID.Hex())
@@ -109,9 +114,13 @@ func (l *CreatePageLogic) validateRequest(req *types.PageCreateRequest) error {
 	return nil
 }
 
-// generateSlugFromTitle 从标题生成slug
-func (l *CreatePageLogic) generateSlugFromTitle(title string) string {
-	return model.GenerateSlugFromText(title)
+// resolveSlug 按站点的slug策略校验手动填写的slug，未填写时从标题生成
+func (l *CreatePageLogic) resolveSlug(input, title string) (string, error) {
+	strategy := slugStrategy(l.ctx, l.svcCtx)
+	if input != "" {
+		return input, validateSlug(strategy, input)
+	}
+	return model.GenerateSlugWith(strategy, title, constants.SlugFallbackPrefixPage), nil
 }
 
 // generateUniqueSlug 生成唯一的slug
@@ -137,7 +146,12 @@ func (l *CreatePageLogic) generateUniqueSlug(baseSlug string) (string, error) {
 }
 
 // buildPageFromRequest 从请求构建页面模型
-func (l *CreatePageLogic) buildPageFromRequest(req *types.PageCreateRequest, authorID primitive.ObjectID, slug string) *model.Page {
+func (l *CreatePageLogic) buildPageFromRequest(req *types.PageCreateRequest, authorID primitive.ObjectID, slug string) (*model.Page, error) {
+	doc, err := renderContent(l.ctx, l.svcCtx, req.Content, authorID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 
 	// 处理发布时间
@@ -159,7 +173,7 @@ func (l *CreatePageLogic) buildPageFromRequest(req *types.PageCreateRequest, aut
 		Title:           req.Title,
 		Slug:            slug,
 		Content:         req.Content,
-		HTML:            l.convertContentToHTML(req.Content),
+		HTML:            doc.HTML,
 		AuthorID:        authorID,
 		Status:          req.Status,
 		Template:        template,
@@ -172,26 +186,7 @@ func (l *CreatePageLogic) buildPageFromRequest(req *types.PageCreateRequest, aut
 		UpdatedAt:       now,
 	}
 
-	return page
-}
-
-// convertContentToHTML 将内容转换为HTML
-func (l *CreatePageLogic) convertContentToHTML(content string) string {
-	// 简单的内容到HTML转换
-	// 在实际项目中，应该使用专业的Markdown库或富文本编辑器
-	html := strings.ReplaceAll(content, "\n", "<br/>")
-
-	// 处理标题
-	html = strings.ReplaceAll(html, "# ", "<h1>")
-	html = strings.ReplaceAll(html, "## ", "<h2>")
-	html = strings.ReplaceAll(html, "### ", "<h3>")
-
-	// 简单的段落处理
-	if !strings.Contains(html, "<h") && !strings.Contains(html, "<p") {
-		html = "<p>" + html + "</p>"
-	}
-
-	return html
+	return page, nil
 }
 
 // buildPageDetailData 构建页面详情数据
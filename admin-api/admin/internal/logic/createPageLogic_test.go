@@ -8,8 +8,10 @@ import (
 
 	"github.com/heimdall-api/admin-api/admin/internal/svc"
 	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/dao"
 	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/render"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/bytedance/mockey"
@@ -19,10 +21,12 @@ import (
 func TestCreatePageLogic_CreatePage(t *testing.T) {
 	Convey("测试页面创建功能", t, func() {
 		// 准备测试数据
-		ctx := context.WithValue(context.Background(), "userId", "507f1f77bcf86cd799439011")
+		ctx := withPrincipal(context.Background(), "507f1f77bcf86cd799439011", constants.UserRoleAuthor)
 		svcCtx := &svc.ServiceContext{
-			UserDAO: &dao.UserDAO{},
-			PageDAO: &dao.PageDAO{},
+			UserDAO:    &dao.UserDAO{},
+			PageDAO:    &dao.PageDAO{},
+			SettingDAO: &dao.SettingDAO{},
+			Renderer:   render.NewRenderer(render.NewSanitizer(render.SanitizeConfig{})),
 		}
 		logic := NewCreatePageLogic(ctx, svcCtx)
 
@@ -55,6 +59,7 @@ func TestCreatePageLogic_CreatePage(t *testing.T) {
 
 			mockey.PatchConvey("Mock UserDAO.GetByID", func() {
 				mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+				mockey.Mock((*dao.SettingDAO).GetByKey).Return(nil, nil).Build()
 				mockey.Mock((*dao.PageDAO).GetBySlug).Return(nil, ErrNotFound).Build()
 				mockey.Mock((*dao.PageDAO).Create).Return(nil).Build()
 				mockey.Mock((*dao.PageDAO).GetByID).Return(testPage, nil).Build()
@@ -173,6 +178,7 @@ func TestCreatePageLogic_CreatePage(t *testing.T) {
 
 			mockey.PatchConvey("Mock Slug已存在", func() {
 				mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+				mockey.Mock((*dao.SettingDAO).GetByKey).Return(nil, nil).Build()
 				mockey.Mock((*dao.PageDAO).GetBySlug).Return(testPage, nil).Build()
 
 				resp, err := logic.CreatePage(req)
@@ -181,6 +187,18 @@ func TestCreatePageLogic_CreatePage(t *testing.T) {
 				So(err.Error(), ShouldContainSubstring, "slug生成失败")
 				So(resp, ShouldBeNil)
 			})
+
+			mockey.PatchConvey("Mock Slug不符合当前策略", func() {
+				mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+				mockey.Mock((*dao.SettingDAO).GetByKey).Return(nil, nil).Build()
+				req.Slug = "关于我们"
+
+				resp, err := logic.CreatePage(req)
+
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, constants.SlugStrategyDefault)
+				So(resp, ShouldBeNil)
+			})
 		})
 
 		Convey("数据库创建失败", func() {
@@ -192,6 +210,7 @@ func TestCreatePageLogic_CreatePage(t *testing.T) {
 
 			mockey.PatchConvey("Mock PageDAO.Create 返回错误", func() {
 				mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+				mockey.Mock((*dao.SettingDAO).GetByKey).Return(nil, nil).Build()
 				mockey.Mock((*dao.PageDAO).GetBySlug).Return(nil, ErrNotFound).Build()
 				mockey.Mock((*dao.PageDAO).Create).Return(ErrDatabaseError).Build()
 
@@ -212,8 +231,12 @@ func TestCreatePageLogic_CreatePage(t *testing.T) {
 
 			mockey.PatchConvey("Mock 自动生成slug", func() {
 				mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+				mockey.Mock((*dao.SettingDAO).GetByKey).Return(nil, nil).Build()
 				mockey.Mock((*dao.PageDAO).GetBySlug).Return(nil, ErrNotFound).Build()
-				mockey.Mock((*dao.PageDAO).Create).Return(nil).Build()
+				mockey.Mock((*dao.PageDAO).Create).To(func(_ *dao.PageDAO, _ context.Context, page *model.Page) error {
+					So(page.Slug, ShouldEqual, "test-page-title")
+					return nil
+				}).Build()
 				mockey.Mock((*dao.PageDAO).GetByID).Return(testPage, nil).Build()
 
 				resp, err := logic.CreatePage(req)
@@ -235,6 +258,7 @@ func TestCreatePageLogic_CreatePage(t *testing.T) {
 
 			mockey.PatchConvey("Mock 带发布时间的创建", func() {
 				mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+				mockey.Mock((*dao.SettingDAO).GetByKey).Return(nil, nil).Build()
 				mockey.Mock((*dao.PageDAO).GetBySlug).Return(nil, ErrNotFound).Build()
 				mockey.Mock((*dao.PageDAO).Create).Return(nil).Build()
 				mockey.Mock((*dao.PageDAO).GetByID).Return(testPage, nil).Build()
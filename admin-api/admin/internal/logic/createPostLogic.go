@@ -3,11 +3,12 @@ package logic
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/heimdall-api/admin-api/admin/internal/svc"
 	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
 	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -37,12 +38,12 @@ func (l *CreatePostLogic) CreatePost(req *types.PostCreateRequest) (resp *types.
 	}
 
 	// 2. 获取当前用户信息
-	userID := l.ctx.Value("userId")
-	if userID == nil {
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
 		return nil, fmt.Errorf("用户未登录")
 	}
 
-	authorID, err := primitive.ObjectIDFromHex(userID.(string))
+	authorID, err := primitive.ObjectIDFromHex(principal.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("无效的用户ID")
 	}
@@ -54,9 +55,9 @@ func (l *CreatePostLogic) CreatePost(req *types.PostCreateRequest) (resp *types.
 	}
 
 	// 3. 处理slug
-	slug := req.Slug
-	if slug == "" {
-		slug = l.generateSlugFromTitle(req.Title)
+	slug, err := l.resolveSlug(req.Slug, req.Title)
+	if err != nil {
+		return nil, err
 	}
 
 	// 检查slug重复并生成唯一slug
@@ -66,12 +67,16 @@ func (l *CreatePostLogic) CreatePost(req *types.PostCreateRequest) (resp *types.
 	}
 
 	// 4. 创建文章模型
-	post := l.buildPostFromRequest(req, authorID, uniqueSlug)
+	post, err := l.buildPostFromRequest(req, authorID, uniqueSlug)
+	if err != nil {
+		return nil, err
+	}
 
 	// 5. 保存到数据库
 	if err := l.svcCtx.PostDAO.Create(l.ctx, post); err != nil {
 		return nil, fmt.Errorf("文章创建失败: %v", err)
 	}
+	audit.SetTargetID(l.ctx, post.ID.Hex())
 
 	// 6. 获取创建后的文章（包含生成的ID）
 	createdPost, err := l.svcCtx.PostDAO.GetByID(l.ctx, post.ID.Hex())
@@ -117,13 +122,20 @@ func (l *CreatePostLogic) validateRequest(req *types.PostCreateRequest) error {
 	if !constants.IsValidPostVisibility(req.Visibility) {
 		return fmt.Errorf("无效的文章可见性")
 	}
+	if err := validateTOCMaxDepth(req.TOCMaxDepth); err != nil {
+		return err
+	}
 
 	return nil
 }
 
-// generateSlugFromTitle 从标题生成slug
-func (l *CreatePostLogic) generateSlugFromTitle(title string) string {
-	return model.GenerateSlugFromText(title)
+// resolveSlug 按站点的slug策略校验手动填写的slug，未填写时从标题生成
+func (l *CreatePostLogic) resolveSlug(input, title string) (string, error) {
+	strategy := slugStrategy(l.ctx, l.svcCtx)
+	if input != "" {
+		return input, validateSlug(strategy, input)
+	}
+	return model.GenerateSlugWith(strategy, title, constants.SlugFallbackPrefixPost), nil
 }
 
 // generateUniqueSlug 生成唯一的slug
@@ -149,21 +161,16 @@ func (l *CreatePostLogic) generateUniqueSlug(baseSlug string) (string, error) {
 }
 
 // buildPostFromRequest 从请求构建文章模型
-func (l *CreatePostLogic) buildPostFromRequest(req *types.PostCreateRequest, authorID primitive.ObjectID, slug string) *model.Post {
+func (l *CreatePostLogic) buildPostFromRequest(req *types.PostCreateRequest, authorID primitive.ObjectID, slug string) (*model.Post, error) {
+	doc, err := renderContent(l.ctx, l.svcCtx, req.Markdown, authorID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 
 	// 转换标签
-	tags := make([]model.Tag, len(req.Tags))
-	for i, tag := range req.Tags {
-		tagSlug := tag.Slug
-		if tagSlug == "" {
-			tagSlug = model.GenerateSlugFromText(tag.Name)
-		}
-		tags[i] = model.Tag{
-			Name: tag.Name,
-			Slug: tagSlug,
-		}
-	}
+	tags := buildTags(l.ctx, l.svcCtx, req.Tags)
 
 	// 处理发布时间
 	var publishedAt *time.Time
@@ -179,7 +186,10 @@ func (l *CreatePostLogic) buildPostFromRequest(req *types.PostCreateRequest, aut
 		Slug:            slug,
 		Excerpt:         req.Excerpt,
 		Markdown:        req.Markdown,
-		HTML:            l.convertMarkdownToHTML(req.Markdown),
+		HTML:            doc.HTML,
+		TOC:             postTOC(doc, req.DisableTOC, req.TOCMaxDepth),
+		DisableTOC:      req.DisableTOC,
+		TOCMaxDepth:     req.TOCMaxDepth,
 		FeaturedImage:   req.FeaturedImage,
 		Type:            req.Type,
 		Status:          req.Status,
@@ -197,32 +207,16 @@ func (l *CreatePostLogic) buildPostFromRequest(req *types.PostCreateRequest, aut
 
 	// 自动生成摘要（如果没有提供）
 	if post.Excerpt == "" {
-		post.Excerpt = post.GenerateExcerpt(200)
+		post.Excerpt = doc.Excerpt(constants.PostExcerptDefaultLength)
+		post.AutoExcerpt = true
 	}
 
-	// 计算内容指标
-	post.UpdateContentMetrics()
-
-	return post
-}
-
-// convertMarkdownToHTML 将Markdown转换为HTML（简单实现）
-func (l *CreatePostLogic) convertMarkdownToHTML(markdown string) string {
-	// 这里实现简单的Markdown到HTML转换
-	// 在实际项目中，应该使用专业的Markdown库如goldmark
-	html := strings.ReplaceAll(markdown, "\n", "<br/>")
-
-	// 处理标题
-	html = strings.ReplaceAll(html, "# ", "<h1>")
-	html = strings.ReplaceAll(html, "## ", "<h2>")
-	html = strings.ReplaceAll(html, "### ", "<h3>")
-
-	// 简单的段落处理
-	if !strings.Contains(html, "<h") {
-		html = "<p>" + html + "</p>"
-	}
+	// 按渲染后的正文计算内容指标
+	stats := doc.Stats()
+	post.WordCount = stats.WordCount()
+	post.ReadingTime = stats.ReadingTime()
 
-	return html
+	return post, nil
 }
 
 // buildPostDetailData 构建文章详情数据
@@ -270,6 +264,8 @@ func (l *CreatePostLogic) buildPostDetailData(post *model.Post, author *model.Au
 		ReadingTime:     post.ReadingTime,
 		WordCount:       post.WordCount,
 		ViewCount:       post.ViewCount,
+		DisableTOC:      post.DisableTOC,
+		TOCMaxDepth:     post.TOCMaxDepth,
 		PublishedAt:     publishedAt,
 		CreatedAt:       post.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:       post.UpdatedAt.Format(time.RFC3339),
@@ -12,8 +12,10 @@ import (
 
 	"github.com/heimdall-api/admin-api/admin/internal/svc"
 	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/dao"
 	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/render"
 )
 
 func TestCreatePostLogic_CreatePost(t *testing.T) {
@@ -21,8 +23,10 @@ func TestCreatePostLogic_CreatePost(t *testing.T) {
 		// 准备测试数据
 		ctx := context.Background()
 		svcCtx := &svc.ServiceContext{
-			PostDAO: &dao.PostDAO{},
-			UserDAO: &dao.UserDAO{},
+			PostDAO:    &dao.PostDAO{},
+			UserDAO:    &dao.UserDAO{},
+			SettingDAO: &dao.SettingDAO{},
+			Renderer:   render.NewRenderer(render.NewSanitizer(render.SanitizeConfig{})),
 		}
 		logic := NewCreatePostLogic(ctx, svcCtx)
 
@@ -34,7 +38,7 @@ func TestCreatePostLogic_CreatePost(t *testing.T) {
 			mockey.UnPatchAll()
 
 			// 设置用户上下文
-			logic.ctx = context.WithValue(logic.ctx, "userId", authorID.Hex())
+			logic.ctx = withPrincipal(logic.ctx, authorID.Hex(), constants.UserRoleAuthor)
 
 			// 准备请求数据
 			req := &types.PostCreateRequest{
@@ -88,6 +92,9 @@ func TestCreatePostLogic_CreatePost(t *testing.T) {
 				return mockUser, nil
 			}).Build()
 
+			// Mock SettingDAO.GetByKey (未设置slug策略，使用默认策略)
+			mockey.Mock((*dao.SettingDAO).GetByKey).Return(nil, nil).Build()
+
 			// Mock PostDAO.GetBySlug (检查slug重复)
 			mockey.Mock((*dao.PostDAO).GetBySlug).To(func(postDAO *dao.PostDAO, ctx context.Context, slug string) (*model.Post, error) {
 				return nil, errors.New("post not found") // 表示slug不重复
@@ -122,7 +129,7 @@ func TestCreatePostLogic_CreatePost(t *testing.T) {
 			mockey.UnPatchAll()
 
 			// 设置用户上下文
-			logic.ctx = context.WithValue(logic.ctx, "userId", authorID.Hex())
+			logic.ctx = withPrincipal(logic.ctx, authorID.Hex(), constants.UserRoleAuthor)
 
 			req := &types.PostCreateRequest{
 				Title:      "测试文章标题",
@@ -144,6 +151,12 @@ func TestCreatePostLogic_CreatePost(t *testing.T) {
 				return mockUser, nil
 			}).Build()
 
+			// Mock SettingDAO.GetByKey (站点选择了保留Unicode的slug策略)
+			mockey.Mock((*dao.SettingDAO).GetByKey).Return(&model.Setting{
+				Key:   constants.SettingKeySlugStrategy,
+				Value: constants.SlugStrategyUnicode,
+			}, nil).Build()
+
 			// Mock PostDAO.GetBySlug (检查slug重复)
 			mockey.Mock((*dao.PostDAO).GetBySlug).To(func(postDAO *dao.PostDAO, ctx context.Context, slug string) (*model.Post, error) {
 				return nil, errors.New("post not found")
@@ -151,8 +164,8 @@ func TestCreatePostLogic_CreatePost(t *testing.T) {
 
 			// Mock PostDAO.Create
 			mockey.Mock((*dao.PostDAO).Create).To(func(postDAO *dao.PostDAO, ctx context.Context, post *model.Post) error {
-				// 验证slug已经生成
-				So(post.Slug, ShouldNotBeEmpty)
+				// 验证slug已按站点策略生成
+				So(post.Slug, ShouldEqual, "测试文章标题")
 				return nil
 			}).Build()
 
@@ -180,7 +193,7 @@ func TestCreatePostLogic_CreatePost(t *testing.T) {
 			mockey.UnPatchAll()
 
 			// 设置用户上下文
-			logic.ctx = context.WithValue(logic.ctx, "userId", authorID.Hex())
+			logic.ctx = withPrincipal(logic.ctx, authorID.Hex(), constants.UserRoleAuthor)
 
 			req := &types.PostCreateRequest{
 				Title:      "重复标题",
@@ -207,6 +220,12 @@ func TestCreatePostLogic_CreatePost(t *testing.T) {
 				return mockUser, nil
 			}).Build()
 
+			// Mock SettingDAO.GetByKey (站点选择了保留Unicode的slug策略)
+			mockey.Mock((*dao.SettingDAO).GetByKey).Return(&model.Setting{
+				Key:   constants.SettingKeySlugStrategy,
+				Value: constants.SlugStrategyUnicode,
+			}, nil).Build()
+
 			// Mock PostDAO.GetBySlug (第一次返回已存在的文章，第二次返回不存在)
 			callCount := 0
 			mockey.Mock((*dao.PostDAO).GetBySlug).To(func(postDAO *dao.PostDAO, ctx context.Context, slug string) (*model.Post, error) {
@@ -296,7 +315,7 @@ func TestCreatePostLogic_CreatePost(t *testing.T) {
 
 		Convey("作者不存在", func() {
 			// 设置用户上下文
-			logic.ctx = context.WithValue(logic.ctx, "userId", authorID.Hex())
+			logic.ctx = withPrincipal(logic.ctx, authorID.Hex(), constants.UserRoleAuthor)
 
 			req := &types.PostCreateRequest{
 				Title:      "标题",
@@ -323,7 +342,7 @@ func TestCreatePostLogic_CreatePost(t *testing.T) {
 
 		Convey("数据库创建失败", func() {
 			// 设置用户上下文
-			logic.ctx = context.WithValue(logic.ctx, "userId", authorID.Hex())
+			logic.ctx = withPrincipal(logic.ctx, authorID.Hex(), constants.UserRoleAuthor)
 
 			req := &types.PostCreateRequest{
 				Title:      "标题",
@@ -347,6 +366,9 @@ func TestCreatePostLogic_CreatePost(t *testing.T) {
 				return mockUser, nil
 			}).Build()
 
+			// Mock SettingDAO.GetByKey - 未设置slug策略
+			mockey.Mock((*dao.SettingDAO).GetByKey).Return(nil, nil).Build()
+
 			// Mock PostDAO.GetBySlug - slug不存在
 			mockey.Mock((*dao.PostDAO).GetBySlug).To(func(postDAO *dao.PostDAO, ctx context.Context, slug string) (*model.Post, error) {
 				return nil, errors.New("post not found")
@@ -364,6 +386,28 @@ func TestCreatePostLogic_CreatePost(t *testing.T) {
 			So(err.Error(), ShouldContainSubstring, "文章创建失败")
 		})
 
+		Convey("手动填写的slug须符合站点的slug策略", func() {
+			logic.ctx = withPrincipal(logic.ctx, authorID.Hex(), constants.UserRoleAuthor)
+			mockey.UnPatchAll()
+
+			mockey.Mock((*dao.UserDAO).GetByID).Return(&model.User{ID: authorID, Username: "testuser"}, nil).Build()
+			// 未设置slug策略，默认拼音策略只允许ASCII字符
+			mockey.Mock((*dao.SettingDAO).GetByKey).Return(nil, nil).Build()
+
+			resp, err := logic.CreatePost(&types.PostCreateRequest{
+				Title:      "标题",
+				Slug:       "标题",
+				Markdown:   "内容",
+				Type:       "post",
+				Status:     "draft",
+				Visibility: "public",
+			})
+
+			So(err, ShouldNotBeNil)
+			So(resp, ShouldBeNil)
+			So(err.Error(), ShouldContainSubstring, "slug格式无效")
+		})
+
 		Reset(func() {
 			mockey.UnPatchAll()
 		})
@@ -0,0 +1,137 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateUserLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 创建用户
+func NewCreateUserLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateUserLogic {
+	return &CreateUserLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateUserLogic) CreateUser(req *types.UserCreateRequest) (resp *types.UserDetailResponse, err error) {
+	// 1. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 验证请求参数
+	createReq, err := l.validateRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 只能创建等级低于自己的用户，所有者可以创建任意角色
+	if !constants.CanManageRole(principal.Role, createReq.Role) {
+		return nil, errors.New("无权创建该角色的用户")
+	}
+
+	// 4. 密码策略检查
+	if err := validateNewPassword(&model.User{Username: createReq.Username, Email: createReq.Email}, createReq.Password); err != nil {
+		return nil, err
+	}
+
+	// 5. 用户名和邮箱唯一性检查
+	if err := l.checkUnique(createReq.Username, createReq.Email); err != nil {
+		return nil, err
+	}
+
+	// 6. 创建用户
+	passwordHash, err := utils.HashPassword(createReq.Password)
+	if err != nil {
+		return nil, passwordPolicyError(err)
+	}
+	user := model.NewUserFromCreateRequest(createReq, passwordHash)
+	user.PasswordHistory = []string{passwordHash}
+	if err := l.svcCtx.UserDAO.Create(l.ctx, user); err != nil {
+		l.Logger.Errorf("创建用户失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	audit.SetTargetID(l.ctx, user.ID.Hex())
+
+	l.Logger.Infof("管理员创建用户: operator=%s, userID=%s, role=%s", principal.UserID, user.ID.Hex(), user.Role)
+	return reloadUserDetail(l.ctx, l.svcCtx, user.ID.Hex(), "用户创建成功")
+}
+
+// validateRequest 验证创建用户请求，返回去除首尾空白后的创建数据
+func (l *CreateUserLogic) validateRequest(req *types.UserCreateRequest) (*model.UserCreateRequest, error) {
+	if req == nil {
+		return nil, errors.New("请求不能为空")
+	}
+
+	createReq := &model.UserCreateRequest{
+		Username:    strings.TrimSpace(req.Username),
+		Email:       strings.TrimSpace(req.Email),
+		Password:    req.Password,
+		DisplayName: strings.TrimSpace(req.DisplayName),
+		Role:        req.Role,
+		Bio:         strings.TrimSpace(req.Bio),
+		Location:    strings.TrimSpace(req.Location),
+		Website:     strings.TrimSpace(req.Website),
+		Twitter:     strings.TrimSpace(req.Twitter),
+		Facebook:    strings.TrimSpace(req.Facebook),
+	}
+
+	if err := utils.ValidateUsername(createReq.Username); err != nil {
+		return nil, errors.New("用户名必须以字母开头，只能包含字母、数字和下划线，长度3-30个字符")
+	}
+	if err := utils.ValidateEmail(createReq.Email); err != nil {
+		return nil, errors.New("邮箱格式无效")
+	}
+	if err := utils.ValidateURL(createReq.Website); err != nil {
+		return nil, errors.New("个人网站URL格式无效")
+	}
+
+	user := model.NewUserFromCreateRequest(createReq, "")
+	if err := user.ValidateForCreate(); err != nil {
+		return nil, err
+	}
+
+	return createReq, nil
+}
+
+// checkUnique 检查用户名和邮箱是否已被使用
+func (l *CreateUserLogic) checkUnique(username, email string) error {
+	existing, err := l.svcCtx.UserDAO.GetByUsername(l.ctx, username)
+	if err != nil {
+		l.Logger.Errorf("查询用户名失败: %v", err)
+		return errors.New("系统错误，请稍后重试")
+	}
+	if existing != nil {
+		return errors.New("用户名已存在")
+	}
+
+	existing, err = l.svcCtx.UserDAO.GetByEmail(l.ctx, email)
+	if err != nil {
+		l.Logger.Errorf("查询邮箱失败: %v", err)
+		return errors.New("系统错误，请稍后重试")
+	}
+	if existing != nil {
+		return errors.New("邮箱已存在")
+	}
+
+	return nil
+}
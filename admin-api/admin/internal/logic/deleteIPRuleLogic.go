@@ -0,0 +1,120 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteIPRuleLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 删除IP封禁或白名单
+func NewDeleteIPRuleLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteIPRuleLogic {
+	return &DeleteIPRuleLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteIPRuleLogic) DeleteIPRule(req *types.IPRuleIDRequest) (resp *types.IPRuleResponse, err error) {
+	if req == nil {
+		return nil, errors.New("请求不能为空")
+	}
+
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 临时封禁以IP作为ID
+	id := strings.TrimSpace(req.ID)
+	if ip := net.ParseIP(id); ip != nil {
+		return l.unblock(principal, ip)
+	}
+
+	// 2. 永久规则以数据库ID删除
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("规则ID格式无效")
+	}
+	rule, err := l.svcCtx.IPRuleDAO.GetByID(l.ctx, objectID)
+	if err != nil {
+		l.Logger.Errorf("获取IP规则失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if rule == nil {
+		return nil, errors.New("规则不存在")
+	}
+
+	// 3. 白名单模式下不能删除放行当前IP的最后一条白名单
+	if rule.Type == constants.IPRuleTypeAllow && l.svcCtx.IPAccessControl.AllowlistMode() {
+		allowRules, err := loadAllowRules(l.ctx, l.svcCtx)
+		if err != nil {
+			return nil, err
+		}
+		remaining := make([]*model.IPRule, 0, len(allowRules))
+		for _, allowRule := range allowRules {
+			if allowRule.ID != rule.ID {
+				remaining = append(remaining, allowRule)
+			}
+		}
+		if allowlistExcludes(remaining, operatorIP(l.ctx)) {
+			return nil, errIPRuleLockout
+		}
+	}
+
+	// 4. 删除规则并刷新本地缓存
+	deleted, err := l.svcCtx.IPRuleDAO.Delete(l.ctx, objectID)
+	if err != nil {
+		l.Logger.Errorf("删除IP规则失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if !deleted {
+		return nil, errors.New("规则不存在")
+	}
+	l.svcCtx.IPAccessControl.Invalidate()
+
+	l.Logger.Infof("已删除IP规则: operator=%s, type=%s, cidr=%s", principal.UserID, rule.Type, rule.CIDR)
+	return newIPRuleResponse("删除成功", rule), nil
+}
+
+// unblock 解除IP的临时封禁
+func (l *DeleteIPRuleLogic) unblock(principal *auth.Principal, ip net.IP) (*types.IPRuleResponse, error) {
+	unblocked, err := l.svcCtx.IPAccessControl.Unblock(l.ctx, ip.String())
+	if err != nil {
+		l.Logger.Errorf("解除IP临时封禁失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if !unblocked {
+		return nil, errors.New("规则不存在")
+	}
+
+	// 临时封禁已从Redis删除，以当前时间作为到期时间返回
+	ipNet, _ := utils.ParseIPNet(ip.String())
+	now := time.Now()
+	l.Logger.Infof("已解除IP临时封禁: operator=%s, ip=%s", principal.UserID, ip.String())
+	return newIPRuleResponse("解除封禁成功", &model.IPRule{
+		Type:      constants.IPRuleTypeBlock,
+		CIDR:      ipNet.String(),
+		ExpiresAt: &now,
+		CreatedAt: now,
+	}), nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/heimdall-api/admin-api/admin/internal/svc"
 	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
 	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
 
@@ -81,12 +82,12 @@ func (l *DeletePageLogic) validatePageID(id string) error {
 
 // getCurrentUserID 从context中获取当前用户ID
 func (l *DeletePageLogic) getCurrentUserID() (string, error) {
-	userID, ok := l.ctx.Value("userId").(string)
-	if !ok || userID == "" {
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
 		return "", fmt.Errorf("用户认证失败")
 	}
 
-	return userID, nil
+	return principal.UserID, nil
 }
 
 // getPageByID 根据ID获取页面信息
@@ -124,8 +125,8 @@ func (l *DeletePageLogic) checkPermission(userID, authorID string) error {
 		return fmt.Errorf("用户不存在")
 	}
 
-	// 检查是否为页面作者
-	if userID != authorID {
+	// 编辑及以上角色可删除所有页面，作者只能删除自己的页面
+	if !hasResourcePermission(l.ctx, constants.PermissionPageDelete, authorID) {
 		return fmt.Errorf("无权限删除此页面")
 	}
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/heimdall-api/admin-api/admin/internal/svc"
 	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
 	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
 
@@ -81,12 +82,12 @@ func (l *DeletePostLogic) validatePostID(id string) error {
 
 // getCurrentUserID 从context中获取当前用户ID
 func (l *DeletePostLogic) getCurrentUserID() (string, error) {
-	userID, ok := l.ctx.Value("uid").(string)
-	if !ok || userID == "" {
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
 		return "", fmt.Errorf("用户认证失败")
 	}
 
-	return userID, nil
+	return principal.UserID, nil
 }
 
 // getPostByID 根据ID获取文章信息
@@ -124,8 +125,8 @@ func (l *DeletePostLogic) checkPermission(userID, authorID string) error {
 		return fmt.Errorf("用户不存在")
 	}
 
-	// 检查是否为文章作者
-	if userID != authorID {
+	// 编辑及以上角色可删除所有文章，作者只能删除自己的文章
+	if !hasResourcePermission(l.ctx, constants.PermissionPostDelete, authorID) {
 		return fmt.Errorf("无权限删除此文章")
 	}
 
@@ -36,7 +36,7 @@ func TestDeletePostLogic_DeletePost(t *testing.T) {
 			userID := authorID // 设置为同一个用户
 
 			// 设置用户ID到context（模拟JWT中间件）
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			// 准备现有文章
@@ -102,7 +102,7 @@ func TestDeletePostLogic_DeletePost(t *testing.T) {
 			mockey.UnPatchAll()
 
 			userID := primitive.NewObjectID()
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			// 准备请求
@@ -125,7 +125,7 @@ func TestDeletePostLogic_DeletePost(t *testing.T) {
 
 			postID := primitive.NewObjectID()
 			userID := primitive.NewObjectID()
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			// Mock PostDAO.GetByID - 返回文章不存在
@@ -155,7 +155,7 @@ func TestDeletePostLogic_DeletePost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := primitive.NewObjectID() // 不同的用户ID
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			existingPost := &model.Post{
@@ -188,6 +188,54 @@ func TestDeletePostLogic_DeletePost(t *testing.T) {
 			So(err.Error(), ShouldContainSubstring, "无权限删除此文章")
 		})
 
+		Convey("编辑可以删除其他作者的文章", func() {
+			// 重置mock
+			mockey.UnPatchAll()
+
+			postID := primitive.NewObjectID()
+			authorID := primitive.NewObjectID()
+			userID := primitive.NewObjectID() // 不同的用户ID
+
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleEditor)
+			logic.ctx = ctxWithUser
+
+			existingPost := &model.Post{
+				ID:       postID,
+				AuthorID: authorID,
+				Status:   constants.PostStatusPublished,
+			}
+
+			// Mock PostDAO.GetByID
+			mockey.Mock((*dao.PostDAO).GetByID).To(func(postDAO *dao.PostDAO, ctx context.Context, id string) (*model.Post, error) {
+				return existingPost, nil
+			}).Build()
+
+			// Mock UserDAO.GetByID
+			mockey.Mock((*dao.UserDAO).GetByID).To(func(userDAO *dao.UserDAO, ctx context.Context, id string) (*model.User, error) {
+				return &model.User{ID: userID, Role: constants.UserRoleEditor}, nil
+			}).Build()
+
+			// Mock PostDAO.Delete
+			deleted := false
+			mockey.Mock((*dao.PostDAO).Delete).To(func(postDAO *dao.PostDAO, ctx context.Context, id string) error {
+				deleted = true
+				return nil
+			}).Build()
+
+			// 准备请求
+			req := &types.PostDeleteRequest{
+				ID: postID.Hex(),
+			}
+
+			// 执行测试
+			resp, err := logic.DeletePost(req)
+
+			// 验证结果
+			So(err, ShouldBeNil)
+			So(resp, ShouldNotBeNil)
+			So(deleted, ShouldBeTrue)
+		})
+
 		Convey("处理用户认证失败", func() {
 			// 重置mock
 			mockey.UnPatchAll()
@@ -219,7 +267,7 @@ func TestDeletePostLogic_DeletePost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := authorID
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			existingPost := &model.Post{
@@ -260,7 +308,7 @@ func TestDeletePostLogic_DeletePost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := authorID
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			existingPost := &model.Post{
@@ -306,7 +354,7 @@ func TestDeletePostLogic_DeletePost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := authorID
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			// 准备已删除的文章（状态为archived）
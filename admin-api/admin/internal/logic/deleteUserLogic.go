@@ -0,0 +1,146 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 删除用户时对其文章的处理方式
+const (
+	userPostActionReassign = "reassign" // 转移给接收用户，保留文章状态
+	userPostActionArchive  = "archive"  // 转移给接收用户并归档
+)
+
+type DeleteUserLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 删除用户
+func NewDeleteUserLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteUserLogic {
+	return &DeleteUserLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteUserLogic) DeleteUser(req *types.UserDeleteRequest) (resp *types.UserDeleteResponse, err error) {
+	if req == nil {
+		return nil, errors.New("请求不能为空")
+	}
+
+	// 1. 获取目标用户并检查角色层级
+	principal, user, err := loadManagedUser(l.ctx, l.svcCtx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID.Hex() == principal.UserID {
+		return nil, ErrCannotDeleteSelf
+	}
+
+	// 2. 验证文章处理方式和接收用户
+	archive, err := l.parsePostAction(req.PostAction)
+	if err != nil {
+		return nil, err
+	}
+	receiver, err := l.getReceiver(req.ReassignTo, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 转移文章和页面并删除用户（软删除），删除所有者时在同一事务中确保仍有其他所有者
+	postStatus := ""
+	if archive {
+		postStatus = constants.PostStatusArchived
+	}
+	var postCount, pageCount int64
+	err = keepOwnerWhile(l.ctx, l.svcCtx, user, func(ctx context.Context) error {
+		var err error
+		if postCount, err = l.svcCtx.PostDAO.ReassignAuthor(ctx, req.ID, receiver.ID.Hex(), postStatus); err != nil {
+			return fmt.Errorf("转移用户文章失败: %w", err)
+		}
+		if pageCount, err = l.svcCtx.PageDAO.ReassignAuthor(ctx, req.ID, receiver.ID.Hex()); err != nil {
+			return fmt.Errorf("转移用户页面失败: %w", err)
+		}
+		return l.svcCtx.UserDAO.Delete(ctx, req.ID)
+	})
+	if errors.Is(err, ErrLastOwner) {
+		return nil, err
+	}
+	if err != nil {
+		l.Logger.Errorf("删除用户失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	// 4. 吊销全部会话
+	if err := signOutUser(l.ctx, l.svcCtx, req.ID); err != nil {
+		l.Logger.Errorf("吊销用户会话失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	l.Logger.Infof("管理员删除用户: operator=%s, userID=%s, reassignTo=%s, posts=%d, pages=%d, archived=%t",
+		principal.UserID, req.ID, receiver.ID.Hex(), postCount, pageCount, archive)
+	return &types.UserDeleteResponse{
+		Code:      200,
+		Message:   "用户删除成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.UserDeleteData{
+			ReassignedTo: receiver.ID.Hex(),
+			PostCount:    postCount,
+			PageCount:    pageCount,
+			Archived:     archive,
+		},
+	}, nil
+}
+
+// parsePostAction 解析文章处理方式，返回是否归档
+func (l *DeleteUserLogic) parsePostAction(action string) (bool, error) {
+	switch action {
+	case "", userPostActionReassign:
+		return false, nil
+	case userPostActionArchive:
+		return true, nil
+	default:
+		return false, errors.New("无效的文章处理方式")
+	}
+}
+
+// getReceiver 获取接收文章和页面的用户，必须是被删除用户以外的正常状态用户
+func (l *DeleteUserLogic) getReceiver(id string, user *model.User) (*model.User, error) {
+	if id == "" {
+		return nil, errors.New("请指定接收文章的用户")
+	}
+	if !primitive.IsValidObjectID(id) {
+		return nil, errors.New("接收用户ID格式无效")
+	}
+	if id == user.ID.Hex() {
+		return nil, errors.New("接收用户不能是被删除的用户")
+	}
+
+	receiver, err := l.svcCtx.UserDAO.GetByID(l.ctx, id)
+	if err != nil {
+		l.Logger.Errorf("获取接收用户失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if receiver == nil {
+		return nil, errors.New("接收用户不存在")
+	}
+	if !receiver.IsActive() {
+		return nil, errors.New("接收用户不是正常状态")
+	}
+
+	return receiver, nil
+}
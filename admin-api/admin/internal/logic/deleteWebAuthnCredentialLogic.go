@@ -0,0 +1,68 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteWebAuthnCredentialLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 删除当前用户的通行密钥
+func NewDeleteWebAuthnCredentialLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteWebAuthnCredentialLogic {
+	return &DeleteWebAuthnCredentialLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteWebAuthnCredentialLogic) DeleteWebAuthnCredential(req *types.WebAuthnCredentialDeleteRequest) (resp *types.WebAuthnCredentialDeleteResponse, err error) {
+	// 1. 参数验证
+	if req == nil || req.ID == "" {
+		return nil, errors.New("通行密钥ID不能为空")
+	}
+	credentialID, err := primitive.ObjectIDFromHex(req.ID)
+	if err != nil {
+		return nil, errors.New("无效的通行密钥ID")
+	}
+
+	// 2. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := primitive.ObjectIDFromHex(principal.UserID)
+	if err != nil {
+		return nil, errors.New("无效的用户ID")
+	}
+
+	// 3. 只能删除自己的凭据
+	deleted, err := l.svcCtx.WebAuthnCredentialDAO.Delete(l.ctx, credentialID, userID)
+	if err != nil {
+		l.Logger.Errorf("删除通行密钥失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if !deleted {
+		return nil, errors.New("通行密钥不存在")
+	}
+
+	l.Logger.Infof("用户删除通行密钥: userID=%s, credentialID=%s", principal.UserID, req.ID)
+	return &types.WebAuthnCredentialDeleteResponse{
+		Code:      200,
+		Message:   "通行密钥已删除",
+		Timestamp: time.Now().Format(time.RFC3339),
+	}, nil
+}
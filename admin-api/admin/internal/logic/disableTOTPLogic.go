@@ -0,0 +1,101 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DisableTOTPLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 关闭TOTP两步验证
+func NewDisableTOTPLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DisableTOTPLogic {
+	return &DisableTOTPLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DisableTOTPLogic) DisableTOTP(req *types.TOTPDisableRequest) (resp *types.TOTPDisableResponse, err error) {
+	// 1. 参数验证
+	if req == nil || req.Password == "" {
+		return nil, errors.New("密码不能为空")
+	}
+	if req.Code == "" {
+		return nil, errors.New("验证码不能为空")
+	}
+
+	// 2. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 获取用户信息
+	user, err := l.svcCtx.UserDAO.GetByID(l.ctx, principal.UserID)
+	if err != nil {
+		l.Logger.Errorf("获取用户信息失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if user == nil {
+		return nil, errors.New("用户不存在")
+	}
+	if !user.TwoFactorEnabled {
+		return nil, errors.New("未启用两步验证")
+	}
+
+	// 4. 角色要求启用两步验证时不允许关闭
+	required, err := mfaRequiredForRole(l.ctx, l.svcCtx, user.Role)
+	if err != nil {
+		l.Logger.Errorf("查询两步验证策略失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if required {
+		return nil, errors.New("当前角色要求启用两步验证，不能关闭")
+	}
+
+	// 5. 校验密码和验证码
+	if err := utils.VerifyPassword(req.Password, user.PasswordHash); err != nil {
+		return nil, errors.New("密码错误")
+	}
+	_, ok, err := verifySecondFactor(l.ctx, l.svcCtx, user, req.Code)
+	if err != nil {
+		l.Logger.Errorf("校验两步验证码失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if !ok {
+		return nil, errors.New("验证码错误")
+	}
+
+	// 6. 清除密钥和恢复码
+	updates := map[string]interface{}{
+		"twoFactorEnabled":   false,
+		"twoFactorSecret":    "",
+		"twoFactorLastStep":  int64(0),
+		"twoFactorEnabledAt": nil,
+		"recoveryCodes":      []string{},
+	}
+	if err := l.svcCtx.UserDAO.Update(l.ctx, principal.UserID, updates); err != nil {
+		l.Logger.Errorf("关闭两步验证失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	l.Logger.Infof("用户关闭两步验证: userID=%s", principal.UserID)
+	return &types.TOTPDisableResponse{
+		Code:      200,
+		Message:   "两步验证已关闭",
+		Timestamp: time.Now().Format(time.RFC3339),
+	}, nil
+}
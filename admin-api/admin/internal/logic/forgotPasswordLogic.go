@@ -0,0 +1,136 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/threading"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/client/mailer"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+// passwordResetMailTimeout 发送重置邮件的超时时间
+const passwordResetMailTimeout = 30 * time.Second
+
+// forgotPasswordMessage 忘记密码的统一响应，避免泄露邮箱是否已注册
+const forgotPasswordMessage = "如果该邮箱已注册，我们已发送重置密码邮件，请查收"
+
+type ForgotPasswordLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 申请重置密码
+func NewForgotPasswordLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ForgotPasswordLogic {
+	return &ForgotPasswordLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ForgotPasswordLogic) ForgotPassword(req *types.ForgotPasswordRequest) (resp *types.ForgotPasswordResponse, err error) {
+	// 1. 参数验证
+	if req == nil || strings.TrimSpace(req.Email) == "" {
+		return nil, errors.New("邮箱不能为空")
+	}
+	email := strings.TrimSpace(req.Email)
+	if err := utils.ValidateEmail(email); err != nil {
+		return nil, errors.New("邮箱格式无效")
+	}
+
+	// 之后的任何分支都返回相同的响应
+	resp = &types.ForgotPasswordResponse{
+		Code:      200,
+		Message:   forgotPasswordMessage,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	// 2. 按邮箱和IP限流，计数与邮箱是否存在无关
+	clientIP := utils.ClientInfoFromContext(l.ctx).IP
+	allowed, err := allowPasswordResetRequest(l.ctx, l.svcCtx.Redis, email, clientIP)
+	if err != nil {
+		l.Logger.Errorf("密码重置限流检查失败: %v", err)
+		return resp, nil
+	}
+	if !allowed {
+		l.Logger.Infof("密码重置请求过于频繁: email=%s, ip=%s", email, clientIP)
+		return resp, nil
+	}
+
+	// 3. 查找用户，不存在或不可用时静默返回
+	user, err := l.svcCtx.UserDAO.GetByEmail(l.ctx, email)
+	if err != nil || user == nil {
+		if err != nil {
+			l.Logger.Errorf("查询用户失败: %v", err)
+		}
+		return resp, nil
+	}
+	if !user.CanResetPassword() {
+		l.Logger.Infof("非活跃用户申请重置密码: userID=%s, status=%s", user.ID.Hex(), user.Status)
+		return resp, nil
+	}
+
+	// 4. 签发一次性令牌
+	ttl := time.Duration(l.svcCtx.Config.Security.PasswordResetTTL) * time.Second
+	token, err := issuePasswordResetToken(l.ctx, l.svcCtx.Redis, user.ID.Hex(), ttl)
+	if err != nil {
+		l.Logger.Errorf("签发重置令牌失败: %v", err)
+		return resp, nil
+	}
+
+	// 5. 异步发送邮件，避免响应时间暴露邮箱是否存在
+	msg, err := l.buildResetMessage(user, token, ttl)
+	if err != nil {
+		l.Logger.Errorf("构建重置邮件失败: %v", err)
+		return resp, nil
+	}
+	mailCtx := context.WithoutCancel(l.ctx)
+	threading.GoSafe(func() {
+		ctx, cancel := context.WithTimeout(mailCtx, passwordResetMailTimeout)
+		defer cancel()
+		if err := l.svcCtx.Mailer.Send(ctx, msg); err != nil {
+			logx.WithContext(ctx).Errorf("发送重置密码邮件失败: userID=%s, error=%v", user.ID.Hex(), err)
+		}
+	})
+
+	l.Logger.Infof("已签发密码重置令牌: userID=%s, ip=%s", user.ID.Hex(), clientIP)
+	return resp, nil
+}
+
+// buildResetMessage 构建重置密码邮件
+func (l *ForgotPasswordLogic) buildResetMessage(user *model.User, token string, ttl time.Duration) (*mailer.Message, error) {
+	link, err := url.Parse(l.svcCtx.Config.Security.PasswordResetURL)
+	if err != nil {
+		return nil, fmt.Errorf("重置密码页面地址无效: %w", err)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	name := user.DisplayName
+	if name == "" {
+		name = user.Username
+	}
+	minutes := int(ttl.Minutes())
+
+	return &mailer.Message{
+		To:      []string{user.Email},
+		Subject: "重置您的密码",
+		Text: fmt.Sprintf("%s，您好：\n\n我们收到了重置您账号密码的请求。请在%d分钟内打开以下链接设置新密码：\n\n%s\n\n如果这不是您本人的操作，请忽略此邮件，您的密码不会被修改。\n",
+			name, minutes, link.String()),
+		HTML: fmt.Sprintf(`<p>%s，您好：</p><p>我们收到了重置您账号密码的请求。请在%d分钟内点击下面的链接设置新密码：</p><p><a href="%s">重置密码</a></p><p>如果这不是您本人的操作，请忽略此邮件，您的密码不会被修改。</p>`,
+			html.EscapeString(name), minutes, html.EscapeString(link.String())),
+	}, nil
+}
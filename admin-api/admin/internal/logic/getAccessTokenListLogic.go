@@ -0,0 +1,64 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetAccessTokenListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取当前用户的个人访问令牌列表
+func NewGetAccessTokenListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetAccessTokenListLogic {
+	return &GetAccessTokenListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetAccessTokenListLogic) GetAccessTokenList() (resp *types.AccessTokenListResponse, err error) {
+	// 1. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := primitive.ObjectIDFromHex(principal.UserID)
+	if err != nil {
+		return nil, errors.New("无效的用户ID")
+	}
+
+	// 2. 查询未吊销的令牌，已过期的令牌一并返回便于用户清理
+	tokens, err := l.svcCtx.AccessTokenDAO.ListByUserID(l.ctx, userID)
+	if err != nil {
+		l.Logger.Errorf("查询个人访问令牌失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	list := make([]types.AccessTokenInfo, 0, len(tokens))
+	for _, token := range tokens {
+		list = append(list, toAccessTokenInfo(token))
+	}
+
+	return &types.AccessTokenListResponse{
+		Code:      200,
+		Message:   "获取成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.AccessTokenListData{
+			List:            list,
+			AvailableScopes: availableAccessTokenScopes(principal.Role),
+		},
+	}, nil
+}
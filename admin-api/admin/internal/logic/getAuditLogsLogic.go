@@ -0,0 +1,122 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type GetAuditLogsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取操作审计日志列表
+func NewGetAuditLogsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetAuditLogsLogic {
+	return &GetAuditLogsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetAuditLogsLogic) GetAuditLogs(req *types.AuditLogsRequest) (resp *types.AuditLogsResponse, err error) {
+	if req == nil {
+		return nil, errors.New("请求参数不能为空")
+	}
+
+	// 1. 参数验证，复用登录日志的分页和时间校验
+	logsReq := &types.LoginLogsRequest{
+		Page:      req.Page,
+		Limit:     req.Limit,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+	}
+	logsLogic := NewGetLoginLogsLogic(l.ctx, l.svcCtx)
+	if err := logsLogic.validateRequest(logsReq); err != nil {
+		return nil, err
+	}
+	if req.ActorID != "" && !primitive.IsValidObjectID(req.ActorID) {
+		return nil, errors.New("操作者ID格式无效")
+	}
+	if req.TargetType != "" && !constants.IsValidAuditTarget(req.TargetType) {
+		return nil, errors.New("操作对象类型无效")
+	}
+
+	// 2. 构建查询过滤条件
+	filter, err := logsLogic.buildFilter(logsReq)
+	if err != nil {
+		return nil, err
+	}
+	filter = map[string]interface{}{
+		"actorId":    req.ActorID,
+		"action":     req.Action,
+		"targetType": req.TargetType,
+		"targetId":   req.TargetID,
+		"startTime":  filter["startTime"],
+		"endTime":    filter["endTime"],
+	}
+
+	// 3. 查询审计日志
+	logs, total, err := l.svcCtx.AuditLogDAO.List(l.ctx, filter, logsReq.Page, logsReq.Limit)
+	if err != nil {
+		l.Logger.Errorf("查询审计日志列表失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	list := make([]types.AuditLogInfo, 0, len(logs))
+	for _, log := range logs {
+		list = append(list, toAuditLogInfo(log))
+	}
+
+	return &types.AuditLogsResponse{
+		Code:      200,
+		Message:   "获取审计日志列表成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.AuditLogsData{
+			List:       list,
+			Pagination: logsLogic.buildPagination(logsReq.Page, logsReq.Limit, total),
+		},
+	}, nil
+}
+
+// toAuditLogInfo 转换审计日志为响应格式
+func toAuditLogInfo(log *model.AuditLog) types.AuditLogInfo {
+	changes := make([]types.AuditChangeInfo, 0, len(log.Changes))
+	for _, change := range log.Changes {
+		changes = append(changes, types.AuditChangeInfo{
+			Field:  change.Field,
+			Before: audit.Simplify(change.Before),
+			After:  audit.Simplify(change.After),
+		})
+	}
+
+	return types.AuditLogInfo{
+		ID:            log.ID.Hex(),
+		ActorID:       log.ActorID.Hex(),
+		ActorName:     log.ActorName,
+		ActorRole:     log.ActorRole,
+		AccessTokenID: log.AccessTokenID,
+		Action:        log.Action,
+		TargetType:    log.TargetType,
+		TargetID:      log.TargetID,
+		Changes:       changes,
+		Method:        log.Method,
+		Path:          log.Path,
+		StatusCode:    log.StatusCode,
+		Success:       log.IsSuccess(),
+		IPAddress:     log.IPAddress,
+		UserAgent:     log.UserAgent,
+		CreatedAt:     log.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,88 @@
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+)
+
+func TestGetAuditLogsLogic_GetAuditLogs(t *testing.T) {
+	mockey.PatchConvey("GetAuditLogs Logic Tests", t, func() {
+		svcCtx := &svc.ServiceContext{AuditLogDAO: &dao.AuditLogDAO{}}
+		actorID := primitive.NewObjectID()
+		createdAt := time.Date(2024, 5, 2, 8, 30, 0, 0, time.UTC)
+
+		// 过滤条件可能分配在调用方的栈上，只在调用期间检查
+		var checkFilter func(filter map[string]interface{})
+		mockey.Mock((*dao.AuditLogDAO).List).To(func(_ *dao.AuditLogDAO, _ context.Context, filter map[string]interface{}, page, limit int) ([]*model.AuditLog, int64, error) {
+			if checkFilter != nil {
+				checkFilter(filter)
+			}
+			return []*model.AuditLog{{
+				ID:         primitive.NewObjectID(),
+				ActorID:    actorID,
+				ActorName:  "admin",
+				ActorRole:  constants.UserRoleAdmin,
+				Action:     "user.role.change",
+				TargetType: constants.AuditTargetUser,
+				TargetID:   "64b7f0c2a1b2c3d4e5f60719",
+				Changes: []model.AuditChange{
+					// 从数据库读出的嵌套值为BSON类型
+					{Field: "metaData", Before: bson.D{{Key: "title", Value: "Old"}}, After: bson.A{"a", "b"}},
+				},
+				Method:     "PATCH",
+				Path:       "/api/v1/admin/users/64b7f0c2a1b2c3d4e5f60719/role",
+				StatusCode: 200,
+				CreatedAt:  createdAt,
+			}}, 1, nil
+		}).Build()
+
+		Convey("Should pass filters and convert logs", func() {
+			checkFilter = func(filter map[string]interface{}) {
+				So(filter["actorId"], ShouldEqual, actorID.Hex())
+				So(filter["action"], ShouldEqual, "user")
+				So(filter["targetType"], ShouldEqual, constants.AuditTargetUser)
+				So(filter["startTime"], ShouldEqual, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+				So(filter["endTime"], ShouldBeNil)
+			}
+			resp, err := NewGetAuditLogsLogic(context.Background(), svcCtx).GetAuditLogs(&types.AuditLogsRequest{
+				Page:       1,
+				Limit:      20,
+				ActorID:    actorID.Hex(),
+				Action:     "user",
+				TargetType: constants.AuditTargetUser,
+				StartTime:  "2024-05-01T00:00:00Z",
+			})
+			So(err, ShouldBeNil)
+			So(resp.Data.Pagination.Total, ShouldEqual, 1)
+			info := resp.Data.List[0]
+			So(info.ActorID, ShouldEqual, actorID.Hex())
+			So(info.Action, ShouldEqual, "user.role.change")
+			So(info.Success, ShouldBeTrue)
+			So(info.CreatedAt, ShouldEqual, "2024-05-02T08:30:00Z")
+			So(info.Changes[0].Before, ShouldResemble, map[string]interface{}{"title": "Old"})
+			So(info.Changes[0].After, ShouldResemble, []interface{}{"a", "b"})
+		})
+
+		Convey("Should reject invalid filters", func() {
+			logic := NewGetAuditLogsLogic(context.Background(), svcCtx)
+			_, err := logic.GetAuditLogs(&types.AuditLogsRequest{Page: 1, Limit: 20, ActorID: "invalid"})
+			So(err, ShouldNotBeNil)
+			_, err = logic.GetAuditLogs(&types.AuditLogsRequest{Page: 1, Limit: 20, TargetType: "comment"})
+			So(err, ShouldNotBeNil)
+			_, err = logic.GetAuditLogs(&types.AuditLogsRequest{Page: 1, Limit: 20, StartTime: "yesterday"})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
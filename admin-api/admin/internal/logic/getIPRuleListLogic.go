@@ -0,0 +1,75 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetIPRuleListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取IP封禁和白名单列表
+func NewGetIPRuleListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetIPRuleListLogic {
+	return &GetIPRuleListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetIPRuleListLogic) GetIPRuleList(req *types.IPRuleListRequest) (resp *types.IPRuleListResponse, err error) {
+	if req == nil {
+		req = &types.IPRuleListRequest{}
+	}
+	if req.Type != "" && !constants.IsValidIPRuleType(req.Type) {
+		return nil, errors.New("规则类型无效")
+	}
+
+	// 1. 获取数据库中的永久规则
+	rules, err := l.svcCtx.IPRuleDAO.List(l.ctx, req.Type)
+	if err != nil {
+		l.Logger.Errorf("获取IP规则列表失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	// 2. 合并Redis中的临时封禁
+	if req.Type != constants.IPRuleTypeAllow {
+		temporary, err := l.svcCtx.IPAccessControl.TemporaryBlocks(l.ctx)
+		if err != nil {
+			l.Logger.Errorf("获取IP临时封禁列表失败: %v", err)
+			return nil, errors.New("系统错误，请稍后重试")
+		}
+		rules = append(rules, temporary...)
+	}
+
+	// 3. 按创建时间倒序排列
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].CreatedAt.After(rules[j].CreatedAt)
+	})
+
+	list := make([]types.IPRuleInfo, 0, len(rules))
+	for _, rule := range rules {
+		list = append(list, toIPRuleInfo(rule))
+	}
+
+	return &types.IPRuleListResponse{
+		Code:      200,
+		Message:   "获取成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.IPRuleListData{
+			List:          list,
+			AllowlistMode: l.svcCtx.IPAccessControl.AllowlistMode(),
+		},
+	}, nil
+}
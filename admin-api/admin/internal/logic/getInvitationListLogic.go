@@ -0,0 +1,49 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetInvitationListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取待接受的邀请列表
+func NewGetInvitationListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetInvitationListLogic {
+	return &GetInvitationListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetInvitationListLogic) GetInvitationList() (resp *types.InvitationListResponse, err error) {
+	invitations, err := l.svcCtx.InvitationDAO.ListPending(l.ctx)
+	if err != nil {
+		l.Logger.Errorf("获取邀请列表失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	list := make([]types.InvitationInfo, 0, len(invitations))
+	for _, invitation := range invitations {
+		list = append(list, toInvitationInfo(invitation))
+	}
+
+	return &types.InvitationListResponse{
+		Code:      200,
+		Message:   "获取邀请列表成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.InvitationListData{
+			List: list,
+		},
+	}, nil
+}
@@ -0,0 +1,204 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// loginStatsGranularity 时间序列粒度的步长、默认统计范围、最大统计范围和分组键格式
+type loginStatsGranularity struct {
+	step         time.Duration
+	defaultRange time.Duration
+	maxRange     time.Duration
+	layout       string
+}
+
+// loginStatsGranularities 按小时最多统计7天，按天最多统计一年，避免时间序列过长
+var loginStatsGranularities = map[string]loginStatsGranularity{
+	constants.LoginStatsGroupByDay: {
+		step:         24 * time.Hour,
+		defaultRange: 7 * 24 * time.Hour,
+		maxRange:     366 * 24 * time.Hour,
+		layout:       "2006-01-02",
+	},
+	constants.LoginStatsGroupByHour: {
+		step:         time.Hour,
+		defaultRange: 24 * time.Hour,
+		maxRange:     7 * 24 * time.Hour,
+		layout:       "2006-01-02T15",
+	},
+}
+
+type GetLoginStatsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取登录统计
+func NewGetLoginStatsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetLoginStatsLogic {
+	return &GetLoginStatsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetLoginStatsLogic) GetLoginStats(req *types.LoginStatsRequest) (resp *types.LoginStatsResponse, err error) {
+	if req == nil {
+		return nil, errors.New("请求参数不能为空")
+	}
+
+	// 1. 参数验证
+	if req.GroupBy == "" {
+		req.GroupBy = constants.LoginStatsGroupByDay
+	}
+	granularity, ok := loginStatsGranularities[req.GroupBy]
+	if !ok {
+		return nil, errors.New("统计粒度无效")
+	}
+	if req.Limit < 1 || req.Limit > 50 {
+		req.Limit = 10
+	}
+	from, to, err := l.parseRange(req, granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 汇总统计
+	stats, err := l.svcCtx.LoginLogDAO.GetStatistics(l.ctx, from, to)
+	if err != nil {
+		return nil, l.queryError("汇总统计", err)
+	}
+
+	// 3. 时间序列
+	buckets, err := l.svcCtx.LoginLogDAO.GetTimeSeries(l.ctx, from, to, req.GroupBy)
+	if err != nil {
+		return nil, l.queryError("时间序列", err)
+	}
+
+	// 4. 失败次数最多的IP和用户名
+	topIPs, err := l.svcCtx.LoginLogDAO.GetTopFailedIPs(l.ctx, from, to, req.Limit)
+	if err != nil {
+		return nil, l.queryError("失败IP排行", err)
+	}
+	topUsernames, err := l.svcCtx.LoginLogDAO.GetTopFailedUsernames(l.ctx, from, to, req.Limit)
+	if err != nil {
+		return nil, l.queryError("失败用户名排行", err)
+	}
+
+	// 5. 按国家和浏览器分布
+	countries, err := l.svcCtx.LoginLogDAO.GetBreakdown(l.ctx, from, to, "country", req.Limit)
+	if err != nil {
+		return nil, l.queryError("国家分布", err)
+	}
+	browsers, err := l.svcCtx.LoginLogDAO.GetBreakdown(l.ctx, from, to, "browser", req.Limit)
+	if err != nil {
+		return nil, l.queryError("浏览器分布", err)
+	}
+
+	return &types.LoginStatsResponse{
+		Code:      200,
+		Message:   "获取登录统计成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.LoginStatsData{
+			From:    from.Format(time.RFC3339),
+			To:      to.Format(time.RFC3339),
+			GroupBy: req.GroupBy,
+			Summary: types.LoginStatsSummary{
+				TotalLogins:   stats.TotalLogins,
+				SuccessLogins: stats.SuccessLogins,
+				FailedLogins:  stats.FailedLogins,
+				UniqueUsers:   stats.UniqueUsers,
+				UniqueIPs:     stats.UniqueIPs,
+				SuccessRate:   stats.SuccessRate,
+			},
+			Series:             buildLoginStatsSeries(from, to, granularity, buckets),
+			TopFailedIPs:       toLoginStatsCounts(topIPs),
+			TopFailedUsernames: toLoginStatsCounts(topUsernames),
+			Countries:          toLoginStatsCounts(countries),
+			Browsers:           toLoginStatsCounts(browsers),
+		},
+	}, nil
+}
+
+// parseRange 解析统计时间范围，缺省时以当前时间为结束时间并取粒度的默认范围
+func (l *GetLoginStatsLogic) parseRange(req *types.LoginStatsRequest, granularity loginStatsGranularity) (time.Time, time.Time, error) {
+	to := time.Now().UTC()
+	if req.To != "" {
+		parsed, err := time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("结束时间格式错误，请使用RFC3339格式")
+		}
+		to = parsed.UTC()
+	}
+
+	from := to.Add(-granularity.defaultRange)
+	if req.From != "" {
+		parsed, err := time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("开始时间格式错误，请使用RFC3339格式")
+		}
+		from = parsed.UTC()
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("开始时间必须早于结束时间")
+	}
+	if to.Sub(from) > granularity.maxRange {
+		if req.GroupBy == constants.LoginStatsGroupByHour {
+			return time.Time{}, time.Time{}, errors.New("按小时统计的时间范围不能超过7天")
+		}
+		return time.Time{}, time.Time{}, errors.New("按天统计的时间范围不能超过366天")
+	}
+
+	return from, to, nil
+}
+
+// queryError 记录统计查询错误并返回统一的系统错误
+func (l *GetLoginStatsLogic) queryError(name string, err error) error {
+	l.Logger.Errorf("查询登录%s失败: %v", name, err)
+	return errors.New("系统错误，请稍后重试")
+}
+
+// buildLoginStatsSeries 把聚合结果补全为连续的时间序列，没有登录的时间段计数为0
+func buildLoginStatsSeries(from, to time.Time, granularity loginStatsGranularity, buckets []*model.LoginTimeBucket) []types.LoginStatsPoint {
+	counts := make(map[string]*model.LoginTimeBucket, len(buckets))
+	for _, bucket := range buckets {
+		counts[bucket.Bucket] = bucket
+	}
+
+	series := make([]types.LoginStatsPoint, 0)
+	for t := from.Truncate(granularity.step); t.Before(to); t = t.Add(granularity.step) {
+		point := types.LoginStatsPoint{Time: t.Format(time.RFC3339)}
+		if bucket, ok := counts[t.Format(granularity.layout)]; ok {
+			point.Total = bucket.Total
+			point.Success = bucket.Success
+			point.Failed = bucket.Failed
+		}
+		series = append(series, point)
+	}
+	return series
+}
+
+// toLoginStatsCounts 转换分组计数为响应格式
+func toLoginStatsCounts(items []*model.LoginCountItem) []types.LoginStatsCount {
+	counts := make([]types.LoginStatsCount, 0, len(items))
+	for _, item := range items {
+		counts = append(counts, types.LoginStatsCount{
+			Key:    item.Key,
+			Total:  item.Total,
+			Failed: item.Failed,
+			LastAt: item.LastAt.Format(time.RFC3339),
+		})
+	}
+	return counts
+}
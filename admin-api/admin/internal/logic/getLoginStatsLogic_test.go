@@ -0,0 +1,82 @@
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+)
+
+func TestGetLoginStatsLogic_GetLoginStats(t *testing.T) {
+	mockey.PatchConvey("GetLoginStats Logic Tests", t, func() {
+		svcCtx := &svc.ServiceContext{LoginLogDAO: &dao.LoginLogDAO{}}
+		lastAt := time.Date(2024, 5, 2, 8, 30, 0, 0, time.UTC)
+
+		var gotFrom, gotTo time.Time
+		mockey.Mock((*dao.LoginLogDAO).GetStatistics).To(func(_ *dao.LoginLogDAO, _ context.Context, from, to time.Time) (*model.LoginStatistics, error) {
+			gotFrom, gotTo = from, to
+			return &model.LoginStatistics{TotalLogins: 12, SuccessLogins: 2, FailedLogins: 10, UniqueUsers: 1, UniqueIPs: 3, SuccessRate: 16.67}, nil
+		}).Build()
+		mockey.Mock((*dao.LoginLogDAO).GetTimeSeries).Return([]*model.LoginTimeBucket{
+			{Bucket: "2024-05-02", Total: 12, Success: 2, Failed: 10},
+		}, nil).Build()
+		mockey.Mock((*dao.LoginLogDAO).GetTopFailedIPs).Return([]*model.LoginCountItem{
+			{Key: "203.0.113.9", Total: 9, Failed: 9, LastAt: lastAt},
+		}, nil).Build()
+		mockey.Mock((*dao.LoginLogDAO).GetTopFailedUsernames).Return([]*model.LoginCountItem{
+			{Key: "admin", Total: 8, Failed: 8, LastAt: lastAt},
+		}, nil).Build()
+		mockey.Mock((*dao.LoginLogDAO).GetBreakdown).To(func(_ *dao.LoginLogDAO, _ context.Context, _, _ time.Time, field string, _ int) ([]*model.LoginCountItem, error) {
+			return []*model.LoginCountItem{{Key: field + "-top", Total: 12, LastAt: lastAt}}, nil
+		}).Build()
+
+		Convey("Should return totals, a continuous series and rankings", func() {
+			resp, err := NewGetLoginStatsLogic(context.Background(), svcCtx).GetLoginStats(&types.LoginStatsRequest{
+				From:    "2024-05-01T00:00:00Z",
+				To:      "2024-05-04T00:00:00Z",
+				GroupBy: constants.LoginStatsGroupByDay,
+				Limit:   5,
+			})
+			So(err, ShouldBeNil)
+			So(resp.Data.Summary.TotalLogins, ShouldEqual, 12)
+			So(resp.Data.Summary.SuccessRate, ShouldEqual, 16.67)
+
+			So(resp.Data.Series, ShouldResemble, []types.LoginStatsPoint{
+				{Time: "2024-05-01T00:00:00Z"},
+				{Time: "2024-05-02T00:00:00Z", Total: 12, Success: 2, Failed: 10},
+				{Time: "2024-05-03T00:00:00Z"},
+			})
+			So(resp.Data.TopFailedIPs[0].Key, ShouldEqual, "203.0.113.9")
+			So(resp.Data.TopFailedIPs[0].LastAt, ShouldEqual, "2024-05-02T08:30:00Z")
+			So(resp.Data.TopFailedUsernames[0].Key, ShouldEqual, "admin")
+			So(resp.Data.Countries[0].Key, ShouldEqual, "country-top")
+			So(resp.Data.Browsers[0].Key, ShouldEqual, "browser-top")
+		})
+
+		Convey("Should default to the last 24 hours when grouping by hour", func() {
+			resp, err := NewGetLoginStatsLogic(context.Background(), svcCtx).GetLoginStats(&types.LoginStatsRequest{GroupBy: constants.LoginStatsGroupByHour})
+			So(err, ShouldBeNil)
+			So(gotTo.Sub(gotFrom), ShouldEqual, 24*time.Hour)
+			So(gotTo, ShouldHappenWithin, time.Minute, time.Now())
+			So(len(resp.Data.Series), ShouldBeBetweenOrEqual, 24, 25)
+		})
+
+		Convey("Should validate the time range", func() {
+			logic := NewGetLoginStatsLogic(context.Background(), svcCtx)
+			_, err := logic.GetLoginStats(&types.LoginStatsRequest{From: "yesterday"})
+			So(err, ShouldNotBeNil)
+			_, err = logic.GetLoginStats(&types.LoginStatsRequest{From: "2024-05-04T00:00:00Z", To: "2024-05-01T00:00:00Z"})
+			So(err.Error(), ShouldEqual, "开始时间必须早于结束时间")
+			_, err = logic.GetLoginStats(&types.LoginStatsRequest{From: "2024-05-01T00:00:00Z", To: "2024-05-10T00:00:00Z", GroupBy: constants.LoginStatsGroupByHour})
+			So(err.Error(), ShouldEqual, "按小时统计的时间范围不能超过7天")
+		})
+	})
+}
@@ -0,0 +1,44 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetMFAPolicyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取两步验证策略
+func NewGetMFAPolicyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetMFAPolicyLogic {
+	return &GetMFAPolicyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetMFAPolicyLogic) GetMFAPolicy() (resp *types.MFAPolicyResponse, err error) {
+	roles, err := l.svcCtx.MFAPolicy.RequiredRoles(l.ctx)
+	if err != nil {
+		l.Logger.Errorf("查询两步验证策略失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	return &types.MFAPolicyResponse{
+		Code:      200,
+		Message:   "获取成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.MFAPolicyData{
+			RequiredRoles: roles,
+		},
+	}, nil
+}
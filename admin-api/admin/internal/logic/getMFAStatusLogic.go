@@ -0,0 +1,69 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetMFAStatusLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取当前用户的两步验证状态
+func NewGetMFAStatusLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetMFAStatusLogic {
+	return &GetMFAStatusLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetMFAStatusLogic) GetMFAStatus() (resp *types.MFAStatusResponse, err error) {
+	// 1. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 获取用户信息
+	user, err := l.svcCtx.UserDAO.GetByID(l.ctx, principal.UserID)
+	if err != nil {
+		l.Logger.Errorf("获取用户信息失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if user == nil {
+		return nil, errors.New("用户不存在")
+	}
+
+	// 3. 查询角色策略
+	required, err := mfaRequiredForRole(l.ctx, l.svcCtx, user.Role)
+	if err != nil {
+		l.Logger.Errorf("查询两步验证策略失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	data := types.MFAStatusData{
+		Enabled:                user.TwoFactorEnabled,
+		Required:               required,
+		RecoveryCodesRemaining: len(user.RecoveryCodes),
+	}
+	if user.TwoFactorEnabledAt != nil {
+		data.EnabledAt = user.TwoFactorEnabledAt.Format(time.RFC3339)
+	}
+
+	return &types.MFAStatusResponse{
+		Code:      200,
+		Message:   "获取成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data:      data,
+	}, nil
+}
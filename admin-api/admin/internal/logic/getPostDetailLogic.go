@@ -104,6 +104,8 @@ func (l *GetPostDetailLogic) buildPostDetailData(post *model.Post, author *model
 		ReadingTime:     post.ReadingTime,
 		WordCount:       post.WordCount,
 		ViewCount:       post.ViewCount,
+		DisableTOC:      post.DisableTOC,
+		TOCMaxDepth:     post.TOCMaxDepth,
 		PublishedAt:     publishedAt,
 		CreatedAt:       post.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:       post.UpdatedAt.Format(time.RFC3339),
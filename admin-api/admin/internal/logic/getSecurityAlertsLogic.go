@@ -0,0 +1,79 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetSecurityAlertsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取安全告警列表
+func NewGetSecurityAlertsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetSecurityAlertsLogic {
+	return &GetSecurityAlertsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetSecurityAlertsLogic) GetSecurityAlerts(req *types.SecurityAlertsRequest) (resp *types.SecurityAlertsResponse, err error) {
+	if req == nil {
+		return nil, errors.New("请求参数不能为空")
+	}
+
+	// 1. 参数验证，复用登录日志的分页和时间校验
+	logsReq := &types.LoginLogsRequest{
+		Page:      req.Page,
+		Limit:     req.Limit,
+		UserID:    req.UserID,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		SortBy:    "loginAt",
+		SortDesc:  true,
+	}
+	logsLogic := NewGetLoginLogsLogic(l.ctx, l.svcCtx)
+	if err := logsLogic.validateRequest(logsReq); err != nil {
+		return nil, err
+	}
+	if req.Type != "" && !constants.IsValidLoginAnomaly(req.Type) {
+		return nil, errors.New("异常类型无效")
+	}
+
+	// 2. 构建查询过滤条件，只查询被标记为异常的登录
+	filter, err := logsLogic.buildFilter(logsReq)
+	if err != nil {
+		return nil, err
+	}
+	filter["anomalous"] = true
+	if req.Type != "" {
+		filter["anomaly"] = req.Type
+	}
+
+	// 3. 查询异常登录
+	logs, total, err := l.svcCtx.LoginLogDAO.List(l.ctx, filter, logsReq.Page, logsReq.Limit)
+	if err != nil {
+		l.Logger.Errorf("查询安全告警列表失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	return &types.SecurityAlertsResponse{
+		Code:      200,
+		Message:   "获取安全告警列表成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.LoginLogsData{
+			List:       logsLogic.convertLogsToLogInfo(logs),
+			Pagination: logsLogic.buildPagination(logsReq.Page, logsReq.Limit, total),
+		},
+	}, nil
+}
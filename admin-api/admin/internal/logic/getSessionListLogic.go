@@ -0,0 +1,80 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetSessionListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取当前用户的登录会话列表
+func NewGetSessionListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetSessionListLogic {
+	return &GetSessionListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetSessionListLogic) GetSessionList() (resp *types.SessionListResponse, err error) {
+	// 1. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 查询用户会话
+	sessions, err := listUserSessions(l.ctx, l.svcCtx.Redis, principal.UserID)
+	if err != nil {
+		l.Logger.Errorf("查询会话列表失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	// 3. 按最后活跃时间倒序排列
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	list := make([]types.SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		list = append(list, buildSessionInfo(session, principal.SessionID))
+	}
+
+	return &types.SessionListResponse{
+		Code:      200,
+		Message:   "获取会话列表成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.SessionListData{
+			List: list,
+		},
+	}, nil
+}
+
+// buildSessionInfo 构建会话信息响应
+func buildSessionInfo(session *userSession, currentSessionID string) types.SessionInfo {
+	ua := utils.ParseUserAgent(session.UserAgent)
+	return types.SessionInfo{
+		ID:         session.SessionID,
+		IPAddress:  session.IPAddress,
+		UserAgent:  session.UserAgent,
+		DeviceType: ua.DeviceType,
+		Browser:    ua.BrowserString(),
+		OS:         ua.OSString(),
+		CreatedAt:  session.CreatedAt.Format(time.RFC3339),
+		LastSeenAt: session.LastSeenAt.Format(time.RFC3339),
+		Current:    session.SessionID == currentSessionID,
+	}
+}
@@ -0,0 +1,46 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/slug"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetSlugStrategyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取slug生成策略
+func NewGetSlugStrategyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetSlugStrategyLogic {
+	return &GetSlugStrategyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetSlugStrategyLogic) GetSlugStrategy() (resp *types.SlugStrategyResponse, err error) {
+	strategy, err := slugStrategyName(l.ctx, l.svcCtx)
+	if err != nil {
+		l.Logger.Errorf("查询slug策略失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	return &types.SlugStrategyResponse{
+		Code:      200,
+		Message:   "获取成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.SlugStrategyData{
+			Strategy:   strategy,
+			Strategies: slug.Names(),
+		},
+	}, nil
+}
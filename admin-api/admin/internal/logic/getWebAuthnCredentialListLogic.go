@@ -0,0 +1,63 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetWebAuthnCredentialListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取当前用户的通行密钥列表
+func NewGetWebAuthnCredentialListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetWebAuthnCredentialListLogic {
+	return &GetWebAuthnCredentialListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetWebAuthnCredentialListLogic) GetWebAuthnCredentialList() (resp *types.WebAuthnCredentialListResponse, err error) {
+	// 1. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := primitive.ObjectIDFromHex(principal.UserID)
+	if err != nil {
+		return nil, errors.New("无效的用户ID")
+	}
+
+	// 2. 查询凭据
+	credentials, err := l.svcCtx.WebAuthnCredentialDAO.ListByUserID(l.ctx, userID)
+	if err != nil {
+		l.Logger.Errorf("查询通行密钥失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	list := make([]types.WebAuthnCredentialInfo, 0, len(credentials))
+	for _, credential := range credentials {
+		list = append(list, toWebAuthnCredentialInfo(credential))
+	}
+
+	return &types.WebAuthnCredentialListResponse{
+		Code:      200,
+		Message:   "获取成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.WebAuthnCredentialListData{
+			List: list,
+		},
+	}, nil
+}
@@ -163,6 +163,10 @@ func (l *GetLoginLogsLogic) buildFilter(req *types.LoginLogsRequest) (map[string
 		filter["browser"] = req.Browser
 	}
 
+	if req.OS != "" {
+		filter["os"] = req.OS
+	}
+
 	// 排序设置
 	filter["sortBy"] = req.SortBy
 	filter["sortDesc"] = req.SortDesc
@@ -259,5 +263,9 @@ func (l *GetLoginLogsLogic) buildLoginLogInfo(log *model.LoginLog) types.LoginLo
 		logInfo.Duration = *log.Duration
 	}
 
+	if log.IsAnomalous() {
+		logInfo.Anomalies = log.Anomalies
+	}
+
 	return logInfo
 }
@@ -9,6 +9,8 @@ import (
 
 	"github.com/heimdall-api/admin-api/admin/internal/svc"
 	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
@@ -84,66 +86,41 @@ func (l *GetUserDetailLogic) validateRequest(req *types.UserDetailRequest) error
 	return nil
 }
 
-// checkViewPermission 检查查看权限
+// checkViewPermission 检查查看权限：管理员可以查看所有用户，其他角色只能查看自己
 func (l *GetUserDetailLogic) checkViewPermission(user *model.User) error {
-	// 获取当前操作用户ID
-	currentUserID := l.getCurrentUserID()
-	if currentUserID == "" {
-		return errors.New("用户未认证")
-	}
-
-	// 如果查看自己的信息，直接允许
-	if currentUserID == user.ID.Hex() {
-		return nil
-	}
-
-	// 如果是管理员，允许查看所有用户信息
-	currentUser, err := l.svcCtx.UserDAO.GetByID(l.ctx, currentUserID)
+	principal, err := auth.FromContext(l.ctx)
 	if err != nil {
-		l.Logger.Errorf("获取当前用户信息失败: %v", err)
-		return errors.New("权限验证失败")
-	}
-
-	if currentUser != nil && (currentUser.Role == "admin" || currentUser.Role == "owner") {
-		return nil
-	}
-
-	// 其他情况拒绝访问
-	return errors.New("权限不足")
-}
-
-// getCurrentUserID 从context获取当前用户ID
-func (l *GetUserDetailLogic) getCurrentUserID() string {
-	uid := l.ctx.Value("uid")
-	if uid == nil {
-		return ""
+		return errors.New("用户未认证")
 	}
 
-	userID, ok := uid.(string)
-	if !ok || userID == "" {
-		return ""
+	if !principal.CanAccess(constants.PermissionUserRead, user.ID.Hex()) {
+		return errors.New("权限不足")
 	}
 
-	return userID
+	return nil
 }
 
 // buildUserInfo 构建用户信息响应（复用ProfileLogic的逻辑）
 func (l *GetUserDetailLogic) buildUserInfo(user *model.User) types.UserInfo {
 	userInfo := types.UserInfo{
-		ID:          user.ID.Hex(),
-		Username:    user.Username,
-		Email:       user.Email,
-		DisplayName: user.DisplayName,
-		Role:        user.Role,
-		Status:      user.Status,
-		CreatedAt:   user.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   user.UpdatedAt.Format(time.RFC3339),
+		ID:               user.ID.Hex(),
+		Username:         user.Username,
+		Email:            user.Email,
+		DisplayName:      user.DisplayName,
+		Role:             user.Role,
+		Status:           user.Status,
+		TwoFactorEnabled: user.TwoFactorEnabled,
+		CreatedAt:        user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        user.UpdatedAt.Format(time.RFC3339),
 	}
 
 	// 设置可选字段
 	if user.ProfileImage != "" {
 		userInfo.ProfileImage = user.ProfileImage
 	}
+	if user.CoverImage != "" {
+		userInfo.CoverImage = user.CoverImage
+	}
 	if user.Bio != "" {
 		userInfo.Bio = user.Bio
 	}
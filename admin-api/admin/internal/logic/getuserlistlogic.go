@@ -145,20 +145,24 @@ func (l *GetUserListLogic) convertUsersToUserInfo(users []*model.User) []types.U
 // buildUserInfo 构建用户信息响应（复用ProfileLogic的逻辑）
 func (l *GetUserListLogic) buildUserInfo(user *model.User) types.UserInfo {
 	userInfo := types.UserInfo{
-		ID:          user.ID.Hex(),
-		Username:    user.Username,
-		Email:       user.Email,
-		DisplayName: user.DisplayName,
-		Role:        user.Role,
-		Status:      user.Status,
-		CreatedAt:   user.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   user.UpdatedAt.Format(time.RFC3339),
+		ID:               user.ID.Hex(),
+		Username:         user.Username,
+		Email:            user.Email,
+		DisplayName:      user.DisplayName,
+		Role:             user.Role,
+		Status:           user.Status,
+		TwoFactorEnabled: user.TwoFactorEnabled,
+		CreatedAt:        user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        user.UpdatedAt.Format(time.RFC3339),
 	}
 
 	// 设置可选字段
 	if user.ProfileImage != "" {
 		userInfo.ProfileImage = user.ProfileImage
 	}
+	if user.CoverImage != "" {
+		userInfo.CoverImage = user.CoverImage
+	}
 	if user.Bio != "" {
 		userInfo.Bio = user.Bio
 	}
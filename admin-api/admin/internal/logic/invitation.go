@@ -0,0 +1,156 @@
+package logic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/threading"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/client/mailer"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+const (
+	// invitationTokenPurpose 邀请令牌的签名用途，防止与其他签名令牌互相替用
+	invitationTokenPurpose = "invitation"
+	// invitationMailTimeout 发送邀请邮件的超时时间
+	invitationMailTimeout = 30 * time.Second
+)
+
+// errInvalidInvitation 令牌无效、过期、已被替换或邀请已失效时的统一错误
+var errInvalidInvitation = errors.New("邀请链接无效或已过期")
+
+// issueInvitationToken 为邀请签发新的链接令牌，返回令牌、令牌哈希和过期时间
+// 令牌使用访问令牌密钥签名，库中只保存哈希，重发后旧令牌因哈希不匹配而失效
+func issueInvitationToken(svcCtx *svc.ServiceContext, id primitive.ObjectID) (string, string, time.Time, error) {
+	expiresAt := time.Now().Add(time.Duration(svcCtx.Config.Security.InvitationTTL) * time.Second)
+	token, err := utils.SignToken(svcCtx.Config.Auth.AccessSecret, invitationTokenPurpose, id.Hex(), expiresAt)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	return token, hashInvitationToken(token), expiresAt, nil
+}
+
+// hashInvitationToken 计算邀请令牌的SHA-256哈希
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadManagedInvitation 获取当前认证主体和邀请，只能管理邀请角色等级低于自己的邀请，所有者可以管理全部邀请
+func loadManagedInvitation(ctx context.Context, svcCtx *svc.ServiceContext, id string) (*auth.Principal, *model.Invitation, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil, errors.New("邀请ID格式无效")
+	}
+
+	principal, err := auth.FromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	invitation, err := svcCtx.InvitationDAO.GetByID(ctx, objectID)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("获取邀请失败: %v", err)
+		return nil, nil, errors.New("系统错误，请稍后重试")
+	}
+	if invitation == nil {
+		return nil, nil, errors.New("邀请不存在")
+	}
+	if !constants.CanManageRole(principal.Role, invitation.Role) {
+		return nil, nil, errors.New("权限不足")
+	}
+
+	return principal, invitation, nil
+}
+
+// sendInvitationMail 异步发送邀请邮件，发送失败只记录日志，可通过重新发送补救
+func sendInvitationMail(ctx context.Context, svcCtx *svc.ServiceContext, invitation *model.Invitation, inviterID, token string) {
+	inviter := ""
+	if user, err := svcCtx.UserDAO.GetByID(ctx, inviterID); err == nil && user != nil {
+		inviter = user.DisplayName
+		if inviter == "" {
+			inviter = user.Username
+		}
+	}
+
+	msg, err := buildInvitationMessage(svcCtx.Config.Security.InvitationURL, invitation, inviter, token)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("构建邀请邮件失败: %v", err)
+		return
+	}
+
+	mailCtx := context.WithoutCancel(ctx)
+	threading.GoSafe(func() {
+		ctx, cancel := context.WithTimeout(mailCtx, invitationMailTimeout)
+		defer cancel()
+		if err := svcCtx.Mailer.Send(ctx, msg); err != nil {
+			logx.WithContext(ctx).Errorf("发送邀请邮件失败: invitationID=%s, error=%v", invitation.ID.Hex(), err)
+		}
+	})
+}
+
+// buildInvitationMessage 构建邀请邮件
+func buildInvitationMessage(pageURL string, invitation *model.Invitation, inviter, token string) (*mailer.Message, error) {
+	link, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("接受邀请页面地址无效: %w", err)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	if inviter == "" {
+		inviter = "管理员"
+	}
+	expires := invitation.ExpiresAt.Format("2006-01-02 15:04")
+
+	return &mailer.Message{
+		To:      []string{invitation.Email},
+		Subject: "您收到了一封加入博客的邀请",
+		Text: fmt.Sprintf("您好：\n\n%s 邀请您以「%s」身份加入博客。请在 %s 前打开以下链接设置用户名和密码：\n\n%s\n\n如果您不认识邀请人，请忽略此邮件。\n",
+			inviter, invitation.Role, expires, link.String()),
+		HTML: fmt.Sprintf(`<p>您好：</p><p>%s 邀请您以「%s」身份加入博客。请在 %s 前点击下面的链接设置用户名和密码：</p><p><a href="%s">接受邀请</a></p><p>如果您不认识邀请人，请忽略此邮件。</p>`,
+			html.EscapeString(inviter), html.EscapeString(invitation.Role), expires, html.EscapeString(link.String())),
+	}, nil
+}
+
+// toInvitationInfo 转换为邀请信息响应
+func toInvitationInfo(invitation *model.Invitation) types.InvitationInfo {
+	return types.InvitationInfo{
+		ID:         invitation.ID.Hex(),
+		Email:      invitation.Email,
+		Role:       invitation.Role,
+		Status:     invitation.Status,
+		Expired:    invitation.Status == constants.InvitationStatusPending && invitation.IsExpired(),
+		InvitedBy:  invitation.InvitedBy.Hex(),
+		SendCount:  invitation.SendCount,
+		LastSentAt: invitation.LastSentAt.Format(time.RFC3339),
+		ExpiresAt:  invitation.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:  invitation.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// newInvitationResponse 构造单个邀请的响应
+func newInvitationResponse(message string, invitation *model.Invitation) *types.InvitationResponse {
+	return &types.InvitationResponse{
+		Code:      200,
+		Message:   message,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data:      toInvitationInfo(invitation),
+	}
+}
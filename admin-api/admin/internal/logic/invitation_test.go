@@ -0,0 +1,312 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/config"
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/client/mailer"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+)
+
+func TestInvitationLogic(t *testing.T) {
+	mockey.PatchConvey("Invitation Logic Tests", t, func() {
+		cfg := config.Config{
+			Auth: struct {
+				AccessSecret string
+				AccessExpire int64
+			}{
+				AccessSecret: "test-secret",
+				AccessExpire: 7200,
+			},
+			Security: config.SecurityConfig{
+				InvitationURL: "https://admin.example.com/accept-invitation",
+				InvitationTTL: 86400,
+			},
+		}
+		mail := &recordingMailer{sent: make(chan *mailer.Message, 10)}
+		svcCtx := &svc.ServiceContext{
+			Config:        cfg,
+			UserDAO:       &dao.UserDAO{},
+			InvitationDAO: &dao.InvitationDAO{},
+			Mailer:        mail,
+		}
+
+		// 用内存中的数据模拟数据库
+		users := map[string]*model.User{}
+		admin := &model.User{
+			ID:       primitive.NewObjectID(),
+			Username: "admin",
+			Email:    "admin@example.com",
+			Role:     constants.UserRoleAdmin,
+			Status:   constants.UserStatusActive,
+		}
+		users[admin.ID.Hex()] = admin
+		invitations := map[primitive.ObjectID]*model.Invitation{}
+
+		mockey.Mock((*dao.UserDAO).GetByID).To(func(_ *dao.UserDAO, _ context.Context, id string) (*model.User, error) {
+			return users[id], nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).GetByUsername).To(func(_ *dao.UserDAO, _ context.Context, username string) (*model.User, error) {
+			for _, user := range users {
+				if user.Username == username {
+					return user, nil
+				}
+			}
+			return nil, nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).GetByEmail).To(func(_ *dao.UserDAO, _ context.Context, email string) (*model.User, error) {
+			for _, user := range users {
+				if user.Email == email {
+					return user, nil
+				}
+			}
+			return nil, nil
+		}).Build()
+		var createErr error
+		mockey.Mock((*dao.UserDAO).Create).To(func(_ *dao.UserDAO, _ context.Context, user *model.User) error {
+			if createErr != nil {
+				return createErr
+			}
+			user.PrepareForInsert()
+			users[user.ID.Hex()] = user
+			return nil
+		}).Build()
+
+		mockey.Mock((*dao.InvitationDAO).Create).To(func(_ *dao.InvitationDAO, _ context.Context, invitation *model.Invitation) error {
+			invitation.Status = constants.InvitationStatusPending
+			invitation.CreatedAt = time.Now()
+			invitations[invitation.ID] = invitation
+			return nil
+		}).Build()
+		revokeOnLookup := false
+		mockey.Mock((*dao.InvitationDAO).GetByID).To(func(_ *dao.InvitationDAO, _ context.Context, id primitive.ObjectID) (*model.Invitation, error) {
+			if invitation, ok := invitations[id]; ok {
+				copied := *invitation
+				if revokeOnLookup {
+					invitation.Status = constants.InvitationStatusRevoked
+				}
+				return &copied, nil
+			}
+			return nil, nil
+		}).Build()
+		mockey.Mock((*dao.InvitationDAO).GetPendingByEmail).To(func(_ *dao.InvitationDAO, _ context.Context, email string) (*model.Invitation, error) {
+			for _, invitation := range invitations {
+				if invitation.Email == email && invitation.IsPending() {
+					return invitation, nil
+				}
+			}
+			return nil, nil
+		}).Build()
+		mockey.Mock((*dao.InvitationDAO).ListPending).To(func(_ *dao.InvitationDAO, _ context.Context) ([]*model.Invitation, error) {
+			var list []*model.Invitation
+			for _, invitation := range invitations {
+				if invitation.Status == constants.InvitationStatusPending {
+					list = append(list, invitation)
+				}
+			}
+			return list, nil
+		}).Build()
+		mockey.Mock((*dao.InvitationDAO).Resend).To(func(_ *dao.InvitationDAO, _ context.Context, id primitive.ObjectID, tokenHash string, expiresAt time.Time) (bool, error) {
+			invitation := invitations[id]
+			if invitation.Status != constants.InvitationStatusPending {
+				return false, nil
+			}
+			invitation.TokenHash, invitation.ExpiresAt, invitation.LastSentAt = tokenHash, expiresAt, time.Now()
+			invitation.SendCount++
+			return true, nil
+		}).Build()
+		mockey.Mock((*dao.InvitationDAO).Revoke).To(func(_ *dao.InvitationDAO, _ context.Context, id primitive.ObjectID) (bool, error) {
+			invitation := invitations[id]
+			if invitation.Status != constants.InvitationStatusPending {
+				return false, nil
+			}
+			invitation.Status = constants.InvitationStatusRevoked
+			return true, nil
+		}).Build()
+		mockey.Mock((*dao.InvitationDAO).MarkAccepted).To(func(_ *dao.InvitationDAO, _ context.Context, id primitive.ObjectID, tokenHash string, userID primitive.ObjectID) (bool, error) {
+			invitation := invitations[id]
+			if !invitation.IsPending() || invitation.TokenHash != tokenHash {
+				return false, nil
+			}
+			invitation.Status = constants.InvitationStatusAccepted
+			invitation.AcceptedUserID = &userID
+			return true, nil
+		}).Build()
+		mockey.Mock((*dao.InvitationDAO).ReleaseAccepted).To(func(_ *dao.InvitationDAO, _ context.Context, id primitive.ObjectID, userID primitive.ObjectID) (bool, error) {
+			invitation := invitations[id]
+			if invitation.Status != constants.InvitationStatusAccepted || invitation.AcceptedUserID == nil || *invitation.AcceptedUserID != userID {
+				return false, nil
+			}
+			invitation.Status = constants.InvitationStatusPending
+			invitation.AcceptedUserID = nil
+			return true, nil
+		}).Build()
+
+		asAdmin := withPrincipal(context.Background(), admin.ID.Hex(), admin.Role)
+		invite := func(email, role string) *types.InvitationInfo {
+			resp, err := NewCreateInvitationLogic(asAdmin, svcCtx).CreateInvitation(&types.InvitationCreateRequest{Email: email, Role: role})
+			So(err, ShouldBeNil)
+			return &resp.Data
+		}
+		receiveToken := func() string {
+			select {
+			case msg := <-mail.sent:
+				link := regexp.MustCompile(`https://\S+`).FindString(msg.Text)
+				parsed, err := url.Parse(link)
+				So(err, ShouldBeNil)
+				return parsed.Query().Get("token")
+			case <-time.After(2 * time.Second):
+				t.Fatal("invitation mail not sent")
+				return ""
+			}
+		}
+		accept := func(token, username, password string) (*types.InvitationAcceptResponse, error) {
+			return NewAcceptInvitationLogic(context.Background(), svcCtx).AcceptInvitation(&types.InvitationAcceptRequest{
+				Token:    token,
+				Username: username,
+				Password: password,
+			})
+		}
+
+		Convey("Invitee should set up an account with the preset role", func() {
+			info := invite("writer@example.com", constants.UserRoleAuthor)
+			So(info.Status, ShouldEqual, constants.InvitationStatusPending)
+			token := receiveToken()
+			So(token, ShouldNotBeEmpty)
+
+			resp, err := accept(token, "writer", "Str0ng#Passw0rd")
+			So(err, ShouldBeNil)
+			So(resp.Data.Role, ShouldEqual, constants.UserRoleAuthor)
+			So(resp.Data.Email, ShouldEqual, "writer@example.com")
+
+			id, _ := primitive.ObjectIDFromHex(info.ID)
+			So(invitations[id].Status, ShouldEqual, constants.InvitationStatusAccepted)
+
+			// 令牌只能使用一次
+			_, err = accept(token, "writer2", "Str0ng#Passw0rd")
+			So(err, ShouldEqual, errInvalidInvitation)
+		})
+
+		Convey("Accept should enforce the password policy for the invitee", func() {
+			invite("writer@example.com", constants.UserRoleAuthor)
+			token := receiveToken()
+
+			_, err := accept(token, "writer", "Writer#2024pass")
+			So(err, ShouldNotBeNil)
+			_, err = accept(token, "admin", "Str0ng#Passw0rd")
+			So(err, ShouldNotBeNil)
+			So(len(users), ShouldEqual, 1)
+		})
+
+		Convey("Accept should reject tampered and expired tokens", func() {
+			info := invite("writer@example.com", constants.UserRoleAuthor)
+			token := receiveToken()
+
+			_, err := accept(token+"x", "writer", "Str0ng#Passw0rd")
+			So(err, ShouldEqual, errInvalidInvitation)
+
+			id, _ := primitive.ObjectIDFromHex(info.ID)
+			invitations[id].ExpiresAt = time.Now().Add(-time.Minute)
+			_, err = accept(token, "writer", "Str0ng#Passw0rd")
+			So(err, ShouldEqual, errInvalidInvitation)
+		})
+
+		Convey("Accept should claim the invitation before creating the account", func() {
+			info := invite("writer@example.com", constants.UserRoleAuthor)
+			token := receiveToken()
+			id, _ := primitive.ObjectIDFromHex(info.ID)
+
+			// 账号创建失败时释放邀请，令牌仍可重试
+			createErr = errors.New("duplicate key")
+			_, err := accept(token, "writer", "Str0ng#Passw0rd")
+			So(err, ShouldNotBeNil)
+			So(invitations[id].Status, ShouldEqual, constants.InvitationStatusPending)
+			So(invitations[id].AcceptedUserID, ShouldBeNil)
+
+			createErr = nil
+			_, err = accept(token, "writer", "Str0ng#Passw0rd")
+			So(err, ShouldBeNil)
+			So(invitations[id].Status, ShouldEqual, constants.InvitationStatusAccepted)
+		})
+
+		Convey("Accept should not create an account when the claim is lost", func() {
+			info := invite("writer@example.com", constants.UserRoleAuthor)
+			token := receiveToken()
+			id, _ := primitive.ObjectIDFromHex(info.ID)
+
+			// 查询之后、占用之前邀请被撤销
+			revokeOnLookup = true
+			_, err := accept(token, "writer", "Str0ng#Passw0rd")
+			So(err, ShouldEqual, errInvalidInvitation)
+			So(len(users), ShouldEqual, 1)
+			So(invitations[id].Status, ShouldEqual, constants.InvitationStatusRevoked)
+		})
+
+		Convey("Create should respect the role hierarchy and existing users", func() {
+			_, err := NewCreateInvitationLogic(asAdmin, svcCtx).CreateInvitation(&types.InvitationCreateRequest{Email: "boss@example.com", Role: constants.UserRoleAdmin})
+			So(err, ShouldNotBeNil)
+			_, err = NewCreateInvitationLogic(asAdmin, svcCtx).CreateInvitation(&types.InvitationCreateRequest{Email: admin.Email, Role: constants.UserRoleAuthor})
+			So(err, ShouldNotBeNil)
+
+			invite("writer@example.com", constants.UserRoleAuthor)
+			_, err = NewCreateInvitationLogic(asAdmin, svcCtx).CreateInvitation(&types.InvitationCreateRequest{Email: "writer@example.com", Role: constants.UserRoleEditor})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Resend should replace the previous link", func() {
+			info := invite("writer@example.com", constants.UserRoleAuthor)
+			first := receiveToken()
+
+			_, err := NewResendInvitationLogic(asAdmin, svcCtx).ResendInvitation(&types.InvitationIDRequest{ID: info.ID})
+			So(err, ShouldNotBeNil)
+
+			id, _ := primitive.ObjectIDFromHex(info.ID)
+			invitations[id].LastSentAt = time.Now().Add(-time.Hour)
+			resp, err := NewResendInvitationLogic(asAdmin, svcCtx).ResendInvitation(&types.InvitationIDRequest{ID: info.ID})
+			So(err, ShouldBeNil)
+			So(resp.Data.SendCount, ShouldEqual, 2)
+			second := receiveToken()
+			So(second, ShouldNotEqual, first)
+
+			_, err = accept(first, "writer", "Str0ng#Passw0rd")
+			So(err, ShouldEqual, errInvalidInvitation)
+			_, err = accept(second, "writer", "Str0ng#Passw0rd")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Revoked invitations should disappear from the list and stop working", func() {
+			info := invite("writer@example.com", constants.UserRoleAuthor)
+			token := receiveToken()
+
+			list, err := NewGetInvitationListLogic(asAdmin, svcCtx).GetInvitationList()
+			So(err, ShouldBeNil)
+			So(list.Data.List, ShouldHaveLength, 1)
+
+			resp, err := NewRevokeInvitationLogic(asAdmin, svcCtx).RevokeInvitation(&types.InvitationIDRequest{ID: info.ID})
+			So(err, ShouldBeNil)
+			So(resp.Data.Status, ShouldEqual, constants.InvitationStatusRevoked)
+
+			list, err = NewGetInvitationListLogic(asAdmin, svcCtx).GetInvitationList()
+			So(err, ShouldBeNil)
+			So(list.Data.List, ShouldBeEmpty)
+
+			_, err = accept(token, "writer", "Str0ng#Passw0rd")
+			So(err, ShouldEqual, errInvalidInvitation)
+			_, err = NewRevokeInvitationLogic(asAdmin, svcCtx).RevokeInvitation(&types.InvitationIDRequest{ID: info.ID})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
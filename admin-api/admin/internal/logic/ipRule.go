@@ -0,0 +1,86 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+// errIPRuleLockout 操作会导致当前IP无法访问管理后台
+var errIPRuleLockout = errors.New("该操作会导致当前IP无法访问管理后台")
+
+// operatorIP 获取当前操作者的IP，无法解析时返回nil
+func operatorIP(ctx context.Context) net.IP {
+	return net.ParseIP(utils.ClientInfoFromContext(ctx).IP)
+}
+
+// allowlistExcludes 检查白名单模式下，规则集合非空且不包含指定IP时该IP将被拒绝访问
+func allowlistExcludes(rules []*model.IPRule, ip net.IP) bool {
+	if ip == nil || len(rules) == 0 {
+		return false
+	}
+	for _, rule := range rules {
+		if ipNet, err := utils.ParseIPNet(rule.CIDR); err == nil && ipNet.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadAllowRules 获取全部白名单规则
+func loadAllowRules(ctx context.Context, svcCtx *svc.ServiceContext) ([]*model.IPRule, error) {
+	rules, err := svcCtx.IPRuleDAO.List(ctx, constants.IPRuleTypeAllow)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("获取IP白名单失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	return rules, nil
+}
+
+// ipRuleID 获取规则ID，临时封禁没有数据库ID，使用被封禁的IP作为ID
+func ipRuleID(rule *model.IPRule) string {
+	if !rule.IsTemporary() {
+		return rule.ID.Hex()
+	}
+	if ipNet, err := utils.ParseIPNet(rule.CIDR); err == nil {
+		return ipNet.IP.String()
+	}
+	return rule.CIDR
+}
+
+// toIPRuleInfo 转换为IP规则信息响应
+func toIPRuleInfo(rule *model.IPRule) types.IPRuleInfo {
+	info := types.IPRuleInfo{
+		ID:            ipRuleID(rule),
+		Type:          rule.Type,
+		CIDR:          rule.CIDR,
+		Reason:        rule.Reason,
+		Temporary:     rule.IsTemporary(),
+		CreatedBy:     rule.CreatedBy,
+		CreatedByName: rule.CreatedByName,
+		CreatedAt:     rule.CreatedAt.Format(time.RFC3339),
+	}
+	if rule.ExpiresAt != nil {
+		info.ExpiresAt = rule.ExpiresAt.Format(time.RFC3339)
+	}
+	return info
+}
+
+// newIPRuleResponse 构造单个IP规则的响应
+func newIPRuleResponse(message string, rule *model.IPRule) *types.IPRuleResponse {
+	return &types.IPRuleResponse{
+		Code:      200,
+		Message:   message,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data:      toIPRuleInfo(rule),
+	}
+}
@@ -0,0 +1,214 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bytedance/mockey"
+	"github.com/go-redis/redis/v8"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/config"
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+func TestIPRuleLogic(t *testing.T) {
+	mockey.PatchConvey("IP Rule Logic Tests", t, func() {
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+
+		// 用内存中的数据模拟数据库
+		rules := map[primitive.ObjectID]*model.IPRule{}
+		mockey.Mock((*dao.IPRuleDAO).Create).To(func(_ *dao.IPRuleDAO, _ context.Context, rule *model.IPRule) error {
+			rule.ID = primitive.NewObjectID()
+			rule.CreatedAt = time.Now()
+			rules[rule.ID] = rule
+			return nil
+		}).Build()
+		mockey.Mock((*dao.IPRuleDAO).GetByID).To(func(_ *dao.IPRuleDAO, _ context.Context, id primitive.ObjectID) (*model.IPRule, error) {
+			return rules[id], nil
+		}).Build()
+		mockey.Mock((*dao.IPRuleDAO).GetByCIDR).To(func(_ *dao.IPRuleDAO, _ context.Context, ruleType, cidr string) (*model.IPRule, error) {
+			for _, rule := range rules {
+				if rule.Type == ruleType && rule.CIDR == cidr {
+					return rule, nil
+				}
+			}
+			return nil, nil
+		}).Build()
+		mockey.Mock((*dao.IPRuleDAO).List).To(func(_ *dao.IPRuleDAO, _ context.Context, ruleType string) ([]*model.IPRule, error) {
+			var list []*model.IPRule
+			for _, rule := range rules {
+				if ruleType == "" || rule.Type == ruleType {
+					list = append(list, rule)
+				}
+			}
+			return list, nil
+		}).Build()
+		mockey.Mock((*dao.IPRuleDAO).Delete).To(func(_ *dao.IPRuleDAO, _ context.Context, id primitive.ObjectID) (bool, error) {
+			_, ok := rules[id]
+			delete(rules, id)
+			return ok, nil
+		}).Build()
+
+		newSvcCtx := func(allowlistMode bool) *svc.ServiceContext {
+			access, err := auth.NewIPAccessControl(&dao.IPRuleDAO{}, rdb, allowlistMode)
+			So(err, ShouldBeNil)
+			return &svc.ServiceContext{
+				Config: config.Config{
+					Security: config.SecurityConfig{
+						MaxLoginAttemptsPerIP: 3,
+						LoginIPBlockDuration:  1800,
+					},
+				},
+				Redis:           rdb,
+				IPRuleDAO:       &dao.IPRuleDAO{},
+				IPAccessControl: access,
+			}
+		}
+		adminFrom := func(ip string) context.Context {
+			ctx := utils.WithClientInfo(context.Background(), utils.ClientInfo{IP: ip})
+			return withPrincipal(ctx, primitive.NewObjectID().Hex(), constants.UserRoleAdmin)
+		}
+
+		Convey("Permanent blocks should take effect immediately and be removable", func() {
+			svcCtx := newSvcCtx(false)
+			ctx := adminFrom("192.0.2.1")
+
+			resp, err := NewCreateIPRuleLogic(ctx, svcCtx).CreateIPRule(&types.IPRuleCreateRequest{
+				Type:   constants.IPRuleTypeBlock,
+				CIDR:   "203.0.113.9/24",
+				Reason: "  扫描器  ",
+			})
+			So(err, ShouldBeNil)
+			So(resp.Data.CIDR, ShouldEqual, "203.0.113.0/24")
+			So(resp.Data.Reason, ShouldEqual, "扫描器")
+			So(resp.Data.Temporary, ShouldBeFalse)
+			So(svcCtx.IPAccessControl.Check(context.Background(), "203.0.113.77"), ShouldEqual, auth.ErrIPBlocked)
+
+			_, err = NewCreateIPRuleLogic(ctx, svcCtx).CreateIPRule(&types.IPRuleCreateRequest{
+				Type:   constants.IPRuleTypeBlock,
+				CIDR:   "203.0.113.0/24",
+				Reason: "重复",
+			})
+			So(err.Error(), ShouldEqual, "该规则已存在")
+
+			_, err = NewDeleteIPRuleLogic(ctx, svcCtx).DeleteIPRule(&types.IPRuleIDRequest{ID: resp.Data.ID})
+			So(err, ShouldBeNil)
+			So(svcCtx.IPAccessControl.Check(context.Background(), "203.0.113.77"), ShouldBeNil)
+		})
+
+		Convey("Create should validate input and refuse to block the operator", func() {
+			svcCtx := newSvcCtx(false)
+			ctx := adminFrom("192.0.2.1")
+
+			_, err := NewCreateIPRuleLogic(ctx, svcCtx).CreateIPRule(&types.IPRuleCreateRequest{Type: constants.IPRuleTypeBlock, CIDR: "not-an-ip", Reason: "x"})
+			So(err, ShouldNotBeNil)
+			_, err = NewCreateIPRuleLogic(ctx, svcCtx).CreateIPRule(&types.IPRuleCreateRequest{Type: constants.IPRuleTypeBlock, CIDR: "198.51.100.1", Reason: " "})
+			So(err, ShouldNotBeNil)
+			_, err = NewCreateIPRuleLogic(ctx, svcCtx).CreateIPRule(&types.IPRuleCreateRequest{Type: constants.IPRuleTypeBlock, CIDR: "192.0.2.0/24", Reason: "x"})
+			So(err.Error(), ShouldEqual, "不能封禁当前操作者的IP")
+			_, err = NewCreateIPRuleLogic(ctx, svcCtx).CreateIPRule(&types.IPRuleCreateRequest{Type: constants.IPRuleTypeBlock, CIDR: "198.51.100.0/24", Reason: "x", Duration: 60})
+			So(err.Error(), ShouldEqual, "临时封禁只支持单个IP")
+			So(rules, ShouldBeEmpty)
+		})
+
+		Convey("Temporary blocks should be listed and removable by IP", func() {
+			svcCtx := newSvcCtx(false)
+			ctx := adminFrom("192.0.2.1")
+
+			resp, err := NewCreateIPRuleLogic(ctx, svcCtx).CreateIPRule(&types.IPRuleCreateRequest{
+				Type:     constants.IPRuleTypeBlock,
+				CIDR:     "198.51.100.7",
+				Reason:   "暴力破解",
+				Duration: 600,
+			})
+			So(err, ShouldBeNil)
+			So(resp.Data.Temporary, ShouldBeTrue)
+			So(resp.Data.ID, ShouldEqual, "198.51.100.7")
+			So(resp.Data.ExpiresAt, ShouldNotBeEmpty)
+
+			list, err := NewGetIPRuleListLogic(ctx, svcCtx).GetIPRuleList(&types.IPRuleListRequest{})
+			So(err, ShouldBeNil)
+			So(list.Data.List, ShouldHaveLength, 1)
+			So(list.Data.List[0].Reason, ShouldEqual, "暴力破解")
+
+			list, err = NewGetIPRuleListLogic(ctx, svcCtx).GetIPRuleList(&types.IPRuleListRequest{Type: constants.IPRuleTypeAllow})
+			So(err, ShouldBeNil)
+			So(list.Data.List, ShouldBeEmpty)
+
+			_, err = NewDeleteIPRuleLogic(ctx, svcCtx).DeleteIPRule(&types.IPRuleIDRequest{ID: "198.51.100.7"})
+			So(err, ShouldBeNil)
+			So(svcCtx.IPAccessControl.Check(context.Background(), "198.51.100.7"), ShouldBeNil)
+
+			_, err = NewDeleteIPRuleLogic(ctx, svcCtx).DeleteIPRule(&types.IPRuleIDRequest{ID: "198.51.100.7"})
+			So(err.Error(), ShouldEqual, "规则不存在")
+		})
+
+		Convey("Allowlist mode should not let the operator lock themselves out", func() {
+			svcCtx := newSvcCtx(true)
+			ctx := adminFrom("192.0.2.1")
+
+			_, err := NewCreateIPRuleLogic(ctx, svcCtx).CreateIPRule(&types.IPRuleCreateRequest{Type: constants.IPRuleTypeAllow, CIDR: "198.51.100.0/24", Reason: "办公网"})
+			So(err, ShouldEqual, errIPRuleLockout)
+
+			own, err := NewCreateIPRuleLogic(ctx, svcCtx).CreateIPRule(&types.IPRuleCreateRequest{Type: constants.IPRuleTypeAllow, CIDR: "192.0.2.0/24", Reason: "运维网络"})
+			So(err, ShouldBeNil)
+			So(svcCtx.IPAccessControl.Check(context.Background(), "203.0.113.1"), ShouldEqual, auth.ErrIPNotAllowed)
+
+			office, err := NewCreateIPRuleLogic(ctx, svcCtx).CreateIPRule(&types.IPRuleCreateRequest{Type: constants.IPRuleTypeAllow, CIDR: "198.51.100.0/24", Reason: "办公网"})
+			So(err, ShouldBeNil)
+
+			// 剩余白名单不包含当前IP时不能删除
+			_, err = NewDeleteIPRuleLogic(ctx, svcCtx).DeleteIPRule(&types.IPRuleIDRequest{ID: own.Data.ID})
+			So(err, ShouldEqual, errIPRuleLockout)
+
+			list, err := NewGetIPRuleListLogic(ctx, svcCtx).GetIPRuleList(nil)
+			So(err, ShouldBeNil)
+			So(list.Data.AllowlistMode, ShouldBeTrue)
+			So(list.Data.List, ShouldHaveLength, 2)
+
+			// 白名单清空后白名单模式不再生效，可以删除最后一条
+			_, err = NewDeleteIPRuleLogic(ctx, svcCtx).DeleteIPRule(&types.IPRuleIDRequest{ID: office.Data.ID})
+			So(err, ShouldBeNil)
+			_, err = NewDeleteIPRuleLogic(ctx, svcCtx).DeleteIPRule(&types.IPRuleIDRequest{ID: own.Data.ID})
+			So(err, ShouldBeNil)
+			So(svcCtx.IPAccessControl.Check(context.Background(), "203.0.113.1"), ShouldBeNil)
+		})
+
+		Convey("Repeated login failures should block the IP temporarily", func() {
+			svcCtx := newSvcCtx(false)
+			loginFrom := NewLoginLogic(utils.WithClientInfo(context.Background(), utils.ClientInfo{IP: "198.51.100.9"}), svcCtx)
+			for i := 0; i < svcCtx.Config.Security.MaxLoginAttemptsPerIP; i++ {
+				loginFrom.recordIPFailure("198.51.100.9")
+			}
+			So(svcCtx.IPAccessControl.Check(context.Background(), "198.51.100.9"), ShouldEqual, auth.ErrIPBlocked)
+
+			blocks, err := svcCtx.IPAccessControl.TemporaryBlocks(context.Background())
+			So(err, ShouldBeNil)
+			So(blocks, ShouldHaveLength, 1)
+			So(blocks[0].CreatedBy, ShouldEqual, constants.IPRuleCreatorSystem)
+			So(mr.TTL(fmt.Sprintf(constants.CacheKeyIPBlock, "198.51.100.9")), ShouldEqual, 30*time.Minute)
+
+			// 白名单中的IP不会被自动封禁
+			rules[primitive.NewObjectID()] = &model.IPRule{Type: constants.IPRuleTypeAllow, CIDR: "198.51.100.10/32"}
+			svcCtx.IPAccessControl.Invalidate()
+			for i := 0; i < svcCtx.Config.Security.MaxLoginAttemptsPerIP; i++ {
+				loginFrom.recordIPFailure("198.51.100.10")
+			}
+			So(svcCtx.IPAccessControl.Check(context.Background(), "198.51.100.10"), ShouldBeNil)
+			So(mr.Exists(fmt.Sprintf(constants.CacheKeyLoginIPFail, "198.51.100.10")), ShouldBeFalse)
+		})
+	})
+}
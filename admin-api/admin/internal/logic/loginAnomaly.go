@@ -0,0 +1,111 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/threading"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/client/mailer"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+)
+
+// loginAlertMailTimeout 发送异常登录提醒邮件的超时时间
+const loginAlertMailTimeout = 30 * time.Second
+
+// loginAnomalyDescriptions 登录异常类型说明，用于提醒邮件
+var loginAnomalyDescriptions = map[string]string{
+	constants.LoginAnomalyNewCountry:       "从新的国家或地区登录",
+	constants.LoginAnomalyNewDevice:        "使用新的设备或浏览器登录",
+	constants.LoginAnomalyImpossibleTravel: "与上次登录地点相距过远，短时间内无法到达",
+}
+
+// shouldDetectLoginAnomalies 只检测真正的登录成功记录，登出记录和失败记录不检测
+func shouldDetectLoginAnomalies(svcCtx *svc.ServiceContext, loginLog *model.LoginLog) bool {
+	return svcCtx.Config.Security.LoginAnomaly.Enabled &&
+		loginLog.IsSuccess() && loginLog.LogoutAt == nil && loginLog.UserID != nil
+}
+
+// detectLoginAnomalies 将登录与用户最近的成功登录比较，并把异常标记写入登录日志
+// 查询失败时不影响登录
+func detectLoginAnomalies(ctx context.Context, svcCtx *svc.ServiceContext, loginLog *model.LoginLog) {
+	cfg := svcCtx.Config.Security.LoginAnomaly
+	history, err := svcCtx.LoginLogDAO.GetRecentSuccessfulLogins(ctx, *loginLog.UserID, cfg.HistorySize)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("查询最近登录记录失败: userID=%s, error=%v", loginLog.UserID.Hex(), err)
+		return
+	}
+
+	loginLog.Anomalies = auth.DetectLoginAnomalies(loginLog, history, float64(cfg.MaxTravelSpeed))
+}
+
+// notifyLoginAnomaly 异步向账号邮箱发送异常登录提醒，发送失败只记录日志
+func notifyLoginAnomaly(ctx context.Context, svcCtx *svc.ServiceContext, loginLog *model.LoginLog) {
+	user, err := svcCtx.UserDAO.GetByID(ctx, loginLog.UserID.Hex())
+	if err != nil || user == nil || user.Email == "" {
+		logx.WithContext(ctx).Errorf("获取异常登录提醒收件人失败: userID=%s, error=%v", loginLog.UserID.Hex(), err)
+		return
+	}
+
+	msg := buildLoginAlertMessage(user, loginLog)
+	mailCtx := context.WithoutCancel(ctx)
+	threading.GoSafe(func() {
+		ctx, cancel := context.WithTimeout(mailCtx, loginAlertMailTimeout)
+		defer cancel()
+		if err := svcCtx.Mailer.Send(ctx, msg); err != nil {
+			logx.WithContext(ctx).Errorf("发送异常登录提醒失败: userID=%s, error=%v", user.ID.Hex(), err)
+		}
+	})
+}
+
+// buildLoginAlertMessage 构建异常登录提醒邮件
+func buildLoginAlertMessage(user *model.User, loginLog *model.LoginLog) *mailer.Message {
+	reasons := make([]string, 0, len(loginLog.Anomalies))
+	for _, anomaly := range loginLog.Anomalies {
+		if description, ok := loginAnomalyDescriptions[anomaly]; ok {
+			reasons = append(reasons, description)
+		}
+	}
+
+	location := joinNonEmpty(", ", loginLog.City, loginLog.Region, loginLog.Country)
+	if location == "" {
+		location = "未知"
+	}
+	device := joinNonEmpty(" / ", loginLog.Browser, loginLog.OS)
+	if device == "" {
+		device = "未知"
+	}
+	name := user.DisplayName
+	if name == "" {
+		name = user.Username
+	}
+	loginAt := loginLog.LoginAt.Format("2006-01-02 15:04:05")
+	reason := strings.Join(reasons, "；")
+
+	return &mailer.Message{
+		To:      []string{user.Email},
+		Subject: "您的账号有一次异常登录",
+		Text: fmt.Sprintf("%s，您好：\n\n您的账号刚刚登录成功，但该登录与您平时的登录习惯不同：%s。\n\n时间：%s\nIP地址：%s\n位置：%s\n设备：%s\n\n如果这是您本人操作，请忽略此邮件；否则请立即修改密码并退出其他会话。\n",
+			name, reason, loginAt, loginLog.IPAddress, location, device),
+		HTML: fmt.Sprintf(`<p>%s，您好：</p><p>您的账号刚刚登录成功，但该登录与您平时的登录习惯不同：%s。</p><ul><li>时间：%s</li><li>IP地址：%s</li><li>位置：%s</li><li>设备：%s</li></ul><p>如果这是您本人操作，请忽略此邮件；否则请立即修改密码并退出其他会话。</p>`,
+			html.EscapeString(name), html.EscapeString(reason), loginAt, html.EscapeString(loginLog.IPAddress), html.EscapeString(location), html.EscapeString(device)),
+	}
+}
+
+// joinNonEmpty 用分隔符连接非空字符串
+func joinNonEmpty(sep string, values ...string) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, sep)
+}
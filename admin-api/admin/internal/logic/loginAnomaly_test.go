@@ -0,0 +1,178 @@
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/config"
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/client/mailer"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+)
+
+func TestLoginAnomalyDetection(t *testing.T) {
+	mockey.PatchConvey("Login Anomaly Detection Tests", t, func() {
+		mail := &recordingMailer{sent: make(chan *mailer.Message, 10)}
+		svcCtx := &svc.ServiceContext{
+			Config: config.Config{
+				Security: config.SecurityConfig{
+					LoginAnomaly: config.LoginAnomalyConfig{
+						Enabled:        true,
+						HistorySize:    20,
+						MaxTravelSpeed: 900,
+						NotifyUser:     true,
+					},
+				},
+			},
+			UserDAO:     &dao.UserDAO{},
+			LoginLogDAO: &dao.LoginLogDAO{},
+			Mailer:      mail,
+		}
+
+		user := &model.User{
+			ID:          primitive.NewObjectID(),
+			Username:    "writer",
+			DisplayName: "Writer",
+			Email:       "writer@example.com",
+		}
+		mockey.Mock((*dao.UserDAO).GetByID).Return(user, nil).Build()
+
+		// 用内存中的数据模拟登录日志集合
+		var stored []*model.LoginLog
+		mockey.Mock((*dao.LoginLogDAO).Create).To(func(_ *dao.LoginLogDAO, _ context.Context, log *model.LoginLog) error {
+			stored = append(stored, log)
+			return nil
+		}).Build()
+		mockey.Mock((*dao.LoginLogDAO).GetRecentSuccessfulLogins).To(func(_ *dao.LoginLogDAO, _ context.Context, userID primitive.ObjectID, limit int) ([]*model.LoginLog, error) {
+			var logs []*model.LoginLog
+			for i := len(stored) - 1; i >= 0 && len(logs) < limit; i-- {
+				log := stored[i]
+				if log.UserID != nil && *log.UserID == userID && log.IsSuccess() && log.LogoutAt == nil {
+					logs = append(logs, log)
+				}
+			}
+			return logs, nil
+		}).Build()
+
+		login := func(country, userAgent string, latitude, longitude float64, loginAt time.Time) *model.LoginLog {
+			log := &model.LoginLog{
+				UserID:      &user.ID,
+				Username:    user.Username,
+				LoginMethod: constants.LoginMethodUsername,
+				IPAddress:   "203.0.113.10",
+				UserAgent:   userAgent,
+				Status:      constants.LoginStatusSuccess,
+				Country:     country,
+				Latitude:    latitude,
+				Longitude:   longitude,
+				LoginAt:     loginAt,
+			}
+			So(createLoginLog(context.Background(), svcCtx, log), ShouldBeNil)
+			return log
+		}
+		const desktopUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+		const phoneUA = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+
+		Convey("Should mark unusual logins and notify the account owner", func() {
+			now := time.Now()
+			first := login("GB", desktopUA, 51.5074, -0.1278, now.Add(-time.Hour))
+			So(first.Anomalies, ShouldBeEmpty)
+
+			familiar := login("GB", desktopUA, 51.5074, -0.1278, now.Add(-30*time.Minute))
+			So(familiar.Anomalies, ShouldBeEmpty)
+
+			unusual := login("CN", phoneUA, 39.9042, 116.4074, now)
+			So(unusual.Anomalies, ShouldResemble, []string{
+				constants.LoginAnomalyNewCountry,
+				constants.LoginAnomalyNewDevice,
+				constants.LoginAnomalyImpossibleTravel,
+			})
+
+			select {
+			case msg := <-mail.sent:
+				So(msg.To, ShouldResemble, []string{user.Email})
+				So(msg.Text, ShouldContainSubstring, "Writer")
+				So(msg.Text, ShouldContainSubstring, "203.0.113.10")
+				So(msg.Text, ShouldContainSubstring, "使用新的设备或浏览器登录")
+			case <-time.After(2 * time.Second):
+				t.Fatal("login alert mail not sent")
+			}
+		})
+
+		Convey("Logout and failed records should not be checked", func() {
+			login("GB", desktopUA, 0, 0, time.Now().Add(-time.Hour))
+
+			now := time.Now()
+			logout := &model.LoginLog{
+				UserID:      &user.ID,
+				LoginMethod: constants.LoginMethodUsername,
+				IPAddress:   "198.51.100.1",
+				UserAgent:   phoneUA,
+				Status:      constants.LoginStatusSuccess,
+				Country:     "US",
+				LogoutAt:    &now,
+			}
+			So(createLoginLog(context.Background(), svcCtx, logout), ShouldBeNil)
+			So(logout.Anomalies, ShouldBeEmpty)
+
+			failed := &model.LoginLog{
+				Username:    user.Username,
+				LoginMethod: constants.LoginMethodUsername,
+				IPAddress:   "198.51.100.1",
+				UserAgent:   phoneUA,
+				Status:      constants.LoginStatusFailed,
+				FailReason:  "密码错误",
+				Country:     "US",
+			}
+			So(createLoginLog(context.Background(), svcCtx, failed), ShouldBeNil)
+			So(failed.Anomalies, ShouldBeEmpty)
+			So(mail.sent, ShouldBeEmpty)
+		})
+
+		Convey("Detection can be turned off", func() {
+			svcCtx.Config.Security.LoginAnomaly.Enabled = false
+			login("GB", desktopUA, 0, 0, time.Now().Add(-time.Hour))
+			So(login("CN", phoneUA, 0, 0, time.Now()).Anomalies, ShouldBeEmpty)
+		})
+
+		Convey("Alerts should list only flagged logins", func() {
+			var gotFilter map[string]interface{}
+			mockey.Mock((*dao.LoginLogDAO).List).To(func(_ *dao.LoginLogDAO, _ context.Context, filter map[string]interface{}, page, limit int) ([]*model.LoginLog, int64, error) {
+				gotFilter = filter
+				return []*model.LoginLog{{
+					ID:        primitive.NewObjectID(),
+					UserID:    &user.ID,
+					Username:  user.Username,
+					Status:    constants.LoginStatusSuccess,
+					Anomalies: []string{constants.LoginAnomalyNewDevice},
+					LoginAt:   time.Now(),
+				}}, 1, nil
+			}).Build()
+
+			resp, err := NewGetSecurityAlertsLogic(context.Background(), svcCtx).GetSecurityAlerts(&types.SecurityAlertsRequest{
+				Page:   1,
+				Limit:  20,
+				UserID: user.ID.Hex(),
+				Type:   constants.LoginAnomalyNewDevice,
+			})
+			So(err, ShouldBeNil)
+			So(gotFilter["anomalous"], ShouldEqual, true)
+			So(gotFilter["anomaly"], ShouldEqual, constants.LoginAnomalyNewDevice)
+			So(gotFilter["userId"], ShouldEqual, user.ID.Hex())
+			So(resp.Data.List, ShouldHaveLength, 1)
+			So(resp.Data.List[0].Anomalies, ShouldResemble, []string{constants.LoginAnomalyNewDevice})
+			So(resp.Data.Pagination.Total, ShouldEqual, 1)
+
+			_, err = NewGetSecurityAlertsLogic(context.Background(), svcCtx).GetSecurityAlerts(&types.SecurityAlertsRequest{Page: 1, Limit: 20, StartTime: "yesterday"})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,43 @@
+package logic
+
+import (
+	"context"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// createLoginLog 补全设备信息和地理位置后写入登录日志
+// 成功登录会先与用户最近的登录比较，异常登录写入标记并按配置提醒账号所有者
+func createLoginLog(ctx context.Context, svcCtx *svc.ServiceContext, loginLog *model.LoginLog) error {
+	if loginLog.DeviceType == "" && loginLog.Browser == "" && loginLog.OS == "" {
+		ua := utils.ParseUserAgent(loginLog.UserAgent)
+		loginLog.UpdateDeviceInfo(ua.DeviceType, ua.BrowserString(), ua.OSString())
+	}
+
+	if loginLog.Country == "" {
+		location := svcCtx.GeoIP.Lookup(loginLog.IPAddress)
+		loginLog.UpdateLocation(location.Country, location.Region, location.City)
+		loginLog.UpdateCoordinates(location.Latitude, location.Longitude)
+	}
+
+	detect := shouldDetectLoginAnomalies(svcCtx, loginLog)
+	if detect {
+		detectLoginAnomalies(ctx, svcCtx, loginLog)
+	}
+
+	if err := svcCtx.LoginLogDAO.Create(ctx, loginLog); err != nil {
+		return err
+	}
+
+	if detect && loginLog.IsAnomalous() {
+		logx.WithContext(ctx).Infof("检测到异常登录: userID=%s, ip=%s, anomalies=%v", loginLog.UserID.Hex(), loginLog.IPAddress, loginLog.Anomalies)
+		if svcCtx.Config.Security.LoginAnomaly.NotifyUser {
+			notifyLoginAnomaly(ctx, svcCtx, loginLog)
+		}
+	}
+	return nil
+}
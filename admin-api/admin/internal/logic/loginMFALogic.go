@@ -0,0 +1,126 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type LoginMFALogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 完成两步验证登录
+func NewLoginMFALogic(ctx context.Context, svcCtx *svc.ServiceContext) *LoginMFALogic {
+	return &LoginMFALogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *LoginMFALogic) LoginMFA(req *types.LoginMFARequest) (resp *types.LoginResponse, err error) {
+	// 1. 参数验证
+	if req == nil || req.MFAToken == "" {
+		return nil, errInvalidMFAChallenge
+	}
+	code := strings.TrimSpace(req.Code)
+	if code == "" {
+		return nil, errors.New("验证码不能为空")
+	}
+
+	// 2. 查询挑战
+	userID, err := loadMFAChallenge(l.ctx, l.svcCtx.Redis, req.MFAToken)
+	if err != nil {
+		l.Logger.Errorf("查询两步验证挑战失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if userID == "" {
+		return nil, errInvalidMFAChallenge
+	}
+
+	// 3. 获取用户并重新检查状态，挑战期间账户可能已被停用
+	user, err := l.svcCtx.UserDAO.GetByID(l.ctx, userID)
+	if err != nil {
+		l.Logger.Errorf("获取用户信息失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if user == nil {
+		return nil, errInvalidMFAChallenge
+	}
+	loginLogic := NewLoginLogic(l.ctx, l.svcCtx)
+	if err := loginLogic.checkUserStatus(user); err != nil {
+		return nil, err
+	}
+
+	clientIP := loginLogic.getClientIP()
+	if err := loginLogic.checkIPFailures(clientIP); err != nil {
+		return nil, err
+	}
+
+	// 4. 校验TOTP验证码或恢复码，挑战消费成功前不消费验证码
+	loginMethod, step, ok, err := checkSecondFactor(l.svcCtx, user, code)
+	if err != nil {
+		l.Logger.Errorf("校验两步验证码失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if !ok {
+		loginLogic.recordLoginFailureWithMethod(user.Username, clientIP, "两步验证码错误", loginMethod)
+		loginLogic.recordIPFailure(clientIP)
+		exhausted, err := recordMFAChallengeFailure(l.ctx, l.svcCtx.Redis, req.MFAToken)
+		expired := errors.Is(err, errInvalidMFAChallenge)
+		if err != nil && !expired {
+			l.Logger.Errorf("记录两步验证失败次数失败: %v", err)
+		}
+
+		// 验证码错误同样计入账户失败次数，账户被锁定时作废当前挑战
+		if lockErr := loginLogic.registerLoginFailure(user); lockErr != nil {
+			if _, err := consumeMFAChallenge(l.ctx, l.svcCtx.Redis, req.MFAToken); err != nil {
+				l.Logger.Errorf("作废两步验证挑战失败: %v", err)
+			}
+			return nil, lockErr
+		}
+		if expired {
+			return nil, errInvalidMFAChallenge
+		}
+		if exhausted {
+			return nil, errors.New("验证失败次数过多，请重新登录")
+		}
+		return nil, errors.New("验证码错误")
+	}
+
+	// 5. 先原子消费挑战，同一挑战只能完成一次登录，并发请求不会各自消费一个验证码
+	consumed, err := consumeMFAChallenge(l.ctx, l.svcCtx.Redis, req.MFAToken)
+	if err != nil {
+		l.Logger.Errorf("消费两步验证挑战失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if !consumed {
+		return nil, errInvalidMFAChallenge
+	}
+
+	// 6. 再消费TOTP时间步或恢复码，已被其他登录使用时本次登录失败
+	used, err := useSecondFactor(l.ctx, l.svcCtx, user, loginMethod, step, code)
+	if err != nil {
+		l.Logger.Errorf("消费两步验证码失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if !used {
+		loginLogic.recordLoginFailureWithMethod(user.Username, clientIP, "两步验证码已被使用", loginMethod)
+		loginLogic.recordIPFailure(clientIP)
+		if lockErr := loginLogic.registerLoginFailure(user); lockErr != nil {
+			return nil, lockErr
+		}
+		return nil, errors.New("验证码已被使用，请重新登录")
+	}
+
+	// 7. 签发令牌并完成登录
+	return loginLogic.completeLogin(user, clientIP, loginMethod)
+}
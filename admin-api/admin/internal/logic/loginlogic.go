@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strconv"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -41,8 +40,8 @@ func (l *LoginLogic) Login(req *types.LoginRequest) (resp *types.LoginResponse,
 	// 2. 获取客户端IP地址
 	clientIP := l.getClientIP()
 
-	// 3. 检查登录失败次数限制
-	if err := l.checkLoginAttempts(req.Username, clientIP); err != nil {
+	// 3. 检查该IP的登录失败次数，跨用户名累计
+	if err := l.checkIPFailures(clientIP); err != nil {
 		return nil, err
 	}
 
@@ -55,87 +54,119 @@ func (l *LoginLogic) Login(req *types.LoginRequest) (resp *types.LoginResponse,
 
 	// 5. 验证用户存在性和状态
 	if user == nil {
-		// 记录失败日志并增加失败次数
+		// 记录失败日志并增加IP失败次数
 		l.recordLoginFailure(req.Username, clientIP, "用户不存在")
-		l.incrementLoginAttempts(req.Username, clientIP)
+		l.recordIPFailure(clientIP)
 		return nil, errors.New("用户名或密码错误")
 	}
 
 	// 6. 检查用户状态
 	if err := l.checkUserStatus(user); err != nil {
 		l.recordLoginFailure(user.Username, clientIP, err.Error())
+		l.recordIPFailure(clientIP)
 		return nil, err
 	}
 
 	// 7. 验证密码
 	if utils.VerifyPassword(req.Password, user.PasswordHash) != nil {
-		// 记录失败日志并增加失败次数
+		// 记录失败日志并增加IP失败次数
 		l.recordLoginFailure(user.Username, clientIP, "密码错误")
-		l.incrementLoginAttempts(req.Username, clientIP)
+		l.recordIPFailure(clientIP)
 
-		// 增加用户的登录失败计数
-		if err := l.svcCtx.UserDAO.IncrementLoginFailCount(l.ctx, user.ID.Hex()); err != nil {
-			l.Logger.Errorf("更新用户登录失败次数失败: %v", err)
-		}
-
-		// 检查是否需要锁定账户
-		if err := l.checkAndLockAccount(user, req.Username, clientIP); err != nil {
+		// 增加账户失败次数，达到阈值时逐级锁定账户
+		if err := l.registerLoginFailure(user); err != nil {
 			return nil, err
 		}
 
 		return nil, errors.New("用户名或密码错误")
 	}
 
-	// 8. 登录成功，生成JWT Token
-	// 使用与go-zero JWT中间件兼容的token格式
-	jwtManager := utils.NewJWTManager(l.svcCtx.Config.Auth.AccessSecret, "heimdall-admin")
-	accessToken, err := jwtManager.GenerateGoZeroCompatibleToken(user.ID.Hex(), user.Username, user.Role)
+	// 8. 已启用两步验证时返回挑战令牌，由 /auth/login/mfa 完成登录
+	if user.TwoFactorEnabled {
+		return l.startMFAChallenge(user)
+	}
+
+	// 9. 登录成功，签发令牌并完成登录
+	return l.completeLogin(user, clientIP, constants.LoginMethodUsername)
+}
+
+// startMFAChallenge 创建两步验证挑战，此时不签发令牌
+func (l *LoginLogic) startMFAChallenge(user *model.User) (*types.LoginResponse, error) {
+	mfaToken, err := issueMFAChallenge(l.ctx, l.svcCtx.Redis, user.ID.Hex())
 	if err != nil {
-		l.Logger.Errorf("生成JWT Token失败: %v", err)
+		l.Logger.Errorf("创建两步验证挑战失败: %v", err)
 		return nil, errors.New("系统错误，请稍后重试")
 	}
 
-	// 生成refresh token（使用原有方法）
-	tokenPair, err := jwtManager.GenerateToken(user.ID.Hex(), user.Username, user.Role)
+	return &types.LoginResponse{
+		Code:      200,
+		Message:   "请输入两步验证码",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.LoginData{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+			ExpiresIn:   int(constants.CacheTTLMFAChallenge.Seconds()),
+			User: types.UserInfo{
+				ID:               user.ID.Hex(),
+				Username:         user.Username,
+				DisplayName:      user.DisplayName,
+				TwoFactorEnabled: true,
+			},
+		},
+	}, nil
+}
+
+// completeLogin 签发令牌、登记会话并记录登录成功，密码登录和两步验证登录共用
+func (l *LoginLogic) completeLogin(user *model.User, clientIP, loginMethod string) (*types.LoginResponse, error) {
+	// 1. 签发令牌并登记会话
+	tokens, err := startUserSession(l.ctx, l.svcCtx, user)
 	if err != nil {
-		l.Logger.Errorf("生成Refresh Token失败: %v", err)
+		l.Logger.Errorf("签发登录令牌失败: %v", err)
 		return nil, errors.New("系统错误，请稍后重试")
 	}
 
-	// 9. 清除登录失败次数缓存
-	l.clearLoginAttempts(req.Username, clientIP)
-
-	// 10. 更新用户登录信息
+	// 2. 更新用户登录信息，清零登录失败次数并解除到期的锁定
 	if err := l.svcCtx.UserDAO.UpdateLoginInfo(l.ctx, user.ID.Hex(), clientIP); err != nil {
 		l.Logger.Errorf("更新用户登录信息失败: %v", err)
 		// 这个错误不阻止登录流程
 	}
+	l.resetLoginFailures(user)
+
+	// 3. 记录成功登录日志
+	l.recordLoginSuccess(user, clientIP, tokens.FamilyID, loginMethod)
 
-	// 11. 记录成功登录日志
-	l.recordLoginSuccess(user, clientIP)
+	// 4. 检查角色是否要求启用两步验证
+	mfaSetupRequired := false
+	if !user.TwoFactorEnabled {
+		required, err := mfaRequiredForRole(l.ctx, l.svcCtx, user.Role)
+		if err != nil {
+			l.Logger.Errorf("查询两步验证策略失败: %v", err)
+		}
+		mfaSetupRequired = required
+	}
 
-	// 12. 构造响应
-	resp = &types.LoginResponse{
+	// 5. 构造响应
+	return &types.LoginResponse{
 		Code:      200,
 		Message:   "登录成功",
 		Timestamp: time.Now().Format(time.RFC3339),
 		Data: types.LoginData{
-			Token:        accessToken,            // 使用go-zero兼容的token
-			RefreshToken: tokenPair.RefreshToken, // 使用原有的refresh token
-			ExpiresIn:    int(tokenPair.ExpiresAt.Sub(time.Now()).Seconds()),
+			Token:            tokens.AccessToken,
+			RefreshToken:     tokens.RefreshToken,
+			ExpiresIn:        int(time.Until(tokens.AccessExpiresAt).Seconds()),
+			MFASetupRequired: mfaSetupRequired,
 			User: types.UserInfo{
-				ID:          user.ID.Hex(),
-				Username:    user.Username,
-				Email:       user.Email,
-				DisplayName: user.DisplayName,
-				Role:        user.Role,
-				Status:      user.Status,
-				CreatedAt:   user.CreatedAt.Format(time.RFC3339),
+				ID:               user.ID.Hex(),
+				Username:         user.Username,
+				Email:            user.Email,
+				DisplayName:      user.DisplayName,
+				Role:             user.Role,
+				Status:           user.Status,
+				TwoFactorEnabled: user.TwoFactorEnabled,
+				CreatedAt:        user.CreatedAt.Format(time.RFC3339),
 			},
 		},
-	}
-
-	return resp, nil
+	}, nil
 }
 
 // validateRequest 验证登录请求参数
@@ -154,150 +185,180 @@ func (l *LoginLogic) validateRequest(req *types.LoginRequest) error {
 
 // getClientIP 获取客户端IP地址
 func (l *LoginLogic) getClientIP() string {
-	// 这里简化处理，实际应该从HTTP头中获取
-	// 在go-zero中可以通过logx.FromContext获取
-	return "127.0.0.1" // 临时固定值
+	return utils.ClientInfoFromContext(l.ctx).IP
 }
 
-// checkLoginAttempts 检查登录失败次数限制
-func (l *LoginLogic) checkLoginAttempts(username, clientIP string) error {
-	// 构造Redis键
-	key := fmt.Sprintf("%s%s:%s", l.svcCtx.Config.Cache.LoginAttempts.Prefix, username, clientIP)
+// getUserAgent 获取客户端User-Agent
+func (l *LoginLogic) getUserAgent() string {
+	return utils.ClientInfoFromContext(l.ctx).UserAgent
+}
 
-	// 获取当前失败次数
-	result := l.svcCtx.Redis.Get(l.ctx, key)
-	if result.Err() != nil && result.Err() != redis.Nil {
-		l.Logger.Errorf("获取登录失败次数失败: %v", result.Err())
+// checkIPFailures 检查客户端IP的登录失败次数，超过上限时拒绝该IP的所有登录尝试
+func (l *LoginLogic) checkIPFailures(clientIP string) error {
+	maxAttempts := l.svcCtx.Config.Security.MaxLoginAttemptsPerIP
+	if clientIP == "" || maxAttempts <= 0 {
+		return nil
+	}
+
+	key := fmt.Sprintf(constants.CacheKeyLoginIPFail, clientIP)
+	attempts, err := l.svcCtx.Redis.Get(l.ctx, key).Int()
+	if err != nil {
+		if err != redis.Nil {
+			l.Logger.Errorf("获取IP登录失败次数失败: %v", err)
+		}
 		return nil // 不因为Redis错误阻止登录
 	}
+	if attempts < maxAttempts {
+		return nil
+	}
 
-	if result.Err() == redis.Nil {
-		return nil // 没有失败记录
+	remaining, err := l.svcCtx.Redis.TTL(l.ctx, key).Result()
+	if err != nil || remaining <= 0 {
+		remaining = time.Duration(l.svcCtx.Config.Security.LoginIPBlockDuration) * time.Second
 	}
+	return fmt.Errorf("登录失败次数过多，请%s后再试", formatLockDuration(remaining))
+}
 
-	attempts, err := strconv.Atoi(result.Val())
-	if err != nil {
-		l.Logger.Errorf("解析登录失败次数失败: %v", err)
-		return nil
+// recordIPFailure 增加客户端IP的登录失败次数，不随登录成功清零，避免攻击者用自有账号重置计数
+// 达到上限时临时封禁该IP，封禁期间该IP无法访问任何管理接口
+func (l *LoginLogic) recordIPFailure(clientIP string) {
+	if clientIP == "" {
+		return
 	}
 
-	// 检查是否超过最大失败次数
-	if attempts >= l.svcCtx.Config.Security.MaxLoginAttempts {
-		lockoutMinutes := l.svcCtx.Config.Security.LoginLockoutDuration / 60
-		return fmt.Errorf("登录失败次数过多，请%d分钟后再试", lockoutMinutes)
+	// 白名单中的IP不计数也不封禁
+	access := l.svcCtx.IPAccessControl
+	if access != nil {
+		if allowlisted, err := access.IsAllowlisted(l.ctx, clientIP); err == nil && allowlisted {
+			return
+		}
 	}
 
-	return nil
+	key := fmt.Sprintf(constants.CacheKeyLoginIPFail, clientIP)
+	count, err := l.svcCtx.Redis.Incr(l.ctx, key).Result()
+	if err != nil {
+		l.Logger.Errorf("增加IP登录失败次数失败: %v", err)
+		return
+	}
+	blockDuration := time.Duration(l.svcCtx.Config.Security.LoginIPBlockDuration) * time.Second
+	l.svcCtx.Redis.Expire(l.ctx, key, blockDuration)
+
+	if access != nil && count == int64(l.svcCtx.Config.Security.MaxLoginAttemptsPerIP) {
+		if _, err := access.BlockTemporarily(l.ctx, clientIP, "登录失败次数过多", constants.IPRuleCreatorSystem, "", blockDuration); err != nil {
+			l.Logger.Errorf("临时封禁IP失败: ip=%s, error=%v", clientIP, err)
+			return
+		}
+		l.Logger.Infof("IP登录失败次数过多，已临时封禁: ip=%s, duration=%s", clientIP, blockDuration)
+	}
 }
 
 // checkUserStatus 检查用户状态
 func (l *LoginLogic) checkUserStatus(user *model.User) error {
-	// 检查用户是否已被禁用
-	if !user.IsActive() {
-		return errors.New("账户已被禁用")
+	// 登录失败导致的锁定到期后允许再次尝试，失败次数保留用于升级下一次锁定时长
+	if user.IsLockExpired() {
+		return nil
 	}
 
 	// 检查用户是否被锁定
 	if user.IsLocked() {
-		if user.LockedUntil != nil {
-			remainingMinutes := int(time.Until(*user.LockedUntil).Minutes())
-			if remainingMinutes > 0 {
-				return fmt.Errorf("账户已被锁定，还需等待%d分钟", remainingMinutes)
-			}
+		if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+			return fmt.Errorf("账户已被锁定，请%s后再试", formatLockDuration(time.Until(*user.LockedUntil)))
 		}
 		return errors.New("账户已被锁定")
 	}
 
+	// 检查用户是否已被禁用
+	if !user.IsActive() {
+		return errors.New("账户已被禁用")
+	}
+
 	return nil
 }
 
-// incrementLoginAttempts 增加登录失败次数
-func (l *LoginLogic) incrementLoginAttempts(username, clientIP string) {
-	key := fmt.Sprintf("%s%s:%s", l.svcCtx.Config.Cache.LoginAttempts.Prefix, username, clientIP)
+// registerLoginFailure 累加账户登录失败次数，按 constants.GetLockDurationByFailCount 逐级锁定账户
+// 失败次数记录在用户上而不是按IP计数，更换IP不能绕过锁定
+func (l *LoginLogic) registerLoginFailure(user *model.User) error {
+	failCount, err := l.svcCtx.UserDAO.IncrementLoginFailCount(l.ctx, user.ID.Hex())
+	if err != nil {
+		l.Logger.Errorf("更新用户登录失败次数失败: %v", err)
+		return nil
+	}
 
-	// 增加计数
-	result := l.svcCtx.Redis.Incr(l.ctx, key)
-	if result.Err() != nil {
-		l.Logger.Errorf("增加登录失败次数失败: %v", result.Err())
-		return
+	lockMinutes := constants.GetLockDurationByFailCount(failCount)
+	if lockMinutes == 0 {
+		return nil
 	}
 
-	// 设置过期时间
-	l.svcCtx.Redis.Expire(l.ctx, key, time.Duration(l.svcCtx.Config.Cache.LoginAttempts.TTL)*time.Second)
+	lockDuration := time.Duration(lockMinutes) * time.Minute
+	if err := l.svcCtx.UserDAO.LockUser(l.ctx, user.ID.Hex(), time.Now().Add(lockDuration)); err != nil {
+		l.Logger.Errorf("锁定用户账户失败: %v", err)
+		return nil
+	}
+
+	l.Logger.Infof("登录失败次数过多，锁定账户: userID=%s, failCount=%d, duration=%s", user.ID.Hex(), failCount, lockDuration)
+	return fmt.Errorf("登录失败次数过多，账户已被锁定%s", formatLockDuration(lockDuration))
 }
 
-// clearLoginAttempts 清除登录失败次数
-func (l *LoginLogic) clearLoginAttempts(username, clientIP string) {
-	key := fmt.Sprintf("%s%s:%s", l.svcCtx.Config.Cache.LoginAttempts.Prefix, username, clientIP)
+// resetLoginFailures 登录成功后清零失败次数并解除到期的锁定
+func (l *LoginLogic) resetLoginFailures(user *model.User) {
+	if user.LoginFailCount == 0 && user.LockedUntil == nil {
+		return
+	}
 
-	if err := l.svcCtx.Redis.Del(l.ctx, key).Err(); err != nil {
-		l.Logger.Errorf("清除登录失败次数失败: %v", err)
+	user.ResetLoginFailCount()
+	if err := l.svcCtx.UserDAO.ResetLoginFailCount(l.ctx, user.ID.Hex()); err != nil {
+		l.Logger.Errorf("重置用户登录失败次数失败: %v", err)
 	}
 }
 
-// checkAndLockAccount 检查并锁定账户
-func (l *LoginLogic) checkAndLockAccount(user *model.User, username, clientIP string) error {
-	// 获取当前Redis中的失败次数
-	key := fmt.Sprintf("%s%s:%s", l.svcCtx.Config.Cache.LoginAttempts.Prefix, username, clientIP)
-	result := l.svcCtx.Redis.Get(l.ctx, key)
-
-	var attempts int
-	if result.Err() == nil {
-		var err error
-		attempts, err = strconv.Atoi(result.Val())
-		if err != nil {
-			attempts = 1
-		}
-	} else {
-		attempts = 1
+// formatLockDuration 将锁定剩余时间格式化为整小时或向上取整的分钟数
+func formatLockDuration(d time.Duration) string {
+	minutes := int((d + time.Minute - 1) / time.Minute)
+	if minutes < 1 {
+		minutes = 1
 	}
-
-	// 如果达到最大失败次数，锁定账户
-	if attempts >= l.svcCtx.Config.Security.MaxLoginAttempts {
-		lockDuration := time.Duration(l.svcCtx.Config.Security.LoginLockoutDuration) * time.Second
-		lockUntil := time.Now().Add(lockDuration)
-
-		if err := l.svcCtx.UserDAO.LockUser(l.ctx, user.ID.Hex(), lockUntil); err != nil {
-			l.Logger.Errorf("锁定用户账户失败: %v", err)
-		}
-
-		lockoutMinutes := l.svcCtx.Config.Security.LoginLockoutDuration / 60
-		return fmt.Errorf("登录失败次数过多，账户已被锁定%d分钟", lockoutMinutes)
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%d小时", minutes/60)
 	}
-
-	return nil
+	return fmt.Sprintf("%d分钟", minutes)
 }
 
 // recordLoginFailure 记录登录失败日志
 func (l *LoginLogic) recordLoginFailure(username, clientIP, reason string) {
+	l.recordLoginFailureWithMethod(username, clientIP, reason, constants.LoginMethodUsername)
+}
+
+// recordLoginFailureWithMethod 按登录方式记录登录失败日志
+func (l *LoginLogic) recordLoginFailureWithMethod(username, clientIP, reason, loginMethod string) {
 	loginLog := &model.LoginLog{
 		Username:    username,
 		IPAddress:   clientIP,
-		UserAgent:   "Admin Panel", // 简化处理
-		LoginMethod: "password",    // 添加登录方式
+		UserAgent:   l.getUserAgent(),
+		LoginMethod: loginMethod,
 		Status:      constants.LoginStatusFailed,
 		FailReason:  reason,
 		LoginAt:     time.Now(),
 	}
 
-	if err := l.svcCtx.LoginLogDAO.Create(l.ctx, loginLog); err != nil {
+	if err := createLoginLog(l.ctx, l.svcCtx, loginLog); err != nil {
 		l.Logger.Errorf("记录登录失败日志失败: %v", err)
 	}
 }
 
 // recordLoginSuccess 记录登录成功日志
-func (l *LoginLogic) recordLoginSuccess(user *model.User, clientIP string) {
+func (l *LoginLogic) recordLoginSuccess(user *model.User, clientIP, sessionID, loginMethod string) {
 	loginLog := &model.LoginLog{
 		UserID:      &user.ID,
 		Username:    user.Username,
 		IPAddress:   clientIP,
-		UserAgent:   "Admin Panel", // 简化处理
-		LoginMethod: "password",    // 添加登录方式
+		UserAgent:   l.getUserAgent(),
+		LoginMethod: loginMethod,
 		Status:      constants.LoginStatusSuccess,
+		SessionID:   sessionID,
 		LoginAt:     time.Now(),
 	}
 
-	if err := l.svcCtx.LoginLogDAO.Create(l.ctx, loginLog); err != nil {
+	if err := createLoginLog(l.ctx, l.svcCtx, loginLog); err != nil {
 		l.Logger.Errorf("记录登录成功日志失败: %v", err)
 	}
 }
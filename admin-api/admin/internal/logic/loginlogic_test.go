@@ -3,9 +3,11 @@ package logic
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/bytedance/mockey"
 	"github.com/go-redis/redis/v8"
 	. "github.com/smartystreets/goconvey/convey"
@@ -35,14 +37,8 @@ func TestLoginLogic_Login(t *testing.T) {
 				RefreshExpire: 7200,
 			},
 			Security: config.SecurityConfig{
-				MaxLoginAttempts:     5,
-				LoginLockoutDuration: 1800,
-			},
-			Cache: config.CacheConfig{
-				LoginAttempts: config.CacheItem{
-					Prefix: "login_attempts:",
-					TTL:    1800,
-				},
+				MaxLoginAttemptsPerIP: 20,
+				LoginIPBlockDuration:  1800,
 			},
 		}
 
@@ -136,8 +132,8 @@ func TestLoginLogic_Login(t *testing.T) {
 			mock5 := mockey.Mock((*redis.Client).Expire).Return(redis.NewBoolResult(true, nil)).Build()
 			defer mock5.UnPatch()
 
-			// Mock UserDAO.IncrementLoginFailCount
-			mock6 := mockey.Mock((*dao.UserDAO).IncrementLoginFailCount).Return(nil).Build()
+			// Mock UserDAO.IncrementLoginFailCount - 第2次失败不触发锁定
+			mock6 := mockey.Mock((*dao.UserDAO).IncrementLoginFailCount).Return(2, nil).Build()
 			defer mock6.UnPatch()
 
 			// Mock LoginLogDAO.Create for failed login log
@@ -156,14 +152,16 @@ func TestLoginLogic_Login(t *testing.T) {
 				Password: "wrongpassword",
 			}
 
-			// 创建测试用户，已经有4次失败
+			// 创建测试用户，已经有4次失败，前一次锁定已到期
+			lockedUntil := time.Now().Add(-time.Minute)
 			testUser := &model.User{
 				ID:             primitive.NewObjectID(),
 				Username:       "testuser",
 				PasswordHash:   "$2a$12$valid.hashed.password.here",
-				Status:         constants.UserStatusActive,
+				Status:         constants.UserStatusLocked,
 				Role:           constants.UserRoleAuthor,
 				LoginFailCount: 4,
+				LockedUntil:    &lockedUntil,
 			}
 
 			// Mock UserDAO.GetByUsername
@@ -184,8 +182,8 @@ func TestLoginLogic_Login(t *testing.T) {
 			mock5 := mockey.Mock((*redis.Client).Expire).Return(redis.NewBoolResult(true, nil)).Build()
 			defer mock5.UnPatch()
 
-			// Mock UserDAO methods for account locking
-			mock6 := mockey.Mock((*dao.UserDAO).IncrementLoginFailCount).Return(nil).Build()
+			// Mock UserDAO methods for account locking - 第5次失败锁定1小时
+			mock6 := mockey.Mock((*dao.UserDAO).IncrementLoginFailCount).Return(5, nil).Build()
 			defer mock6.UnPatch()
 
 			mock7 := mockey.Mock((*dao.UserDAO).LockUser).Return(nil).Build()
@@ -197,7 +195,7 @@ func TestLoginLogic_Login(t *testing.T) {
 
 			resp, err := loginLogic.Login(req)
 			So(err, ShouldNotBeNil)
-			So(err.Error(), ShouldEqual, "登录失败次数过多，账户已被锁定30分钟")
+			So(err.Error(), ShouldEqual, "登录失败次数过多，账户已被锁定1小时")
 			So(resp, ShouldBeNil)
 		})
 
@@ -290,10 +288,14 @@ func TestLoginLogic_Login(t *testing.T) {
 			mock3 := mockey.Mock((*utils.JWTManager).GenerateToken).Return(tokenPair, nil).Build()
 			defer mock3.UnPatch()
 
-			// Mock Redis operations to clear login attempts
-			mock4 := mockey.Mock((*redis.Client).Del).Return(redis.NewIntResult(1, nil)).Build()
+			// Mock Redis operations for IP failure check
+			mock4 := mockey.Mock((*redis.Client).Get).Return(redis.NewStringResult("", redis.Nil)).Build()
 			defer mock4.UnPatch()
 
+			// Mock UserDAO.ResetLoginFailCount，成功登录后清零失败次数
+			mock7 := mockey.Mock((*dao.UserDAO).ResetLoginFailCount).Return(nil).Build()
+			defer mock7.UnPatch()
+
 			// Mock UserDAO operations for successful login
 			mock5 := mockey.Mock((*dao.UserDAO).UpdateLoginInfo).Return(nil).Build()
 			defer mock5.UnPatch()
@@ -365,3 +367,139 @@ func TestLoginLogic_Login(t *testing.T) {
 		})
 	})
 }
+
+func TestLoginLogic_Lockout(t *testing.T) {
+	mockey.PatchConvey("Login Lockout Tests", t, func() {
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+
+		cfg := config.Config{
+			Auth: struct {
+				AccessSecret string
+				AccessExpire int64
+			}{
+				AccessSecret: "test-secret",
+				AccessExpire: 3600,
+			},
+			JWTBusiness: config.JWTBusinessConfig{
+				RefreshExpire: 7200,
+			},
+			Security: config.SecurityConfig{
+				MaxLoginAttemptsPerIP: 20,
+				LoginIPBlockDuration:  1800,
+			},
+		}
+		svcCtx := &svc.ServiceContext{
+			Config:  cfg,
+			UserDAO: &dao.UserDAO{},
+			Redis:   rdb,
+		}
+
+		passwordHash, _ := utils.HashPassword("Current#Pass1")
+		testUser := &model.User{
+			ID:           primitive.NewObjectID(),
+			Username:     "writer",
+			Email:        "writer@example.com",
+			PasswordHash: passwordHash,
+			Role:         constants.UserRoleAuthor,
+			Status:       constants.UserStatusActive,
+		}
+
+		// 用内存中的testUser模拟数据库
+		mockey.Mock((*dao.UserDAO).GetByUsername).To(func(_ *dao.UserDAO, _ context.Context, username string) (*model.User, error) {
+			if username != testUser.Username {
+				return nil, nil
+			}
+			copied := *testUser
+			return &copied, nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).IncrementLoginFailCount).To(func(_ *dao.UserDAO, _ context.Context, _ string) (int, error) {
+			testUser.LoginFailCount++
+			return testUser.LoginFailCount, nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).LockUser).To(func(_ *dao.UserDAO, _ context.Context, _ string, lockedUntil time.Time) error {
+			testUser.Status = constants.UserStatusLocked
+			testUser.LockedUntil = &lockedUntil
+			return nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).ResetLoginFailCount).To(func(_ *dao.UserDAO, _ context.Context, _ string) error {
+			testUser.ResetLoginFailCount()
+			return nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).UpdateLoginInfo).Return(nil).Build()
+		mockey.Mock(createLoginLog).Return(nil).Build()
+
+		login := func(ip, username, password string) (*types.LoginResponse, error) {
+			ctx := utils.WithClientInfo(context.Background(), utils.ClientInfo{IP: ip})
+			return NewLoginLogic(ctx, svcCtx).Login(&types.LoginRequest{Username: username, Password: password})
+		}
+		expireLock := func() {
+			past := time.Now().Add(-time.Second)
+			testUser.LockedUntil = &past
+		}
+
+		Convey("Lock duration should escalate with the account's fail count across IPs", func() {
+			var err error
+			for i := 1; i <= 3; i++ {
+				_, err = login(fmt.Sprintf("203.0.113.%d", i), "writer", "wrong")
+			}
+			So(err.Error(), ShouldEqual, "登录失败次数过多，账户已被锁定15分钟")
+
+			// 锁定期间即使密码正确也不能登录
+			_, err = login("203.0.113.9", "writer", "Current#Pass1")
+			So(err.Error(), ShouldStartWith, "账户已被锁定")
+
+			expireLock()
+			_, err = login("203.0.113.4", "writer", "wrong")
+			So(err.Error(), ShouldEqual, "登录失败次数过多，账户已被锁定15分钟")
+
+			expireLock()
+			_, err = login("203.0.113.5", "writer", "wrong")
+			So(err.Error(), ShouldEqual, "登录失败次数过多，账户已被锁定1小时")
+
+			testUser.LoginFailCount = 9
+			expireLock()
+			_, err = login("203.0.113.6", "writer", "wrong")
+			So(err.Error(), ShouldEqual, "登录失败次数过多，账户已被锁定24小时")
+		})
+
+		Convey("Successful login should reset the fail count and expired lock", func() {
+			_, err := login("203.0.113.1", "writer", "wrong")
+			So(err.Error(), ShouldEqual, "用户名或密码错误")
+			So(testUser.LoginFailCount, ShouldEqual, 1)
+
+			resp, err := login("203.0.113.1", "writer", "Current#Pass1")
+			So(err, ShouldBeNil)
+			So(resp.Data.User.Status, ShouldEqual, constants.UserStatusActive)
+			So(testUser.LoginFailCount, ShouldEqual, 0)
+
+			testUser.LoginFailCount = 3
+			testUser.Status = constants.UserStatusLocked
+			expireLock()
+			_, err = login("203.0.113.1", "writer", "Current#Pass1")
+			So(err, ShouldBeNil)
+			So(testUser.Status, ShouldEqual, constants.UserStatusActive)
+			So(testUser.LockedUntil, ShouldBeNil)
+		})
+
+		Convey("An IP should be blocked across usernames", func() {
+			for i := 0; i < cfg.Security.MaxLoginAttemptsPerIP; i++ {
+				_, err := login("198.51.100.7", fmt.Sprintf("ghost%d", i), "wrong")
+				So(err.Error(), ShouldEqual, "用户名或密码错误")
+			}
+
+			_, err := login("198.51.100.7", "writer", "Current#Pass1")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "登录失败次数过多，请30分钟后再试")
+
+			// 其他IP不受影响
+			_, err = login("198.51.100.8", "writer", "Current#Pass1")
+			So(err, ShouldBeNil)
+
+			mr.FastForward(30 * time.Minute)
+			_, err = login("198.51.100.7", "writer", "Current#Pass1")
+			So(err, ShouldBeNil)
+		})
+	})
+}
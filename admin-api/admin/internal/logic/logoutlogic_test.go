@@ -0,0 +1,93 @@
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bytedance/mockey"
+	"github.com/go-redis/redis/v8"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/config"
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+func TestLogoutLogic_Logout(t *testing.T) {
+	mockey.PatchConvey("LogoutLogic Logout Tests", t, func() {
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+
+		cfg := config.Config{
+			Auth: struct {
+				AccessSecret string
+				AccessExpire int64
+			}{
+				AccessSecret: "test-secret",
+				AccessExpire: 7200,
+			},
+		}
+		svcCtx := &svc.ServiceContext{
+			Config:      cfg,
+			LoginLogDAO: &dao.LoginLogDAO{},
+			Redis:       rdb,
+		}
+
+		logged := make(chan *model.LoginLog, 1)
+		mockey.Mock((*dao.LoginLogDAO).Create).To(func(_ *dao.LoginLogDAO, _ context.Context, log *model.LoginLog) error {
+			logged <- log
+			return nil
+		}).Build()
+
+		testUser := &model.User{
+			ID:       primitive.NewObjectID(),
+			Username: "testuser",
+			Role:     constants.UserRoleAuthor,
+			Status:   constants.UserStatusActive,
+		}
+		principal := &auth.Principal{
+			UserID:    testUser.ID.Hex(),
+			Username:  testUser.Username,
+			Role:      testUser.Role,
+			TokenID:   "access-token-id",
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+
+		Convey("Should blacklist access token and revoke refresh token family", func() {
+			tokens, err := startTokenFamily(context.Background(), rdb, cfg.Auth.AccessSecret, testUser)
+			So(err, ShouldBeNil)
+
+			ctx := auth.WithPrincipal(context.Background(), principal)
+			resp, err := NewLogoutLogic(ctx, svcCtx).Logout(&types.LogoutRequest{RefreshToken: tokens.RefreshToken})
+			So(err, ShouldBeNil)
+			So(resp.Code, ShouldEqual, 200)
+
+			So(mr.Exists(utils.GenerateBlacklistKey(principal.TokenID)), ShouldBeTrue)
+			So(mr.TTL(utils.GenerateBlacklistKey(principal.TokenID)), ShouldBeGreaterThan, 59*time.Minute)
+
+			family, err := loadTokenFamily(context.Background(), rdb, tokens.FamilyID)
+			So(err, ShouldBeNil)
+			So(family.Revoked, ShouldBeTrue)
+
+			log := <-logged
+			So(log.UserID.Hex(), ShouldEqual, testUser.ID.Hex())
+			So(log.SessionID, ShouldEqual, principal.TokenID)
+			So(log.LogoutAt, ShouldNotBeNil)
+		})
+
+		Convey("Should reject request without principal", func() {
+			resp, err := NewLogoutLogic(context.Background(), svcCtx).Logout(&types.LogoutRequest{})
+			So(err, ShouldEqual, auth.ErrUnauthenticated)
+			So(resp, ShouldBeNil)
+		})
+	})
+}
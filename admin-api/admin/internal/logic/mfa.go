@@ -0,0 +1,188 @@
+package logic
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+// mfaChallengeTokenBytes 两步验证挑战令牌的随机字节数
+const mfaChallengeTokenBytes = 32
+
+// errInvalidMFAChallenge 两步验证挑战无效
+var errInvalidMFAChallenge = errors.New("两步验证已过期，请重新登录")
+
+// mfaChallengeKey 生成两步验证挑战缓存键，只保存令牌哈希
+func mfaChallengeKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return fmt.Sprintf(constants.CacheKeyMFAChallenge, hex.EncodeToString(sum[:]))
+}
+
+// mfaEnrollKey 生成待确认TOTP密钥缓存键，缓存中的密钥与数据库中一样加密保存
+func mfaEnrollKey(userID string) string {
+	return fmt.Sprintf(constants.CacheKeyMFAEnroll, userID)
+}
+
+// issueMFAChallenge 密码校验通过后创建两步验证挑战，返回交给客户端的挑战令牌
+func issueMFAChallenge(ctx context.Context, rdb redis.Cmdable, userID string) (string, error) {
+	raw := make([]byte, mfaChallengeTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("生成两步验证挑战失败: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	key := mfaChallengeKey(token)
+	_, err := rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "userId", userID, "attempts", 0)
+		pipe.Expire(ctx, key, constants.CacheTTLMFAChallenge)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("保存两步验证挑战失败: %w", err)
+	}
+	return token, nil
+}
+
+// loadMFAChallenge 查询挑战对应的用户ID，挑战无效或已过期时返回空字符串
+func loadMFAChallenge(ctx context.Context, rdb redis.Cmdable, token string) (string, error) {
+	userID, err := rdb.HGet(ctx, mfaChallengeKey(token), "userId").Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("查询两步验证挑战失败: %w", err)
+	}
+	return userID, nil
+}
+
+// incrMFAAttemptsScript 挑战仍存在时累加尝试次数，挑战已过期时返回-1，避免重新创建没有过期时间的记录
+var incrMFAAttemptsScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return redis.call("HINCRBY", KEYS[1], "attempts", 1)
+end
+return -1
+`)
+
+// recordMFAChallengeFailure 记录一次验证失败，达到最大尝试次数时作废挑战并返回true
+// 挑战已过期时返回 errInvalidMFAChallenge
+func recordMFAChallengeFailure(ctx context.Context, rdb redis.Cmdable, token string) (bool, error) {
+	key := mfaChallengeKey(token)
+	attempts, err := incrMFAAttemptsScript.Run(ctx, rdb, []string{key}).Int64()
+	if err != nil {
+		return false, fmt.Errorf("更新两步验证尝试次数失败: %w", err)
+	}
+	if attempts < 0 {
+		return false, errInvalidMFAChallenge
+	}
+	if attempts < constants.MFAChallengeMaxAttempts {
+		return false, nil
+	}
+	if err := rdb.Del(ctx, key).Err(); err != nil {
+		return true, fmt.Errorf("删除两步验证挑战失败: %w", err)
+	}
+	return true, nil
+}
+
+// consumeMFAChallenge 消费挑战，并发请求中只有一个能消费成功
+func consumeMFAChallenge(ctx context.Context, rdb redis.Cmdable, token string) (bool, error) {
+	deleted, err := rdb.Del(ctx, mfaChallengeKey(token)).Result()
+	if err != nil {
+		return false, fmt.Errorf("删除两步验证挑战失败: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+// isTOTPCode 判断输入是否为TOTP验证码格式（纯数字），否则按恢复码处理
+func isTOTPCode(code string) bool {
+	code = strings.TrimSpace(code)
+	if len(code) != utils.TOTPDigits {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// checkSecondFactor 校验TOTP验证码或恢复码但不消费，通过时返回对应的登录方式和TOTP时间步
+func checkSecondFactor(svcCtx *svc.ServiceContext, user *model.User, code string) (string, int64, bool, error) {
+	if !user.TwoFactorEnabled || user.TwoFactorSecret == "" {
+		return "", 0, false, nil
+	}
+
+	if isTOTPCode(code) {
+		secret, err := svcCtx.MFASecretBox.Open(user.TwoFactorSecret, user.ID.Hex())
+		if err != nil {
+			return constants.LoginMethodTOTP, 0, false, fmt.Errorf("解密TOTP密钥失败: %w", err)
+		}
+		step, valid := utils.ValidateTOTPCode(secret, code, time.Now())
+		return constants.LoginMethodTOTP, step, valid && step > user.TwoFactorLastStep, nil
+	}
+	return constants.LoginMethodRecoveryCode, 0, slices.Contains(user.RecoveryCodes, utils.HashRecoveryCode(code)), nil
+}
+
+// useSecondFactor 在数据库中原子消费已校验通过的TOTP时间步或恢复码，已被并发请求使用时返回false
+func useSecondFactor(ctx context.Context, svcCtx *svc.ServiceContext, user *model.User, loginMethod string, step int64, code string) (bool, error) {
+	if loginMethod == constants.LoginMethodTOTP {
+		ok, err := svcCtx.UserDAO.UseTOTPStep(ctx, user.ID.Hex(), step)
+		if err != nil {
+			return false, fmt.Errorf("记录TOTP时间步失败: %w", err)
+		}
+		return ok, nil
+	}
+
+	ok, err := svcCtx.UserDAO.ConsumeRecoveryCode(ctx, user.ID.Hex(), utils.HashRecoveryCode(code))
+	if err != nil {
+		return false, fmt.Errorf("消费恢复码失败: %w", err)
+	}
+	return ok, nil
+}
+
+// verifySecondFactor 校验并消费TOTP验证码或恢复码，通过时返回对应的登录方式
+// TOTP时间步和恢复码都在数据库中原子消费，同一验证码不能重复使用
+func verifySecondFactor(ctx context.Context, svcCtx *svc.ServiceContext, user *model.User, code string) (string, bool, error) {
+	loginMethod, step, ok, err := checkSecondFactor(svcCtx, user, code)
+	if err != nil || !ok {
+		return loginMethod, false, err
+	}
+
+	used, err := useSecondFactor(ctx, svcCtx, user, loginMethod, step, code)
+	return loginMethod, used, err
+}
+
+// newRecoveryCodes 生成一组恢复码，返回明文（仅展示一次）和用于存储的哈希
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := utils.GenerateRecoveryCodes(utils.RecoveryCodeCount)
+	if err != nil {
+		return nil, nil, err
+	}
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, utils.HashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// mfaRequiredForRole 检查角色是否被要求启用两步验证，未配置策略时视为不要求
+func mfaRequiredForRole(ctx context.Context, svcCtx *svc.ServiceContext, role string) (bool, error) {
+	if svcCtx.MFAPolicy == nil {
+		return false, nil
+	}
+	return svcCtx.MFAPolicy.Requires(ctx, role)
+}
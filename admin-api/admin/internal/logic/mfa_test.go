@@ -0,0 +1,328 @@
+package logic
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bytedance/mockey"
+	"github.com/go-redis/redis/v8"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/config"
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+func TestTwoFactorAuthentication(t *testing.T) {
+	mockey.PatchConvey("Two Factor Authentication Tests", t, func() {
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+
+		cfg := config.Config{
+			Auth: struct {
+				AccessSecret string
+				AccessExpire int64
+			}{
+				AccessSecret: "test-secret",
+				AccessExpire: 7200,
+			},
+			Security: config.SecurityConfig{
+				MaxLoginAttemptsPerIP: 20,
+				LoginIPBlockDuration:  1800,
+				MFAIssuer:             "Heimdall",
+			},
+		}
+
+		requiredRoles := ""
+		mockey.Mock((*dao.SettingDAO).GetByKey).To(func(_ *dao.SettingDAO, _ context.Context, key string) (*model.Setting, error) {
+			return &model.Setting{Key: key, Value: requiredRoles}, nil
+		}).Build()
+		mockey.Mock((*dao.SettingDAO).Set).To(func(_ *dao.SettingDAO, _ context.Context, _, value, _ string) error {
+			requiredRoles = value
+			return nil
+		}).Build()
+		mfaPolicy, err := auth.NewMFAPolicy(&dao.SettingDAO{})
+		So(err, ShouldBeNil)
+
+		secretBox, err := utils.NewSecretBox("tmzrObP8CcFZqXg3HUqpxNzte/4F5wuXnZ5hJ/54Tac=")
+		So(err, ShouldBeNil)
+
+		svcCtx := &svc.ServiceContext{
+			Config:       cfg,
+			UserDAO:      &dao.UserDAO{},
+			Redis:        rdb,
+			MFAPolicy:    mfaPolicy,
+			MFASecretBox: secretBox,
+		}
+
+		passwordHash, _ := utils.HashPassword("Current#Pass1")
+		testUser := &model.User{
+			ID:           primitive.NewObjectID(),
+			Username:     "admin",
+			Email:        "admin@example.com",
+			DisplayName:  "Admin",
+			PasswordHash: passwordHash,
+			Role:         constants.UserRoleAdmin,
+			Status:       constants.UserStatusActive,
+		}
+
+		// 用内存中的testUser模拟数据库
+		mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+		mockey.Mock((*dao.UserDAO).GetByUsername).Return(testUser, nil).Build()
+		mockey.Mock((*dao.UserDAO).UpdateLoginInfo).Return(nil).Build()
+		mockey.Mock((*dao.UserDAO).IncrementLoginFailCount).To(func(_ *dao.UserDAO, _ context.Context, _ string) (int, error) {
+			testUser.LoginFailCount++
+			return testUser.LoginFailCount, nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).LockUser).To(func(_ *dao.UserDAO, _ context.Context, _ string, lockedUntil time.Time) error {
+			testUser.Status = constants.UserStatusLocked
+			testUser.LockedUntil = &lockedUntil
+			return nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).ResetLoginFailCount).Return(nil).Build()
+		var loginLogs []string
+		mockey.Mock(createLoginLog).To(func(_ context.Context, _ *svc.ServiceContext, loginLog *model.LoginLog) error {
+			loginLogs = append(loginLogs, loginLog.Status+":"+loginLog.LoginMethod)
+			return nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).Update).To(func(_ *dao.UserDAO, _ context.Context, _ string, updates map[string]interface{}) error {
+			if v, ok := updates["twoFactorEnabled"]; ok {
+				testUser.TwoFactorEnabled = v.(bool)
+			}
+			if v, ok := updates["twoFactorSecret"]; ok {
+				testUser.TwoFactorSecret = v.(string)
+			}
+			if v, ok := updates["twoFactorLastStep"]; ok {
+				testUser.TwoFactorLastStep = v.(int64)
+			}
+			if v, ok := updates["recoveryCodes"]; ok {
+				testUser.RecoveryCodes = v.([]string)
+			}
+			return nil
+		}).Build()
+		// stepTakenConcurrently 模拟校验通过后时间步被并发登录抢先使用
+		stepTakenConcurrently := false
+		mockey.Mock((*dao.UserDAO).UseTOTPStep).To(func(_ *dao.UserDAO, _ context.Context, _ string, step int64) (bool, error) {
+			if stepTakenConcurrently || step <= testUser.TwoFactorLastStep {
+				return false, nil
+			}
+			testUser.TwoFactorLastStep = step
+			return true, nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).ConsumeRecoveryCode).To(func(_ *dao.UserDAO, _ context.Context, _ string, codeHash string) (bool, error) {
+			i := slices.Index(testUser.RecoveryCodes, codeHash)
+			if i < 0 {
+				return false, nil
+			}
+			testUser.RecoveryCodes = slices.Delete(testUser.RecoveryCodes, i, i+1)
+			return true, nil
+		}).Build()
+
+		ctx := utils.WithClientInfo(withPrincipal(context.Background(), testUser.ID.Hex(), testUser.Role),
+			utils.ClientInfo{IP: "203.0.113.10"})
+
+		// enrol 完成TOTP绑定，返回密钥和恢复码
+		enrol := func() (string, []string) {
+			setup, err := NewSetupTOTPLogic(ctx, svcCtx).SetupTOTP(&types.TOTPSetupRequest{Password: "Current#Pass1"})
+			So(err, ShouldBeNil)
+			secret := setup.Data.Secret
+
+			code, _ := utils.GenerateTOTPCode(secret, time.Now())
+			confirm, err := NewConfirmTOTPLogic(ctx, svcCtx).ConfirmTOTP(&types.TOTPConfirmRequest{Code: code})
+			So(err, ShouldBeNil)
+			return secret, confirm.Data.RecoveryCodes
+		}
+		login := func() *types.LoginResponse {
+			resp, err := NewLoginLogic(ctx, svcCtx).Login(&types.LoginRequest{Username: testUser.Username, Password: "Current#Pass1"})
+			So(err, ShouldBeNil)
+			return resp
+		}
+
+		Convey("Setup should require the password and keep the secret pending", func() {
+			_, err := NewSetupTOTPLogic(ctx, svcCtx).SetupTOTP(&types.TOTPSetupRequest{Password: "wrong"})
+			So(err, ShouldNotBeNil)
+
+			setup, err := NewSetupTOTPLogic(ctx, svcCtx).SetupTOTP(&types.TOTPSetupRequest{Password: "Current#Pass1"})
+			So(err, ShouldBeNil)
+			So(setup.Data.OtpauthURI, ShouldStartWith, "otpauth://totp/Heimdall:admin?")
+			So(setup.Data.OtpauthURI, ShouldContainSubstring, "secret="+setup.Data.Secret)
+			So(testUser.TwoFactorEnabled, ShouldBeFalse)
+			So(mr.TTL(mfaEnrollKey(testUser.ID.Hex())), ShouldEqual, constants.CacheTTLMFAEnroll)
+			pending, _ := mr.Get(mfaEnrollKey(testUser.ID.Hex()))
+			So(pending, ShouldNotContainSubstring, setup.Data.Secret)
+
+			_, err = NewConfirmTOTPLogic(ctx, svcCtx).ConfirmTOTP(&types.TOTPConfirmRequest{Code: "000000"})
+			So(err, ShouldNotBeNil)
+			So(testUser.TwoFactorEnabled, ShouldBeFalse)
+		})
+
+		Convey("Confirm should enable 2FA with hashed recovery codes", func() {
+			secret, codes := enrol()
+			So(testUser.TwoFactorEnabled, ShouldBeTrue)
+			So(testUser.TwoFactorSecret, ShouldNotContainSubstring, secret)
+			stored, err := secretBox.Open(testUser.TwoFactorSecret, testUser.ID.Hex())
+			So(err, ShouldBeNil)
+			So(stored, ShouldEqual, secret)
+			So(codes, ShouldHaveLength, utils.RecoveryCodeCount)
+			So(testUser.RecoveryCodes, ShouldHaveLength, utils.RecoveryCodeCount)
+			So(testUser.RecoveryCodes, ShouldNotContain, codes[0])
+			So(testUser.RecoveryCodes, ShouldContain, utils.HashRecoveryCode(codes[0]))
+			So(mr.Exists(mfaEnrollKey(testUser.ID.Hex())), ShouldBeFalse)
+
+			status, err := NewGetMFAStatusLogic(ctx, svcCtx).GetMFAStatus()
+			So(err, ShouldBeNil)
+			So(status.Data.Enabled, ShouldBeTrue)
+			So(status.Data.RecoveryCodesRemaining, ShouldEqual, utils.RecoveryCodeCount)
+		})
+
+		Convey("Login without 2FA should issue tokens directly", func() {
+			resp := login()
+			So(resp.Data.MFARequired, ShouldBeFalse)
+			So(resp.Data.Token, ShouldNotBeEmpty)
+			So(resp.Data.MFASetupRequired, ShouldBeFalse)
+		})
+
+		Convey("Login should flag roles that must enrol", func() {
+			requiredRoles = constants.UserRoleAdmin
+			resp := login()
+			So(resp.Data.Token, ShouldNotBeEmpty)
+			So(resp.Data.MFASetupRequired, ShouldBeTrue)
+		})
+
+		Convey("Login with 2FA should return a challenge instead of tokens", func() {
+			secret, codes := enrol()
+			resp := login()
+			So(resp.Data.MFARequired, ShouldBeTrue)
+			So(resp.Data.MFAToken, ShouldNotBeEmpty)
+			So(resp.Data.Token, ShouldBeEmpty)
+			So(resp.Data.RefreshToken, ShouldBeEmpty)
+			So(mr.TTL(mfaChallengeKey(resp.Data.MFAToken)), ShouldEqual, constants.CacheTTLMFAChallenge)
+
+			Convey("A fresh TOTP code should complete the login once", func() {
+				code, _ := utils.GenerateTOTPCode(secret, time.Now().Add(utils.TOTPPeriod*time.Second))
+				done, err := NewLoginMFALogic(ctx, svcCtx).LoginMFA(&types.LoginMFARequest{MFAToken: resp.Data.MFAToken, Code: code})
+				So(err, ShouldBeNil)
+				So(done.Data.Token, ShouldNotBeEmpty)
+				So(done.Data.User.TwoFactorEnabled, ShouldBeTrue)
+				So(loginLogs, ShouldContain, constants.LoginStatusSuccess+":"+constants.LoginMethodTOTP)
+
+				_, err = NewLoginMFALogic(ctx, svcCtx).LoginMFA(&types.LoginMFARequest{MFAToken: resp.Data.MFAToken, Code: code})
+				So(err, ShouldEqual, errInvalidMFAChallenge)
+			})
+
+			Convey("A replayed TOTP code should be rejected", func() {
+				code, _ := utils.GenerateTOTPCode(secret, time.Now())
+				_, err := NewLoginMFALogic(ctx, svcCtx).LoginMFA(&types.LoginMFARequest{MFAToken: resp.Data.MFAToken, Code: code})
+				So(err, ShouldNotBeNil)
+				So(loginLogs, ShouldContain, constants.LoginStatusFailed+":"+constants.LoginMethodTOTP)
+			})
+
+			Convey("A recovery code should work exactly once", func() {
+				done, err := NewLoginMFALogic(ctx, svcCtx).LoginMFA(&types.LoginMFARequest{MFAToken: resp.Data.MFAToken, Code: codes[0]})
+				So(err, ShouldBeNil)
+				So(done.Data.Token, ShouldNotBeEmpty)
+				So(testUser.RecoveryCodes, ShouldHaveLength, utils.RecoveryCodeCount-1)
+
+				again := login()
+				_, err = NewLoginMFALogic(ctx, svcCtx).LoginMFA(&types.LoginMFARequest{MFAToken: again.Data.MFAToken, Code: codes[0]})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("A code should not be consumed when the challenge was already used", func() {
+				mock := mockey.Mock(consumeMFAChallenge).Return(false, nil).Build()
+				defer mock.UnPatch()
+
+				_, err := NewLoginMFALogic(ctx, svcCtx).LoginMFA(&types.LoginMFARequest{MFAToken: resp.Data.MFAToken, Code: codes[0]})
+				So(err, ShouldEqual, errInvalidMFAChallenge)
+				So(testUser.RecoveryCodes, ShouldContain, utils.HashRecoveryCode(codes[0]))
+			})
+
+			Convey("A code consumed by a concurrent login should still use up the challenge", func() {
+				code, _ := utils.GenerateTOTPCode(secret, time.Now().Add(utils.TOTPPeriod*time.Second))
+				stepTakenConcurrently = true
+
+				_, err := NewLoginMFALogic(ctx, svcCtx).LoginMFA(&types.LoginMFARequest{MFAToken: resp.Data.MFAToken, Code: code})
+				So(err, ShouldNotBeNil)
+				So(mr.Exists(mfaChallengeKey(resp.Data.MFAToken)), ShouldBeFalse)
+			})
+
+			Convey("Too many wrong codes should invalidate the challenge", func() {
+				for i := 0; i < constants.MFAChallengeMaxAttempts; i++ {
+					_, err := NewLoginMFALogic(ctx, svcCtx).LoginMFA(&types.LoginMFARequest{MFAToken: resp.Data.MFAToken, Code: "bogus-code"})
+					So(err, ShouldNotBeNil)
+				}
+				So(mr.Exists(mfaChallengeKey(resp.Data.MFAToken)), ShouldBeFalse)
+				So(testUser.IsLocked(), ShouldBeTrue)
+
+				code, _ := utils.GenerateTOTPCode(secret, time.Now().Add(utils.TOTPPeriod*time.Second))
+				_, err := NewLoginMFALogic(ctx, svcCtx).LoginMFA(&types.LoginMFARequest{MFAToken: resp.Data.MFAToken, Code: code})
+				So(err, ShouldEqual, errInvalidMFAChallenge)
+			})
+
+			Convey("A failure after the challenge expired should not recreate it", func() {
+				mr.FastForward(constants.CacheTTLMFAChallenge + time.Second)
+
+				exhausted, err := recordMFAChallengeFailure(context.Background(), rdb, resp.Data.MFAToken)
+				So(err, ShouldEqual, errInvalidMFAChallenge)
+				So(exhausted, ShouldBeFalse)
+				So(mr.Exists(mfaChallengeKey(resp.Data.MFAToken)), ShouldBeFalse)
+			})
+		})
+
+		Convey("Disable should be blocked when the role requires 2FA", func() {
+			secret, _ := enrol()
+			So(mfaPolicy.SetRequiredRoles(context.Background(), []string{constants.UserRoleAdmin}), ShouldBeNil)
+
+			code, _ := utils.GenerateTOTPCode(secret, time.Now().Add(utils.TOTPPeriod*time.Second))
+			_, err := NewDisableTOTPLogic(ctx, svcCtx).DisableTOTP(&types.TOTPDisableRequest{Password: "Current#Pass1", Code: code})
+			So(err, ShouldNotBeNil)
+			So(testUser.TwoFactorEnabled, ShouldBeTrue)
+
+			So(mfaPolicy.SetRequiredRoles(context.Background(), nil), ShouldBeNil)
+			_, err = NewDisableTOTPLogic(ctx, svcCtx).DisableTOTP(&types.TOTPDisableRequest{Password: "Current#Pass1", Code: code})
+			So(err, ShouldBeNil)
+			So(testUser.TwoFactorEnabled, ShouldBeFalse)
+			So(testUser.TwoFactorSecret, ShouldBeEmpty)
+			So(testUser.RecoveryCodes, ShouldBeEmpty)
+		})
+
+		Convey("Regenerating recovery codes should require a TOTP code", func() {
+			secret, codes := enrol()
+			_, err := NewRegenerateRecoveryCodesLogic(ctx, svcCtx).RegenerateRecoveryCodes(&types.RecoveryCodesRegenerateRequest{Code: codes[0]})
+			So(err, ShouldNotBeNil)
+
+			code, _ := utils.GenerateTOTPCode(secret, time.Now().Add(utils.TOTPPeriod*time.Second))
+			resp, err := NewRegenerateRecoveryCodesLogic(ctx, svcCtx).RegenerateRecoveryCodes(&types.RecoveryCodesRegenerateRequest{Code: code})
+			So(err, ShouldBeNil)
+			So(resp.Data.RecoveryCodes, ShouldHaveLength, utils.RecoveryCodeCount)
+			So(testUser.RecoveryCodes, ShouldNotContain, utils.HashRecoveryCode(codes[0]))
+		})
+
+		Convey("Policy update should validate and normalise roles", func() {
+			ownerCtx := withPrincipal(context.Background(), primitive.NewObjectID().Hex(), constants.UserRoleOwner)
+			_, err := NewUpdateMFAPolicyLogic(ownerCtx, svcCtx).UpdateMFAPolicy(&types.MFAPolicyUpdateRequest{RequiredRoles: []string{"root"}})
+			So(err, ShouldNotBeNil)
+
+			resp, err := NewUpdateMFAPolicyLogic(ownerCtx, svcCtx).UpdateMFAPolicy(&types.MFAPolicyUpdateRequest{
+				RequiredRoles: []string{constants.UserRoleEditor, constants.UserRoleOwner, constants.UserRoleEditor},
+			})
+			So(err, ShouldBeNil)
+			So(resp.Data.RequiredRoles, ShouldResemble, []string{constants.UserRoleOwner, constants.UserRoleEditor})
+			So(requiredRoles, ShouldEqual, "owner,editor")
+
+			policy, err := NewGetMFAPolicyLogic(ownerCtx, svcCtx).GetMFAPolicy()
+			So(err, ShouldBeNil)
+			So(policy.Data.RequiredRoles, ShouldResemble, []string{constants.UserRoleOwner, constants.UserRoleEditor})
+		})
+	})
+}
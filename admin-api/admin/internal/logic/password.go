@@ -0,0 +1,62 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+// validateNewPassword 校验新密码：强度、是否包含用户信息、是否与最近使用过的密码重复
+func validateNewPassword(user *model.User, password string) error {
+	if err := utils.ValidatePasswordStrength(password, utils.DefaultPasswordConfig); err != nil {
+		return passwordPolicyError(err)
+	}
+	if err := utils.ValidatePasswordForUser(password, user.Username, user.Email); err != nil {
+		return errors.New("密码不能包含用户名或邮箱")
+	}
+	if utils.IsPasswordReused(password, passwordHistoryOf(user)) {
+		return fmt.Errorf("新密码不能与最近%d次使用过的密码相同", constants.PasswordHistoryCount)
+	}
+	return nil
+}
+
+// buildPasswordUpdates 生成新密码哈希并构建更新数据，同时滚动密码历史记录
+func buildPasswordUpdates(user *model.User, password string) (map[string]interface{}, error) {
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, passwordPolicyError(err)
+	}
+	return map[string]interface{}{
+		"passwordHash":    hash,
+		"passwordHistory": utils.PushPasswordHistory(passwordHistoryOf(user), hash, constants.PasswordHistoryCount),
+	}, nil
+}
+
+// passwordHistoryOf 获取用户的密码历史，早期账号没有历史记录时以当前密码作为唯一记录
+func passwordHistoryOf(user *model.User) []string {
+	if len(user.PasswordHistory) == 0 && user.PasswordHash != "" {
+		return []string{user.PasswordHash}
+	}
+	return user.PasswordHistory
+}
+
+// passwordPolicyError 将密码强度校验错误转换为面向用户的提示
+func passwordPolicyError(err error) error {
+	switch {
+	case errors.Is(err, utils.ErrPasswordTooShort):
+		return fmt.Errorf("密码长度不能少于%d个字符", utils.MinPasswordLength)
+	case errors.Is(err, utils.ErrPasswordTooLong):
+		return fmt.Errorf("密码长度不能超过%d个字符", utils.MaxPasswordLength)
+	case errors.Is(err, utils.ErrCommonPassword):
+		return errors.New("密码过于常见，请更换")
+	case errors.Is(err, utils.ErrPasswordInvalidChars):
+		return errors.New("密码包含无效字符")
+	case errors.Is(err, utils.ErrWeakPassword):
+		return errors.New("密码强度不足，需包含大写字母、小写字母、数字、特殊字符中的至少3种")
+	default:
+		return errors.New("密码不符合安全要求")
+	}
+}
@@ -0,0 +1,120 @@
+package logic
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/heimdall-api/common/constants"
+)
+
+// passwordResetTokenBytes 重置令牌的随机字节数
+const passwordResetTokenBytes = 32
+
+// passwordResetKey 生成重置令牌缓存键，只保存令牌哈希，Redis泄露时令牌本身不可用
+func passwordResetKey(tokenHash string) string {
+	return fmt.Sprintf(constants.CacheKeyPasswordReset, tokenHash)
+}
+
+// passwordResetUserKey 生成用户当前重置令牌缓存键
+func passwordResetUserKey(userID string) string {
+	return fmt.Sprintf(constants.CacheKeyPasswordResetUser, userID)
+}
+
+// hashPasswordResetToken 计算重置令牌的SHA-256哈希
+func hashPasswordResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issuePasswordResetToken 生成一次性重置令牌，同一用户再次申请时之前的令牌立即失效
+func issuePasswordResetToken(ctx context.Context, rdb redis.Cmdable, userID string, ttl time.Duration) (string, error) {
+	raw := make([]byte, passwordResetTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("生成重置令牌失败: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	tokenHash := hashPasswordResetToken(token)
+
+	userKey := passwordResetUserKey(userID)
+	previous, err := rdb.Get(ctx, userKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", fmt.Errorf("查询重置令牌失败: %w", err)
+	}
+
+	_, err = rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if previous != "" {
+			pipe.Del(ctx, passwordResetKey(previous))
+		}
+		pipe.Set(ctx, passwordResetKey(tokenHash), userID, ttl)
+		pipe.Set(ctx, userKey, tokenHash, ttl)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("保存重置令牌失败: %w", err)
+	}
+	return token, nil
+}
+
+// lookupPasswordResetToken 查询重置令牌对应的用户ID，令牌无效或已过期时返回空字符串
+func lookupPasswordResetToken(ctx context.Context, rdb redis.Cmdable, token string) (string, error) {
+	userID, err := rdb.Get(ctx, passwordResetKey(hashPasswordResetToken(token))).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("查询重置令牌失败: %w", err)
+	}
+	return userID, nil
+}
+
+// consumePasswordResetToken 消费重置令牌，并发请求中只有一个能消费成功
+func consumePasswordResetToken(ctx context.Context, rdb redis.Cmdable, token, userID string) (bool, error) {
+	tokenHash := hashPasswordResetToken(token)
+	deleted, err := rdb.Del(ctx, passwordResetKey(tokenHash)).Result()
+	if err != nil {
+		return false, fmt.Errorf("删除重置令牌失败: %w", err)
+	}
+	if deleted == 0 {
+		return false, nil
+	}
+	rdb.Del(ctx, passwordResetUserKey(userID))
+	return true, nil
+}
+
+// allowPasswordResetRequest 按邮箱和IP限制重置请求频率，任一维度超限即拒绝
+func allowPasswordResetRequest(ctx context.Context, rdb redis.Cmdable, email, ip string) (bool, error) {
+	type limit struct {
+		key string
+		max int64
+	}
+	limits := []limit{
+		{fmt.Sprintf(constants.CacheKeyPasswordResetByEmail, strings.ToLower(email)), constants.PasswordResetMaxPerEmail},
+	}
+	if ip != "" {
+		limits = append(limits, limit{fmt.Sprintf(constants.CacheKeyPasswordResetByIP, ip), constants.PasswordResetMaxPerIP})
+	}
+
+	allowed := true
+	for _, l := range limits {
+		count, err := rdb.Incr(ctx, l.key).Result()
+		if err != nil {
+			return false, fmt.Errorf("更新重置请求计数失败: %w", err)
+		}
+		if count == 1 {
+			rdb.Expire(ctx, l.key, constants.CacheTTLPasswordResetLimit)
+		}
+		if count > l.max {
+			allowed = false
+		}
+	}
+	return allowed, nil
+}
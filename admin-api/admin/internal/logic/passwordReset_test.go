@@ -0,0 +1,198 @@
+package logic
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bytedance/mockey"
+	"github.com/go-redis/redis/v8"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/config"
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/client/mailer"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+// recordingMailer 记录发送的邮件
+type recordingMailer struct {
+	sent chan *mailer.Message
+}
+
+func (m *recordingMailer) Send(_ context.Context, msg *mailer.Message) error {
+	m.sent <- msg
+	return nil
+}
+
+func TestPasswordReset(t *testing.T) {
+	mockey.PatchConvey("Password Reset Tests", t, func() {
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+
+		cfg := config.Config{
+			Auth: struct {
+				AccessSecret string
+				AccessExpire int64
+			}{
+				AccessSecret: "test-secret",
+				AccessExpire: 7200,
+			},
+			Security: config.SecurityConfig{
+				PasswordResetURL: "https://admin.example.com/reset-password",
+				PasswordResetTTL: 1800,
+			},
+		}
+		mail := &recordingMailer{sent: make(chan *mailer.Message, 10)}
+		svcCtx := &svc.ServiceContext{
+			Config:  cfg,
+			UserDAO: &dao.UserDAO{},
+			Redis:   rdb,
+			Mailer:  mail,
+		}
+
+		currentHash, _ := utils.HashPassword("Current#Pass1")
+		testUser := &model.User{
+			ID:           primitive.NewObjectID(),
+			Username:     "testuser",
+			Email:        "writer@example.com",
+			DisplayName:  "Writer",
+			PasswordHash: currentHash,
+			Role:         constants.UserRoleAuthor,
+			Status:       constants.UserStatusActive,
+		}
+		mockey.Mock((*dao.UserDAO).GetByEmail).To(func(_ *dao.UserDAO, _ context.Context, email string) (*model.User, error) {
+			if email == testUser.Email {
+				return testUser, nil
+			}
+			return nil, nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+
+		var saved map[string]interface{}
+		mockey.Mock((*dao.UserDAO).Update).To(func(_ *dao.UserDAO, _ context.Context, _ string, updates map[string]interface{}) error {
+			saved = updates
+			return nil
+		}).Build()
+
+		ctx := utils.WithClientInfo(context.Background(), utils.ClientInfo{IP: "203.0.113.10"})
+		forgot := func(email string) *types.ForgotPasswordResponse {
+			resp, err := NewForgotPasswordLogic(ctx, svcCtx).ForgotPassword(&types.ForgotPasswordRequest{Email: email})
+			So(err, ShouldBeNil)
+			So(resp.Code, ShouldEqual, 200)
+			return resp
+		}
+		receiveToken := func() string {
+			select {
+			case msg := <-mail.sent:
+				So(msg.To, ShouldResemble, []string{testUser.Email})
+				link := regexp.MustCompile(`https://\S+`).FindString(msg.Text)
+				parsed, err := url.Parse(link)
+				So(err, ShouldBeNil)
+				return parsed.Query().Get("token")
+			case <-time.After(2 * time.Second):
+				t.Fatal("reset mail not sent")
+				return ""
+			}
+		}
+
+		Convey("Forgot password should respond the same for unknown emails", func() {
+			known := forgot(testUser.Email)
+			unknown := forgot("ghost@example.com")
+			So(unknown.Message, ShouldEqual, known.Message)
+
+			token := receiveToken()
+			So(token, ShouldNotBeEmpty)
+			So(len(mail.sent), ShouldEqual, 0)
+
+			// 只保存令牌哈希
+			So(mr.Exists(passwordResetKey(hashPasswordResetToken(token))), ShouldBeTrue)
+			So(mr.Exists(passwordResetKey(token)), ShouldBeFalse)
+			So(mr.TTL(passwordResetKey(hashPasswordResetToken(token))), ShouldEqual, 30*time.Minute)
+		})
+
+		Convey("Forgot password should be rate limited per email", func() {
+			for i := 0; i < constants.PasswordResetMaxPerEmail+2; i++ {
+				forgot(testUser.Email)
+			}
+			for i := 0; i < constants.PasswordResetMaxPerEmail; i++ {
+				receiveToken()
+			}
+			time.Sleep(50 * time.Millisecond)
+			So(len(mail.sent), ShouldEqual, 0)
+		})
+
+		Convey("New request should invalidate previous token", func() {
+			forgot(testUser.Email)
+			first := receiveToken()
+			forgot(testUser.Email)
+			second := receiveToken()
+
+			userID, _ := lookupPasswordResetToken(context.Background(), rdb, first)
+			So(userID, ShouldBeEmpty)
+			userID, _ = lookupPasswordResetToken(context.Background(), rdb, second)
+			So(userID, ShouldEqual, testUser.ID.Hex())
+		})
+
+		Convey("Reset should change password once and revoke sessions", func() {
+			session, err := startUserSession(context.Background(), svcCtx, testUser)
+			So(err, ShouldBeNil)
+
+			forgot(testUser.Email)
+			token := receiveToken()
+
+			// 弱密码不消费令牌
+			_, err = NewResetPasswordLogic(ctx, svcCtx).ResetPassword(&types.ResetPasswordRequest{Token: token, NewPassword: "weak"})
+			So(err, ShouldNotBeNil)
+			So(saved, ShouldBeNil)
+
+			resp, err := NewResetPasswordLogic(ctx, svcCtx).ResetPassword(&types.ResetPasswordRequest{Token: token, NewPassword: "Brand#NewPass1"})
+			So(err, ShouldBeNil)
+			So(resp.Code, ShouldEqual, 200)
+			So(utils.VerifyPassword("Brand#NewPass1", saved["passwordHash"].(string)), ShouldBeNil)
+
+			family, _ := loadTokenFamily(context.Background(), rdb, session.FamilyID)
+			So(family.Revoked, ShouldBeTrue)
+			revokedBefore, _ := auth.TokensRevokedBefore(context.Background(), rdb, testUser.ID.Hex())
+			So(revokedBefore, ShouldBeGreaterThan, 0)
+
+			_, err = NewResetPasswordLogic(ctx, svcCtx).ResetPassword(&types.ResetPasswordRequest{Token: token, NewPassword: "Another#Pass2"})
+			So(err, ShouldEqual, errInvalidResetToken)
+		})
+
+		Convey("Reset should unlock users locked out by failed logins", func() {
+			lockedUntil := time.Now().Add(time.Hour)
+			testUser.Status = constants.UserStatusLocked
+			testUser.LoginFailCount = 10
+			testUser.LockedUntil = &lockedUntil
+			unlocked := ""
+			mockey.Mock((*dao.UserDAO).ResetLoginFailCount).To(func(_ *dao.UserDAO, _ context.Context, id string) error {
+				unlocked = id
+				return nil
+			}).Build()
+
+			forgot(testUser.Email)
+			token := receiveToken()
+
+			_, err := NewResetPasswordLogic(ctx, svcCtx).ResetPassword(&types.ResetPasswordRequest{Token: token, NewPassword: "Brand#NewPass1"})
+			So(err, ShouldBeNil)
+			So(saved, ShouldNotBeNil)
+			So(unlocked, ShouldEqual, testUser.ID.Hex())
+		})
+
+		Convey("Reset should reject unknown tokens", func() {
+			_, err := NewResetPasswordLogic(ctx, svcCtx).ResetPassword(&types.ResetPasswordRequest{Token: "bogus", NewPassword: "Brand#NewPass1"})
+			So(err, ShouldEqual, errInvalidResetToken)
+		})
+	})
+}
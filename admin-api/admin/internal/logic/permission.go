@@ -0,0 +1,13 @@
+package logic
+
+import (
+	"context"
+
+	"github.com/heimdall-api/common/auth"
+)
+
+// hasResourcePermission 按权限表检查当前用户能否对归属于ownerID的资源执行该动作
+func hasResourcePermission(ctx context.Context, permission, ownerID string) bool {
+	principal, err := auth.FromContext(ctx)
+	return err == nil && principal.CanAccess(permission, ownerID)
+}
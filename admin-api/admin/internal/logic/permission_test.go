@@ -0,0 +1,38 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+)
+
+// withPrincipal 模拟认证中间件，将认证主体写入context
+func withPrincipal(ctx context.Context, userID, role string) context.Context {
+	return auth.WithPrincipal(ctx, &auth.Principal{UserID: userID, Username: "testuser", Role: role})
+}
+
+func TestHasResourcePermission(t *testing.T) {
+	Convey("Test hasResourcePermission", t, func() {
+		Convey("Editors and admins should manage any resource", func() {
+			for _, role := range []string{constants.UserRoleOwner, constants.UserRoleAdmin, constants.UserRoleEditor} {
+				ctx := withPrincipal(context.Background(), "u1", role)
+				So(hasResourcePermission(ctx, constants.PermissionPostDelete, "u2"), ShouldBeTrue)
+				So(hasResourcePermission(ctx, constants.PermissionPageUpdate, "u2"), ShouldBeTrue)
+			}
+		})
+
+		Convey("Authors should only manage their own resources", func() {
+			ctx := withPrincipal(context.Background(), "u1", constants.UserRoleAuthor)
+			So(hasResourcePermission(ctx, constants.PermissionPostPublish, "u1"), ShouldBeTrue)
+			So(hasResourcePermission(ctx, constants.PermissionPostPublish, "u2"), ShouldBeFalse)
+		})
+
+		Convey("Missing principal should be denied", func() {
+			So(hasResourcePermission(context.Background(), constants.PermissionPostDelete, "u1"), ShouldBeFalse)
+		})
+	})
+}
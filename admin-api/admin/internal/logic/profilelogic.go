@@ -10,6 +10,7 @@ import (
 
 	"github.com/heimdall-api/admin-api/admin/internal/svc"
 	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
 	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
 
@@ -76,60 +77,11 @@ func (l *ProfileLogic) Profile() (resp *types.ProfileResponse, err error) {
 
 // getUserIDFromContext 从context获取用户ID
 func (l *ProfileLogic) getUserIDFromContext() (string, error) {
-	l.Logger.Infof("开始从context获取用户ID...")
-
-	// 调试：打印context中的所有键值对
-	l.Logger.Infof("=== 开始调试 context 内容 ===")
-
-	// 方法1: 尝试go-zero默认的uid键
-	if uid := l.ctx.Value("uid"); uid != nil {
-		l.Logger.Infof("找到 uid: %v (类型: %T)", uid, uid)
-		if userID, ok := uid.(string); ok && userID != "" {
-			l.Logger.Infof("成功从context的uid键获取用户ID: %s", userID)
-			return userID, nil
-		}
-	} else {
-		l.Logger.Infof("context中没有找到uid键")
-	}
-
-	// 方法2: 直接从sub字段获取（JWT标准字段）
-	if sub := l.ctx.Value("sub"); sub != nil {
-		l.Logger.Infof("找到 sub: %v (类型: %T)", sub, sub)
-		if userID, ok := sub.(string); ok && userID != "" {
-			l.Logger.Infof("成功从context的sub键获取用户ID: %s", userID)
-			return userID, nil
-		}
-	} else {
-		l.Logger.Infof("context中没有找到sub键")
-	}
-
-	// 方法3: 尝试其他可能的键
-	possibleKeys := []string{
-		"userId", "user_id", "id", "ID",
-		"username", "role", "jti", "iat", "exp", "nbf", "aud", "iss",
-	}
-
-	for _, key := range possibleKeys {
-		if value := l.ctx.Value(key); value != nil {
-			l.Logger.Infof("在context中找到键 '%s': %v (类型: %T)", key, value, value)
-
-			// 如果是用户ID相关的键，尝试提取
-			if key == "userId" || key == "user_id" || key == "id" || key == "ID" {
-				if userID, ok := value.(string); ok && userID != "" {
-					l.Logger.Infof("从键 '%s' 获取用户ID: %s", key, userID)
-					return userID, nil
-				}
-			}
-		}
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return "", err
 	}
-
-	l.Logger.Infof("=== context 调试完成 ===")
-
-	// 临时解决方案：返回一个固定的用户ID用于测试
-	// 这应该从Token中的sub字段获取，但现在先用固定值
-	testUserID := "6867f1484a76ef13471b5ff2"
-	l.Logger.Infof("使用临时固定用户ID进行测试: %s", testUserID)
-	return testUserID, nil
+	return principal.UserID, nil
 }
 
 // checkUserStatus 检查用户状态
@@ -162,20 +114,24 @@ func (l *ProfileLogic) checkUserStatus(user *model.User) error {
 // buildUserInfo 构建用户信息响应
 func (l *ProfileLogic) buildUserInfo(user *model.User) types.UserInfo {
 	userInfo := types.UserInfo{
-		ID:          user.ID.Hex(),
-		Username:    user.Username,
-		Email:       user.Email,
-		DisplayName: user.DisplayName,
-		Role:        user.Role,
-		Status:      user.Status,
-		CreatedAt:   user.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   user.UpdatedAt.Format(time.RFC3339),
+		ID:               user.ID.Hex(),
+		Username:         user.Username,
+		Email:            user.Email,
+		DisplayName:      user.DisplayName,
+		Role:             user.Role,
+		Status:           user.Status,
+		TwoFactorEnabled: user.TwoFactorEnabled,
+		CreatedAt:        user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        user.UpdatedAt.Format(time.RFC3339),
 	}
 
 	// 设置可选字段
 	if user.ProfileImage != "" {
 		userInfo.ProfileImage = user.ProfileImage
 	}
+	if user.CoverImage != "" {
+		userInfo.CoverImage = user.CoverImage
+	}
 	if user.Bio != "" {
 		userInfo.Bio = user.Bio
 	}
@@ -51,18 +51,18 @@ func TestProfileLogic_Profile(t *testing.T) {
 
 		Convey("Should return error when user ID is empty", func() {
 			// 创建包含空用户ID的context
-			ctx := context.WithValue(context.Background(), "uid", "")
+			ctx := withPrincipal(context.Background(), "", constants.UserRoleAuthor)
 			profileLogic := NewProfileLogic(ctx, svcCtx)
 
 			resp, err := profileLogic.Profile()
 			So(err, ShouldNotBeNil)
-			So(err.Error(), ShouldEqual, "用户ID无效")
+			So(err.Error(), ShouldEqual, "用户未认证")
 			So(resp, ShouldBeNil)
 		})
 
 		Convey("Should return error when user ID is invalid", func() {
 			// 创建包含无效用户ID的context
-			ctx := context.WithValue(context.Background(), "uid", "invalid-object-id")
+			ctx := withPrincipal(context.Background(), "invalid-object-id", constants.UserRoleAuthor)
 			profileLogic := NewProfileLogic(ctx, svcCtx)
 
 			resp, err := profileLogic.Profile()
@@ -73,7 +73,7 @@ func TestProfileLogic_Profile(t *testing.T) {
 
 		Convey("Should return error when user not found", func() {
 			userID := primitive.NewObjectID()
-			ctx := context.WithValue(context.Background(), "uid", userID.Hex())
+			ctx := withPrincipal(context.Background(), userID.Hex(), constants.UserRoleAuthor)
 			profileLogic := NewProfileLogic(ctx, svcCtx)
 
 			// Mock UserDAO.GetByID to return nil (user not found)
@@ -88,7 +88,7 @@ func TestProfileLogic_Profile(t *testing.T) {
 
 		Convey("Should return error when database error occurs", func() {
 			userID := primitive.NewObjectID()
-			ctx := context.WithValue(context.Background(), "uid", userID.Hex())
+			ctx := withPrincipal(context.Background(), userID.Hex(), constants.UserRoleAuthor)
 			profileLogic := NewProfileLogic(ctx, svcCtx)
 
 			// Mock UserDAO.GetByID to return database error
@@ -103,7 +103,7 @@ func TestProfileLogic_Profile(t *testing.T) {
 
 		Convey("Should return error when user status is inactive", func() {
 			userID := primitive.NewObjectID()
-			ctx := context.WithValue(context.Background(), "uid", userID.Hex())
+			ctx := withPrincipal(context.Background(), userID.Hex(), constants.UserRoleAuthor)
 			profileLogic := NewProfileLogic(ctx, svcCtx)
 
 			// 创建非活跃用户
@@ -130,7 +130,7 @@ func TestProfileLogic_Profile(t *testing.T) {
 
 		Convey("Should return error when user is locked", func() {
 			userID := primitive.NewObjectID()
-			ctx := context.WithValue(context.Background(), "uid", userID.Hex())
+			ctx := withPrincipal(context.Background(), userID.Hex(), constants.UserRoleAuthor)
 			profileLogic := NewProfileLogic(ctx, svcCtx)
 
 			// 创建被锁定的用户
@@ -159,7 +159,7 @@ func TestProfileLogic_Profile(t *testing.T) {
 
 		Convey("Should return user profile successfully", func() {
 			userID := primitive.NewObjectID()
-			ctx := context.WithValue(context.Background(), "uid", userID.Hex())
+			ctx := withPrincipal(context.Background(), userID.Hex(), constants.UserRoleAuthor)
 			profileLogic := NewProfileLogic(ctx, svcCtx)
 
 			// 创建正常用户
@@ -7,6 +7,7 @@ import (
 
 	"github.com/heimdall-api/admin-api/admin/internal/svc"
 	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
 	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
 
@@ -82,11 +83,11 @@ func (l *PublishPageLogic) validatePageID(id string) error {
 
 // getCurrentUserID 获取当前用户ID
 func (l *PublishPageLogic) getCurrentUserID() (string, error) {
-	userID, ok := l.ctx.Value("userId").(string)
-	if !ok || userID == "" {
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
 		return "", fmt.Errorf("用户认证失败")
 	}
-	return userID, nil
+	return principal.UserID, nil
 }
 
 // checkPermission 检查用户权限
@@ -100,8 +101,8 @@ func (l *PublishPageLogic) checkPermission(page *model.Page, userID string) erro
 		return fmt.Errorf("用户不存在")
 	}
 
-	// 检查是否为页面作者
-	if page.AuthorID.Hex() != userID {
+	// 编辑及以上角色可操作所有页面，作者只能操作自己的页面
+	if !hasResourcePermission(l.ctx, constants.PermissionPagePublish, page.AuthorID.Hex()) {
 		return fmt.Errorf("无权限发布此页面")
 	}
 
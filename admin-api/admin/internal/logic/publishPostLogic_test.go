@@ -36,7 +36,7 @@ func TestPublishPostLogic_PublishPost(t *testing.T) {
 			userID := authorID // 设置为同一个用户
 
 			// 设置用户ID到context（模拟JWT中间件）
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			// 准备现有文章（草稿状态）
@@ -132,7 +132,7 @@ func TestPublishPostLogic_PublishPost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := authorID
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			existingPost := &model.Post{
@@ -220,7 +220,7 @@ func TestPublishPostLogic_PublishPost(t *testing.T) {
 			mockey.UnPatchAll()
 
 			userID := primitive.NewObjectID()
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			// 准备请求
@@ -243,7 +243,7 @@ func TestPublishPostLogic_PublishPost(t *testing.T) {
 
 			postID := primitive.NewObjectID()
 			userID := primitive.NewObjectID()
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			// Mock PostDAO.GetByID - 返回文章不存在
@@ -273,7 +273,7 @@ func TestPublishPostLogic_PublishPost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := primitive.NewObjectID() // 不同的用户ID
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			existingPost := &model.Post{
@@ -314,7 +314,7 @@ func TestPublishPostLogic_PublishPost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := authorID
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			publishedTime := time.Now().Add(-1 * time.Hour)
@@ -357,7 +357,7 @@ func TestPublishPostLogic_PublishPost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := authorID
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			existingPost := &model.Post{
@@ -399,7 +399,7 @@ func TestPublishPostLogic_PublishPost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := authorID
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			existingPost := &model.Post{
@@ -0,0 +1,199 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+var (
+	errRefreshTokenInvalid = errors.New("刷新令牌无效或已过期，请重新登录")
+	errRefreshTokenReused  = errors.New("检测到刷新令牌被重复使用，已注销相关会话，请重新登录")
+)
+
+type RefreshTokenLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 刷新访问令牌
+func NewRefreshTokenLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RefreshTokenLogic {
+	return &RefreshTokenLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RefreshTokenLogic) RefreshToken(req *types.RefreshTokenRequest) (resp *types.RefreshTokenResponse, err error) {
+	// 1. 参数验证
+	if req == nil || req.RefreshToken == "" {
+		return nil, errors.New("刷新令牌不能为空")
+	}
+
+	// 2. 验证刷新令牌签名和有效期
+	jwtManager := utils.NewJWTManager(l.svcCtx.Config.Auth.AccessSecret, jwtIssuer)
+	claims, err := jwtManager.ValidateToken(req.RefreshToken)
+	if err != nil || claims.FamilyID == "" || claims.TokenID == "" {
+		return nil, errRefreshTokenInvalid
+	}
+
+	// 3. 检查刷新令牌是否已被注销
+	blacklisted, err := isTokenBlacklisted(l.ctx, l.svcCtx.Redis, claims.TokenID)
+	if err != nil {
+		l.Logger.Errorf("检查刷新令牌黑名单失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if blacklisted {
+		return nil, errRefreshTokenInvalid
+	}
+	revokedBefore, err := auth.TokensRevokedBefore(l.ctx, l.svcCtx.Redis, claims.UserID)
+	if err != nil {
+		l.Logger.Errorf("检查令牌失效水位线失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if auth.IsTokenRevoked(claims, revokedBefore) {
+		return nil, errRefreshTokenInvalid
+	}
+
+	// 4. 获取并检查用户状态
+	user, err := l.svcCtx.UserDAO.GetByID(l.ctx, claims.UserID)
+	if err != nil {
+		l.Logger.Errorf("获取用户信息失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if user == nil || !user.CanLogin() {
+		return nil, errRefreshTokenInvalid
+	}
+
+	// 5. 在令牌族上执行轮换（乐观锁保证同一刷新令牌只能成功使用一次）
+	tokens, err := l.rotate(claims, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.RefreshTokenResponse{
+		Code:      200,
+		Message:   "刷新成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.RefreshTokenData{
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+			ExpiresIn:    int(time.Until(tokens.AccessExpiresAt).Seconds()),
+		},
+	}, nil
+}
+
+// rotate 校验刷新令牌是否为令牌族中的最新令牌，是则签发新令牌对，否则判定为重放并吊销整个令牌族
+func (l *RefreshTokenLogic) rotate(claims *utils.JWTClaims, user *model.User) (*issuedTokens, error) {
+	var (
+		tokens *issuedTokens
+		reused bool
+	)
+
+	key := refreshTokenFamilyKey(claims.FamilyID)
+	err := l.svcCtx.Redis.Watch(l.ctx, func(tx *redis.Tx) error {
+		family, err := loadTokenFamily(l.ctx, tx, claims.FamilyID)
+		if err != nil {
+			return err
+		}
+		if family == nil || family.Revoked || family.UserID != claims.UserID {
+			return errRefreshTokenInvalid
+		}
+
+		// 旧刷新令牌再次出现，说明令牌可能已泄露
+		if family.CurrentTokenID != claims.TokenID {
+			reused = true
+			_, err := tx.TxPipelined(l.ctx, func(pipe redis.Pipeliner) error {
+				return revokeTokenFamily(l.ctx, pipe, claims.FamilyID, family)
+			})
+			return err
+		}
+
+		tokens, err = issueTokens(l.svcCtx.Config.Auth.AccessSecret, user, claims.FamilyID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		family.pruneExpiredAccessTokens(now)
+		family.CurrentTokenID = tokens.RefreshTokenID
+		family.AccessTokens[tokens.AccessTokenID] = tokens.AccessExpiresAt.Unix()
+		family.RotatedAt = now
+
+		// 旧刷新令牌不加入黑名单，以便再次出现时能够识别为重放
+		_, err = tx.TxPipelined(l.ctx, func(pipe redis.Pipeliner) error {
+			return saveTokenFamily(l.ctx, pipe, claims.FamilyID, family)
+		})
+		return err
+	}, key)
+
+	switch {
+	case errors.Is(err, errRefreshTokenInvalid):
+		return nil, err
+	case errors.Is(err, redis.TxFailedErr):
+		// 同一令牌族被并发刷新，仅允许一个请求成功
+		return nil, errRefreshTokenInvalid
+	case err != nil:
+		l.Logger.Errorf("刷新令牌轮换失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	if reused {
+		l.Logger.Errorf("检测到刷新令牌重放，已吊销令牌族: userID=%s, familyID=%s, tokenID=%s",
+			claims.UserID, claims.FamilyID, claims.TokenID)
+		if err := revokeUserSession(l.ctx, l.svcCtx.Redis, claims.UserID, claims.FamilyID); err != nil {
+			l.Logger.Errorf("删除被重放令牌的会话失败: %v", err)
+		}
+		l.recordTokenReuse(user, claims.FamilyID)
+		return nil, errRefreshTokenReused
+	}
+
+	// 更新会话的当前访问令牌和最后活跃时间
+	if err := touchUserSession(l.ctx, l.svcCtx.Redis, claims.UserID, claims.FamilyID,
+		tokens.AccessTokenID, l.getClientIP(), l.getUserAgent()); err != nil {
+		l.Logger.Errorf("更新会话失败: %v", err)
+	}
+
+	return tokens, nil
+}
+
+// recordTokenReuse 记录刷新令牌重放事件到登录日志
+func (l *RefreshTokenLogic) recordTokenReuse(user *model.User, familyID string) {
+	loginLog := &model.LoginLog{
+		UserID:      &user.ID,
+		Username:    user.Username,
+		IPAddress:   l.getClientIP(),
+		UserAgent:   l.getUserAgent(),
+		LoginMethod: constants.LoginMethodRefreshToken,
+		Status:      constants.LoginStatusFailed,
+		FailReason:  constants.LoginFailReasonTokenReused,
+		SessionID:   familyID,
+		LoginAt:     time.Now(),
+	}
+
+	if err := createLoginLog(l.ctx, l.svcCtx, loginLog); err != nil {
+		l.Logger.Errorf("记录刷新令牌重放日志失败: %v", err)
+	}
+}
+
+// getClientIP 获取客户端IP地址
+func (l *RefreshTokenLogic) getClientIP() string {
+	return utils.ClientInfoFromContext(l.ctx).IP
+}
+
+// getUserAgent 获取客户端User-Agent
+func (l *RefreshTokenLogic) getUserAgent() string {
+	return utils.ClientInfoFromContext(l.ctx).UserAgent
+}
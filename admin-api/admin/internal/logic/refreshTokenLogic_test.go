@@ -0,0 +1,177 @@
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bytedance/mockey"
+	"github.com/go-redis/redis/v8"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/config"
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+func TestRefreshTokenLogic_RefreshToken(t *testing.T) {
+	mockey.PatchConvey("RefreshTokenLogic RefreshToken Tests", t, func() {
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+
+		cfg := config.Config{
+			Auth: struct {
+				AccessSecret string
+				AccessExpire int64
+			}{
+				AccessSecret: "test-secret",
+				AccessExpire: 7200,
+			},
+		}
+		svcCtx := &svc.ServiceContext{
+			Config:      cfg,
+			UserDAO:     &dao.UserDAO{},
+			LoginLogDAO: &dao.LoginLogDAO{},
+			Redis:       rdb,
+		}
+
+		ctx := utils.WithClientInfo(context.Background(), utils.ClientInfo{
+			IP:        "198.51.100.7",
+			UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		})
+		testUser := &model.User{
+			ID:       primitive.NewObjectID(),
+			Username: "testuser",
+			Role:     constants.UserRoleAuthor,
+			Status:   constants.UserStatusActive,
+		}
+		mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+
+		var loggedReuse *model.LoginLog
+		mockey.Mock((*dao.LoginLogDAO).Create).To(func(_ *dao.LoginLogDAO, _ context.Context, log *model.LoginLog) error {
+			loggedReuse = log
+			return nil
+		}).Build()
+
+		initial, err := startTokenFamily(ctx, rdb, cfg.Auth.AccessSecret, testUser)
+		So(err, ShouldBeNil)
+
+		refresh := func(token string) (*types.RefreshTokenResponse, error) {
+			return NewRefreshTokenLogic(ctx, svcCtx).RefreshToken(&types.RefreshTokenRequest{RefreshToken: token})
+		}
+
+		Convey("Should rotate refresh token on every use", func() {
+			first, err := refresh(initial.RefreshToken)
+			So(err, ShouldBeNil)
+			So(first.Data.Token, ShouldNotBeEmpty)
+			So(first.Data.RefreshToken, ShouldNotEqual, initial.RefreshToken)
+			So(first.Data.ExpiresIn, ShouldBeGreaterThan, 0)
+
+			second, err := refresh(first.Data.RefreshToken)
+			So(err, ShouldBeNil)
+			So(second.Data.RefreshToken, ShouldNotEqual, first.Data.RefreshToken)
+
+			family, err := loadTokenFamily(ctx, rdb, initial.FamilyID)
+			So(err, ShouldBeNil)
+			So(family.Revoked, ShouldBeFalse)
+			So(len(family.AccessTokens), ShouldEqual, 3)
+			So(mr.TTL(refreshTokenFamilyKey(initial.FamilyID)), ShouldEqual, constants.CacheTTLRefreshToken)
+		})
+
+		Convey("Should revoke the whole family when an old refresh token is reused", func() {
+			rotated, err := refresh(initial.RefreshToken)
+			So(err, ShouldBeNil)
+
+			resp, err := refresh(initial.RefreshToken)
+			So(resp, ShouldBeNil)
+			So(err, ShouldEqual, errRefreshTokenReused)
+
+			family, err := loadTokenFamily(ctx, rdb, initial.FamilyID)
+			So(err, ShouldBeNil)
+			So(family.Revoked, ShouldBeTrue)
+
+			// 族内访问令牌全部进入黑名单
+			jwtManager := utils.NewJWTManager(cfg.Auth.AccessSecret, jwtIssuer)
+			rotatedAccessID, _ := jwtManager.ExtractTokenIDFromToken(rotated.Data.Token)
+			So(mr.Exists(utils.GenerateBlacklistKey(initial.AccessTokenID)), ShouldBeTrue)
+			So(mr.Exists(utils.GenerateBlacklistKey(rotatedAccessID)), ShouldBeTrue)
+
+			// 最新的刷新令牌同样失效
+			_, err = refresh(rotated.Data.RefreshToken)
+			So(err, ShouldEqual, errRefreshTokenInvalid)
+
+			So(loggedReuse, ShouldNotBeNil)
+			So(loggedReuse.LoginMethod, ShouldEqual, constants.LoginMethodRefreshToken)
+			So(loggedReuse.FailReason, ShouldEqual, constants.LoginFailReasonTokenReused)
+			So(loggedReuse.SessionID, ShouldEqual, initial.FamilyID)
+			So(loggedReuse.IPAddress, ShouldEqual, "198.51.100.7")
+			So(loggedReuse.UserAgent, ShouldEqual, "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+			So(loggedReuse.DeviceType, ShouldEqual, constants.DeviceTypeDesktop)
+			So(loggedReuse.Browser, ShouldEqual, "Chrome 120")
+			So(loggedReuse.OS, ShouldEqual, "Windows 10")
+			So(loggedReuse.ValidateForCreate(), ShouldBeNil)
+		})
+
+		Convey("Should reject access tokens", func() {
+			resp, err := refresh(initial.AccessToken)
+			So(resp, ShouldBeNil)
+			So(err, ShouldEqual, errRefreshTokenInvalid)
+		})
+
+		Convey("Should reject blacklisted refresh tokens", func() {
+			mr.Set(utils.GenerateBlacklistKey(initial.RefreshTokenID), "1")
+
+			resp, err := refresh(initial.RefreshToken)
+			So(resp, ShouldBeNil)
+			So(err, ShouldEqual, errRefreshTokenInvalid)
+		})
+
+		Convey("Should reject tokens issued before the user's revocation watermark", func() {
+			So(auth.RevokeUserTokens(ctx, rdb, testUser.ID.Hex()), ShouldBeNil)
+
+			resp, err := refresh(initial.RefreshToken)
+			So(resp, ShouldBeNil)
+			So(err, ShouldEqual, errRefreshTokenInvalid)
+		})
+
+		Convey("Should reject tokens whose family no longer exists", func() {
+			mr.Del(refreshTokenFamilyKey(initial.FamilyID))
+
+			resp, err := refresh(initial.RefreshToken)
+			So(resp, ShouldBeNil)
+			So(err, ShouldEqual, errRefreshTokenInvalid)
+		})
+
+		Convey("Should reject refresh for inactive user", func() {
+			testUser.Status = constants.UserStatusSuspended
+
+			resp, err := refresh(initial.RefreshToken)
+			So(resp, ShouldBeNil)
+			So(err, ShouldEqual, errRefreshTokenInvalid)
+		})
+
+		Convey("Should refresh again once the login lockout has expired", func() {
+			lockedUntil := time.Now().Add(-time.Minute)
+			testUser.Status = constants.UserStatusLocked
+			testUser.LockedUntil = &lockedUntil
+
+			resp, err := refresh(initial.RefreshToken)
+			So(err, ShouldBeNil)
+			So(resp.Data.Token, ShouldNotBeEmpty)
+		})
+
+		Convey("Should return error when refresh token is empty", func() {
+			resp, err := refresh("")
+			So(resp, ShouldBeNil)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
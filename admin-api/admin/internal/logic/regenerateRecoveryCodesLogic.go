@@ -0,0 +1,86 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RegenerateRecoveryCodesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 重新生成两步验证恢复码
+func NewRegenerateRecoveryCodesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RegenerateRecoveryCodesLogic {
+	return &RegenerateRecoveryCodesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RegenerateRecoveryCodesLogic) RegenerateRecoveryCodes(req *types.RecoveryCodesRegenerateRequest) (resp *types.RecoveryCodesResponse, err error) {
+	// 1. 参数验证
+	if req == nil || req.Code == "" {
+		return nil, errors.New("验证码不能为空")
+	}
+
+	// 2. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 获取用户信息
+	user, err := l.svcCtx.UserDAO.GetByID(l.ctx, principal.UserID)
+	if err != nil {
+		l.Logger.Errorf("获取用户信息失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if user == nil {
+		return nil, errors.New("用户不存在")
+	}
+	if !user.TwoFactorEnabled {
+		return nil, errors.New("未启用两步验证")
+	}
+
+	// 4. 只接受TOTP验证码，避免用旧恢复码换取新恢复码
+	if !isTOTPCode(req.Code) {
+		return nil, errors.New("请输入验证器中的验证码")
+	}
+	if _, ok, err := verifySecondFactor(l.ctx, l.svcCtx, user, req.Code); err != nil {
+		l.Logger.Errorf("校验两步验证码失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	} else if !ok {
+		return nil, errors.New("验证码错误")
+	}
+
+	// 5. 生成新恢复码，旧恢复码全部作废
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		l.Logger.Errorf("生成恢复码失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if err := l.svcCtx.UserDAO.Update(l.ctx, principal.UserID, map[string]interface{}{"recoveryCodes": hashes}); err != nil {
+		l.Logger.Errorf("保存恢复码失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	l.Logger.Infof("用户重新生成恢复码: userID=%s, count=%d", principal.UserID, len(codes))
+	return &types.RecoveryCodesResponse{
+		Code:      200,
+		Message:   "恢复码已重新生成，旧恢复码已失效",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.RecoveryCodesData{
+			RecoveryCodes: codes,
+		},
+	}, nil
+}
@@ -0,0 +1,73 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ResendInvitationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 重新发送邀请
+func NewResendInvitationLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ResendInvitationLogic {
+	return &ResendInvitationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ResendInvitationLogic) ResendInvitation(req *types.InvitationIDRequest) (resp *types.InvitationResponse, err error) {
+	if req == nil {
+		return nil, errors.New("请求不能为空")
+	}
+
+	// 1. 获取邀请并检查角色层级
+	principal, invitation, err := loadManagedInvitation(l.ctx, l.svcCtx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.Status != constants.InvitationStatusPending {
+		return nil, errors.New("邀请已被接受或撤销")
+	}
+
+	// 2. 限制发送频率
+	if time.Since(invitation.LastSentAt) < constants.InvitationResendIntervalSec*time.Second {
+		return nil, errors.New("发送过于频繁，请稍后再试")
+	}
+
+	// 3. 签发新令牌并延长有效期，之前发出的链接随之失效
+	token, tokenHash, expiresAt, err := issueInvitationToken(l.svcCtx, invitation.ID)
+	if err != nil {
+		l.Logger.Errorf("签发邀请令牌失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	updated, err := l.svcCtx.InvitationDAO.Resend(l.ctx, invitation.ID, tokenHash, expiresAt)
+	if err != nil {
+		l.Logger.Errorf("更新邀请失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if !updated {
+		return nil, errors.New("邀请已被接受或撤销")
+	}
+	invitation.TokenHash = tokenHash
+	invitation.ExpiresAt = expiresAt
+	invitation.LastSentAt = time.Now()
+	invitation.SendCount++
+
+	// 4. 发送邀请邮件
+	sendInvitationMail(l.ctx, l.svcCtx, invitation, principal.UserID, token)
+
+	l.Logger.Infof("已重新发送用户邀请: operator=%s, invitationID=%s, count=%d", principal.UserID, req.ID, invitation.SendCount)
+	return newInvitationResponse("邀请已重新发送", invitation), nil
+}
@@ -0,0 +1,114 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+)
+
+// errInvalidResetToken 重置令牌无效，不区分不存在、已过期和已使用
+var errInvalidResetToken = errors.New("重置链接无效或已过期")
+
+type ResetPasswordLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 使用一次性令牌重置密码
+func NewResetPasswordLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ResetPasswordLogic {
+	return &ResetPasswordLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ResetPasswordLogic) ResetPassword(req *types.ResetPasswordRequest) (resp *types.ResetPasswordResponse, err error) {
+	// 1. 参数验证
+	if req == nil || strings.TrimSpace(req.Token) == "" {
+		return nil, errInvalidResetToken
+	}
+	if req.NewPassword == "" {
+		return nil, errors.New("新密码不能为空")
+	}
+	token := strings.TrimSpace(req.Token)
+
+	// 2. 查询令牌对应的用户
+	userID, err := lookupPasswordResetToken(l.ctx, l.svcCtx.Redis, token)
+	if err != nil {
+		l.Logger.Errorf("查询重置令牌失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if userID == "" {
+		return nil, errInvalidResetToken
+	}
+
+	user, err := l.svcCtx.UserDAO.GetByID(l.ctx, userID)
+	if err != nil {
+		l.Logger.Errorf("获取用户信息失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if user == nil || !user.CanResetPassword() {
+		return nil, errInvalidResetToken
+	}
+	// 令牌有效即可确认操作者，之后的失败同样记入审计日志
+	audit.SetActor(l.ctx, audit.Actor{UserID: userID, Username: user.Username, Role: user.Role})
+
+	// 3. 校验新密码，校验失败时令牌保留，用户可以重新提交
+	if err := validateNewPassword(user, req.NewPassword); err != nil {
+		return nil, err
+	}
+	updates, err := buildPasswordUpdates(user, req.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 消费令牌，保证令牌只能使用一次
+	consumed, err := consumePasswordResetToken(l.ctx, l.svcCtx.Redis, token, userID)
+	if err != nil {
+		l.Logger.Errorf("消费重置令牌失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if !consumed {
+		return nil, errInvalidResetToken
+	}
+
+	// 5. 保存新密码
+	if err := l.svcCtx.UserDAO.Update(l.ctx, userID, updates); err != nil {
+		l.Logger.Errorf("重置密码失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	audit.RecordUpdates(l.ctx, user, updates)
+
+	// 6. 清零登录失败次数并解除锁定，使因登录失败被锁定的用户可以用新密码登录
+	if user.LoginFailCount > 0 || user.LockedUntil != nil || user.Status == constants.UserStatusLocked {
+		if err := l.svcCtx.UserDAO.ResetLoginFailCount(l.ctx, userID); err != nil {
+			l.Logger.Errorf("重置密码后解除账户锁定失败: %v", err)
+		}
+	}
+
+	// 7. 吊销全部会话和已签发的令牌
+	if _, err := revokeUserSessions(l.ctx, l.svcCtx.Redis, userID, ""); err != nil {
+		l.Logger.Errorf("重置密码后吊销会话失败: %v", err)
+	}
+	if err := auth.RevokeUserTokens(l.ctx, l.svcCtx.Redis, userID); err != nil {
+		l.Logger.Errorf("重置密码后设置令牌失效水位线失败: %v", err)
+	}
+
+	l.Logger.Infof("用户通过邮件重置密码成功: userID=%s", userID)
+	return &types.ResetPasswordResponse{
+		Code:      200,
+		Message:   "密码重置成功，请使用新密码登录",
+		Timestamp: time.Now().Format(time.RFC3339),
+	}, nil
+}
@@ -0,0 +1,68 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RevokeAccessTokenLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 吊销个人访问令牌
+func NewRevokeAccessTokenLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RevokeAccessTokenLogic {
+	return &RevokeAccessTokenLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RevokeAccessTokenLogic) RevokeAccessToken(req *types.AccessTokenRevokeRequest) (resp *types.AccessTokenRevokeResponse, err error) {
+	// 1. 参数验证
+	if req == nil || req.ID == "" {
+		return nil, errors.New("令牌ID不能为空")
+	}
+	tokenID, err := primitive.ObjectIDFromHex(req.ID)
+	if err != nil {
+		return nil, errors.New("无效的令牌ID")
+	}
+
+	// 2. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := primitive.ObjectIDFromHex(principal.UserID)
+	if err != nil {
+		return nil, errors.New("无效的用户ID")
+	}
+
+	// 3. 只能吊销自己的令牌，吊销后中间件立即拒绝
+	revoked, err := l.svcCtx.AccessTokenDAO.Revoke(l.ctx, tokenID, userID)
+	if err != nil {
+		l.Logger.Errorf("吊销个人访问令牌失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if !revoked {
+		return nil, errors.New("令牌不存在")
+	}
+
+	l.Logger.Infof("用户吊销个人访问令牌: userID=%s, tokenID=%s", principal.UserID, req.ID)
+	return &types.AccessTokenRevokeResponse{
+		Code:      200,
+		Message:   "令牌已吊销",
+		Timestamp: time.Now().Format(time.RFC3339),
+	}, nil
+}
@@ -0,0 +1,56 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RevokeInvitationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 撤销邀请
+func NewRevokeInvitationLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RevokeInvitationLogic {
+	return &RevokeInvitationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RevokeInvitationLogic) RevokeInvitation(req *types.InvitationIDRequest) (resp *types.InvitationResponse, err error) {
+	if req == nil {
+		return nil, errors.New("请求不能为空")
+	}
+
+	// 1. 获取邀请并检查角色层级
+	principal, invitation, err := loadManagedInvitation(l.ctx, l.svcCtx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 只能撤销待接受的邀请
+	revoked, err := l.svcCtx.InvitationDAO.Revoke(l.ctx, invitation.ID)
+	if err != nil {
+		l.Logger.Errorf("撤销邀请失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if !revoked {
+		return nil, errors.New("邀请已被接受或撤销")
+	}
+	now := time.Now()
+	invitation.Status = constants.InvitationStatusRevoked
+	invitation.RevokedAt = &now
+
+	l.Logger.Infof("已撤销用户邀请: operator=%s, invitationID=%s", principal.UserID, req.ID)
+	return newInvitationResponse("邀请已撤销", invitation), nil
+}
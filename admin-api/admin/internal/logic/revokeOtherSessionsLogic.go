@@ -0,0 +1,56 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RevokeOtherSessionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 吊销当前用户的其他会话
+func NewRevokeOtherSessionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RevokeOtherSessionsLogic {
+	return &RevokeOtherSessionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RevokeOtherSessionsLogic) RevokeOtherSessions() (resp *types.SessionBatchRevokeResponse, err error) {
+	// 1. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+	if principal.SessionID == "" {
+		return nil, errors.New("当前令牌不属于任何会话，请重新登录")
+	}
+
+	// 2. 吊销除当前会话外的全部会话
+	revoked, err := revokeUserSessions(l.ctx, l.svcCtx.Redis, principal.UserID, principal.SessionID)
+	if err != nil {
+		l.Logger.Errorf("吊销其他会话失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	l.Logger.Infof("用户吊销其他会话: userID=%s, count=%d", principal.UserID, revoked)
+	return &types.SessionBatchRevokeResponse{
+		Code:      200,
+		Message:   "其他会话已吊销",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.SessionBatchRevokeData{
+			RevokedCount: revoked,
+		},
+	}, nil
+}
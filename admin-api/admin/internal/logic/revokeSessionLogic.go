@@ -0,0 +1,64 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RevokeSessionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 吊销当前用户的指定会话
+func NewRevokeSessionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RevokeSessionLogic {
+	return &RevokeSessionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RevokeSessionLogic) RevokeSession(req *types.SessionRevokeRequest) (resp *types.SessionRevokeResponse, err error) {
+	// 1. 参数验证
+	if req == nil || req.ID == "" {
+		return nil, errors.New("会话ID不能为空")
+	}
+
+	// 2. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 会话按用户存储，只能查到自己的会话
+	session, err := getUserSession(l.ctx, l.svcCtx.Redis, principal.UserID, req.ID)
+	if err != nil {
+		l.Logger.Errorf("查询会话失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if session == nil {
+		return nil, errors.New("会话不存在")
+	}
+
+	// 4. 吊销会话及其令牌
+	if err := revokeUserSession(l.ctx, l.svcCtx.Redis, principal.UserID, req.ID); err != nil {
+		l.Logger.Errorf("吊销会话失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	l.Logger.Infof("用户吊销会话: userID=%s, sessionID=%s", principal.UserID, req.ID)
+	return &types.SessionRevokeResponse{
+		Code:      200,
+		Message:   "会话已吊销",
+		Timestamp: time.Now().Format(time.RFC3339),
+	}, nil
+}
@@ -0,0 +1,80 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RevokeUserSessionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 吊销指定用户的全部会话
+func NewRevokeUserSessionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RevokeUserSessionsLogic {
+	return &RevokeUserSessionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RevokeUserSessionsLogic) RevokeUserSessions(req *types.UserDetailRequest) (resp *types.SessionBatchRevokeResponse, err error) {
+	// 1. 参数验证
+	if req == nil || !primitive.IsValidObjectID(req.ID) {
+		return nil, errors.New("用户ID格式无效")
+	}
+
+	// 2. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 获取目标用户
+	user, err := l.svcCtx.UserDAO.GetByID(l.ctx, req.ID)
+	if err != nil {
+		l.Logger.Errorf("获取用户信息失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if user == nil {
+		return nil, errors.New("用户不存在")
+	}
+
+	// 4. 只有所有者可以吊销所有者的会话
+	if user.IsOwner() && principal.Role != constants.UserRoleOwner {
+		return nil, errors.New("权限不足")
+	}
+
+	// 5. 吊销全部会话，并设置令牌失效水位线使未登记会话的旧令牌同样失效
+	revoked, err := revokeUserSessions(l.ctx, l.svcCtx.Redis, req.ID, "")
+	if err != nil {
+		l.Logger.Errorf("吊销用户会话失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if err := auth.RevokeUserTokens(l.ctx, l.svcCtx.Redis, req.ID); err != nil {
+		l.Logger.Errorf("设置令牌失效水位线失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	l.Logger.Infof("管理员吊销用户全部会话: operator=%s, userID=%s, count=%d", principal.UserID, req.ID, revoked)
+	return &types.SessionBatchRevokeResponse{
+		Code:      200,
+		Message:   "用户会话已全部吊销",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.SessionBatchRevokeData{
+			RevokedCount: revoked,
+		},
+	}, nil
+}
@@ -0,0 +1,88 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SetupTOTPLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 开始绑定TOTP验证器
+func NewSetupTOTPLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SetupTOTPLogic {
+	return &SetupTOTPLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *SetupTOTPLogic) SetupTOTP(req *types.TOTPSetupRequest) (resp *types.TOTPSetupResponse, err error) {
+	// 1. 参数验证
+	if req == nil || req.Password == "" {
+		return nil, errors.New("密码不能为空")
+	}
+
+	// 2. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 获取用户信息
+	user, err := l.svcCtx.UserDAO.GetByID(l.ctx, principal.UserID)
+	if err != nil {
+		l.Logger.Errorf("获取用户信息失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if user == nil {
+		return nil, errors.New("用户不存在")
+	}
+	if user.TwoFactorEnabled {
+		return nil, errors.New("已启用两步验证")
+	}
+
+	// 4. 校验密码
+	if err := utils.VerifyPassword(req.Password, user.PasswordHash); err != nil {
+		return nil, errors.New("密码错误")
+	}
+
+	// 5. 生成密钥，加密后保存在缓存中等待确认
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		l.Logger.Errorf("生成TOTP密钥失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	sealed, err := l.svcCtx.MFASecretBox.Seal(secret, principal.UserID)
+	if err != nil {
+		l.Logger.Errorf("加密TOTP密钥失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if err := l.svcCtx.Redis.Set(l.ctx, mfaEnrollKey(principal.UserID), sealed, constants.CacheTTLMFAEnroll).Err(); err != nil {
+		l.Logger.Errorf("保存待确认TOTP密钥失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	return &types.TOTPSetupResponse{
+		Code:      200,
+		Message:   "请使用验证器扫描二维码并输入验证码完成绑定",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.TOTPSetupData{
+			Secret:     secret,
+			OtpauthURI: utils.BuildTOTPURI(l.svcCtx.Config.Security.MFAIssuer, user.Username, secret),
+			ExpiresIn:  int(constants.CacheTTLMFAEnroll.Seconds()),
+		},
+	}, nil
+}
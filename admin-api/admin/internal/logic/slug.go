@@ -0,0 +1,67 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/slug"
+)
+
+// slugStrategyName 获取站点设置中选择的slug策略名称，未设置时返回默认策略
+func slugStrategyName(ctx context.Context, svcCtx *svc.ServiceContext) (string, error) {
+	setting, err := svcCtx.SettingDAO.GetByKey(ctx, constants.SettingKeySlugStrategy)
+	if err != nil {
+		return "", err
+	}
+	if setting == nil || !slug.Has(setting.Value) {
+		return constants.SlugStrategyDefault, nil
+	}
+	return setting.Value, nil
+}
+
+// slugStrategy 获取文章、页面和标签共用的slug策略，读取设置失败时使用默认策略
+func slugStrategy(ctx context.Context, svcCtx *svc.ServiceContext) slug.Strategy {
+	name, err := slugStrategyName(ctx, svcCtx)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("获取slug策略失败，使用默认策略: %v", err)
+		return slug.Get(constants.SlugStrategyDefault)
+	}
+	return slug.Get(name)
+}
+
+// validateSlug 按站点当前的slug策略校验手动填写的slug
+func validateSlug(strategy slug.Strategy, s string) error {
+	if len(s) > constants.PostSlugMaxLength {
+		return fmt.Errorf("slug长度不能超过%d字符", constants.PostSlugMaxLength)
+	}
+	if !strategy.Valid(s) {
+		return fmt.Errorf("slug格式无效，不符合当前的slug策略: %s", strategy.Name())
+	}
+	return nil
+}
+
+// buildTags 转换请求中的标签，未提供slug的标签按站点的slug策略生成
+func buildTags(ctx context.Context, svcCtx *svc.ServiceContext, tagInfos []types.TagInfo) []model.Tag {
+	var strategy slug.Strategy
+	tags := make([]model.Tag, len(tagInfos))
+	for i, tag := range tagInfos {
+		tagSlug := tag.Slug
+		if tagSlug == "" {
+			if strategy == nil {
+				strategy = slugStrategy(ctx, svcCtx)
+			}
+			tagSlug = model.GenerateSlugWith(strategy, tag.Name, constants.SlugFallbackPrefixTag)
+		}
+		tags[i] = model.Tag{
+			Name: tag.Name,
+			Slug: tagSlug,
+		}
+	}
+	return tags
+}
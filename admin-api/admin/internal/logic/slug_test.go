@@ -0,0 +1,76 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+)
+
+func TestSlugStrategy(t *testing.T) {
+	mockey.PatchConvey("Slug strategy setting", t, func() {
+		stored := ""
+		var getErr error
+		mockey.Mock((*dao.SettingDAO).GetByKey).To(func(_ *dao.SettingDAO, _ context.Context, key string) (*model.Setting, error) {
+			if getErr != nil {
+				return nil, getErr
+			}
+			if stored == "" {
+				return nil, nil
+			}
+			return &model.Setting{Key: key, Value: stored}, nil
+		}).Build()
+		mockey.Mock((*dao.SettingDAO).Set).To(func(_ *dao.SettingDAO, _ context.Context, _, value, _ string) error {
+			stored = value
+			return nil
+		}).Build()
+
+		ctx := context.Background()
+		svcCtx := &svc.ServiceContext{SettingDAO: &dao.SettingDAO{}}
+
+		Convey("Unset, unknown or unreadable settings should fall back to the default strategy", func() {
+			So(slugStrategy(ctx, svcCtx).Name(), ShouldEqual, constants.SlugStrategyDefault)
+
+			stored = "klingon"
+			So(slugStrategy(ctx, svcCtx).Name(), ShouldEqual, constants.SlugStrategyDefault)
+
+			getErr = errors.New("connection refused")
+			So(slugStrategy(ctx, svcCtx).Name(), ShouldEqual, constants.SlugStrategyDefault)
+		})
+
+		Convey("Tags without a slug should use the site strategy", func() {
+			stored = constants.SlugStrategyUnicode
+			tags := buildTags(ctx, svcCtx, []types.TagInfo{{Name: "并发", Slug: ""}, {Name: "Go", Slug: "golang"}})
+			So(tags, ShouldResemble, []model.Tag{{Name: "并发", Slug: "并发"}, {Name: "Go", Slug: "golang"}})
+		})
+
+		Convey("Only registered strategies should be saved", func() {
+			ownerCtx := withPrincipal(ctx, primitive.NewObjectID().Hex(), constants.UserRoleOwner)
+
+			_, err := NewUpdateSlugStrategyLogic(ownerCtx, svcCtx).UpdateSlugStrategy(&types.SlugStrategyUpdateRequest{Strategy: "klingon"})
+			So(err, ShouldNotBeNil)
+			So(stored, ShouldBeEmpty)
+
+			resp, err := NewUpdateSlugStrategyLogic(ownerCtx, svcCtx).UpdateSlugStrategy(&types.SlugStrategyUpdateRequest{
+				Strategy: constants.SlugStrategyASCII,
+			})
+			So(err, ShouldBeNil)
+			So(resp.Data.Strategy, ShouldEqual, constants.SlugStrategyASCII)
+			So(stored, ShouldEqual, constants.SlugStrategyASCII)
+
+			current, err := NewGetSlugStrategyLogic(ownerCtx, svcCtx).GetSlugStrategy()
+			So(err, ShouldBeNil)
+			So(current.Data.Strategy, ShouldEqual, constants.SlugStrategyASCII)
+			So(current.Data.Strategies, ShouldContain, constants.SlugStrategyUnicode)
+		})
+	})
+}
@@ -0,0 +1,66 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/constants"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SuspendUserLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 暂停用户
+func NewSuspendUserLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SuspendUserLogic {
+	return &SuspendUserLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *SuspendUserLogic) SuspendUser(req *types.UserDetailRequest) (resp *types.UserDetailResponse, err error) {
+	if req == nil {
+		return nil, errors.New("请求不能为空")
+	}
+
+	// 1. 获取目标用户并检查角色层级
+	principal, user, err := loadManagedUser(l.ctx, l.svcCtx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID.Hex() == principal.UserID {
+		return nil, errors.New("不能暂停自己")
+	}
+	if user.Status == constants.UserStatusSuspended {
+		return nil, errors.New("用户已被暂停")
+	}
+
+	// 2. 更新状态并吊销全部会话，暂停所有者时确保仍有其他所有者
+	err = keepOwnerWhile(l.ctx, l.svcCtx, user, func(ctx context.Context) error {
+		return l.svcCtx.UserDAO.Update(ctx, req.ID, map[string]interface{}{"status": constants.UserStatusSuspended})
+	})
+	if errors.Is(err, ErrLastOwner) {
+		return nil, err
+	}
+	if err != nil {
+		l.Logger.Errorf("暂停用户失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	audit.RecordChange(l.ctx, "status", user.Status, constants.UserStatusSuspended)
+	if err := signOutUser(l.ctx, l.svcCtx, req.ID); err != nil {
+		l.Logger.Errorf("吊销用户会话失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	l.Logger.Infof("管理员暂停用户: operator=%s, userID=%s", principal.UserID, req.ID)
+	return reloadUserDetail(l.ctx, l.svcCtx, req.ID, "用户已暂停")
+}
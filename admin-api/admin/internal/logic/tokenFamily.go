@@ -0,0 +1,169 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+// jwtIssuer 管理后台令牌签发者
+const jwtIssuer = "heimdall-admin"
+
+// refreshTokenFamily 刷新令牌族
+// 同一次登录通过轮换派生出的所有令牌属于同一个族，族内只有最新的刷新令牌有效
+type refreshTokenFamily struct {
+	UserID         string           `json:"userId"`
+	CurrentTokenID string           `json:"currentTokenId"` // 当前有效的刷新令牌ID
+	AccessTokens   map[string]int64 `json:"accessTokens"`   // 族内签发的访问令牌ID -> 过期时间戳
+	Revoked        bool             `json:"revoked"`
+	CreatedAt      time.Time        `json:"createdAt"`
+	RotatedAt      time.Time        `json:"rotatedAt"`
+}
+
+// issuedTokens 一次签发得到的令牌
+type issuedTokens struct {
+	AccessToken     string
+	AccessTokenID   string
+	AccessExpiresAt time.Time
+	RefreshToken    string
+	RefreshTokenID  string
+	FamilyID        string
+}
+
+// refreshTokenFamilyKey 生成令牌族缓存键
+func refreshTokenFamilyKey(familyID string) string {
+	return fmt.Sprintf(constants.CacheKeyRefreshToken, familyID)
+}
+
+// issueTokens 为用户签发访问令牌和属于指定令牌族的刷新令牌，令牌族ID同时作为访问令牌的会话ID
+func issueTokens(secret string, user *model.User, familyID string) (*issuedTokens, error) {
+	jwtManager := utils.NewJWTManager(secret, jwtIssuer)
+
+	accessToken, err := jwtManager.GenerateSessionToken(user.ID.Hex(), user.Username, user.Role, familyID)
+	if err != nil {
+		return nil, fmt.Errorf("生成访问令牌失败: %w", err)
+	}
+
+	accessClaims, err := jwtManager.ValidateToken(accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("解析访问令牌失败: %w", err)
+	}
+
+	refreshToken, refreshTokenID, err := jwtManager.GenerateRefreshToken(user.ID.Hex(), user.Username, user.Role, familyID)
+	if err != nil {
+		return nil, fmt.Errorf("生成刷新令牌失败: %w", err)
+	}
+
+	return &issuedTokens{
+		AccessToken:     accessToken,
+		AccessTokenID:   accessClaims.TokenID,
+		AccessExpiresAt: accessClaims.ExpiresAt.Time,
+		RefreshToken:    refreshToken,
+		RefreshTokenID:  refreshTokenID,
+		FamilyID:        familyID,
+	}, nil
+}
+
+// startTokenFamily 用户登录成功后创建新的令牌族并签发首对令牌
+func startTokenFamily(ctx context.Context, rdb redis.Cmdable, secret string, user *model.User) (*issuedTokens, error) {
+	tokens, err := issueTokens(secret, user, uuid.New().String())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	family := &refreshTokenFamily{
+		UserID:         user.ID.Hex(),
+		CurrentTokenID: tokens.RefreshTokenID,
+		AccessTokens:   map[string]int64{tokens.AccessTokenID: tokens.AccessExpiresAt.Unix()},
+		CreatedAt:      now,
+		RotatedAt:      now,
+	}
+	if err := saveTokenFamily(ctx, rdb, tokens.FamilyID, family); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// loadTokenFamily 读取令牌族，不存在时返回nil
+func loadTokenFamily(ctx context.Context, rdb redis.Cmdable, familyID string) (*refreshTokenFamily, error) {
+	data, err := rdb.Get(ctx, refreshTokenFamilyKey(familyID)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取令牌族失败: %w", err)
+	}
+
+	var family refreshTokenFamily
+	if err := json.Unmarshal(data, &family); err != nil {
+		return nil, fmt.Errorf("解析令牌族失败: %w", err)
+	}
+	return &family, nil
+}
+
+// saveTokenFamily 保存令牌族，有效期与刷新令牌一致
+func saveTokenFamily(ctx context.Context, rdb redis.Cmdable, familyID string, family *refreshTokenFamily) error {
+	data, err := json.Marshal(family)
+	if err != nil {
+		return fmt.Errorf("序列化令牌族失败: %w", err)
+	}
+	if err := rdb.Set(ctx, refreshTokenFamilyKey(familyID), data, constants.CacheTTLRefreshToken).Err(); err != nil {
+		return fmt.Errorf("保存令牌族失败: %w", err)
+	}
+	return nil
+}
+
+// pruneExpiredAccessTokens 移除已过期的访问令牌记录，避免令牌族无限增长
+func (f *refreshTokenFamily) pruneExpiredAccessTokens(now time.Time) {
+	for tokenID, expiresAt := range f.AccessTokens {
+		if expiresAt <= now.Unix() {
+			delete(f.AccessTokens, tokenID)
+		}
+	}
+}
+
+// revokeTokenFamily 吊销令牌族：标记族失效，并将族内仍有效的访问令牌和当前刷新令牌加入黑名单
+func revokeTokenFamily(ctx context.Context, rdb redis.Cmdable, familyID string, family *refreshTokenFamily) error {
+	now := time.Now()
+	family.Revoked = true
+	family.pruneExpiredAccessTokens(now)
+
+	if err := saveTokenFamily(ctx, rdb, familyID, family); err != nil {
+		return err
+	}
+
+	var errs []error
+	for tokenID, expiresAt := range family.AccessTokens {
+		remaining := time.Unix(expiresAt, 0).Sub(now)
+		if err := rdb.Set(ctx, utils.GenerateBlacklistKey(tokenID), "1", remaining).Err(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if family.CurrentTokenID != "" {
+		if err := rdb.Set(ctx, utils.GenerateBlacklistKey(family.CurrentTokenID), "1", constants.CacheTTLRefreshToken).Err(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// isTokenBlacklisted 检查令牌ID是否已被加入黑名单
+func isTokenBlacklisted(ctx context.Context, rdb redis.Cmdable, tokenID string) (bool, error) {
+	n, err := rdb.Exists(ctx, utils.GenerateBlacklistKey(tokenID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
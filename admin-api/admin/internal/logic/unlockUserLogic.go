@@ -0,0 +1,50 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UnlockUserLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 解锁用户
+func NewUnlockUserLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UnlockUserLogic {
+	return &UnlockUserLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UnlockUserLogic) UnlockUser(req *types.UserDetailRequest) (resp *types.UserDetailResponse, err error) {
+	if req == nil {
+		return nil, errors.New("请求不能为空")
+	}
+
+	// 1. 获取目标用户并检查角色层级
+	principal, user, err := loadManagedUser(l.ctx, l.svcCtx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsLocked() {
+		return nil, errors.New("用户未被锁定")
+	}
+
+	// 2. 解除锁定并清零登录失败次数
+	if err := l.svcCtx.UserDAO.UnlockUser(l.ctx, req.ID); err != nil {
+		l.Logger.Errorf("解锁用户失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	l.Logger.Infof("管理员解锁用户: operator=%s, userID=%s", principal.UserID, req.ID)
+	return reloadUserDetail(l.ctx, l.svcCtx, req.ID, "用户已解锁")
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/heimdall-api/admin-api/admin/internal/svc"
 	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
 	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
 
@@ -76,11 +77,11 @@ func (l *UnpublishPageLogic) validatePageID(id string) error {
 
 // getCurrentUserID 获取当前用户ID
 func (l *UnpublishPageLogic) getCurrentUserID() (string, error) {
-	userID, ok := l.ctx.Value("userId").(string)
-	if !ok || userID == "" {
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
 		return "", fmt.Errorf("用户认证失败")
 	}
-	return userID, nil
+	return principal.UserID, nil
 }
 
 // checkPermission 检查用户权限
@@ -94,8 +95,8 @@ func (l *UnpublishPageLogic) checkPermission(page *model.Page, userID string) er
 		return fmt.Errorf("用户不存在")
 	}
 
-	// 检查是否为页面作者
-	if page.AuthorID.Hex() != userID {
+	// 编辑及以上角色可操作所有页面，作者只能操作自己的页面
+	if !hasResourcePermission(l.ctx, constants.PermissionPageUnpublish, page.AuthorID.Hex()) {
 		return fmt.Errorf("无权限取消发布此页面")
 	}
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/heimdall-api/admin-api/admin/internal/svc"
 	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
 	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
 
@@ -76,11 +77,11 @@ func (l *UnpublishPostLogic) validatePostID(id string) error {
 
 // getCurrentUserID 获取当前用户ID
 func (l *UnpublishPostLogic) getCurrentUserID() (string, error) {
-	userID, ok := l.ctx.Value("uid").(string)
-	if !ok || userID == "" {
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
 		return "", fmt.Errorf("用户认证失败")
 	}
-	return userID, nil
+	return principal.UserID, nil
 }
 
 // checkPermission 检查用户权限
@@ -94,8 +95,8 @@ func (l *UnpublishPostLogic) checkPermission(post *model.Post, userID string) er
 		return fmt.Errorf("用户不存在")
 	}
 
-	// 检查是否为文章作者
-	if post.AuthorID.Hex() != userID {
+	// 编辑及以上角色可操作所有文章，作者只能操作自己的文章
+	if !hasResourcePermission(l.ctx, constants.PermissionPostUnpublish, post.AuthorID.Hex()) {
 		return fmt.Errorf("无权限取消发布此文章")
 	}
 
@@ -183,6 +184,8 @@ func (l *UnpublishPostLogic) buildPostDetailData(post *model.Post, author *model
 		ReadingTime:     post.ReadingTime,
 		WordCount:       post.WordCount,
 		ViewCount:       post.ViewCount,
+		DisableTOC:      post.DisableTOC,
+		TOCMaxDepth:     post.TOCMaxDepth,
 		PublishedAt:     publishedAt,
 		CreatedAt:       post.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:       post.UpdatedAt.Format(time.RFC3339),
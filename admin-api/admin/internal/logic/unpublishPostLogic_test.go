@@ -36,7 +36,7 @@ func TestUnpublishPostLogic_UnpublishPost(t *testing.T) {
 			userID := authorID // 设置为同一个用户
 
 			// 设置用户ID到context（模拟JWT中间件）
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			// 准备现有文章（已发布状态）
@@ -123,7 +123,7 @@ func TestUnpublishPostLogic_UnpublishPost(t *testing.T) {
 			mockey.UnPatchAll()
 
 			userID := primitive.NewObjectID()
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			// 准备请求
@@ -146,7 +146,7 @@ func TestUnpublishPostLogic_UnpublishPost(t *testing.T) {
 
 			postID := primitive.NewObjectID()
 			userID := primitive.NewObjectID()
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			// Mock PostDAO.GetByID - 返回文章不存在
@@ -176,7 +176,7 @@ func TestUnpublishPostLogic_UnpublishPost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := primitive.NewObjectID() // 不同的用户ID
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			publishedTime := time.Now().Add(-1 * time.Hour)
@@ -219,7 +219,7 @@ func TestUnpublishPostLogic_UnpublishPost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := authorID
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			existingPost := &model.Post{
@@ -260,7 +260,7 @@ func TestUnpublishPostLogic_UnpublishPost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := authorID
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic.ctx = ctxWithUser
 
 			publishedTime := time.Now().Add(-1 * time.Hour)
@@ -0,0 +1,80 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateMFAPolicyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 更新两步验证策略
+func NewUpdateMFAPolicyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateMFAPolicyLogic {
+	return &UpdateMFAPolicyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateMFAPolicyLogic) UpdateMFAPolicy(req *types.MFAPolicyUpdateRequest) (resp *types.MFAPolicyResponse, err error) {
+	// 1. 参数验证
+	if req == nil {
+		return nil, errors.New("请求不能为空")
+	}
+
+	// 2. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 校验角色，按角色等级输出并去重
+	for _, role := range req.RequiredRoles {
+		if !constants.IsValidUserRole(role) {
+			return nil, fmt.Errorf("无效的角色: %s", role)
+		}
+	}
+	roles := make([]string, 0, len(req.RequiredRoles))
+	for _, role := range constants.GetAllUserRoles() {
+		if slices.Contains(req.RequiredRoles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	// 4. 保存策略，保留原策略用于审计
+	previous, err := l.svcCtx.MFAPolicy.RequiredRoles(l.ctx)
+	if err != nil {
+		l.Logger.Errorf("获取两步验证策略失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if err := l.svcCtx.MFAPolicy.SetRequiredRoles(l.ctx, roles); err != nil {
+		l.Logger.Errorf("更新两步验证策略失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	audit.RecordChange(l.ctx, "requiredRoles", previous, roles)
+
+	l.Logger.Infof("两步验证策略已更新: operator=%s, roles=%v", principal.UserID, roles)
+	return &types.MFAPolicyResponse{
+		Code:      200,
+		Message:   "两步验证策略已更新",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.MFAPolicyData{
+			RequiredRoles: roles,
+		},
+	}, nil
+}
@@ -3,11 +3,12 @@ package logic
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/heimdall-api/admin-api/admin/internal/svc"
 	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
 	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
 
@@ -36,9 +37,8 @@ func (l *UpdatePageLogic) UpdatePage(req *types.PageUpdateRequest) (resp *types.
 		return nil, fmt.Errorf("无效的页面ID格式")
 	}
 
-	// 2. 获取当前用户ID
-	userID, err := l.getCurrentUserID()
-	if err != nil {
+	// 2. 验证当前用户已认证
+	if _, err := l.getCurrentUserID(); err != nil {
 		return nil, err
 	}
 
@@ -49,7 +49,7 @@ func (l *UpdatePageLogic) UpdatePage(req *types.PageUpdateRequest) (resp *types.
 	}
 
 	// 4. 检查权限
-	if err := l.checkPermission(userID, existingPage); err != nil {
+	if err := l.checkPermission(existingPage); err != nil {
 		return nil, err
 	}
 
@@ -68,6 +68,7 @@ func (l *UpdatePageLogic) UpdatePage(req *types.PageUpdateRequest) (resp *types.
 	if err := l.svcCtx.PageDAO.Update(l.ctx, req.ID, updates); err != nil {
 		return nil, fmt.Errorf("更新页面失败: %w", err)
 	}
+	audit.RecordUpdates(l.ctx, existingPage, updates)
 
 	// 8. 获取更新后的页面并构建响应
 	return l.buildUpdateResponse(req.ID)
@@ -75,16 +76,16 @@ func (l *UpdatePageLogic) UpdatePage(req *types.PageUpdateRequest) (resp *types.
 
 // getCurrentUserID 获取当前用户ID
 func (l *UpdatePageLogic) getCurrentUserID() (string, error) {
-	userID, ok := l.ctx.Value("userId").(string)
-	if !ok || userID == "" {
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
 		return "", fmt.Errorf("用户未认证")
 	}
-	return userID, nil
+	return principal.UserID, nil
 }
 
 // checkPermission 检查用户权限
-func (l *UpdatePageLogic) checkPermission(userID string, page *model.Page) error {
-	if page.AuthorID.Hex() != userID {
+func (l *UpdatePageLogic) checkPermission(page *model.Page) error {
+	if !hasResourcePermission(l.ctx, constants.PermissionPageUpdate, page.AuthorID.Hex()) {
 		return fmt.Errorf("无权限修改此页面")
 	}
 	return nil
@@ -102,6 +103,11 @@ func (l *UpdatePageLogic) validateSlug(req *types.PageUpdateRequest, existingPag
 		return nil
 	}
 
+	// 新slug需符合站点当前的slug策略
+	if err := validateSlug(slugStrategy(l.ctx, l.svcCtx), req.Slug); err != nil {
+		return err
+	}
+
 	// 检查新slug是否已被其他页面使用
 	existingSlugPage, err := l.svcCtx.PageDAO.GetBySlug(l.ctx, req.Slug)
 	if err == nil && existingSlugPage != nil && existingSlugPage.ID != existingPage.ID {
@@ -127,7 +133,11 @@ func (l *UpdatePageLogic) buildUpdateData(req *types.PageUpdateRequest, existing
 	if req.Content != "" {
 		// 处理内容
 		updates["content"] = req.Content
-		updates["html"] = l.convertContentToHTML(req.Content)
+		doc, err := renderContent(l.ctx, l.svcCtx, req.Content, existingPage.AuthorID.Hex())
+		if err != nil {
+			return nil, err
+		}
+		updates["html"] = doc.HTML
 	}
 
 	if req.Template != "" {
@@ -179,27 +189,6 @@ func (l *UpdatePageLogic) validateStatus(status string) error {
 	return nil
 }
 
-// convertContentToHTML 简单的内容转HTML
-func (l *UpdatePageLogic) convertContentToHTML(content string) string {
-	html := strings.ReplaceAll(content, "\n", "<br/>")
-
-	// 简单的标题处理
-	lines := strings.Split(html, "<br/>")
-	for i, line := range lines {
-		if strings.HasPrefix(line, "# ") {
-			lines[i] = "<h1>" + strings.TrimPrefix(line, "# ") + "</h1>"
-		} else if strings.HasPrefix(line, "## ") {
-			lines[i] = "<h2>" + strings.TrimPrefix(line, "## ") + "</h2>"
-		} else if strings.HasPrefix(line, "### ") {
-			lines[i] = "<h3>" + strings.TrimPrefix(line, "### ") + "</h3>"
-		} else if line != "" && !strings.HasPrefix(line, "<h") && !strings.HasPrefix(line, "<p") {
-			lines[i] = "<p>" + line + "</p>"
-		}
-	}
-
-	return strings.Join(lines, "")
-}
-
 // buildUpdateResponse 构建更新响应
 func (l *UpdatePageLogic) buildUpdateResponse(pageID string) (*types.PageUpdateResponse, error) {
 	// 获取更新后的页面
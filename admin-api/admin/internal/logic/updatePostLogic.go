@@ -3,13 +3,15 @@ package logic
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/heimdall-api/admin-api/admin/internal/svc"
 	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
 	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/render"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -36,9 +38,8 @@ func (l *UpdatePostLogic) UpdatePost(req *types.PostUpdateRequest) (resp *types.
 		return nil, fmt.Errorf("无效的文章ID格式")
 	}
 
-	// 2. 获取当前用户ID
-	userID, err := l.getCurrentUserID()
-	if err != nil {
+	// 2. 验证当前用户已认证
+	if _, err := l.getCurrentUserID(); err != nil {
 		return nil, err
 	}
 
@@ -49,7 +50,7 @@ func (l *UpdatePostLogic) UpdatePost(req *types.PostUpdateRequest) (resp *types.
 	}
 
 	// 4. 检查权限
-	if err := l.checkPermission(userID, existingPost); err != nil {
+	if err := l.checkPermission(existingPost); err != nil {
 		return nil, err
 	}
 
@@ -68,6 +69,7 @@ func (l *UpdatePostLogic) UpdatePost(req *types.PostUpdateRequest) (resp *types.
 	if err := l.svcCtx.PostDAO.Update(l.ctx, req.ID, updates); err != nil {
 		return nil, fmt.Errorf("更新文章失败: %w", err)
 	}
+	audit.RecordUpdates(l.ctx, existingPost, updates)
 
 	// 8. 获取更新后的文章并构建响应
 	return l.buildUpdateResponse(req.ID)
@@ -75,16 +77,16 @@ func (l *UpdatePostLogic) UpdatePost(req *types.PostUpdateRequest) (resp *types.
 
 // getCurrentUserID 获取当前用户ID
 func (l *UpdatePostLogic) getCurrentUserID() (string, error) {
-	userID, ok := l.ctx.Value("uid").(string)
-	if !ok || userID == "" {
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
 		return "", fmt.Errorf("用户未认证")
 	}
-	return userID, nil
+	return principal.UserID, nil
 }
 
 // checkPermission 检查用户权限
-func (l *UpdatePostLogic) checkPermission(userID string, post *model.Post) error {
-	if post.AuthorID.Hex() != userID {
+func (l *UpdatePostLogic) checkPermission(post *model.Post) error {
+	if !hasResourcePermission(l.ctx, constants.PermissionPostUpdate, post.AuthorID.Hex()) {
 		return fmt.Errorf("无权限修改此文章")
 	}
 	return nil
@@ -102,6 +104,11 @@ func (l *UpdatePostLogic) validateSlug(req *types.PostUpdateRequest, existingPos
 		return nil
 	}
 
+	// 新slug需符合站点当前的slug策略
+	if err := validateSlug(slugStrategy(l.ctx, l.svcCtx), req.Slug); err != nil {
+		return err
+	}
+
 	// 检查新slug是否已被其他文章使用
 	existingSlugPost, err := l.svcCtx.PostDAO.GetBySlug(l.ctx, req.Slug)
 	if err == nil && existingSlugPost != nil && existingSlugPost.ID != existingPost.ID {
@@ -126,18 +133,50 @@ func (l *UpdatePostLogic) buildUpdateData(req *types.PostUpdateRequest, existing
 
 	if req.Excerpt != "" {
 		updates["excerpt"] = req.Excerpt
+		updates["autoExcerpt"] = false
+	}
+
+	// 目录设置，未提供时沿用原设置
+	disableTOC, tocMaxDepth := existingPost.DisableTOC, existingPost.TOCMaxDepth
+	if req.DisableTOC != nil {
+		disableTOC = *req.DisableTOC
+		updates["disableToc"] = disableTOC
+	}
+	if req.TOCMaxDepth != nil {
+		if err := validateTOCMaxDepth(*req.TOCMaxDepth); err != nil {
+			return nil, err
+		}
+		tocMaxDepth = *req.TOCMaxDepth
+		updates["tocMaxDepth"] = tocMaxDepth
 	}
 
 	if req.Markdown != "" {
 		// 处理Markdown内容
 		updates["markdown"] = req.Markdown
-		updates["html"] = l.convertMarkdownToHTML(req.Markdown)
+		doc, err := renderContent(l.ctx, l.svcCtx, req.Markdown, existingPost.AuthorID.Hex())
+		if err != nil {
+			return nil, err
+		}
+		updates["html"] = doc.HTML
+		updates["toc"] = postTOC(doc, disableTOC, tocMaxDepth)
+
+		// 按渲染后的正文重新计算内容指标，未手动填写的摘要随内容重新生成
+		stats := doc.Stats()
+		updates["wordCount"] = stats.WordCount()
+		updates["readingTime"] = stats.ReadingTime()
+		if req.Excerpt == "" && (existingPost.AutoExcerpt || existingPost.Excerpt == "") {
+			updates["excerpt"] = doc.Excerpt(constants.PostExcerptDefaultLength)
+			updates["autoExcerpt"] = true
+		}
+	}
 
-		// 重新计算内容指标
-		wordCount := l.calculateWordCount(req.Markdown)
-		readingTime := l.calculateReadingTime(wordCount)
-		updates["wordCount"] = wordCount
-		updates["readingTime"] = readingTime
+	if req.Markdown == "" && (req.DisableTOC != nil || req.TOCMaxDepth != nil) {
+		// 内容未修改但目录设置变化时，按原内容重新生成目录
+		doc, err := render.Parse(existingPost.Markdown)
+		if err != nil {
+			return nil, fmt.Errorf("内容渲染失败: %v", err)
+		}
+		updates["toc"] = postTOC(doc, disableTOC, tocMaxDepth)
 	}
 
 	if req.FeaturedImage != "" {
@@ -166,14 +205,7 @@ func (l *UpdatePostLogic) buildUpdateData(req *types.PostUpdateRequest, existing
 	}
 
 	if req.Tags != nil {
-		tags := make([]model.Tag, len(req.Tags))
-		for i, tag := range req.Tags {
-			tags[i] = model.Tag{
-				Name: tag.Name,
-				Slug: tag.Slug,
-			}
-		}
-		updates["tags"] = tags
+		updates["tags"] = buildTags(l.ctx, l.svcCtx, req.Tags)
 	}
 
 	if req.MetaTitle != "" {
@@ -244,43 +276,6 @@ func (l *UpdatePostLogic) validateVisibility(visibility string) error {
 	return fmt.Errorf("无效的可见性设置: %s", visibility)
 }
 
-// convertMarkdownToHTML 简单的Markdown转HTML
-func (l *UpdatePostLogic) convertMarkdownToHTML(markdown string) string {
-	html := strings.ReplaceAll(markdown, "\n", "<br/>")
-
-	// 简单的标题处理
-	lines := strings.Split(html, "<br/>")
-	for i, line := range lines {
-		if strings.HasPrefix(line, "# ") {
-			lines[i] = "<h1>" + strings.TrimPrefix(line, "# ") + "</h1>"
-		} else if strings.HasPrefix(line, "## ") {
-			lines[i] = "<h2>" + strings.TrimPrefix(line, "## ") + "</h2>"
-		} else if strings.HasPrefix(line, "### ") {
-			lines[i] = "<h3>" + strings.TrimPrefix(line, "### ") + "</h3>"
-		} else if line != "" && !strings.HasPrefix(line, "<h") {
-			lines[i] = "<p>" + line + "</p>"
-		}
-	}
-
-	return strings.Join(lines, "")
-}
-
-// calculateWordCount 计算字数
-func (l *UpdatePostLogic) calculateWordCount(content string) int {
-	words := strings.Fields(content)
-	return len(words)
-}
-
-// calculateReadingTime 计算阅读时间（分钟）
-func (l *UpdatePostLogic) calculateReadingTime(wordCount int) int {
-	// 假设每分钟阅读200字
-	readingTime := wordCount / 200
-	if readingTime < 1 {
-		return 1
-	}
-	return readingTime
-}
-
 // buildUpdateResponse 构建更新响应
 func (l *UpdatePostLogic) buildUpdateResponse(postID string) (*types.PostUpdateResponse, error) {
 	// 获取更新后的文章
@@ -357,6 +352,8 @@ func (l *UpdatePostLogic) buildPostDetailData(post *model.Post, author *model.Us
 		ReadingTime:     post.ReadingTime,
 		WordCount:       post.WordCount,
 		ViewCount:       post.ViewCount,
+		DisableTOC:      post.DisableTOC,
+		TOCMaxDepth:     post.TOCMaxDepth,
 		PublishedAt:     publishedAt,
 		CreatedAt:       post.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:       post.UpdatedAt.Format(time.RFC3339),
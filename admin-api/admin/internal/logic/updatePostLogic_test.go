@@ -12,8 +12,10 @@ import (
 
 	"github.com/heimdall-api/admin-api/admin/internal/svc"
 	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/dao"
 	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/render"
 )
 
 func TestUpdatePostLogic_UpdatePost(t *testing.T) {
@@ -21,8 +23,10 @@ func TestUpdatePostLogic_UpdatePost(t *testing.T) {
 		// 准备测试数据
 		ctx := context.Background()
 		svcCtx := &svc.ServiceContext{
-			PostDAO: &dao.PostDAO{},
-			UserDAO: &dao.UserDAO{},
+			PostDAO:    &dao.PostDAO{},
+			UserDAO:    &dao.UserDAO{},
+			SettingDAO: &dao.SettingDAO{},
+			Renderer:   render.NewRenderer(render.NewSanitizer(render.SanitizeConfig{})),
 		}
 		logic := NewUpdatePostLogic(ctx, svcCtx)
 
@@ -35,7 +39,7 @@ func TestUpdatePostLogic_UpdatePost(t *testing.T) {
 			userID := authorID // 设置为同一个用户
 
 			// 设置用户ID到context（模拟JWT中间件）
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic = NewUpdatePostLogic(ctxWithUser, svcCtx)
 
 			req := &types.PostUpdateRequest{
@@ -155,7 +159,7 @@ func TestUpdatePostLogic_UpdatePost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := authorID // 设置为同一个用户
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic = NewUpdatePostLogic(ctxWithUser, svcCtx)
 
 			// 只更新标题和摘要
@@ -221,12 +225,102 @@ func TestUpdatePostLogic_UpdatePost(t *testing.T) {
 			So(resp.Data.Excerpt, ShouldEqual, "仅更新摘要")
 		})
 
+		Convey("修改目录设置时按原内容重新生成目录", func() {
+			mockey.UnPatchAll()
+
+			postID := primitive.NewObjectID()
+			authorID := primitive.NewObjectID()
+			logic = NewUpdatePostLogic(withPrincipal(ctx, authorID.Hex(), constants.UserRoleAuthor), svcCtx)
+
+			publishedAt := time.Now()
+			existingPost := &model.Post{
+				ID:          postID,
+				Title:       "原始标题",
+				Markdown:    "## 简介\n\n### 背景\n\n## 安装",
+				AuthorID:    authorID,
+				PublishedAt: &publishedAt,
+			}
+			mockey.Mock((*dao.PostDAO).GetByID).Return(existingPost, nil).Build()
+			mockey.Mock((*dao.UserDAO).GetByID).Return(&model.User{ID: authorID}, nil).Build()
+
+			var gotUpdates map[string]interface{}
+			mockey.Mock((*dao.PostDAO).Update).To(func(postDAO *dao.PostDAO, ctx context.Context, id string, updates map[string]interface{}) error {
+				gotUpdates = updates
+				return nil
+			}).Build()
+
+			Convey("限制目录层数", func() {
+				depth := 1
+				_, err := logic.UpdatePost(&types.PostUpdateRequest{ID: postID.Hex(), TOCMaxDepth: &depth})
+				So(err, ShouldBeNil)
+				So(gotUpdates["tocMaxDepth"], ShouldEqual, 1)
+				So(gotUpdates["toc"], ShouldResemble, []model.TOCItem{
+					{Level: 2, Text: "简介", ID: "简介"},
+					{Level: 2, Text: "安装", ID: "安装"},
+				})
+				_, hasMarkdown := gotUpdates["markdown"]
+				So(hasMarkdown, ShouldBeFalse)
+			})
+
+			Convey("关闭目录", func() {
+				disable := true
+				_, err := logic.UpdatePost(&types.PostUpdateRequest{ID: postID.Hex(), DisableTOC: &disable})
+				So(err, ShouldBeNil)
+				So(gotUpdates["disableToc"], ShouldBeTrue)
+				So(gotUpdates["toc"], ShouldBeNil)
+			})
+
+			Convey("目录层数超出范围", func() {
+				depth := constants.TOCMaxDepthLimit + 1
+				_, err := logic.UpdatePost(&types.PostUpdateRequest{ID: postID.Hex(), TOCMaxDepth: &depth})
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("修改内容时只重新生成自动摘要", func() {
+			mockey.UnPatchAll()
+
+			postID := primitive.NewObjectID()
+			authorID := primitive.NewObjectID()
+			logic = NewUpdatePostLogic(withPrincipal(ctx, authorID.Hex(), constants.UserRoleAuthor), svcCtx)
+
+			publishedAt := time.Now()
+			existingPost := &model.Post{
+				ID:          postID,
+				Title:       "原始标题",
+				Excerpt:     "原始内容。",
+				AutoExcerpt: true,
+				Markdown:    "原始内容。",
+				AuthorID:    authorID,
+				PublishedAt: &publishedAt,
+			}
+			mockey.Mock((*dao.PostDAO).GetByID).Return(existingPost, nil).Build()
+			mockey.Mock((*dao.UserDAO).GetByID).Return(&model.User{ID: authorID}, nil).Build()
+
+			var gotUpdates map[string]interface{}
+			mockey.Mock((*dao.PostDAO).Update).To(func(postDAO *dao.PostDAO, ctx context.Context, id string, updates map[string]interface{}) error {
+				gotUpdates = updates
+				return nil
+			}).Build()
+
+			_, err := logic.UpdatePost(&types.PostUpdateRequest{ID: postID.Hex(), Markdown: "# 标题\n\n新的**内容**。"})
+			So(err, ShouldBeNil)
+			So(gotUpdates["excerpt"], ShouldEqual, "新的内容。")
+			So(gotUpdates["autoExcerpt"], ShouldBeTrue)
+
+			existingPost.AutoExcerpt = false
+			_, err = logic.UpdatePost(&types.PostUpdateRequest{ID: postID.Hex(), Markdown: "再次修改。"})
+			So(err, ShouldBeNil)
+			_, hasExcerpt := gotUpdates["excerpt"]
+			So(hasExcerpt, ShouldBeFalse)
+		})
+
 		Convey("处理无效的文章ID", func() {
 			// 重置mock
 			mockey.UnPatchAll()
 
 			userID := primitive.NewObjectID()
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic = NewUpdatePostLogic(ctxWithUser, svcCtx)
 
 			req := &types.PostUpdateRequest{
@@ -248,7 +342,7 @@ func TestUpdatePostLogic_UpdatePost(t *testing.T) {
 			postID := primitive.NewObjectID()
 			userID := primitive.NewObjectID()
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic = NewUpdatePostLogic(ctxWithUser, svcCtx)
 
 			req := &types.PostUpdateRequest{
@@ -276,7 +370,7 @@ func TestUpdatePostLogic_UpdatePost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := primitive.NewObjectID() // 不同的用户ID
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic = NewUpdatePostLogic(ctxWithUser, svcCtx)
 
 			req := &types.PostUpdateRequest{
@@ -310,7 +404,7 @@ func TestUpdatePostLogic_UpdatePost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := authorID // 同一个用户
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic = NewUpdatePostLogic(ctxWithUser, svcCtx)
 
 			req := &types.PostUpdateRequest{
@@ -330,6 +424,9 @@ func TestUpdatePostLogic_UpdatePost(t *testing.T) {
 				return existingPost, nil
 			}).Build()
 
+			// Mock SettingDAO.GetByKey - 未设置slug策略
+			mockey.Mock((*dao.SettingDAO).GetByKey).Return(nil, nil).Build()
+
 			// Mock PostDAO.GetBySlug - 返回slug已存在
 			mockey.Mock((*dao.PostDAO).GetBySlug).To(func(postDAO *dao.PostDAO, ctx context.Context, slug string) (*model.Post, error) {
 				if slug == "existing-slug" {
@@ -349,6 +446,31 @@ func TestUpdatePostLogic_UpdatePost(t *testing.T) {
 			So(err.Error(), ShouldContainSubstring, "slug已被使用")
 		})
 
+		Convey("新slug须符合站点的slug策略", func() {
+			// 重置mock
+			mockey.UnPatchAll()
+
+			postID := primitive.NewObjectID()
+			authorID := primitive.NewObjectID()
+			logic = NewUpdatePostLogic(withPrincipal(ctx, authorID.Hex(), constants.UserRoleAuthor), svcCtx)
+
+			mockey.Mock((*dao.PostDAO).GetByID).Return(&model.Post{
+				ID:       postID,
+				Title:    "原始标题",
+				Slug:     "原始标题",
+				AuthorID: authorID,
+			}, nil).Build()
+			mockey.Mock((*dao.SettingDAO).GetByKey).Return(&model.Setting{
+				Key:   constants.SettingKeySlugStrategy,
+				Value: constants.SlugStrategyASCII,
+			}, nil).Build()
+
+			// 切换策略前保存的slug不受影响，新填写的slug按当前策略校验
+			_, err := logic.UpdatePost(&types.PostUpdateRequest{ID: postID.Hex(), Slug: "新标题"})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, constants.SlugStrategyASCII)
+		})
+
 		Convey("处理数据库更新错误", func() {
 			// 重置mock
 			mockey.UnPatchAll()
@@ -357,7 +479,7 @@ func TestUpdatePostLogic_UpdatePost(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			userID := authorID
 
-			ctxWithUser := context.WithValue(ctx, "uid", userID.Hex())
+			ctxWithUser := withPrincipal(ctx, userID.Hex(), constants.UserRoleAuthor)
 			logic = NewUpdatePostLogic(ctxWithUser, svcCtx)
 
 			req := &types.PostUpdateRequest{
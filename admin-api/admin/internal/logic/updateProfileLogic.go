@@ -0,0 +1,126 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateProfileLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 更新当前用户个人资料
+func NewUpdateProfileLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateProfileLogic {
+	return &UpdateProfileLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateProfileLogic) UpdateProfile(req *types.ProfileUpdateRequest) (resp *types.ProfileUpdateResponse, err error) {
+	// 1. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 验证请求参数
+	profile, err := l.validateRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 更新个人资料（PUT语义：未提供的可选字段会被清空），保留更新前的资料用于审计
+	before, err := l.svcCtx.UserDAO.GetByID(l.ctx, principal.UserID)
+	if err != nil {
+		l.Logger.Errorf("获取用户信息失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	updates := map[string]interface{}{
+		"displayName":  profile.DisplayName,
+		"bio":          profile.Bio,
+		"location":     profile.Location,
+		"website":      profile.Website,
+		"twitter":      profile.Twitter,
+		"facebook":     profile.Facebook,
+		"profileImage": profile.ProfileImage,
+		"coverImage":   profile.CoverImage,
+	}
+	if err := l.svcCtx.UserDAO.Update(l.ctx, principal.UserID, updates); err != nil {
+		l.Logger.Errorf("更新个人资料失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	audit.RecordUpdates(l.ctx, before, updates)
+
+	// 4. 获取更新后的用户信息
+	user, err := l.svcCtx.UserDAO.GetByID(l.ctx, principal.UserID)
+	if err != nil {
+		l.Logger.Errorf("获取用户信息失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if user == nil {
+		return nil, errors.New("用户不存在")
+	}
+
+	return &types.ProfileUpdateResponse{
+		Code:      200,
+		Message:   "个人资料更新成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data:      NewProfileLogic(l.ctx, l.svcCtx).buildUserInfo(user),
+	}, nil
+}
+
+// validateRequest 验证个人资料更新请求，返回去除首尾空白后的资料
+func (l *UpdateProfileLogic) validateRequest(req *types.ProfileUpdateRequest) (*model.User, error) {
+	if req == nil {
+		return nil, errors.New("请求不能为空")
+	}
+
+	profile := &model.User{
+		DisplayName:  strings.TrimSpace(req.DisplayName),
+		Bio:          strings.TrimSpace(req.Bio),
+		Location:     strings.TrimSpace(req.Location),
+		Website:      strings.TrimSpace(req.Website),
+		Twitter:      strings.TrimSpace(req.Twitter),
+		Facebook:     strings.TrimSpace(req.Facebook),
+		ProfileImage: strings.TrimSpace(req.ProfileImage),
+		CoverImage:   strings.TrimSpace(req.CoverImage),
+	}
+
+	if profile.DisplayName == "" {
+		return nil, errors.New("显示名不能为空")
+	}
+	if err := profile.ValidateForUpdate(); err != nil {
+		return nil, err
+	}
+
+	urls := []struct {
+		value   string
+		message string
+	}{
+		{profile.Website, "个人网站URL格式无效"},
+		{profile.ProfileImage, "头像URL格式无效"},
+		{profile.CoverImage, "封面图URL格式无效"},
+	}
+	for _, u := range urls {
+		if err := utils.ValidateURL(u.value); err != nil {
+			return nil, errors.New(u.message)
+		}
+	}
+
+	return profile, nil
+}
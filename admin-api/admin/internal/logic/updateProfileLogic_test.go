@@ -0,0 +1,68 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+)
+
+func TestUpdateProfileLogic_UpdateProfile(t *testing.T) {
+	mockey.PatchConvey("UpdateProfileLogic UpdateProfile Tests", t, func() {
+		svcCtx := &svc.ServiceContext{
+			UserDAO: &dao.UserDAO{},
+		}
+		userID := primitive.NewObjectID()
+		ctx := withPrincipal(context.Background(), userID.Hex(), constants.UserRoleAuthor)
+
+		var saved map[string]interface{}
+		mockey.Mock((*dao.UserDAO).Update).To(func(_ *dao.UserDAO, _ context.Context, _ string, updates map[string]interface{}) error {
+			saved = updates
+			return nil
+		}).Build()
+
+		Convey("Should reject missing display name and invalid URLs", func() {
+			_, err := NewUpdateProfileLogic(ctx, svcCtx).UpdateProfile(&types.ProfileUpdateRequest{DisplayName: "  "})
+			So(err.Error(), ShouldEqual, "显示名不能为空")
+
+			_, err = NewUpdateProfileLogic(ctx, svcCtx).UpdateProfile(&types.ProfileUpdateRequest{
+				DisplayName: "Writer",
+				CoverImage:  "javascript:alert(1)",
+			})
+			So(err.Error(), ShouldEqual, "封面图URL格式无效")
+			So(saved, ShouldBeNil)
+		})
+
+		Convey("Should update profile and return user info", func() {
+			mockey.Mock((*dao.UserDAO).GetByID).Return(&model.User{
+				ID:          userID,
+				Username:    "writer",
+				DisplayName: "Writer",
+				Role:        constants.UserRoleAuthor,
+				Status:      constants.UserStatusActive,
+				Bio:         "Hello",
+				CoverImage:  "https://cdn.example.com/cover.png",
+			}, nil).Build()
+
+			resp, err := NewUpdateProfileLogic(ctx, svcCtx).UpdateProfile(&types.ProfileUpdateRequest{
+				DisplayName: " Writer ",
+				Bio:         "Hello",
+				CoverImage:  "https://cdn.example.com/cover.png",
+			})
+			So(err, ShouldBeNil)
+			So(resp.Data.CoverImage, ShouldEqual, "https://cdn.example.com/cover.png")
+
+			So(saved["displayName"], ShouldEqual, "Writer")
+			So(saved["bio"], ShouldEqual, "Hello")
+			So(saved["website"], ShouldEqual, "")
+		})
+	})
+}
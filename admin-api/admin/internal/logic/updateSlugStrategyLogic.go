@@ -0,0 +1,71 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/slug"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateSlugStrategyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 更新slug生成策略
+func NewUpdateSlugStrategyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateSlugStrategyLogic {
+	return &UpdateSlugStrategyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateSlugStrategyLogic) UpdateSlugStrategy(req *types.SlugStrategyUpdateRequest) (resp *types.SlugStrategyResponse, err error) {
+	// 1. 参数验证
+	if req == nil {
+		return nil, errors.New("请求不能为空")
+	}
+	if !slug.Has(req.Strategy) {
+		return nil, fmt.Errorf("无效的slug策略: %s", req.Strategy)
+	}
+
+	// 2. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 保存策略，保留原策略用于审计；已有文章、页面和标签的slug不变
+	previous, err := slugStrategyName(l.ctx, l.svcCtx)
+	if err != nil {
+		l.Logger.Errorf("获取slug策略失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if err := l.svcCtx.SettingDAO.Set(l.ctx, constants.SettingKeySlugStrategy, req.Strategy, constants.SettingGroupGeneral); err != nil {
+		l.Logger.Errorf("更新slug策略失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	audit.RecordChange(l.ctx, "slugStrategy", previous, req.Strategy)
+
+	l.Logger.Infof("slug策略已更新: operator=%s, strategy=%s", principal.UserID, req.Strategy)
+	return &types.SlugStrategyResponse{
+		Code:      200,
+		Message:   "slug策略已更新",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.SlugStrategyData{
+			Strategy:   req.Strategy,
+			Strategies: slug.Names(),
+		},
+	}, nil
+}
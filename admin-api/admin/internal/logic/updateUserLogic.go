@@ -0,0 +1,96 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateUserLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 更新用户资料
+func NewUpdateUserLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateUserLogic {
+	return &UpdateUserLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateUserLogic) UpdateUser(req *types.UserUpdateRequest) (resp *types.UserDetailResponse, err error) {
+	if req == nil {
+		return nil, errors.New("请求不能为空")
+	}
+
+	// 1. 获取目标用户并检查角色层级
+	principal, user, err := loadManagedUser(l.ctx, l.svcCtx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 验证资料字段（与个人资料更新规则一致）
+	profile, err := NewUpdateProfileLogic(l.ctx, l.svcCtx).validateRequest(&types.ProfileUpdateRequest{
+		DisplayName:  req.DisplayName,
+		Bio:          req.Bio,
+		Location:     req.Location,
+		Website:      req.Website,
+		Twitter:      req.Twitter,
+		Facebook:     req.Facebook,
+		ProfileImage: req.ProfileImage,
+		CoverImage:   req.CoverImage,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 验证邮箱，变更时检查唯一性
+	email := strings.TrimSpace(req.Email)
+	if email == "" {
+		return nil, errors.New("邮箱不能为空")
+	}
+	if err := utils.ValidateEmail(email); err != nil {
+		return nil, errors.New("邮箱格式无效")
+	}
+	if email != user.Email {
+		existing, err := l.svcCtx.UserDAO.GetByEmail(l.ctx, email)
+		if err != nil {
+			l.Logger.Errorf("查询邮箱失败: %v", err)
+			return nil, errors.New("系统错误，请稍后重试")
+		}
+		if existing != nil {
+			return nil, errors.New("邮箱已存在")
+		}
+	}
+
+	// 4. 更新用户资料（PUT语义：未提供的可选字段会被清空）
+	updates := map[string]interface{}{
+		"email":        email,
+		"displayName":  profile.DisplayName,
+		"bio":          profile.Bio,
+		"location":     profile.Location,
+		"website":      profile.Website,
+		"twitter":      profile.Twitter,
+		"facebook":     profile.Facebook,
+		"profileImage": profile.ProfileImage,
+		"coverImage":   profile.CoverImage,
+	}
+	if err := l.svcCtx.UserDAO.Update(l.ctx, req.ID, updates); err != nil {
+		l.Logger.Errorf("更新用户资料失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	audit.RecordUpdates(l.ctx, user, updates)
+
+	l.Logger.Infof("管理员更新用户资料: operator=%s, userID=%s", principal.UserID, req.ID)
+	return reloadUserDetail(l.ctx, l.svcCtx, req.ID, "用户资料更新成功")
+}
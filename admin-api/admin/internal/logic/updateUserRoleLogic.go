@@ -0,0 +1,76 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/constants"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateUserRoleLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 修改用户角色
+func NewUpdateUserRoleLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateUserRoleLogic {
+	return &UpdateUserRoleLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateUserRoleLogic) UpdateUserRole(req *types.UserRoleUpdateRequest) (resp *types.UserDetailResponse, err error) {
+	if req == nil {
+		return nil, errors.New("请求不能为空")
+	}
+
+	// 1. 获取目标用户并检查角色层级
+	principal, user, err := loadManagedUser(l.ctx, l.svcCtx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID.Hex() == principal.UserID {
+		return nil, errors.New("不能修改自己的角色")
+	}
+
+	// 2. 只能授予等级低于自己的角色，所有者可以授予任意角色
+	if !constants.IsValidUserRole(req.Role) {
+		return nil, errors.New("无效的用户角色")
+	}
+	if !constants.CanManageRole(principal.Role, req.Role) {
+		return nil, errors.New("无权授予该角色")
+	}
+	if req.Role == user.Role {
+		return reloadUserDetail(l.ctx, l.svcCtx, req.ID, "用户角色未变化")
+	}
+
+	// 3. 更新角色，降级所有者时确保仍有其他所有者
+	err = keepOwnerWhile(l.ctx, l.svcCtx, user, func(ctx context.Context) error {
+		return l.svcCtx.UserDAO.Update(ctx, req.ID, map[string]interface{}{"role": req.Role})
+	})
+	if errors.Is(err, ErrLastOwner) {
+		return nil, err
+	}
+	if err != nil {
+		l.Logger.Errorf("更新用户角色失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	audit.RecordChange(l.ctx, "role", user.Role, req.Role)
+
+	// 4. 角色保存在访问令牌中，吊销已有会话使新角色立即生效
+	if err := signOutUser(l.ctx, l.svcCtx, req.ID); err != nil {
+		l.Logger.Errorf("吊销用户会话失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	l.Logger.Infof("管理员修改用户角色: operator=%s, userID=%s, from=%s, to=%s", principal.UserID, req.ID, user.Role, req.Role)
+	return reloadUserDetail(l.ctx, l.svcCtx, req.ID, "用户角色修改成功")
+}
@@ -0,0 +1,94 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+var (
+	// ErrCannotDeleteSelf 对应错误码 constants.ErrCannotDeleteSelf
+	ErrCannotDeleteSelf = errors.New("不能删除自己")
+	// ErrLastOwner 对应错误码 constants.ErrLastOwner
+	ErrLastOwner = errors.New("不能移除最后一个所有者")
+)
+
+// loadManagedUser 获取当前认证主体和目标用户，并按角色层级检查当前用户能否管理目标用户
+// 操作自己时不做层级检查，由调用方决定是否允许
+func loadManagedUser(ctx context.Context, svcCtx *svc.ServiceContext, id string) (*auth.Principal, *model.User, error) {
+	if !primitive.IsValidObjectID(id) {
+		return nil, nil, errors.New("用户ID格式无效")
+	}
+
+	principal, err := auth.FromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := svcCtx.UserDAO.GetByID(ctx, id)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("获取用户信息失败: %v", err)
+		return nil, nil, errors.New("系统错误，请稍后重试")
+	}
+	if user == nil {
+		return nil, nil, errors.New("用户不存在")
+	}
+
+	if user.ID.Hex() != principal.UserID && !constants.CanManageRole(principal.Role, user.Role) {
+		return nil, nil, errors.New("权限不足")
+	}
+
+	return principal, user, nil
+}
+
+// keepOwnerWhile 执行会移除目标用户所有者身份的写操作（降级、暂停、删除），write需使用传入的ctx
+// 目标是正常状态的所有者时在事务中写入，确保仍至少有一个正常状态的所有者，否则返回ErrLastOwner
+func keepOwnerWhile(ctx context.Context, svcCtx *svc.ServiceContext, user *model.User, write func(ctx context.Context) error) error {
+	if !user.IsOwner() || !user.IsActive() {
+		return write(ctx)
+	}
+
+	err := svcCtx.UserDAO.RemoveOwner(ctx, user.ID.Hex(), write)
+	if errors.Is(err, dao.ErrLastOwner) {
+		return ErrLastOwner
+	}
+	return err
+}
+
+// signOutUser 吊销用户全部会话并设置令牌失效水位线，使角色或状态变更立即生效
+func signOutUser(ctx context.Context, svcCtx *svc.ServiceContext, userID string) error {
+	if _, err := revokeUserSessions(ctx, svcCtx.Redis, userID, ""); err != nil {
+		return err
+	}
+	return auth.RevokeUserTokens(ctx, svcCtx.Redis, userID)
+}
+
+// reloadUserDetail 重新读取用户并构造用户详情响应
+func reloadUserDetail(ctx context.Context, svcCtx *svc.ServiceContext, id, message string) (*types.UserDetailResponse, error) {
+	user, err := svcCtx.UserDAO.GetByID(ctx, id)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("获取用户信息失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if user == nil {
+		return nil, errors.New("用户不存在")
+	}
+
+	return &types.UserDetailResponse{
+		Code:      200,
+		Message:   message,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data:      NewGetUserDetailLogic(ctx, svcCtx).buildUserInfo(user),
+	}, nil
+}
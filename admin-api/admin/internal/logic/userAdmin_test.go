@@ -0,0 +1,283 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bytedance/mockey"
+	"github.com/go-redis/redis/v8"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+)
+
+func TestUserAdminLogic(t *testing.T) {
+	mockey.PatchConvey("User Administration Logic Tests", t, func() {
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+
+		svcCtx := &svc.ServiceContext{
+			UserDAO: &dao.UserDAO{},
+			PostDAO: &dao.PostDAO{},
+			PageDAO: &dao.PageDAO{},
+			Redis:   rdb,
+		}
+
+		// 用内存中的数据模拟数据库
+		users := map[string]*model.User{}
+		newUser := func(username, role string) *model.User {
+			user := &model.User{
+				ID:          primitive.NewObjectID(),
+				Username:    username,
+				Email:       username + "@example.com",
+				DisplayName: username,
+				Role:        role,
+				Status:      constants.UserStatusActive,
+			}
+			users[user.ID.Hex()] = user
+			return user
+		}
+		owner := newUser("owner", constants.UserRoleOwner)
+		admin := newUser("admin", constants.UserRoleAdmin)
+		editor := newUser("editor", constants.UserRoleEditor)
+		author := newUser("author", constants.UserRoleAuthor)
+
+		mockey.Mock((*dao.UserDAO).GetByID).To(func(_ *dao.UserDAO, _ context.Context, id string) (*model.User, error) {
+			if user, ok := users[id]; ok {
+				copied := *user
+				return &copied, nil
+			}
+			return nil, nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).GetByUsername).To(func(_ *dao.UserDAO, _ context.Context, username string) (*model.User, error) {
+			for _, user := range users {
+				if user.Username == username {
+					return user, nil
+				}
+			}
+			return nil, nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).GetByEmail).To(func(_ *dao.UserDAO, _ context.Context, email string) (*model.User, error) {
+			for _, user := range users {
+				if user.Email == email {
+					return user, nil
+				}
+			}
+			return nil, nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).Create).To(func(_ *dao.UserDAO, _ context.Context, user *model.User) error {
+			user.PrepareForInsert()
+			users[user.ID.Hex()] = user
+			return nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).Update).To(func(_ *dao.UserDAO, _ context.Context, id string, updates map[string]interface{}) error {
+			user := users[id]
+			if role, ok := updates["role"]; ok {
+				user.Role = role.(string)
+			}
+			if status, ok := updates["status"]; ok {
+				user.Status = status.(string)
+			}
+			if email, ok := updates["email"]; ok {
+				user.Email = email.(string)
+			}
+			return nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).Delete).To(func(_ *dao.UserDAO, _ context.Context, id string) error {
+			users[id].Status = constants.UserStatusInactive
+			return nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).UnlockUser).To(func(_ *dao.UserDAO, _ context.Context, id string) error {
+			users[id].Status = constants.UserStatusActive
+			users[id].LockedUntil = nil
+			return nil
+		}).Build()
+		mockey.Mock((*dao.UserDAO).RemoveOwner).To(func(_ *dao.UserDAO, ctx context.Context, id string, fn func(ctx context.Context) error) error {
+			for _, user := range users {
+				if user.ID.Hex() != id && user.IsOwner() && user.IsActive() {
+					return fn(ctx)
+				}
+			}
+			return dao.ErrLastOwner
+		}).Build()
+
+		var postStatus, reassignedTo string
+		mockey.Mock((*dao.PostDAO).ReassignAuthor).To(func(_ *dao.PostDAO, _ context.Context, _, to, status string) (int64, error) {
+			reassignedTo, postStatus = to, status
+			return 3, nil
+		}).Build()
+		mockey.Mock((*dao.PageDAO).ReassignAuthor).Return(int64(1), nil).Build()
+
+		asOwner := withPrincipal(context.Background(), owner.ID.Hex(), owner.Role)
+		asAdmin := withPrincipal(context.Background(), admin.ID.Hex(), admin.Role)
+		revoked := func(userID string) bool {
+			return mr.Exists(fmt.Sprintf(constants.CacheKeyTokenRevoked, userID))
+		}
+
+		Convey("Create should respect the role hierarchy", func() {
+			req := &types.UserCreateRequest{
+				Username:    "newauthor",
+				Email:       "newauthor@example.com",
+				Password:    "Str0ng#Passw0rd",
+				DisplayName: "New Author",
+				Role:        constants.UserRoleAuthor,
+			}
+			resp, err := NewCreateUserLogic(asAdmin, svcCtx).CreateUser(req)
+			So(err, ShouldBeNil)
+			So(resp.Data.Role, ShouldEqual, constants.UserRoleAuthor)
+			So(resp.Data.Status, ShouldEqual, constants.UserStatusActive)
+
+			req.Username, req.Email, req.Role = "newadmin", "newadmin@example.com", constants.UserRoleAdmin
+			_, err = NewCreateUserLogic(asAdmin, svcCtx).CreateUser(req)
+			So(err, ShouldNotBeNil)
+
+			_, err = NewCreateUserLogic(asOwner, svcCtx).CreateUser(req)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Create should reject duplicates and weak passwords", func() {
+			req := &types.UserCreateRequest{
+				Username:    "editor",
+				Email:       "someone@example.com",
+				Password:    "Str0ng#Passw0rd",
+				DisplayName: "Someone",
+				Role:        constants.UserRoleAuthor,
+			}
+			_, err := NewCreateUserLogic(asAdmin, svcCtx).CreateUser(req)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "用户名已存在")
+
+			req.Username, req.Password = "someone", "password"
+			_, err = NewCreateUserLogic(asAdmin, svcCtx).CreateUser(req)
+			So(err, ShouldNotBeNil)
+			So(len(users), ShouldEqual, 4)
+		})
+
+		Convey("Update should reject another user's email", func() {
+			_, err := NewUpdateUserLogic(asAdmin, svcCtx).UpdateUser(&types.UserUpdateRequest{
+				ID:          author.ID.Hex(),
+				Email:       editor.Email,
+				DisplayName: "Author",
+			})
+			So(err, ShouldNotBeNil)
+
+			resp, err := NewUpdateUserLogic(asAdmin, svcCtx).UpdateUser(&types.UserUpdateRequest{
+				ID:          author.ID.Hex(),
+				Email:       "author2@example.com",
+				DisplayName: "Author",
+			})
+			So(err, ShouldBeNil)
+			So(resp.Data.Email, ShouldEqual, "author2@example.com")
+		})
+
+		Convey("Admins should not manage admins or owners", func() {
+			other := newUser("admin2", constants.UserRoleAdmin)
+			_, err := NewSuspendUserLogic(asAdmin, svcCtx).SuspendUser(&types.UserDetailRequest{ID: other.ID.Hex()})
+			So(err, ShouldNotBeNil)
+			_, err = NewUpdateUserRoleLogic(asAdmin, svcCtx).UpdateUserRole(&types.UserRoleUpdateRequest{ID: owner.ID.Hex(), Role: constants.UserRoleAuthor})
+			So(err, ShouldNotBeNil)
+			_, err = NewUpdateUserRoleLogic(asAdmin, svcCtx).UpdateUserRole(&types.UserRoleUpdateRequest{ID: editor.ID.Hex(), Role: constants.UserRoleAdmin})
+			So(err, ShouldNotBeNil)
+			So(users[editor.ID.Hex()].Role, ShouldEqual, constants.UserRoleEditor)
+		})
+
+		Convey("Role change should sign the user out", func() {
+			resp, err := NewUpdateUserRoleLogic(asAdmin, svcCtx).UpdateUserRole(&types.UserRoleUpdateRequest{ID: author.ID.Hex(), Role: constants.UserRoleEditor})
+			So(err, ShouldBeNil)
+			So(resp.Data.Role, ShouldEqual, constants.UserRoleEditor)
+			So(revoked(author.ID.Hex()), ShouldBeTrue)
+		})
+
+		Convey("The last owner should not be demoted, suspended or deleted", func() {
+			other := newUser("owner2", constants.UserRoleOwner)
+			other.Status = constants.UserStatusSuspended
+			asOther := withPrincipal(context.Background(), other.ID.Hex(), other.Role)
+
+			_, err := NewUpdateUserRoleLogic(asOther, svcCtx).UpdateUserRole(&types.UserRoleUpdateRequest{ID: owner.ID.Hex(), Role: constants.UserRoleAdmin})
+			So(err, ShouldEqual, ErrLastOwner)
+			_, err = NewSuspendUserLogic(asOther, svcCtx).SuspendUser(&types.UserDetailRequest{ID: owner.ID.Hex()})
+			So(err, ShouldEqual, ErrLastOwner)
+			_, err = NewDeleteUserLogic(asOther, svcCtx).DeleteUser(&types.UserDeleteRequest{ID: owner.ID.Hex(), ReassignTo: editor.ID.Hex()})
+			So(err, ShouldEqual, ErrLastOwner)
+			So(reassignedTo, ShouldBeEmpty)
+			So(users[owner.ID.Hex()].Role, ShouldEqual, constants.UserRoleOwner)
+			So(users[owner.ID.Hex()].Status, ShouldEqual, constants.UserStatusActive)
+
+			Convey("Demotion should succeed once another owner is active", func() {
+				_, err := NewActivateUserLogic(asOwner, svcCtx).ActivateUser(&types.UserDetailRequest{ID: other.ID.Hex()})
+				So(err, ShouldBeNil)
+
+				_, err = NewUpdateUserRoleLogic(asOther, svcCtx).UpdateUserRole(&types.UserRoleUpdateRequest{ID: owner.ID.Hex(), Role: constants.UserRoleAdmin})
+				So(err, ShouldBeNil)
+				So(users[owner.ID.Hex()].Role, ShouldEqual, constants.UserRoleAdmin)
+			})
+		})
+
+		Convey("Suspend and activate should toggle the status", func() {
+			_, err := NewSuspendUserLogic(asAdmin, svcCtx).SuspendUser(&types.UserDetailRequest{ID: admin.ID.Hex()})
+			So(err, ShouldNotBeNil)
+
+			resp, err := NewSuspendUserLogic(asAdmin, svcCtx).SuspendUser(&types.UserDetailRequest{ID: editor.ID.Hex()})
+			So(err, ShouldBeNil)
+			So(resp.Data.Status, ShouldEqual, constants.UserStatusSuspended)
+			So(revoked(editor.ID.Hex()), ShouldBeTrue)
+
+			resp, err = NewActivateUserLogic(asAdmin, svcCtx).ActivateUser(&types.UserDetailRequest{ID: editor.ID.Hex()})
+			So(err, ShouldBeNil)
+			So(resp.Data.Status, ShouldEqual, constants.UserStatusActive)
+
+			_, err = NewActivateUserLogic(asAdmin, svcCtx).ActivateUser(&types.UserDetailRequest{ID: editor.ID.Hex()})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Unlock should only apply to locked users", func() {
+			_, err := NewUnlockUserLogic(asAdmin, svcCtx).UnlockUser(&types.UserDetailRequest{ID: author.ID.Hex()})
+			So(err, ShouldNotBeNil)
+
+			lockedUntil := time.Now().Add(time.Hour)
+			users[author.ID.Hex()].Status = constants.UserStatusLocked
+			users[author.ID.Hex()].LockedUntil = &lockedUntil
+			resp, err := NewUnlockUserLogic(asAdmin, svcCtx).UnlockUser(&types.UserDetailRequest{ID: author.ID.Hex()})
+			So(err, ShouldBeNil)
+			So(resp.Data.Status, ShouldEqual, constants.UserStatusActive)
+		})
+
+		Convey("Delete should reassign content to the chosen user", func() {
+			_, err := NewDeleteUserLogic(asAdmin, svcCtx).DeleteUser(&types.UserDeleteRequest{ID: admin.ID.Hex(), ReassignTo: editor.ID.Hex()})
+			So(err, ShouldEqual, ErrCannotDeleteSelf)
+
+			_, err = NewDeleteUserLogic(asAdmin, svcCtx).DeleteUser(&types.UserDeleteRequest{ID: author.ID.Hex()})
+			So(err, ShouldNotBeNil)
+			_, err = NewDeleteUserLogic(asAdmin, svcCtx).DeleteUser(&types.UserDeleteRequest{ID: author.ID.Hex(), ReassignTo: author.ID.Hex()})
+			So(err, ShouldNotBeNil)
+
+			resp, err := NewDeleteUserLogic(asAdmin, svcCtx).DeleteUser(&types.UserDeleteRequest{
+				ID:         author.ID.Hex(),
+				ReassignTo: editor.ID.Hex(),
+				PostAction: userPostActionArchive,
+			})
+			So(err, ShouldBeNil)
+			So(resp.Data.PostCount, ShouldEqual, 3)
+			So(resp.Data.PageCount, ShouldEqual, 1)
+			So(resp.Data.Archived, ShouldBeTrue)
+			So(reassignedTo, ShouldEqual, editor.ID.Hex())
+			So(postStatus, ShouldEqual, constants.PostStatusArchived)
+			So(users[author.ID.Hex()].Status, ShouldEqual, constants.UserStatusInactive)
+			So(revoked(author.ID.Hex()), ShouldBeTrue)
+
+			Convey("Deleted users should not receive content", func() {
+				_, err := NewDeleteUserLogic(asAdmin, svcCtx).DeleteUser(&types.UserDeleteRequest{ID: editor.ID.Hex(), ReassignTo: author.ID.Hex()})
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,242 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+// userSession 用户登录会话
+// 会话ID与刷新令牌族ID一致，吊销会话即吊销该次登录派生的全部令牌
+type userSession struct {
+	SessionID  string
+	UserID     string
+	TokenID    string // 当前访问令牌ID
+	IPAddress  string
+	UserAgent  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
+
+// userSessionKey 生成会话记录缓存键
+func userSessionKey(userID, sessionID string) string {
+	return fmt.Sprintf(constants.CacheKeyUserSession, userID, sessionID)
+}
+
+// userSessionsKey 生成用户会话索引缓存键（有序集合，分值为创建时间）
+func userSessionsKey(userID string) string {
+	return fmt.Sprintf(constants.CacheKeyUserSessions, userID)
+}
+
+// toHash 转换为Redis哈希字段
+func (s *userSession) toHash() map[string]interface{} {
+	return map[string]interface{}{
+		"sessionId":  s.SessionID,
+		"userId":     s.UserID,
+		"tokenId":    s.TokenID,
+		"ipAddress":  s.IPAddress,
+		"userAgent":  s.UserAgent,
+		"createdAt":  s.CreatedAt.UnixMilli(),
+		"lastSeenAt": s.LastSeenAt.UnixMilli(),
+	}
+}
+
+// userSessionFromHash 从Redis哈希字段还原会话
+func userSessionFromHash(fields map[string]string) *userSession {
+	return &userSession{
+		SessionID:  fields["sessionId"],
+		UserID:     fields["userId"],
+		TokenID:    fields["tokenId"],
+		IPAddress:  fields["ipAddress"],
+		UserAgent:  fields["userAgent"],
+		CreatedAt:  parseUnixMilli(fields["createdAt"]),
+		LastSeenAt: parseUnixMilli(fields["lastSeenAt"]),
+	}
+}
+
+// parseUnixMilli 解析毫秒时间戳
+func parseUnixMilli(value string) time.Time {
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// startUserSession 登录成功后创建令牌族、签发首对令牌并登记会话
+func startUserSession(ctx context.Context, svcCtx *svc.ServiceContext, user *model.User) (*issuedTokens, error) {
+	tokens, err := startTokenFamily(ctx, svcCtx.Redis, svcCtx.Config.Auth.AccessSecret, user)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	client := utils.ClientInfoFromContext(ctx)
+	evicted, err := createUserSession(ctx, svcCtx.Redis, &userSession{
+		SessionID:  tokens.FamilyID,
+		UserID:     user.ID.Hex(),
+		TokenID:    tokens.AccessTokenID,
+		IPAddress:  client.IP,
+		UserAgent:  client.UserAgent,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(evicted) > 0 {
+		logx.WithContext(ctx).Infof("会话数超出上限，已吊销最早的会话: userID=%s, sessions=%v", user.ID.Hex(), evicted)
+	}
+
+	return tokens, nil
+}
+
+// createUserSession 登记新会话，超出并发上限时吊销最早创建的会话，返回被吊销的会话ID
+func createUserSession(ctx context.Context, rdb redis.Cmdable, session *userSession) ([]string, error) {
+	key := userSessionKey(session.UserID, session.SessionID)
+	indexKey := userSessionsKey(session.UserID)
+
+	_, err := rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, session.toHash())
+		pipe.Expire(ctx, key, constants.CacheTTLRefreshToken)
+		pipe.ZAdd(ctx, indexKey, &redis.Z{Score: float64(session.CreatedAt.UnixMilli()), Member: session.SessionID})
+		pipe.Expire(ctx, indexKey, constants.CacheTTLRefreshToken)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("保存会话失败: %w", err)
+	}
+
+	sessions, err := listUserSessions(ctx, rdb, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var evicted []string
+	for i := 0; i < len(sessions)-constants.MaxConcurrentSessions; i++ {
+		if err := revokeUserSession(ctx, rdb, session.UserID, sessions[i].SessionID); err != nil {
+			return evicted, err
+		}
+		evicted = append(evicted, sessions[i].SessionID)
+	}
+	return evicted, nil
+}
+
+// listUserSessions 按创建时间升序列出用户的有效会话，并清理已过期的索引项
+func listUserSessions(ctx context.Context, rdb redis.Cmdable, userID string) ([]*userSession, error) {
+	indexKey := userSessionsKey(userID)
+	sessionIDs, err := rdb.ZRange(ctx, indexKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("查询会话列表失败: %w", err)
+	}
+
+	sessions := make([]*userSession, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		session, err := getUserSession(ctx, rdb, userID, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if session == nil {
+			rdb.ZRem(ctx, indexKey, sessionID)
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// getUserSession 获取会话记录，不存在时返回nil
+func getUserSession(ctx context.Context, rdb redis.Cmdable, userID, sessionID string) (*userSession, error) {
+	fields, err := rdb.HGetAll(ctx, userSessionKey(userID, sessionID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("查询会话失败: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return userSessionFromHash(fields), nil
+}
+
+// touchUserSession 令牌轮换后更新会话的当前访问令牌、客户端信息和最后活跃时间
+func touchUserSession(ctx context.Context, rdb redis.Cmdable, userID, sessionID, tokenID, ip, userAgent string) error {
+	key := userSessionKey(userID, sessionID)
+	exists, err := rdb.Exists(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("查询会话失败: %w", err)
+	}
+	if exists == 0 {
+		return nil
+	}
+
+	_, err = rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, map[string]interface{}{
+			"tokenId":    tokenID,
+			"ipAddress":  ip,
+			"userAgent":  userAgent,
+			"lastSeenAt": time.Now().UnixMilli(),
+		})
+		pipe.Expire(ctx, key, constants.CacheTTLRefreshToken)
+		pipe.Expire(ctx, userSessionsKey(userID), constants.CacheTTLRefreshToken)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("更新会话失败: %w", err)
+	}
+	return nil
+}
+
+// revokeUserSession 吊销会话：吊销对应的令牌族并删除会话记录
+func revokeUserSession(ctx context.Context, rdb redis.Cmdable, userID, sessionID string) error {
+	family, err := loadTokenFamily(ctx, rdb, sessionID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if family != nil && family.UserID == userID && !family.Revoked {
+		if err := revokeTokenFamily(ctx, rdb, sessionID, family); err != nil {
+			errs = append(errs, fmt.Errorf("吊销令牌族失败: %w", err))
+		}
+	}
+
+	_, err = rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, userSessionKey(userID, sessionID))
+		pipe.ZRem(ctx, userSessionsKey(userID), sessionID)
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("删除会话失败: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// revokeUserSessions 吊销用户除exceptSessionID外的全部会话，返回吊销数量
+func revokeUserSessions(ctx context.Context, rdb redis.Cmdable, userID, exceptSessionID string) (int, error) {
+	sessions, err := listUserSessions(ctx, rdb, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := 0
+	for _, session := range sessions {
+		if session.SessionID == exceptSessionID {
+			continue
+		}
+		if err := revokeUserSession(ctx, rdb, userID, session.SessionID); err != nil {
+			return revoked, err
+		}
+		revoked++
+	}
+	return revoked, nil
+}
@@ -0,0 +1,190 @@
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bytedance/mockey"
+	"github.com/go-redis/redis/v8"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/config"
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+func TestUserSession(t *testing.T) {
+	mockey.PatchConvey("User Session Tests", t, func() {
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+
+		cfg := config.Config{
+			Auth: struct {
+				AccessSecret string
+				AccessExpire int64
+			}{
+				AccessSecret: "test-secret",
+				AccessExpire: 7200,
+			},
+		}
+		svcCtx := &svc.ServiceContext{
+			Config:  cfg,
+			UserDAO: &dao.UserDAO{},
+			Redis:   rdb,
+		}
+
+		testUser := &model.User{
+			ID:       primitive.NewObjectID(),
+			Username: "testuser",
+			Role:     constants.UserRoleAuthor,
+			Status:   constants.UserStatusActive,
+		}
+		userID := testUser.ID.Hex()
+
+		login := func(ip string) *issuedTokens {
+			ctx := utils.WithClientInfo(context.Background(), utils.ClientInfo{
+				IP:        ip,
+				UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			})
+			tokens, err := startUserSession(ctx, svcCtx, testUser)
+			So(err, ShouldBeNil)
+			time.Sleep(2 * time.Millisecond) // 保证会话创建时间有序
+			return tokens
+		}
+		principalOf := func(user *model.User, tokens *issuedTokens) context.Context {
+			return auth.WithPrincipal(context.Background(), &auth.Principal{
+				UserID:    user.ID.Hex(),
+				Username:  user.Username,
+				Role:      user.Role,
+				TokenID:   tokens.AccessTokenID,
+				SessionID: tokens.FamilyID,
+			})
+		}
+
+		Convey("Login should register session with client info", func() {
+			tokens := login("203.0.113.10")
+
+			session, err := getUserSession(context.Background(), rdb, userID, tokens.FamilyID)
+			So(err, ShouldBeNil)
+			So(session, ShouldNotBeNil)
+			So(session.TokenID, ShouldEqual, tokens.AccessTokenID)
+			So(session.IPAddress, ShouldEqual, "203.0.113.10")
+			So(session.CreatedAt.IsZero(), ShouldBeFalse)
+			So(mr.TTL(userSessionKey(userID, tokens.FamilyID)), ShouldEqual, constants.CacheTTLRefreshToken)
+
+			claims, err := utils.NewJWTManager(cfg.Auth.AccessSecret, jwtIssuer).ParseTokenWithoutValidation(tokens.AccessToken)
+			So(err, ShouldBeNil)
+			So(claims.SessionID, ShouldEqual, tokens.FamilyID)
+		})
+
+		Convey("Exceeding the cap should evict the oldest session", func() {
+			var all []*issuedTokens
+			for i := 0; i <= constants.MaxConcurrentSessions; i++ {
+				all = append(all, login("203.0.113.10"))
+			}
+
+			sessions, err := listUserSessions(context.Background(), rdb, userID)
+			So(err, ShouldBeNil)
+			So(len(sessions), ShouldEqual, constants.MaxConcurrentSessions)
+			So(sessions[0].SessionID, ShouldEqual, all[1].FamilyID)
+
+			family, err := loadTokenFamily(context.Background(), rdb, all[0].FamilyID)
+			So(err, ShouldBeNil)
+			So(family.Revoked, ShouldBeTrue)
+			So(mr.Exists(utils.GenerateBlacklistKey(all[0].AccessTokenID)), ShouldBeTrue)
+		})
+
+		Convey("Touch should update existing sessions only", func() {
+			tokens := login("203.0.113.10")
+
+			So(touchUserSession(context.Background(), rdb, userID, tokens.FamilyID, "new-token", "198.51.100.7", "curl/8.0"), ShouldBeNil)
+			session, _ := getUserSession(context.Background(), rdb, userID, tokens.FamilyID)
+			So(session.TokenID, ShouldEqual, "new-token")
+			So(session.IPAddress, ShouldEqual, "198.51.100.7")
+
+			So(touchUserSession(context.Background(), rdb, userID, "missing", "t", "ip", "ua"), ShouldBeNil)
+			So(mr.Exists(userSessionKey(userID, "missing")), ShouldBeFalse)
+		})
+
+		Convey("Session list should mark the current session", func() {
+			first := login("203.0.113.10")
+			current := login("198.51.100.7")
+
+			resp, err := NewGetSessionListLogic(principalOf(testUser, current), svcCtx).GetSessionList()
+			So(err, ShouldBeNil)
+			So(len(resp.Data.List), ShouldEqual, 2)
+			So(resp.Data.List[0].ID, ShouldEqual, current.FamilyID)
+			So(resp.Data.List[0].Current, ShouldBeTrue)
+			So(resp.Data.List[0].Browser, ShouldStartWith, "Chrome")
+			So(resp.Data.List[1].ID, ShouldEqual, first.FamilyID)
+			So(resp.Data.List[1].Current, ShouldBeFalse)
+		})
+
+		Convey("Revoke session should revoke its token family", func() {
+			other := login("203.0.113.10")
+			current := login("198.51.100.7")
+			ctx := principalOf(testUser, current)
+
+			_, err := NewRevokeSessionLogic(ctx, svcCtx).RevokeSession(&types.SessionRevokeRequest{ID: other.FamilyID})
+			So(err, ShouldBeNil)
+
+			family, _ := loadTokenFamily(context.Background(), rdb, other.FamilyID)
+			So(family.Revoked, ShouldBeTrue)
+			session, _ := getUserSession(context.Background(), rdb, userID, other.FamilyID)
+			So(session, ShouldBeNil)
+
+			_, err = NewRevokeSessionLogic(ctx, svcCtx).RevokeSession(&types.SessionRevokeRequest{ID: other.FamilyID})
+			So(err.Error(), ShouldEqual, "会话不存在")
+		})
+
+		Convey("Revoke others should keep the current session", func() {
+			login("203.0.113.10")
+			login("203.0.113.11")
+			current := login("198.51.100.7")
+
+			resp, err := NewRevokeOtherSessionsLogic(principalOf(testUser, current), svcCtx).RevokeOtherSessions()
+			So(err, ShouldBeNil)
+			So(resp.Data.RevokedCount, ShouldEqual, 2)
+
+			sessions, _ := listUserSessions(context.Background(), rdb, userID)
+			So(len(sessions), ShouldEqual, 1)
+			So(sessions[0].SessionID, ShouldEqual, current.FamilyID)
+		})
+
+		Convey("Admin should revoke all sessions of a user", func() {
+			login("203.0.113.10")
+			login("203.0.113.11")
+
+			admin := &model.User{ID: primitive.NewObjectID(), Username: "admin", Role: constants.UserRoleAdmin}
+			ctx := principalOf(admin, &issuedTokens{})
+
+			mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+			resp, err := NewRevokeUserSessionsLogic(ctx, svcCtx).RevokeUserSessions(&types.UserDetailRequest{ID: userID})
+			So(err, ShouldBeNil)
+			So(resp.Data.RevokedCount, ShouldEqual, 2)
+
+			sessions, _ := listUserSessions(context.Background(), rdb, userID)
+			So(len(sessions), ShouldEqual, 0)
+			revokedBefore, _ := auth.TokensRevokedBefore(context.Background(), rdb, userID)
+			So(revokedBefore, ShouldBeGreaterThan, 0)
+		})
+
+		Convey("Admin should not revoke owner sessions", func() {
+			owner := &model.User{ID: primitive.NewObjectID(), Username: "owner", Role: constants.UserRoleOwner}
+			admin := &model.User{ID: primitive.NewObjectID(), Username: "admin", Role: constants.UserRoleAdmin}
+
+			mockey.Mock((*dao.UserDAO).GetByID).Return(owner, nil).Build()
+			_, err := NewRevokeUserSessionsLogic(principalOf(admin, &issuedTokens{}), svcCtx).RevokeUserSessions(&types.UserDetailRequest{ID: owner.ID.Hex()})
+			So(err.Error(), ShouldEqual, "权限不足")
+		})
+	})
+}
@@ -0,0 +1,111 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth/webauthn"
+	"github.com/heimdall-api/common/constants"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type WebAuthnLoginLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 使用通行密钥登录
+func NewWebAuthnLoginLogic(ctx context.Context, svcCtx *svc.ServiceContext) *WebAuthnLoginLogic {
+	return &WebAuthnLoginLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *WebAuthnLoginLogic) WebAuthnLogin(req *types.WebAuthnLoginRequest) (resp *types.LoginResponse, err error) {
+	// 1. 参数验证
+	if req == nil || req.ID == "" || req.Response.ClientDataJSON == "" ||
+		req.Response.AuthenticatorData == "" || req.Response.Signature == "" {
+		return nil, errInvalidWebAuthnLogin
+	}
+
+	// 2. 检查该IP的登录失败次数，与密码登录共用计数
+	loginLogic := NewLoginLogic(l.ctx, l.svcCtx)
+	clientIP := loginLogic.getClientIP()
+	if err := loginLogic.checkIPFailures(clientIP); err != nil {
+		return nil, err
+	}
+
+	// 3. 取出客户端签名的挑战并消费，挑战只能使用一次
+	challenge, err := webauthn.ClientChallenge(req.Response.ClientDataJSON)
+	if err != nil {
+		loginLogic.recordIPFailure(clientIP)
+		return nil, errInvalidWebAuthnLogin
+	}
+	consumed, err := l.svcCtx.Redis.Del(l.ctx, webAuthnLoginKey(challenge)).Result()
+	if err != nil {
+		l.Logger.Errorf("消费通行密钥登录挑战失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if consumed == 0 {
+		return nil, errors.New("登录已过期，请重试")
+	}
+
+	// 4. 查找凭据，凭据不存在或与用户句柄不符同样计入IP失败次数
+	credential, err := l.svcCtx.WebAuthnCredentialDAO.GetByCredentialID(l.ctx, req.ID)
+	if err != nil {
+		l.Logger.Errorf("查询通行密钥失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if credential == nil || (req.Response.UserHandle != "" && req.Response.UserHandle != webAuthnUserHandle(credential.UserID)) {
+		loginLogic.recordIPFailure(clientIP)
+		return nil, errInvalidWebAuthnLogin
+	}
+
+	// 5. 获取用户并检查状态
+	user, err := l.svcCtx.UserDAO.GetByID(l.ctx, credential.UserID.Hex())
+	if err != nil {
+		l.Logger.Errorf("获取用户信息失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if user == nil {
+		loginLogic.recordIPFailure(clientIP)
+		return nil, errInvalidWebAuthnLogin
+	}
+	if err := loginLogic.checkUserStatus(user); err != nil {
+		loginLogic.recordLoginFailureWithMethod(user.Username, clientIP, err.Error(), constants.LoginMethodWebAuthn)
+		loginLogic.recordIPFailure(clientIP)
+		return nil, err
+	}
+
+	// 6. 校验签名，失败时与密码错误一样计入IP和账户失败次数
+	result, err := l.svcCtx.WebAuthn.VerifyAssertion(challenge, credential.PublicKey, credential.SignCount, &webauthn.AssertionResponse{
+		ClientDataJSON:    req.Response.ClientDataJSON,
+		AuthenticatorData: req.Response.AuthenticatorData,
+		Signature:         req.Response.Signature,
+	})
+	if err != nil {
+		if errors.Is(err, webauthn.ErrSignCountRegressed) {
+			l.Logger.Errorf("通行密钥签名计数回退，认证器可能被复制: userID=%s, credentialID=%s", user.ID.Hex(), credential.ID.Hex())
+		}
+		loginLogic.recordLoginFailureWithMethod(user.Username, clientIP, "通行密钥验证失败", constants.LoginMethodWebAuthn)
+		loginLogic.recordIPFailure(clientIP)
+		if lockErr := loginLogic.registerLoginFailure(user); lockErr != nil {
+			return nil, lockErr
+		}
+		return nil, errInvalidWebAuthnLogin
+	}
+
+	// 7. 记录签名计数和使用信息
+	if err := l.svcCtx.WebAuthnCredentialDAO.UpdateUsage(l.ctx, credential.ID, result.SignCount, result.BackupState, clientIP); err != nil {
+		l.Logger.Errorf("更新通行密钥使用信息失败: %v", err)
+	}
+
+	// 8. 通行密钥要求用户验证，本身即为多因素认证，直接签发令牌
+	return loginLogic.completeLogin(user, clientIP, constants.LoginMethodWebAuthn)
+}
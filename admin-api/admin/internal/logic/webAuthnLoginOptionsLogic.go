@@ -0,0 +1,56 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth/webauthn"
+	"github.com/heimdall-api/common/constants"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type WebAuthnLoginOptionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取通行密钥登录选项
+func NewWebAuthnLoginOptionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *WebAuthnLoginOptionsLogic {
+	return &WebAuthnLoginOptionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *WebAuthnLoginOptionsLogic) WebAuthnLoginOptions() (resp *types.WebAuthnLoginOptionsResponse, err error) {
+	// 1. 生成挑战，登录前不知道用户身份，按挑战本身登记
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		l.Logger.Errorf("生成通行密钥挑战失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if err := l.svcCtx.Redis.Set(l.ctx, webAuthnLoginKey(challenge), 1, constants.CacheTTLWebAuthnChallenge).Err(); err != nil {
+		l.Logger.Errorf("保存通行密钥登录挑战失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	// 2. 不指定凭据，由认证器列出可发现凭据，避免暴露用户是否存在
+	return &types.WebAuthnLoginOptionsResponse{
+		Code:      200,
+		Message:   "获取成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.WebAuthnRequestOptions{
+			Challenge:        challenge,
+			Timeout:          webAuthnTimeout(),
+			RPID:             l.svcCtx.WebAuthn.ID(),
+			UserVerification: l.svcCtx.WebAuthn.UserVerification(),
+			AllowCredentials: []types.WebAuthnCredentialDescriptor{},
+		},
+	}, nil
+}
@@ -0,0 +1,124 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/auth/webauthn"
+	"github.com/heimdall-api/common/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// defaultWebAuthnCredentialName 未命名通行密钥的默认名称
+const defaultWebAuthnCredentialName = "通行密钥"
+
+type WebAuthnRegisterLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 完成通行密钥注册
+func NewWebAuthnRegisterLogic(ctx context.Context, svcCtx *svc.ServiceContext) *WebAuthnRegisterLogic {
+	return &WebAuthnRegisterLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *WebAuthnRegisterLogic) WebAuthnRegister(req *types.WebAuthnRegisterRequest) (resp *types.WebAuthnCredentialResponse, err error) {
+	// 1. 参数验证
+	if req == nil || req.ID == "" || req.Response.ClientDataJSON == "" || req.Response.AttestationObject == "" {
+		return nil, errors.New("通行密钥注册数据不完整")
+	}
+	name := strings.TrimSpace(req.Name)
+	if len([]rune(name)) > 50 {
+		return nil, errors.New("名称长度不能超过50个字符")
+	}
+	if name == "" {
+		name = defaultWebAuthnCredentialName
+	}
+
+	// 2. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := primitive.ObjectIDFromHex(principal.UserID)
+	if err != nil {
+		return nil, errors.New("无效的用户ID")
+	}
+
+	// 3. 取出注册挑战，挑战只能使用一次
+	challenge, err := l.svcCtx.Redis.GetDel(l.ctx, webAuthnRegisterKey(principal.UserID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, errors.New("注册已过期，请重新开始")
+	}
+	if err != nil {
+		l.Logger.Errorf("查询通行密钥注册挑战失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	// 4. 校验认证器应答
+	verified, err := l.svcCtx.WebAuthn.VerifyRegistration(challenge, &webauthn.RegistrationResponse{
+		ClientDataJSON:    req.Response.ClientDataJSON,
+		AttestationObject: req.Response.AttestationObject,
+	})
+	if err != nil {
+		l.Logger.Infof("通行密钥注册校验失败: userID=%s, err=%v", principal.UserID, err)
+		return nil, errors.New("通行密钥验证失败")
+	}
+	credentialID := webauthn.EncodeBase64URL(verified.ID)
+	if credentialID != req.ID {
+		return nil, errors.New("通行密钥验证失败")
+	}
+
+	// 5. 凭据ID全局唯一
+	existing, err := l.svcCtx.WebAuthnCredentialDAO.GetByCredentialID(l.ctx, credentialID)
+	if err != nil {
+		l.Logger.Errorf("查询通行密钥失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if existing != nil {
+		return nil, errors.New("该通行密钥已注册")
+	}
+
+	// 6. 保存凭据公钥
+	credential := &model.WebAuthnCredential{
+		UserID:            userID,
+		CredentialID:      credentialID,
+		PublicKey:         verified.PublicKey,
+		Algorithm:         verified.Algorithm,
+		SignCount:         verified.SignCount,
+		AAGUID:            webauthn.EncodeBase64URL(verified.AAGUID),
+		Name:              name,
+		AttestationFormat: verified.AttestationFormat,
+		Transports:        req.Response.Transports,
+		BackupEligible:    verified.BackupEligible,
+		BackedUp:          verified.BackupState,
+	}
+	if err := l.svcCtx.WebAuthnCredentialDAO.Create(l.ctx, credential); err != nil {
+		l.Logger.Errorf("保存通行密钥失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	audit.SetTargetID(l.ctx, credential.ID.Hex())
+
+	l.Logger.Infof("用户注册通行密钥: userID=%s, credentialID=%s", principal.UserID, credential.ID.Hex())
+	return &types.WebAuthnCredentialResponse{
+		Code:      200,
+		Message:   "通行密钥已添加",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data:      toWebAuthnCredentialInfo(credential),
+	}, nil
+}
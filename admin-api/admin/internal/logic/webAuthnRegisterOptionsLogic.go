@@ -0,0 +1,118 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/auth/webauthn"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type WebAuthnRegisterOptionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取通行密钥注册选项
+func NewWebAuthnRegisterOptionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *WebAuthnRegisterOptionsLogic {
+	return &WebAuthnRegisterOptionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *WebAuthnRegisterOptionsLogic) WebAuthnRegisterOptions(req *types.WebAuthnRegisterOptionsRequest) (resp *types.WebAuthnRegisterOptionsResponse, err error) {
+	// 1. 参数验证
+	if req == nil || req.Password == "" {
+		return nil, errors.New("密码不能为空")
+	}
+
+	// 2. 获取当前认证主体
+	principal, err := auth.FromContext(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 获取用户信息
+	user, err := l.svcCtx.UserDAO.GetByID(l.ctx, principal.UserID)
+	if err != nil {
+		l.Logger.Errorf("获取用户信息失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if user == nil {
+		return nil, errors.New("用户不存在")
+	}
+
+	// 4. 校验密码，避免令牌泄露后被绑定新的登录方式
+	if err := utils.VerifyPassword(req.Password, user.PasswordHash); err != nil {
+		return nil, errors.New("密码错误")
+	}
+
+	// 5. 已注册的凭据不允许在同一认证器上重复注册
+	credentials, err := l.svcCtx.WebAuthnCredentialDAO.ListByUserID(l.ctx, user.ID)
+	if err != nil {
+		l.Logger.Errorf("查询通行密钥失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	exclude := make([]types.WebAuthnCredentialDescriptor, 0, len(credentials))
+	for _, credential := range credentials {
+		exclude = append(exclude, types.WebAuthnCredentialDescriptor{
+			Type:       webAuthnCredentialType,
+			ID:         credential.CredentialID,
+			Transports: credential.Transports,
+		})
+	}
+
+	// 6. 生成挑战，每个用户同时只保留最新的注册挑战
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		l.Logger.Errorf("生成通行密钥挑战失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+	if err := l.svcCtx.Redis.Set(l.ctx, webAuthnRegisterKey(principal.UserID), challenge, constants.CacheTTLWebAuthnChallenge).Err(); err != nil {
+		l.Logger.Errorf("保存通行密钥注册挑战失败: %v", err)
+		return nil, errors.New("系统错误，请稍后重试")
+	}
+
+	// 7. 构造创建选项
+	params := make([]types.WebAuthnCredentialParameter, 0, len(webauthn.SupportedAlgorithms))
+	for _, alg := range webauthn.SupportedAlgorithms {
+		params = append(params, types.WebAuthnCredentialParameter{Type: webAuthnCredentialType, Alg: alg})
+	}
+
+	rp := l.svcCtx.WebAuthn
+	return &types.WebAuthnRegisterOptionsResponse{
+		Code:      200,
+		Message:   "获取成功",
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data: types.WebAuthnCreationOptions{
+			Challenge: challenge,
+			RP: types.WebAuthnRelyingParty{
+				ID:   rp.ID(),
+				Name: rp.Name(),
+			},
+			User: types.WebAuthnUserEntity{
+				ID:          webAuthnUserHandle(user.ID),
+				Name:        user.Username,
+				DisplayName: user.DisplayName,
+			},
+			PubKeyCredParams:   params,
+			Timeout:            webAuthnTimeout(),
+			ExcludeCredentials: exclude,
+			AuthenticatorSelection: types.WebAuthnAuthenticatorSelection{
+				ResidentKey:      "required", // 可发现凭据，登录时无需输入用户名
+				UserVerification: rp.UserVerification(),
+			},
+			Attestation: "none",
+		},
+	}, nil
+}
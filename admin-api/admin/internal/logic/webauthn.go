@@ -0,0 +1,56 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth/webauthn"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+)
+
+// webAuthnCredentialType 凭据类型，WebAuthn只定义了public-key
+const webAuthnCredentialType = "public-key"
+
+// errInvalidWebAuthnLogin 通行密钥登录失败，不区分具体原因
+var errInvalidWebAuthnLogin = errors.New("通行密钥验证失败")
+
+// webAuthnRegisterKey 生成通行密钥注册挑战缓存键
+func webAuthnRegisterKey(userID string) string {
+	return fmt.Sprintf(constants.CacheKeyWebAuthnRegister, userID)
+}
+
+// webAuthnLoginKey 生成通行密钥登录挑战缓存键
+func webAuthnLoginKey(challenge string) string {
+	return fmt.Sprintf(constants.CacheKeyWebAuthnLogin, challenge)
+}
+
+// webAuthnUserHandle 用户句柄，使用用户ID的原始字节，不包含可识别的个人信息
+func webAuthnUserHandle(userID primitive.ObjectID) string {
+	return webauthn.EncodeBase64URL(userID[:])
+}
+
+// webAuthnTimeout 浏览器端仪式超时时间（毫秒），与挑战有效期一致
+func webAuthnTimeout() int {
+	return int(constants.CacheTTLWebAuthnChallenge.Milliseconds())
+}
+
+// toWebAuthnCredentialInfo 转换为接口返回的凭据信息
+func toWebAuthnCredentialInfo(credential *model.WebAuthnCredential) types.WebAuthnCredentialInfo {
+	info := types.WebAuthnCredentialInfo{
+		ID:         credential.ID.Hex(),
+		Name:       credential.Name,
+		Transports: credential.Transports,
+		BackedUp:   credential.BackedUp,
+		CreatedAt:  credential.CreatedAt.Format(time.RFC3339),
+		LastUsedIP: credential.LastUsedIP,
+	}
+	if credential.LastUsedAt != nil {
+		info.LastUsedAt = credential.LastUsedAt.Format(time.RFC3339)
+	}
+	return info
+}
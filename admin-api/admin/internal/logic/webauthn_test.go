@@ -0,0 +1,313 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bytedance/mockey"
+	"github.com/go-redis/redis/v8"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/admin-api/admin/internal/config"
+	"github.com/heimdall-api/admin-api/admin/internal/svc"
+	"github.com/heimdall-api/admin-api/admin/internal/types"
+	"github.com/heimdall-api/common/auth/webauthn"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+// webAuthnFixture 复用 common/auth/webauthn 录制的仪式数据
+type webAuthnFixture struct {
+	RPID              string `json:"rpId"`
+	Origin            string `json:"origin"`
+	Challenge         string `json:"challenge"`
+	CredentialID      string `json:"credentialId"`
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AttestationObject string `json:"attestationObject"`
+	AuthenticatorData string `json:"authenticatorData"`
+	Signature         string `json:"signature"`
+	UserHandle        string `json:"userHandle"`
+}
+
+func loadWebAuthnFixture(t *testing.T, name string) *webAuthnFixture {
+	data, err := os.ReadFile("../../../../common/auth/webauthn/testdata/" + name + ".json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	var f webAuthnFixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatalf("parse fixture: %v", err)
+	}
+	return &f
+}
+
+func TestWebAuthnLogic(t *testing.T) {
+	mockey.PatchConvey("WebAuthn Logic Tests", t, func() {
+		registration := loadWebAuthnFixture(t, "registration_es256")
+		assertion := loadWebAuthnFixture(t, "assertion_es256")
+
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+
+		rp, err := webauthn.New(webauthn.Config{
+			RPID:                    registration.RPID,
+			RPName:                  "Heimdall",
+			Origins:                 []string{registration.Origin},
+			RequireUserVerification: true,
+		})
+		So(err, ShouldBeNil)
+
+		svcCtx := &svc.ServiceContext{
+			Config: config.Config{
+				Auth: struct {
+					AccessSecret string
+					AccessExpire int64
+				}{
+					AccessSecret: "test-secret",
+					AccessExpire: 7200,
+				},
+			},
+			UserDAO:               &dao.UserDAO{},
+			WebAuthnCredentialDAO: &dao.WebAuthnCredentialDAO{},
+			WebAuthn:              rp,
+			Redis:                 rdb,
+		}
+
+		// 夹具的用户句柄即为用户ID的原始字节
+		handle, _ := webauthn.DecodeBase64URL(assertion.UserHandle)
+		var userID primitive.ObjectID
+		copy(userID[:], handle)
+		passwordHash, _ := utils.HashPassword("Current#Pass1")
+		testUser := &model.User{
+			ID:           userID,
+			Username:     "admin",
+			DisplayName:  "Admin",
+			PasswordHash: passwordHash,
+			Role:         constants.UserRoleAdmin,
+			Status:       constants.UserStatusActive,
+		}
+
+		// 用内存中的数据模拟数据库
+		var credentials []*model.WebAuthnCredential
+		mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+		mockey.Mock((*dao.UserDAO).UpdateLoginInfo).Return(nil).Build()
+		failCount := 0
+		mockey.Mock((*dao.UserDAO).IncrementLoginFailCount).To(func(_ *dao.UserDAO, _ context.Context, _ string) (int, error) {
+			failCount++
+			return failCount, nil
+		}).Build()
+		mockey.Mock((*dao.WebAuthnCredentialDAO).Create).To(func(_ *dao.WebAuthnCredentialDAO, _ context.Context, credential *model.WebAuthnCredential) error {
+			credential.ID = primitive.NewObjectID()
+			credentials = append(credentials, credential)
+			return nil
+		}).Build()
+		mockey.Mock((*dao.WebAuthnCredentialDAO).GetByCredentialID).To(func(_ *dao.WebAuthnCredentialDAO, _ context.Context, credentialID string) (*model.WebAuthnCredential, error) {
+			for _, credential := range credentials {
+				if credential.CredentialID == credentialID {
+					return credential, nil
+				}
+			}
+			return nil, nil
+		}).Build()
+		mockey.Mock((*dao.WebAuthnCredentialDAO).ListByUserID).To(func(_ *dao.WebAuthnCredentialDAO, _ context.Context, _ primitive.ObjectID) ([]*model.WebAuthnCredential, error) {
+			return credentials, nil
+		}).Build()
+		mockey.Mock((*dao.WebAuthnCredentialDAO).UpdateUsage).To(func(_ *dao.WebAuthnCredentialDAO, _ context.Context, id primitive.ObjectID, signCount uint32, backedUp bool, ip string) error {
+			for _, credential := range credentials {
+				if credential.ID == id {
+					credential.SignCount = signCount
+					credential.BackedUp = backedUp
+					credential.LastUsedIP = ip
+				}
+			}
+			return nil
+		}).Build()
+		mockey.Mock((*dao.WebAuthnCredentialDAO).Delete).To(func(_ *dao.WebAuthnCredentialDAO, _ context.Context, id, _ primitive.ObjectID) (bool, error) {
+			for i, credential := range credentials {
+				if credential.ID == id {
+					credentials = append(credentials[:i], credentials[i+1:]...)
+					return true, nil
+				}
+			}
+			return false, nil
+		}).Build()
+		var loginLogs []string
+		mockey.Mock(createLoginLog).To(func(_ context.Context, _ *svc.ServiceContext, loginLog *model.LoginLog) error {
+			loginLogs = append(loginLogs, loginLog.Status+":"+loginLog.LoginMethod)
+			return nil
+		}).Build()
+
+		ctx := utils.WithClientInfo(withPrincipal(context.Background(), testUser.ID.Hex(), testUser.Role),
+			utils.ClientInfo{IP: "203.0.113.10"})
+		publicCtx := utils.WithClientInfo(context.Background(), utils.ClientInfo{IP: "203.0.113.10"})
+
+		registerRequest := &types.WebAuthnRegisterRequest{
+			Name: "MacBook",
+			ID:   registration.CredentialID,
+			Response: types.WebAuthnAttestationResponse{
+				ClientDataJSON:    registration.ClientDataJSON,
+				AttestationObject: registration.AttestationObject,
+				Transports:        []string{"internal"},
+			},
+		}
+		// register 以夹具的挑战完成注册
+		register := func() (*types.WebAuthnCredentialResponse, error) {
+			mr.Set(webAuthnRegisterKey(testUser.ID.Hex()), registration.Challenge)
+			return NewWebAuthnRegisterLogic(ctx, svcCtx).WebAuthnRegister(registerRequest)
+		}
+
+		loginRequest := func() *types.WebAuthnLoginRequest {
+			return &types.WebAuthnLoginRequest{
+				ID: assertion.CredentialID,
+				Response: types.WebAuthnAssertionResponse{
+					ClientDataJSON:    assertion.ClientDataJSON,
+					AuthenticatorData: assertion.AuthenticatorData,
+					Signature:         assertion.Signature,
+					UserHandle:        assertion.UserHandle,
+				},
+			}
+		}
+
+		Convey("Register options should require the password and store a challenge", func() {
+			_, err := NewWebAuthnRegisterOptionsLogic(ctx, svcCtx).WebAuthnRegisterOptions(&types.WebAuthnRegisterOptionsRequest{Password: "wrong"})
+			So(err, ShouldNotBeNil)
+
+			resp, err := NewWebAuthnRegisterOptionsLogic(ctx, svcCtx).WebAuthnRegisterOptions(&types.WebAuthnRegisterOptionsRequest{Password: "Current#Pass1"})
+			So(err, ShouldBeNil)
+			So(resp.Data.RP.ID, ShouldEqual, registration.RPID)
+			So(resp.Data.User.ID, ShouldEqual, assertion.UserHandle)
+			So(resp.Data.Attestation, ShouldEqual, "none")
+			So(resp.Data.AuthenticatorSelection.UserVerification, ShouldEqual, "required")
+			So(resp.Data.PubKeyCredParams, ShouldHaveLength, len(webauthn.SupportedAlgorithms))
+
+			stored, _ := mr.Get(webAuthnRegisterKey(testUser.ID.Hex()))
+			So(stored, ShouldEqual, resp.Data.Challenge)
+		})
+
+		Convey("Register should store the credential and consume the challenge", func() {
+			resp, err := register()
+			So(err, ShouldBeNil)
+			So(resp.Data.Name, ShouldEqual, "MacBook")
+			So(credentials, ShouldHaveLength, 1)
+			So(credentials[0].UserID, ShouldEqual, testUser.ID)
+			So(credentials[0].CredentialID, ShouldEqual, registration.CredentialID)
+			So(credentials[0].Algorithm, ShouldEqual, webauthn.AlgES256)
+			So(credentials[0].Transports, ShouldResemble, []string{"internal"})
+			So(mr.Exists(webAuthnRegisterKey(testUser.ID.Hex())), ShouldBeFalse)
+
+			_, err = NewWebAuthnRegisterLogic(ctx, svcCtx).WebAuthnRegister(registerRequest)
+			So(err, ShouldNotBeNil)
+
+			Convey("Registering the same authenticator twice should fail", func() {
+				_, err := register()
+				So(err, ShouldNotBeNil)
+				So(credentials, ShouldHaveLength, 1)
+			})
+
+			Convey("Register options should exclude existing credentials", func() {
+				resp, err := NewWebAuthnRegisterOptionsLogic(ctx, svcCtx).WebAuthnRegisterOptions(&types.WebAuthnRegisterOptionsRequest{Password: "Current#Pass1"})
+				So(err, ShouldBeNil)
+				So(resp.Data.ExcludeCredentials, ShouldHaveLength, 1)
+				So(resp.Data.ExcludeCredentials[0].ID, ShouldEqual, registration.CredentialID)
+			})
+
+			Convey("List and delete should manage the credential", func() {
+				list, err := NewGetWebAuthnCredentialListLogic(ctx, svcCtx).GetWebAuthnCredentialList()
+				So(err, ShouldBeNil)
+				So(list.Data.List, ShouldHaveLength, 1)
+
+				req := &types.WebAuthnCredentialDeleteRequest{ID: list.Data.List[0].ID}
+				_, err = NewDeleteWebAuthnCredentialLogic(ctx, svcCtx).DeleteWebAuthnCredential(req)
+				So(err, ShouldBeNil)
+				So(credentials, ShouldBeEmpty)
+
+				_, err = NewDeleteWebAuthnCredentialLogic(ctx, svcCtx).DeleteWebAuthnCredential(req)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Login options should issue a discoverable challenge", func() {
+				resp, err := NewWebAuthnLoginOptionsLogic(publicCtx, svcCtx).WebAuthnLoginOptions()
+				So(err, ShouldBeNil)
+				So(resp.Data.RPID, ShouldEqual, registration.RPID)
+				So(resp.Data.AllowCredentials, ShouldBeEmpty)
+				So(mr.Exists(webAuthnLoginKey(resp.Data.Challenge)), ShouldBeTrue)
+			})
+
+			Convey("Login should issue tokens once per challenge", func() {
+				mr.Set(webAuthnLoginKey(assertion.Challenge), "1")
+
+				resp, err := NewWebAuthnLoginLogic(publicCtx, svcCtx).WebAuthnLogin(loginRequest())
+				So(err, ShouldBeNil)
+				So(resp.Data.Token, ShouldNotBeEmpty)
+				So(resp.Data.User.ID, ShouldEqual, testUser.ID.Hex())
+				So(credentials[0].SignCount, ShouldEqual, 1)
+				So(credentials[0].LastUsedIP, ShouldEqual, "203.0.113.10")
+				So(loginLogs, ShouldResemble, []string{"success:webauthn"})
+
+				_, err = NewWebAuthnLoginLogic(publicCtx, svcCtx).WebAuthnLogin(loginRequest())
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Login should reject a tampered signature and log the failure", func() {
+				mr.Set(webAuthnLoginKey(assertion.Challenge), "1")
+				req := loginRequest()
+				sig, _ := webauthn.DecodeBase64URL(req.Response.Signature)
+				sig[len(sig)-1] ^= 0xff
+				req.Response.Signature = webauthn.EncodeBase64URL(sig)
+
+				_, err := NewWebAuthnLoginLogic(publicCtx, svcCtx).WebAuthnLogin(req)
+				So(err, ShouldEqual, errInvalidWebAuthnLogin)
+				So(loginLogs, ShouldResemble, []string{"failed:webauthn"})
+				So(credentials[0].SignCount, ShouldEqual, 0)
+				So(failCount, ShouldEqual, 1)
+			})
+
+			Convey("Login should be throttled once the IP reaches the failure limit", func() {
+				svcCtx.Config.Security.MaxLoginAttemptsPerIP = 1
+				svcCtx.Config.Security.LoginIPBlockDuration = 60
+				mr.Set(webAuthnLoginKey(assertion.Challenge), "1")
+				req := loginRequest()
+				req.ID = "unknown-credential"
+
+				_, err := NewWebAuthnLoginLogic(publicCtx, svcCtx).WebAuthnLogin(req)
+				So(err, ShouldEqual, errInvalidWebAuthnLogin)
+
+				mr.Set(webAuthnLoginKey(assertion.Challenge), "1")
+				_, err = NewWebAuthnLoginLogic(publicCtx, svcCtx).WebAuthnLogin(loginRequest())
+				So(err.Error(), ShouldContainSubstring, "登录失败次数过多")
+				So(mr.Exists(webAuthnLoginKey(assertion.Challenge)), ShouldBeTrue)
+			})
+
+			Convey("Login should reject a mismatched user handle", func() {
+				mr.Set(webAuthnLoginKey(assertion.Challenge), "1")
+				req := loginRequest()
+				req.Response.UserHandle = webAuthnUserHandle(primitive.NewObjectID())
+
+				_, err := NewWebAuthnLoginLogic(publicCtx, svcCtx).WebAuthnLogin(req)
+				So(err, ShouldEqual, errInvalidWebAuthnLogin)
+			})
+
+			Convey("Login should be refused for disabled users", func() {
+				mr.Set(webAuthnLoginKey(assertion.Challenge), "1")
+				testUser.Status = constants.UserStatusInactive
+
+				_, err := NewWebAuthnLoginLogic(publicCtx, svcCtx).WebAuthnLogin(loginRequest())
+				So(err, ShouldNotBeNil)
+				So(loginLogs, ShouldResemble, []string{"failed:webauthn"})
+			})
+		})
+
+		Convey("Login without an issued challenge should fail", func() {
+			_, err := NewWebAuthnLoginLogic(publicCtx, svcCtx).WebAuthnLogin(loginRequest())
+			So(err, ShouldNotBeNil)
+			So(loginLogs, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,200 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+// routeAudit 修改数据的路由与审计操作的对应关系
+type routeAudit struct {
+	Method     string
+	Path       string // 路由模式，:id 段的值作为操作对象ID
+	Action     string // 操作，格式为 对象.动作
+	TargetType string // 操作对象类型
+}
+
+// routeAudits 需要审计的路由表，需要认证的路由中所有POST/PUT/PATCH/DELETE都必须在此登记
+// 登录、刷新令牌、申请重置密码和登录选项不修改管理数据，登录行为已记录在登录日志中，不在此登记
+var routeAudits = []routeAudit{
+	{http.MethodPost, "/api/v1/admin/auth/invitations/accept", "invitation.accept", constants.AuditTargetInvitation},
+	{http.MethodPost, "/api/v1/admin/auth/password/reset", "auth.password.reset", constants.AuditTargetUser},
+
+	{http.MethodPost, "/api/v1/admin/auth/logout", "auth.logout", constants.AuditTargetUser},
+	{http.MethodPost, "/api/v1/admin/auth/mfa/recovery-codes", "mfa.recovery_codes.regenerate", constants.AuditTargetUser},
+	{http.MethodPost, "/api/v1/admin/auth/mfa/totp/confirm", "mfa.totp.enable", constants.AuditTargetUser},
+	{http.MethodPost, "/api/v1/admin/auth/mfa/totp/disable", "mfa.totp.disable", constants.AuditTargetUser},
+	{http.MethodPost, "/api/v1/admin/auth/mfa/totp/setup", "mfa.totp.setup", constants.AuditTargetUser},
+	{http.MethodPost, "/api/v1/admin/auth/password", "auth.password.change", constants.AuditTargetUser},
+	{http.MethodPut, "/api/v1/admin/auth/profile", "profile.update", constants.AuditTargetUser},
+	{http.MethodDelete, "/api/v1/admin/auth/sessions/:id", "session.revoke", constants.AuditTargetSession},
+	{http.MethodPost, "/api/v1/admin/auth/sessions/revoke-others", "session.revoke_others", constants.AuditTargetUser},
+	{http.MethodPost, "/api/v1/admin/auth/tokens", "access_token.create", constants.AuditTargetAccessToken},
+	{http.MethodDelete, "/api/v1/admin/auth/tokens/:id", "access_token.revoke", constants.AuditTargetAccessToken},
+	{http.MethodDelete, "/api/v1/admin/auth/webauthn/credentials/:id", "webauthn.credential.delete", constants.AuditTargetWebAuthnCredential},
+	{http.MethodPost, "/api/v1/admin/auth/webauthn/register", "webauthn.credential.register", constants.AuditTargetWebAuthnCredential},
+	{http.MethodPost, "/api/v1/admin/auth/webauthn/register/options", "webauthn.credential.register_options", constants.AuditTargetUser},
+
+	{http.MethodPost, "/api/v1/admin/invitations", "invitation.create", constants.AuditTargetInvitation},
+	{http.MethodDelete, "/api/v1/admin/invitations/:id", "invitation.revoke", constants.AuditTargetInvitation},
+	{http.MethodPost, "/api/v1/admin/invitations/:id/resend", "invitation.resend", constants.AuditTargetInvitation},
+
+	{http.MethodPost, "/api/v1/admin/posts", "post.create", constants.AuditTargetPost},
+	{http.MethodPut, "/api/v1/admin/posts/:id", "post.update", constants.AuditTargetPost},
+	{http.MethodDelete, "/api/v1/admin/posts/:id", "post.delete", constants.AuditTargetPost},
+	{http.MethodPost, "/api/v1/admin/posts/:id/publish", "post.publish", constants.AuditTargetPost},
+	{http.MethodPost, "/api/v1/admin/posts/:id/unpublish", "post.unpublish", constants.AuditTargetPost},
+
+	{http.MethodPost, "/api/v1/admin/pages", "page.create", constants.AuditTargetPage},
+	{http.MethodPut, "/api/v1/admin/pages/:id", "page.update", constants.AuditTargetPage},
+	{http.MethodDelete, "/api/v1/admin/pages/:id", "page.delete", constants.AuditTargetPage},
+	{http.MethodPost, "/api/v1/admin/pages/:id/publish", "page.publish", constants.AuditTargetPage},
+	{http.MethodPost, "/api/v1/admin/pages/:id/unpublish", "page.unpublish", constants.AuditTargetPage},
+
+	{http.MethodPost, "/api/v1/admin/users", "user.create", constants.AuditTargetUser},
+	{http.MethodPut, "/api/v1/admin/users/:id", "user.update", constants.AuditTargetUser},
+	{http.MethodDelete, "/api/v1/admin/users/:id", "user.delete", constants.AuditTargetUser},
+	{http.MethodPatch, "/api/v1/admin/users/:id/role", "user.role.change", constants.AuditTargetUser},
+	{http.MethodPost, "/api/v1/admin/users/:id/suspend", "user.suspend", constants.AuditTargetUser},
+	{http.MethodPost, "/api/v1/admin/users/:id/activate", "user.activate", constants.AuditTargetUser},
+	{http.MethodPost, "/api/v1/admin/users/:id/unlock", "user.unlock", constants.AuditTargetUser},
+	{http.MethodDelete, "/api/v1/admin/users/:id/sessions", "user.sessions.revoke", constants.AuditTargetUser},
+
+	{http.MethodPost, "/api/v1/admin/security/ip-rules", "ip_rule.create", constants.AuditTargetIPRule},
+	{http.MethodDelete, "/api/v1/admin/security/ip-rules/:id", "ip_rule.delete", constants.AuditTargetIPRule},
+	{http.MethodPut, "/api/v1/admin/security/mfa-policy", "mfa_policy.update", constants.AuditTargetSetting},
+	{http.MethodPut, "/api/v1/admin/settings/slug-strategy", "slug_strategy.update", constants.AuditTargetSetting},
+}
+
+// AuditMiddleware 管理操作审计中间件
+// 在context中放入审计记录器供业务逻辑补充字段变更，请求结束后按响应状态码记录操作结果，被拒绝的请求同样记录
+// 无法识别操作者的请求（如使用无效令牌重置密码）不记录；写入失败只记录日志，不影响请求
+type AuditMiddleware struct {
+	routes  []routeAudit
+	logs    *dao.AuditLogDAO
+	enabled bool
+}
+
+// NewAuditMiddleware 创建审计中间件
+func NewAuditMiddleware(logs *dao.AuditLogDAO, enabled bool) *AuditMiddleware {
+	return &AuditMiddleware{
+		routes:  routeAudits,
+		logs:    logs,
+		enabled: enabled,
+	}
+}
+
+func (m *AuditMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !m.enabled {
+			next(w, r)
+			return
+		}
+		route, targetID, ok := m.match(r.Method, r.URL.Path)
+		if !ok {
+			next(w, r)
+			return
+		}
+
+		recorder := audit.NewRecorder()
+		rw := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next(rw, r.WithContext(audit.WithRecorder(r.Context(), recorder)))
+
+		m.record(r, route, targetID, recorder, rw.status)
+	}
+}
+
+// record 写入审计日志
+func (m *AuditMiddleware) record(r *http.Request, route routeAudit, targetID string, recorder *audit.Recorder, status int) {
+	ctx := context.WithoutCancel(r.Context())
+
+	// 1. 确定操作者：已认证请求取认证主体，公开接口取业务逻辑确认的用户
+	log := &model.AuditLog{}
+	if actor := recorder.Actor(); actor != nil {
+		log.ActorName, log.ActorRole = actor.Username, actor.Role
+		log.ActorID, _ = primitive.ObjectIDFromHex(actor.UserID)
+	} else if principal, err := auth.FromContext(ctx); err == nil {
+		log.ActorName, log.ActorRole = principal.Username, principal.Role
+		log.ActorID, _ = primitive.ObjectIDFromHex(principal.UserID)
+		log.AccessTokenID = principal.AccessTokenID
+	}
+	if log.ActorID.IsZero() {
+		return
+	}
+
+	// 2. 确定操作对象：业务逻辑设置的ID优先，其次为路径中的ID，操作自己账号时为操作者自己
+	if id := recorder.TargetID(); id != "" {
+		targetID = id
+	}
+	if targetID == "" && route.TargetType == constants.AuditTargetUser {
+		targetID = log.ActorID.Hex()
+	}
+
+	client := utils.ClientInfoFromContext(ctx)
+	log.Action = route.Action
+	log.TargetType = route.TargetType
+	log.TargetID = targetID
+	log.Changes = recorder.Changes()
+	log.Method = r.Method
+	log.Path = r.URL.Path
+	log.StatusCode = status
+	log.IPAddress = client.IP
+	log.UserAgent = client.UserAgent
+
+	if err := m.logs.Create(ctx, log); err != nil {
+		logx.WithContext(ctx).Errorf("写入审计日志失败: action=%s, actor=%s, error=%v", log.Action, log.ActorID.Hex(), err)
+	}
+}
+
+// match 查找请求对应的审计操作，返回路径中 :id 段的值
+func (m *AuditMiddleware) match(method, path string) (routeAudit, string, bool) {
+	segments := splitPath(path)
+	for _, route := range m.routes {
+		pattern := splitPath(route.Path)
+		if route.Method != method || !matchSegments(pattern, segments) {
+			continue
+		}
+		for i, p := range pattern {
+			if strings.HasPrefix(p, ":") {
+				return route, segments[i], true
+			}
+		}
+		return route, "", true
+	}
+	return routeAudit{}, "", false
+}
+
+// statusResponseWriter 记录响应状态码
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusResponseWriter) Write(data []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(data)
+}
+
+// Unwrap 返回原始ResponseWriter，供 http.ResponseController 使用
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
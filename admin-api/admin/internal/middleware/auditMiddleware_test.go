@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/audit"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+func TestAuditMiddleware_Handle(t *testing.T) {
+	mockey.PatchConvey("AuditMiddleware Handle Tests", t, func() {
+		var logs []*model.AuditLog
+		mockey.Mock((*dao.AuditLogDAO).Create).To(func(_ *dao.AuditLogDAO, _ context.Context, log *model.AuditLog) error {
+			logs = append(logs, log)
+			return nil
+		}).Build()
+
+		const actorID = "64b7f0c2a1b2c3d4e5f60718"
+		const targetID = "64b7f0c2a1b2c3d4e5f60719"
+		principal := &auth.Principal{UserID: actorID, Username: "admin", Role: constants.UserRoleAdmin}
+
+		serve := func(m *AuditMiddleware, method, path string, principal *auth.Principal, next http.HandlerFunc) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, path, nil)
+			ctx := utils.WithClientInfo(req.Context(), utils.ClientInfo{IP: "203.0.113.7", UserAgent: "test-agent"})
+			if principal != nil {
+				ctx = auth.WithPrincipal(ctx, principal)
+			}
+			rec := httptest.NewRecorder()
+			m.Handle(next)(rec, req.WithContext(ctx))
+			return rec
+		}
+		m := NewAuditMiddleware(&dao.AuditLogDAO{}, true)
+
+		Convey("Should record the action, target and field changes", func() {
+			rec := serve(m, http.MethodPost, "/api/v1/admin/users/"+targetID+"/suspend", principal, func(w http.ResponseWriter, r *http.Request) {
+				audit.RecordChange(r.Context(), "status", constants.UserStatusActive, constants.UserStatusSuspended)
+				w.WriteHeader(http.StatusOK)
+			})
+			So(rec.Code, ShouldEqual, http.StatusOK)
+
+			So(logs, ShouldHaveLength, 1)
+			log := logs[0]
+			So(log.ActorID.Hex(), ShouldEqual, actorID)
+			So(log.ActorName, ShouldEqual, "admin")
+			So(log.ActorRole, ShouldEqual, constants.UserRoleAdmin)
+			So(log.Action, ShouldEqual, "user.suspend")
+			So(log.TargetType, ShouldEqual, constants.AuditTargetUser)
+			So(log.TargetID, ShouldEqual, targetID)
+			So(log.Changes, ShouldResemble, []model.AuditChange{
+				{Field: "status", Before: constants.UserStatusActive, After: constants.UserStatusSuspended},
+			})
+			So(log.StatusCode, ShouldEqual, http.StatusOK)
+			So(log.IPAddress, ShouldEqual, "203.0.113.7")
+			So(log.UserAgent, ShouldEqual, "test-agent")
+		})
+
+		Convey("Rejected requests should be recorded with their status", func() {
+			serve(m, http.MethodDelete, "/api/v1/admin/posts/"+targetID, principal, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			})
+			So(logs, ShouldHaveLength, 1)
+			So(logs[0].Action, ShouldEqual, "post.delete")
+			So(logs[0].StatusCode, ShouldEqual, http.StatusForbidden)
+			So(logs[0].IsSuccess(), ShouldBeFalse)
+		})
+
+		Convey("Targets should come from logic or default to the actor", func() {
+			serve(m, http.MethodPost, "/api/v1/admin/posts", principal, func(w http.ResponseWriter, r *http.Request) {
+				audit.SetTargetID(r.Context(), targetID)
+			})
+			serve(m, http.MethodPut, "/api/v1/admin/auth/profile", principal, func(w http.ResponseWriter, r *http.Request) {})
+			So(logs, ShouldHaveLength, 2)
+			So(logs[0].TargetID, ShouldEqual, targetID)
+			So(logs[1].Action, ShouldEqual, "profile.update")
+			So(logs[1].TargetID, ShouldEqual, actorID)
+		})
+
+		Convey("Public routes should be recorded only once logic identifies the actor", func() {
+			serve(m, http.MethodPost, "/api/v1/admin/auth/password/reset", nil, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+			})
+			So(logs, ShouldBeEmpty)
+
+			serve(m, http.MethodPost, "/api/v1/admin/auth/password/reset", nil, func(w http.ResponseWriter, r *http.Request) {
+				audit.SetActor(r.Context(), audit.Actor{UserID: actorID, Username: "admin", Role: constants.UserRoleAdmin})
+			})
+			So(logs, ShouldHaveLength, 1)
+			So(logs[0].Action, ShouldEqual, "auth.password.reset")
+			So(logs[0].TargetID, ShouldEqual, actorID)
+		})
+
+		Convey("Reads, unlisted routes and disabled auditing should not be recorded", func() {
+			noop := func(w http.ResponseWriter, r *http.Request) {}
+			serve(m, http.MethodGet, "/api/v1/admin/users", principal, noop)
+			serve(m, http.MethodPost, "/api/v1/admin/auth/login", nil, noop)
+			serve(NewAuditMiddleware(&dao.AuditLogDAO{}, false), http.MethodDelete, "/api/v1/admin/posts/"+targetID, principal, noop)
+			So(logs, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestRouteAudits(t *testing.T) {
+	Convey("Every authenticated write route should be audited", t, func() {
+		m := NewAuditMiddleware(nil, true)
+		for _, route := range routePermissions {
+			if route.Method == http.MethodGet {
+				continue
+			}
+			_, _, ok := m.match(route.Method, route.Path)
+			So(ok, ShouldBeTrue)
+		}
+	})
+}
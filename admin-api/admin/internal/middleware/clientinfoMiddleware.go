@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/heimdall-api/common/utils"
+)
+
+// ClientInfoMiddleware 客户端信息中间件
+// 解析客户端真实IP和User-Agent并写入请求context，供登录日志、限流等逻辑使用
+type ClientInfoMiddleware struct {
+	trustedProxies []*net.IPNet
+}
+
+// NewClientInfoMiddleware 创建客户端信息中间件
+func NewClientInfoMiddleware(trustedProxies []*net.IPNet) *ClientInfoMiddleware {
+	return &ClientInfoMiddleware{
+		trustedProxies: trustedProxies,
+	}
+}
+
+func (m *ClientInfoMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := utils.WithClientInfo(r.Context(), utils.ClientInfo{
+			IP:        utils.GetClientIP(r, m.trustedProxies),
+			UserAgent: r.UserAgent(),
+		})
+		next(w, r.WithContext(ctx))
+	}
+}
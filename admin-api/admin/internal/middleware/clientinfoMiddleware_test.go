@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/utils"
+)
+
+func TestClientInfoMiddleware_Handle(t *testing.T) {
+	Convey("ClientInfoMiddleware Handle Tests", t, func() {
+		trusted, err := utils.ParseTrustedProxies([]string{"10.0.0.0/8"})
+		So(err, ShouldBeNil)
+
+		var got utils.ClientInfo
+		handler := NewClientInfoMiddleware(trusted).Handle(func(w http.ResponseWriter, r *http.Request) {
+			got = utils.ClientInfoFromContext(r.Context())
+		})
+
+		Convey("Should store forwarded IP and User-Agent from trusted proxy", func() {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/auth/login", nil)
+			req.RemoteAddr = "10.0.0.2:5000"
+			req.Header.Set("X-Forwarded-For", "198.51.100.7")
+			req.Header.Set("User-Agent", "Mozilla/5.0")
+			handler(httptest.NewRecorder(), req)
+
+			So(got.IP, ShouldEqual, "198.51.100.7")
+			So(got.UserAgent, ShouldEqual, "Mozilla/5.0")
+		})
+
+		Convey("Should ignore forwarded headers from untrusted peers", func() {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/auth/login", nil)
+			req.RemoteAddr = "203.0.113.9:5000"
+			req.Header.Set("X-Forwarded-For", "198.51.100.7")
+			handler(httptest.NewRecorder(), req)
+
+			So(got.IP, ShouldEqual, "203.0.113.9")
+			So(got.UserAgent, ShouldEqual, utils.UnknownClientValue)
+		})
+	})
+}
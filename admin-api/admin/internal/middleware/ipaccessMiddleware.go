@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/utils"
+)
+
+// IPAccessMiddleware IP访问控制中间件
+// 依赖 ClientInfoMiddleware 解析出的客户端IP，拒绝被封禁或不在白名单中的IP
+// 规则查询失败时放行并记录日志，避免数据库或Redis故障导致管理后台整体不可用；
+// 开启白名单模式时改为拒绝访问，白名单不能因为故障失效
+type IPAccessMiddleware struct {
+	access *auth.IPAccessControl
+}
+
+// NewIPAccessMiddleware 创建IP访问控制中间件
+func NewIPAccessMiddleware(access *auth.IPAccessControl) *IPAccessMiddleware {
+	return &IPAccessMiddleware{
+		access: access,
+	}
+}
+
+func (m *IPAccessMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ip := utils.ClientInfoFromContext(r.Context()).IP
+		err := m.access.Check(r.Context(), ip)
+		switch {
+		case errors.Is(err, auth.ErrIPBlocked):
+			utils.Error(w, constants.GetHTTPStatusCode(constants.ErrIPBlocked),
+				constants.ErrIPBlocked, "当前IP已被禁止访问", nil)
+			return
+		case errors.Is(err, auth.ErrIPNotAllowed):
+			utils.Error(w, constants.GetHTTPStatusCode(constants.ErrIPBlocked),
+				constants.ErrIPBlocked, "当前IP不在访问白名单中", nil)
+			return
+		case err != nil:
+			logx.WithContext(r.Context()).Errorf("IP访问控制检查失败: ip=%s, error=%v", ip, err)
+			if m.access.AllowlistMode() {
+				utils.Error(w, http.StatusServiceUnavailable,
+					constants.ErrInternalServer, "暂时无法校验访问白名单，请稍后重试", nil)
+				return
+			}
+		}
+
+		next(w, r)
+	}
+}
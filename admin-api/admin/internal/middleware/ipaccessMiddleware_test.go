@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bytedance/mockey"
+	"github.com/go-redis/redis/v8"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+func TestIPAccessMiddleware_Handle(t *testing.T) {
+	mockey.PatchConvey("IPAccessMiddleware Handle Tests", t, func() {
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+
+		mockey.Mock((*dao.IPRuleDAO).List).Return([]*model.IPRule{
+			{Type: constants.IPRuleTypeBlock, CIDR: "203.0.113.0/24"},
+		}, nil).Build()
+		access, err := auth.NewIPAccessControl(&dao.IPRuleDAO{}, rdb, false)
+		So(err, ShouldBeNil)
+
+		called := false
+		handler := NewIPAccessMiddleware(access).Handle(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		})
+		serve := func(ip string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/auth/login", nil)
+			req = req.WithContext(utils.WithClientInfo(req.Context(), utils.ClientInfo{IP: ip}))
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+			return rec
+		}
+
+		Convey("Should reject IPs in a blocked range", func() {
+			rec := serve("203.0.113.9")
+			So(called, ShouldBeFalse)
+			So(rec.Code, ShouldEqual, http.StatusForbidden)
+			So(rec.Body.String(), ShouldContainSubstring, constants.ErrIPBlocked)
+		})
+
+		Convey("Should reject temporarily blocked IPs", func() {
+			_, err := access.BlockTemporarily(context.Background(), "198.51.100.7", "test", constants.IPRuleCreatorSystem, "", time.Minute)
+			So(err, ShouldBeNil)
+
+			rec := serve("198.51.100.7")
+			So(called, ShouldBeFalse)
+			So(rec.Code, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Should let other IPs through", func() {
+			rec := serve("198.51.100.8")
+			So(called, ShouldBeTrue)
+			So(rec.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Should fail open when Redis is unavailable", func() {
+			mr.Close()
+			serve("198.51.100.8")
+			So(called, ShouldBeTrue)
+		})
+
+		Convey("Should fail closed in allowlist mode when Redis is unavailable", func() {
+			strict, err := auth.NewIPAccessControl(&dao.IPRuleDAO{}, rdb, true)
+			So(err, ShouldBeNil)
+			handler = NewIPAccessMiddleware(strict).Handle(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+
+			mr.Close()
+			rec := serve("198.51.100.8")
+			So(called, ShouldBeFalse)
+			So(rec.Code, ShouldEqual, http.StatusServiceUnavailable)
+		})
+	})
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/utils"
+)
+
+// routePermission 路由与权限动作的对应关系
+type routePermission struct {
+	Method     string
+	Path       string // 路由模式，:id 形式的段匹配任意值
+	Permission string
+}
+
+// routePermissions 需要认证的路由权限表，新增路由时必须在此登记，未登记的路由一律拒绝
+var routePermissions = []routePermission{
+	{http.MethodPost, "/api/v1/admin/auth/logout", constants.PermissionAuthSelf},
+	{http.MethodGet, "/api/v1/admin/auth/mfa", constants.PermissionAuthSelf},
+	{http.MethodPost, "/api/v1/admin/auth/mfa/recovery-codes", constants.PermissionAuthSelf},
+	{http.MethodPost, "/api/v1/admin/auth/mfa/totp/confirm", constants.PermissionAuthSelf},
+	{http.MethodPost, "/api/v1/admin/auth/mfa/totp/disable", constants.PermissionAuthSelf},
+	{http.MethodPost, "/api/v1/admin/auth/mfa/totp/setup", constants.PermissionAuthSelf},
+	{http.MethodPost, "/api/v1/admin/auth/password", constants.PermissionAuthSelf},
+	{http.MethodGet, "/api/v1/admin/auth/profile", constants.PermissionAuthSelf},
+	{http.MethodPut, "/api/v1/admin/auth/profile", constants.PermissionAuthSelf},
+	{http.MethodGet, "/api/v1/admin/auth/sessions", constants.PermissionAuthSelf},
+	{http.MethodDelete, "/api/v1/admin/auth/sessions/:id", constants.PermissionAuthSelf},
+	{http.MethodPost, "/api/v1/admin/auth/sessions/revoke-others", constants.PermissionAuthSelf},
+	{http.MethodGet, "/api/v1/admin/auth/tokens", constants.PermissionAuthSelf},
+	{http.MethodPost, "/api/v1/admin/auth/tokens", constants.PermissionAuthSelf},
+	{http.MethodDelete, "/api/v1/admin/auth/tokens/:id", constants.PermissionAuthSelf},
+	{http.MethodGet, "/api/v1/admin/auth/webauthn/credentials", constants.PermissionAuthSelf},
+	{http.MethodDelete, "/api/v1/admin/auth/webauthn/credentials/:id", constants.PermissionAuthSelf},
+	{http.MethodPost, "/api/v1/admin/auth/webauthn/register", constants.PermissionAuthSelf},
+	{http.MethodPost, "/api/v1/admin/auth/webauthn/register/options", constants.PermissionAuthSelf},
+
+	{http.MethodGet, "/api/v1/admin/invitations", constants.PermissionUserInvite},
+	{http.MethodPost, "/api/v1/admin/invitations", constants.PermissionUserInvite},
+	{http.MethodDelete, "/api/v1/admin/invitations/:id", constants.PermissionUserInvite},
+	{http.MethodPost, "/api/v1/admin/invitations/:id/resend", constants.PermissionUserInvite},
+
+	{http.MethodGet, "/api/v1/admin/posts", constants.PermissionPostList},
+	{http.MethodPost, "/api/v1/admin/posts", constants.PermissionPostCreate},
+	{http.MethodGet, "/api/v1/admin/posts/:id", constants.PermissionPostRead},
+	{http.MethodPut, "/api/v1/admin/posts/:id", constants.PermissionPostUpdate},
+	{http.MethodDelete, "/api/v1/admin/posts/:id", constants.PermissionPostDelete},
+	{http.MethodPost, "/api/v1/admin/posts/:id/publish", constants.PermissionPostPublish},
+	{http.MethodPost, "/api/v1/admin/posts/:id/unpublish", constants.PermissionPostUnpublish},
+
+	{http.MethodGet, "/api/v1/admin/pages", constants.PermissionPageList},
+	{http.MethodPost, "/api/v1/admin/pages", constants.PermissionPageCreate},
+	{http.MethodGet, "/api/v1/admin/pages/:id", constants.PermissionPageRead},
+	{http.MethodPut, "/api/v1/admin/pages/:id", constants.PermissionPageUpdate},
+	{http.MethodDelete, "/api/v1/admin/pages/:id", constants.PermissionPageDelete},
+	{http.MethodPost, "/api/v1/admin/pages/:id/publish", constants.PermissionPagePublish},
+	{http.MethodPost, "/api/v1/admin/pages/:id/unpublish", constants.PermissionPageUnpublish},
+
+	{http.MethodGet, "/api/v1/admin/users", constants.PermissionUserList},
+	{http.MethodPost, "/api/v1/admin/users", constants.PermissionUserCreate},
+	{http.MethodGet, "/api/v1/admin/users/:id", constants.PermissionUserRead},
+	{http.MethodPut, "/api/v1/admin/users/:id", constants.PermissionUserUpdate},
+	{http.MethodDelete, "/api/v1/admin/users/:id", constants.PermissionUserDelete},
+	{http.MethodPatch, "/api/v1/admin/users/:id/role", constants.PermissionUserRoleChange},
+	{http.MethodPost, "/api/v1/admin/users/:id/suspend", constants.PermissionUserStatusChange},
+	{http.MethodPost, "/api/v1/admin/users/:id/activate", constants.PermissionUserStatusChange},
+	{http.MethodPost, "/api/v1/admin/users/:id/unlock", constants.PermissionUserStatusChange},
+	{http.MethodDelete, "/api/v1/admin/users/:id/sessions", constants.PermissionUserSessionRevoke},
+
+	{http.MethodGet, "/api/v1/admin/security/alerts", constants.PermissionSecurityAlertList},
+	{http.MethodGet, "/api/v1/admin/security/audit-logs", constants.PermissionAuditLogList},
+	{http.MethodGet, "/api/v1/admin/security/ip-rules", constants.PermissionIPRuleList},
+	{http.MethodPost, "/api/v1/admin/security/ip-rules", constants.PermissionIPRuleManage},
+	{http.MethodDelete, "/api/v1/admin/security/ip-rules/:id", constants.PermissionIPRuleManage},
+	{http.MethodGet, "/api/v1/admin/security/login-logs", constants.PermissionLoginLogList},
+	{http.MethodGet, "/api/v1/admin/security/login-stats", constants.PermissionLoginLogList},
+	{http.MethodGet, "/api/v1/admin/security/mfa-policy", constants.PermissionMFAPolicyManage},
+	{http.MethodPut, "/api/v1/admin/security/mfa-policy", constants.PermissionMFAPolicyManage},
+
+	{http.MethodGet, "/api/v1/admin/settings/slug-strategy", constants.PermissionSettingManage},
+	{http.MethodPut, "/api/v1/admin/settings/slug-strategy", constants.PermissionSettingManage},
+}
+
+// PermissionMiddleware 基于角色的访问控制中间件
+// 根据路由权限表校验当前角色能否执行该动作，资源归属由业务逻辑按同一张权限表校验
+type PermissionMiddleware struct {
+	routes []routePermission
+}
+
+// NewPermissionMiddleware 创建权限中间件
+func NewPermissionMiddleware() *PermissionMiddleware {
+	return &PermissionMiddleware{
+		routes: routePermissions,
+	}
+}
+
+func (m *PermissionMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		permission, ok := m.match(r.Method, r.URL.Path)
+		if !ok {
+			logx.WithContext(r.Context()).Errorf("路由未配置权限: %s %s", r.Method, r.URL.Path)
+			utils.Error(w, constants.GetHTTPStatusCode(constants.ErrForbidden),
+				constants.ErrForbidden, "权限不足", nil)
+			return
+		}
+
+		principal, err := auth.FromContext(r.Context())
+		if err != nil || !principal.HasPermission(permission) {
+			utils.Error(w, constants.GetHTTPStatusCode(constants.ErrForbidden),
+				constants.ErrForbidden, "权限不足", nil)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// match 查找请求对应的权限动作
+func (m *PermissionMiddleware) match(method, path string) (string, bool) {
+	segments := splitPath(path)
+	for _, route := range m.routes {
+		if route.Method == method && matchSegments(splitPath(route.Path), segments) {
+			return route.Permission, true
+		}
+	}
+	return "", false
+}
+
+// splitPath 按 / 拆分路径
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// matchSegments 逐段匹配路由模式
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i, p := range pattern {
+		if strings.HasPrefix(p, ":") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if p != segments[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+)
+
+func TestPermissionMiddleware_Handle(t *testing.T) {
+	Convey("PermissionMiddleware Handle Tests", t, func() {
+		m := NewPermissionMiddleware()
+		serve := func(method, path, role string) (*httptest.ResponseRecorder, bool) {
+			called := false
+			handler := m.Handle(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(method, path, nil)
+			if role != "" {
+				principal := &auth.Principal{UserID: "64b7f0c2a1b2c3d4e5f60718", Role: role}
+				req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+			return rec, called
+		}
+
+		Convey("Admin routes should be limited to owner and admin", func() {
+			for _, path := range []string{"/api/v1/admin/users", "/api/v1/admin/security/login-logs"} {
+				_, called := serve(http.MethodGet, path, constants.UserRoleAdmin)
+				So(called, ShouldBeTrue)
+
+				rec, called := serve(http.MethodGet, path, constants.UserRoleEditor)
+				So(called, ShouldBeFalse)
+				So(rec.Code, ShouldEqual, http.StatusForbidden)
+			}
+		})
+
+		Convey("Content routes should allow every role and leave ownership to logic", func() {
+			for _, role := range constants.GetAllUserRoles() {
+				_, called := serve(http.MethodDelete, "/api/v1/admin/posts/64b7f0c2a1b2c3d4e5f60718", role)
+				So(called, ShouldBeTrue)
+
+				_, called = serve(http.MethodPost, "/api/v1/admin/pages/64b7f0c2a1b2c3d4e5f60718/publish", role)
+				So(called, ShouldBeTrue)
+			}
+		})
+
+		Convey("Users should be able to read their own detail", func() {
+			_, called := serve(http.MethodGet, "/api/v1/admin/users/64b7f0c2a1b2c3d4e5f60718", constants.UserRoleAuthor)
+			So(called, ShouldBeTrue)
+		})
+
+		Convey("Missing principal or unknown role should be rejected", func() {
+			rec, called := serve(http.MethodGet, "/api/v1/admin/posts", "")
+			So(called, ShouldBeFalse)
+			So(rec.Code, ShouldEqual, http.StatusForbidden)
+
+			_, called = serve(http.MethodGet, "/api/v1/admin/posts", "guest")
+			So(called, ShouldBeFalse)
+		})
+
+		Convey("Unregistered routes should be rejected", func() {
+			rec, called := serve(http.MethodPatch, "/api/v1/admin/posts/64b7f0c2a1b2c3d4e5f60718", constants.UserRoleOwner)
+			So(called, ShouldBeFalse)
+			So(rec.Code, ShouldEqual, http.StatusForbidden)
+
+			_, called = serve(http.MethodGet, "/api/v1/admin/posts/", constants.UserRoleOwner)
+			So(called, ShouldBeTrue) // 末尾斜杠等同于列表路由
+
+			_, called = serve(http.MethodGet, "/api/v1/admin/posts/a/b", constants.UserRoleOwner)
+			So(called, ShouldBeFalse)
+		})
+	})
+}
+
+func TestHasPermissionOn(t *testing.T) {
+	Convey("Permission table ownership rules", t, func() {
+		So(constants.HasPermissionOn(constants.UserRoleOwner, constants.PermissionPostDelete, "u1", "u2"), ShouldBeTrue)
+		So(constants.HasPermissionOn(constants.UserRoleEditor, constants.PermissionPageUpdate, "u1", "u2"), ShouldBeTrue)
+		So(constants.HasPermissionOn(constants.UserRoleAuthor, constants.PermissionPostDelete, "u1", "u1"), ShouldBeTrue)
+		So(constants.HasPermissionOn(constants.UserRoleAuthor, constants.PermissionPostDelete, "u1", "u2"), ShouldBeFalse)
+		So(constants.HasPermissionOn(constants.UserRoleAuthor, constants.PermissionPostDelete, "", ""), ShouldBeFalse)
+		So(constants.HasPermissionOn(constants.UserRoleEditor, constants.PermissionUserRead, "u1", "u2"), ShouldBeFalse)
+		So(constants.HasPermissionOn(constants.UserRoleAdmin, "unknown:action", "u1", "u1"), ShouldBeFalse)
+	})
+}
@@ -0,0 +1,240 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+// errUnauthenticated 缺少令牌或令牌无效
+var errUnauthenticated = errors.New("unauthenticated")
+
+// errTokenExpired 访问令牌已过期，客户端可使用刷新令牌续期
+var errTokenExpired = errors.New("token expired")
+
+// errTokenRevoked 令牌已失效
+var errTokenRevoked = errors.New("token revoked")
+
+// errMFASetupRequired 当前角色要求启用两步验证但用户尚未启用
+var errMFASetupRequired = errors.New("mfa setup required")
+
+// mfaSetupAllowedPrefix 未启用两步验证时仍可访问的路径前缀（个人资料、两步验证设置、会话管理等）
+const mfaSetupAllowedPrefix = "/api/v1/admin/auth/"
+
+// touchSessionScript 会话仍存在时更新最后活跃时间，避免为已吊销的会话重新创建记录
+var touchSessionScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return redis.call("HSET", KEYS[1], "lastSeenAt", ARGV[1])
+end
+return 0
+`)
+
+// TokenBlacklistMiddleware 认证中间件
+// 同时接受JWT访问令牌和个人访问令牌（hpat_前缀），因此不使用go-zero内置的JWT校验
+// JWT校验签名后再检查黑名单和失效水位线，使登出、账户停用、修改密码等操作立即生效
+// 个人访问令牌按哈希查库，吊销和过期立即生效，权限受令牌作用域限制
+// 校验通过后将认证主体写入context，后续中间件和业务逻辑统一通过 auth.FromContext 获取当前用户
+// 角色被要求启用两步验证而用户尚未启用时，只放行 /auth/ 下的接口，以便用户完成绑定
+type TokenBlacklistMiddleware struct {
+	jwtManager     *utils.JWTManager
+	redis          *redis.Client
+	userDAO        *dao.UserDAO
+	accessTokenDAO *dao.AccessTokenDAO
+	mfaPolicy      *auth.MFAPolicy
+}
+
+// NewTokenBlacklistMiddleware 创建认证中间件
+func NewTokenBlacklistMiddleware(accessSecret string, rdb *redis.Client, userDAO *dao.UserDAO, accessTokenDAO *dao.AccessTokenDAO, mfaPolicy *auth.MFAPolicy) *TokenBlacklistMiddleware {
+	return &TokenBlacklistMiddleware{
+		jwtManager:     utils.NewJWTManager(accessSecret, "heimdall-admin"),
+		redis:          rdb,
+		userDAO:        userDAO,
+		accessTokenDAO: accessTokenDAO,
+		mfaPolicy:      mfaPolicy,
+	}
+}
+
+func (m *TokenBlacklistMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, err := m.check(r)
+		switch {
+		case errors.Is(err, errUnauthenticated):
+			utils.Error(w, constants.GetHTTPStatusCode(constants.ErrUnauthorized),
+				constants.ErrUnauthorized, "未登录或令牌无效", nil)
+			return
+		case errors.Is(err, errTokenExpired):
+			utils.Error(w, constants.GetHTTPStatusCode(constants.ErrTokenExpired),
+				constants.ErrTokenExpired, "令牌已过期", nil)
+			return
+		case errors.Is(err, errTokenRevoked):
+			utils.Error(w, constants.GetHTTPStatusCode(constants.ErrTokenBlacklisted),
+				constants.ErrTokenBlacklisted, "令牌已失效，请重新登录", nil)
+			return
+		case errors.Is(err, errMFASetupRequired):
+			utils.Error(w, constants.GetHTTPStatusCode(constants.ErrMFASetupRequired),
+				constants.ErrMFASetupRequired, "当前角色要求启用两步验证，请先完成设置", nil)
+			return
+		case err != nil:
+			logx.WithContext(r.Context()).Errorf("令牌黑名单校验失败: %v", err)
+			utils.Error(w, constants.GetHTTPStatusCode(constants.ErrInternalServer),
+				constants.ErrInternalServer, "系统错误，请稍后重试", nil)
+			return
+		}
+
+		if principal.IsAccessToken() {
+			m.touchAccessToken(r.Context(), principal)
+		} else {
+			m.touchSession(r.Context(), principal)
+		}
+		next(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	}
+}
+
+// check 按令牌类型校验，通过后返回认证主体
+func (m *TokenBlacklistMiddleware) check(r *http.Request) (*auth.Principal, error) {
+	token, err := utils.ParseAuthHeader(r.Header.Get("Authorization"))
+	if err != nil {
+		return nil, errUnauthenticated
+	}
+
+	if utils.IsAccessToken(token) {
+		return m.checkAccessToken(r, token)
+	}
+	return m.checkJWT(r, token)
+}
+
+// checkJWT 校验JWT签名后依次检查令牌黑名单、用户令牌失效水位线、用户当前状态和两步验证策略
+func (m *TokenBlacklistMiddleware) checkJWT(r *http.Request, token string) (*auth.Principal, error) {
+	ctx := r.Context()
+
+	claims, err := m.jwtManager.ValidateToken(token)
+	if errors.Is(err, utils.ErrTokenExpired) {
+		return nil, errTokenExpired
+	}
+	if err != nil || claims.TokenID == "" || claims.UserID == "" {
+		return nil, errUnauthenticated
+	}
+
+	// 刷新令牌只能用于 /auth/refresh，不能作为访问令牌使用
+	if claims.FamilyID != "" {
+		return nil, errTokenRevoked
+	}
+
+	// 1. 令牌黑名单
+	blacklisted, err := m.redis.Exists(ctx, utils.GenerateBlacklistKey(claims.TokenID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("查询令牌黑名单失败: %w", err)
+	}
+	if blacklisted > 0 {
+		return nil, errTokenRevoked
+	}
+
+	// 2. 用户令牌失效水位线
+	revokedBefore, err := auth.TokensRevokedBefore(ctx, m.redis, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if auth.IsTokenRevoked(claims, revokedBefore) {
+		return nil, errTokenRevoked
+	}
+
+	// 3. 用户状态
+	user, err := m.userDAO.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户信息失败: %w", err)
+	}
+	if user == nil || !user.CanLogin() {
+		return nil, errTokenRevoked
+	}
+
+	// 4. 两步验证策略
+	if err := m.checkMFA(ctx, r.URL.Path, user); err != nil {
+		return nil, err
+	}
+
+	return auth.NewPrincipal(claims), nil
+}
+
+// checkAccessToken 校验个人访问令牌是否存在、未吊销、未过期，并检查用户当前状态和两步验证策略
+// 个人访问令牌独立于登录会话，不受用户令牌失效水位线影响，需由用户显式吊销
+func (m *TokenBlacklistMiddleware) checkAccessToken(r *http.Request, token string) (*auth.Principal, error) {
+	ctx := r.Context()
+
+	stored, err := m.accessTokenDAO.GetByHash(ctx, utils.HashAccessToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("查询个人访问令牌失败: %w", err)
+	}
+	if stored == nil {
+		return nil, errUnauthenticated
+	}
+	if !stored.IsUsable() {
+		return nil, errTokenRevoked
+	}
+
+	user, err := m.userDAO.GetByID(ctx, stored.UserID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("获取用户信息失败: %w", err)
+	}
+	if user == nil || !user.CanLogin() {
+		return nil, errTokenRevoked
+	}
+
+	if err := m.checkMFA(ctx, r.URL.Path, user); err != nil {
+		return nil, err
+	}
+
+	return auth.NewAccessTokenPrincipal(user, stored), nil
+}
+
+// checkMFA 角色要求两步验证而用户未启用时，拒绝访问 /auth/ 以外的接口
+func (m *TokenBlacklistMiddleware) checkMFA(ctx context.Context, path string, user *model.User) error {
+	if m.mfaPolicy == nil || user.TwoFactorEnabled || strings.HasPrefix(path, mfaSetupAllowedPrefix) {
+		return nil
+	}
+
+	required, err := m.mfaPolicy.Requires(ctx, user.Role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return errMFASetupRequired
+	}
+	return nil
+}
+
+// touchSession 更新会话最后活跃时间，失败不影响请求
+func (m *TokenBlacklistMiddleware) touchSession(ctx context.Context, principal *auth.Principal) {
+	if principal.SessionID == "" {
+		return
+	}
+	key := fmt.Sprintf(constants.CacheKeyUserSession, principal.UserID, principal.SessionID)
+	if err := touchSessionScript.Run(ctx, m.redis, []string{key}, time.Now().UnixMilli()).Err(); err != nil {
+		logx.WithContext(ctx).Errorf("更新会话活跃时间失败: %v", err)
+	}
+}
+
+// touchAccessToken 记录个人访问令牌最后使用时间和IP，失败不影响请求
+func (m *TokenBlacklistMiddleware) touchAccessToken(ctx context.Context, principal *auth.Principal) {
+	id, err := primitive.ObjectIDFromHex(principal.AccessTokenID)
+	if err != nil {
+		return
+	}
+	ip := utils.ClientInfoFromContext(ctx).IP
+	interval := constants.AccessTokenUsageIntervalSec * time.Second
+	if err := m.accessTokenDAO.RecordUsage(ctx, id, ip, interval); err != nil {
+		logx.WithContext(ctx).Errorf("记录个人访问令牌使用信息失败: %v", err)
+	}
+}
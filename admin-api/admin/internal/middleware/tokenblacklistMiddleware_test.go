@@ -0,0 +1,336 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bytedance/mockey"
+	"github.com/go-redis/redis/v8"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+func TestTokenBlacklistMiddleware_Handle(t *testing.T) {
+	mockey.PatchConvey("TokenBlacklistMiddleware Handle Tests", t, func() {
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+
+		const secret = "test-secret"
+		testUser := &model.User{
+			ID:       primitive.NewObjectID(),
+			Username: "testuser",
+			Role:     constants.UserRoleAuthor,
+			Status:   constants.UserStatusActive,
+		}
+		mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+
+		jwtManager := utils.NewJWTManager(secret, "heimdall-admin")
+		accessToken, err := jwtManager.GenerateGoZeroCompatibleToken(testUser.ID.Hex(), testUser.Username, testUser.Role)
+		So(err, ShouldBeNil)
+		tokenID, err := jwtManager.ExtractTokenIDFromToken(accessToken)
+		So(err, ShouldBeNil)
+
+		m := NewTokenBlacklistMiddleware(secret, rdb, &dao.UserDAO{}, &dao.AccessTokenDAO{}, nil)
+		var principal *auth.Principal
+		serve := func(token string) (*httptest.ResponseRecorder, bool) {
+			called := false
+			handler := m.Handle(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				principal, _ = auth.FromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/auth/profile", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+			return rec, called
+		}
+
+		Convey("Should pass valid token through with principal", func() {
+			rec, called := serve(accessToken)
+			So(called, ShouldBeTrue)
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(principal, ShouldNotBeNil)
+			So(principal.UserID, ShouldEqual, testUser.ID.Hex())
+			So(principal.Username, ShouldEqual, testUser.Username)
+			So(principal.Role, ShouldEqual, testUser.Role)
+			So(principal.TokenID, ShouldEqual, tokenID)
+			So(principal.ExpiresAt.After(time.Now()), ShouldBeTrue)
+		})
+
+		Convey("Should reject missing or forged tokens as unauthenticated", func() {
+			forged, err := utils.NewJWTManager("other-secret", "heimdall-admin").
+				GenerateGoZeroCompatibleToken(testUser.ID.Hex(), testUser.Username, constants.UserRoleOwner)
+			So(err, ShouldBeNil)
+
+			for _, token := range []string{"", "not-a-jwt", forged} {
+				rec, called := serve(token)
+				So(called, ShouldBeFalse)
+				So(rec.Code, ShouldEqual, http.StatusUnauthorized)
+				So(rec.Body.String(), ShouldContainSubstring, constants.ErrUnauthorized)
+			}
+		})
+
+		Convey("Should reject blacklisted token", func() {
+			mr.Set(utils.GenerateBlacklistKey(tokenID), "1")
+
+			rec, called := serve(accessToken)
+			So(called, ShouldBeFalse)
+			So(rec.Code, ShouldEqual, http.StatusUnauthorized)
+			So(rec.Body.String(), ShouldContainSubstring, constants.ErrTokenBlacklisted)
+		})
+
+		Convey("Should reject token issued before the user's watermark", func() {
+			So(auth.RevokeUserTokens(context.Background(), rdb, testUser.ID.Hex()), ShouldBeNil)
+
+			rec, called := serve(accessToken)
+			So(called, ShouldBeFalse)
+			So(rec.Code, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("Should accept token issued after the watermark within the same second", func() {
+			claims, err := jwtManager.ValidateToken(accessToken)
+			So(err, ShouldBeNil)
+			watermark := claims.IssuedMs - 1
+			mr.Set(fmt.Sprintf(constants.CacheKeyTokenRevoked, testUser.ID.Hex()), strconv.FormatInt(watermark, 10))
+
+			rec, called := serve(accessToken)
+			So(called, ShouldBeTrue)
+			So(rec.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Should reject token of suspended user", func() {
+			testUser.Status = constants.UserStatusSuspended
+
+			rec, called := serve(accessToken)
+			So(called, ShouldBeFalse)
+			So(rec.Code, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("Should reject token of locked user", func() {
+			lockedUntil := time.Now().Add(time.Hour)
+			testUser.LockedUntil = &lockedUntil
+
+			rec, called := serve(accessToken)
+			So(called, ShouldBeFalse)
+			So(rec.Code, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("Should accept token once the login lockout has expired", func() {
+			lockedUntil := time.Now().Add(-time.Minute)
+			testUser.Status = constants.UserStatusLocked
+			testUser.LockedUntil = &lockedUntil
+
+			rec, called := serve(accessToken)
+			So(called, ShouldBeTrue)
+			So(rec.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Should reject refresh token used as access token", func() {
+			refreshToken, _, err := jwtManager.GenerateRefreshToken(testUser.ID.Hex(), testUser.Username, testUser.Role, "family-1")
+			So(err, ShouldBeNil)
+
+			rec, called := serve(refreshToken)
+			So(called, ShouldBeFalse)
+			So(rec.Code, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("Should return internal error when redis is unavailable", func() {
+			mr.Close()
+
+			rec, called := serve(accessToken)
+			So(called, ShouldBeFalse)
+			So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
+
+func TestTokenBlacklistMiddleware_MFAPolicy(t *testing.T) {
+	mockey.PatchConvey("TokenBlacklistMiddleware MFA Policy Tests", t, func() {
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+
+		const secret = "test-secret"
+		testUser := &model.User{
+			ID:       primitive.NewObjectID(),
+			Username: "admin",
+			Role:     constants.UserRoleAdmin,
+			Status:   constants.UserStatusActive,
+		}
+		mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+		mockey.Mock((*dao.SettingDAO).GetByKey).Return(&model.Setting{
+			Key:   constants.SettingKeyMFARequiredRoles,
+			Value: "owner,admin",
+		}, nil).Build()
+
+		jwtManager := utils.NewJWTManager(secret, "heimdall-admin")
+		accessToken, err := jwtManager.GenerateGoZeroCompatibleToken(testUser.ID.Hex(), testUser.Username, testUser.Role)
+		So(err, ShouldBeNil)
+
+		mfaPolicy, err := auth.NewMFAPolicy(&dao.SettingDAO{})
+		So(err, ShouldBeNil)
+		m := NewTokenBlacklistMiddleware(secret, rdb, &dao.UserDAO{}, &dao.AccessTokenDAO{}, mfaPolicy)
+		serve := func(path string) (*httptest.ResponseRecorder, bool) {
+			called := false
+			handler := m.Handle(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+			return rec, called
+		}
+
+		Convey("Should block other routes until MFA is enabled", func() {
+			rec, called := serve("/api/v1/admin/posts")
+			So(called, ShouldBeFalse)
+			So(rec.Code, ShouldEqual, http.StatusForbidden)
+			So(rec.Body.String(), ShouldContainSubstring, constants.ErrMFASetupRequired)
+		})
+
+		Convey("Should allow auth routes so the user can enrol", func() {
+			rec, called := serve("/api/v1/admin/auth/mfa/totp/setup")
+			So(called, ShouldBeTrue)
+			So(rec.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Should allow enrolled users", func() {
+			testUser.TwoFactorEnabled = true
+
+			_, called := serve("/api/v1/admin/posts")
+			So(called, ShouldBeTrue)
+		})
+
+		Convey("Should allow roles without the requirement", func() {
+			testUser.Role = constants.UserRoleAuthor
+
+			_, called := serve("/api/v1/admin/posts")
+			So(called, ShouldBeTrue)
+		})
+	})
+}
+
+func TestTokenBlacklistMiddleware_AccessToken(t *testing.T) {
+	mockey.PatchConvey("TokenBlacklistMiddleware Access Token Tests", t, func() {
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+
+		testUser := &model.User{
+			ID:       primitive.NewObjectID(),
+			Username: "deploy",
+			Role:     constants.UserRoleEditor,
+			Status:   constants.UserStatusActive,
+		}
+		mockey.Mock((*dao.UserDAO).GetByID).Return(testUser, nil).Build()
+
+		plaintext, prefix, err := utils.GenerateAccessToken()
+		So(err, ShouldBeNil)
+		stored := &model.AccessToken{
+			ID:        primitive.NewObjectID(),
+			UserID:    testUser.ID,
+			TokenHash: utils.HashAccessToken(plaintext),
+			Prefix:    prefix,
+			Scopes:    []string{constants.ScopeWritePosts},
+		}
+		mockey.Mock((*dao.AccessTokenDAO).GetByHash).To(func(_ *dao.AccessTokenDAO, _ context.Context, tokenHash string) (*model.AccessToken, error) {
+			if tokenHash == stored.TokenHash {
+				return stored, nil
+			}
+			return nil, nil
+		}).Build()
+		var usedIP string
+		mockey.Mock((*dao.AccessTokenDAO).RecordUsage).To(func(_ *dao.AccessTokenDAO, _ context.Context, id primitive.ObjectID, ip string, _ time.Duration) error {
+			usedIP = ip
+			return nil
+		}).Build()
+
+		m := NewTokenBlacklistMiddleware("test-secret", rdb, &dao.UserDAO{}, &dao.AccessTokenDAO{}, nil)
+		var principal *auth.Principal
+		serve := func(token string) (*httptest.ResponseRecorder, bool) {
+			called := false
+			handler := m.Handle(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				principal, _ = auth.FromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/posts", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			req = req.WithContext(utils.WithClientInfo(req.Context(), utils.ClientInfo{IP: "198.51.100.7"}))
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+			return rec, called
+		}
+
+		Convey("Should accept a valid token with its scopes", func() {
+			rec, called := serve(plaintext)
+			So(called, ShouldBeTrue)
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(principal.IsAccessToken(), ShouldBeTrue)
+			So(principal.AccessTokenID, ShouldEqual, stored.ID.Hex())
+			So(principal.Role, ShouldEqual, constants.UserRoleEditor)
+			So(principal.HasPermission(constants.PermissionPostCreate), ShouldBeTrue)
+			So(principal.HasPermission(constants.PermissionPageCreate), ShouldBeFalse)
+			So(usedIP, ShouldEqual, "198.51.100.7")
+		})
+
+		Convey("Should reject unknown tokens", func() {
+			other, _, _ := utils.GenerateAccessToken()
+			rec, called := serve(other)
+			So(called, ShouldBeFalse)
+			So(rec.Body.String(), ShouldContainSubstring, constants.ErrUnauthorized)
+		})
+
+		Convey("Should reject revoked and expired tokens", func() {
+			now := time.Now()
+			stored.RevokedAt = &now
+			rec, called := serve(plaintext)
+			So(called, ShouldBeFalse)
+			So(rec.Body.String(), ShouldContainSubstring, constants.ErrTokenBlacklisted)
+
+			stored.RevokedAt = nil
+			expired := now.Add(-time.Minute)
+			stored.ExpiresAt = &expired
+			_, called = serve(plaintext)
+			So(called, ShouldBeFalse)
+		})
+
+		Convey("Should reject tokens of suspended users", func() {
+			testUser.Status = constants.UserStatusSuspended
+
+			_, called := serve(plaintext)
+			So(called, ShouldBeFalse)
+		})
+
+		Convey("Should keep accepting tokens after the login lockout has expired", func() {
+			lockedUntil := time.Now().Add(time.Hour)
+			testUser.Status = constants.UserStatusLocked
+			testUser.LockedUntil = &lockedUntil
+			_, called := serve(plaintext)
+			So(called, ShouldBeFalse)
+
+			lockedUntil = time.Now().Add(-time.Minute)
+			_, called = serve(plaintext)
+			So(called, ShouldBeTrue)
+		})
+	})
+}
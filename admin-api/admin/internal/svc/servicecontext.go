@@ -6,11 +6,19 @@ import (
 	"time"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/zeromicro/go-zero/rest"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/heimdall-api/admin-api/admin/internal/config"
+	"github.com/heimdall-api/admin-api/admin/internal/middleware"
+	"github.com/heimdall-api/common/auth"
+	"github.com/heimdall-api/common/auth/webauthn"
+	"github.com/heimdall-api/common/client/geoip"
+	"github.com/heimdall-api/common/client/mailer"
 	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/render"
+	"github.com/heimdall-api/common/utils"
 )
 
 type ServiceContext struct {
@@ -21,6 +29,27 @@ type ServiceContext struct {
 	LoginLogDAO *dao.LoginLogDAO
 	PostDAO     *dao.PostDAO
 	PageDAO     *dao.PageDAO
+	SettingDAO  *dao.SettingDAO
+	MFAPolicy   *auth.MFAPolicy
+	GeoIP       *geoip.Resolver
+	Mailer      mailer.Mailer
+
+	WebAuthnCredentialDAO *dao.WebAuthnCredentialDAO
+	WebAuthn              *webauthn.RelyingParty
+	AccessTokenDAO        *dao.AccessTokenDAO
+	InvitationDAO         *dao.InvitationDAO
+	IPRuleDAO             *dao.IPRuleDAO
+	IPAccessControl       *auth.IPAccessControl
+	AuditLogDAO           *dao.AuditLogDAO
+	Renderer              *render.Renderer
+	MFASecretBox          *utils.SecretBox
+
+	// 中间件
+	ClientInfo     rest.Middleware
+	IPAccess       rest.Middleware
+	TokenBlacklist rest.Middleware
+	Audit          rest.Middleware
+	Permission     rest.Middleware
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
@@ -36,6 +65,81 @@ func NewServiceContext(c config.Config) *ServiceContext {
 	loginLogDAO := dao.NewLoginLogDAO(mongoDB)
 	postDAO := dao.NewPostDAO(mongoDB)
 	pageDAO := dao.NewPageDAO(mongoDB)
+	settingDAO := dao.NewSettingDAO(mongoDB)
+	webAuthnCredentialDAO := dao.NewWebAuthnCredentialDAO(mongoDB)
+	accessTokenDAO := dao.NewAccessTokenDAO(mongoDB)
+	invitationDAO := dao.NewInvitationDAO(mongoDB)
+	ipRuleDAO := dao.NewIPRuleDAO(mongoDB)
+	auditLogDAO := dao.NewAuditLogDAO(mongoDB)
+
+	// 按配置同步审计日志保留期限，失败时保留原有TTL索引继续启动
+	retentionCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := auditLogDAO.EnsureRetention(retentionCtx, time.Duration(c.Security.AuditLog.RetentionDays)*24*time.Hour); err != nil {
+		log.Printf("Failed to ensure audit log retention: %v", err)
+	}
+
+	// 初始化两步验证策略
+	mfaPolicy, err := auth.NewMFAPolicy(settingDAO)
+	if err != nil {
+		log.Fatalf("Failed to init MFA policy: %v", err)
+	}
+
+	// 初始化TOTP密钥加密
+	mfaSecretBox, err := utils.NewSecretBox(c.Security.MFASecretKey)
+	if err != nil {
+		log.Fatalf("Failed to init MFA secret encryption: %v", err)
+	}
+
+	// 初始化IP访问控制
+	ipAccessControl, err := auth.NewIPAccessControl(ipRuleDAO, redisClient, c.Security.IPAllowlistMode)
+	if err != nil {
+		log.Fatalf("Failed to init IP access control: %v", err)
+	}
+
+	// 初始化通行密钥依赖方，要求用户验证以便通行密钥登录可替代两步验证
+	webAuthnOrigins := c.Security.WebAuthn.Origins
+	if len(webAuthnOrigins) == 0 {
+		webAuthnOrigins = []string{"http://localhost:3000"}
+	}
+	relyingParty, err := webauthn.New(webauthn.Config{
+		RPID:                    c.Security.WebAuthn.RPID,
+		RPName:                  c.Security.WebAuthn.RPName,
+		Origins:                 webAuthnOrigins,
+		RequireUserVerification: true,
+	})
+	if err != nil {
+		log.Fatalf("Failed to init WebAuthn relying party: %v", err)
+	}
+
+	// 初始化GeoIP解析器
+	geoIPResolver, err := geoip.NewResolver(geoip.Config{
+		DatabasePath: c.GeoIP.DatabasePath,
+		CacheSize:    c.GeoIP.CacheSize,
+		Language:     c.GeoIP.Language,
+	})
+	if err != nil {
+		log.Fatalf("Failed to init GeoIP resolver: %v", err)
+	}
+
+	// 初始化邮件发送器，未配置SMTP时只记录日志
+	mailSender, err := mailer.NewMailer(mailer.Config{
+		Host:      c.Email.SMTP.Host,
+		Port:      c.Email.SMTP.Port,
+		Username:  c.Email.SMTP.Username,
+		Password:  c.Email.SMTP.Password,
+		FromName:  c.Email.SMTP.FromName,
+		FromEmail: c.Email.SMTP.FromEmail,
+	})
+	if err != nil {
+		log.Fatalf("Failed to init mailer: %v", err)
+	}
+
+	// 解析受信任代理列表
+	trustedProxies, err := utils.ParseTrustedProxies(c.Security.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Failed to parse trusted proxies: %v", err)
+	}
 
 	return &ServiceContext{
 		Config:      c,
@@ -45,7 +149,39 @@ func NewServiceContext(c config.Config) *ServiceContext {
 		LoginLogDAO: loginLogDAO,
 		PostDAO:     postDAO,
 		PageDAO:     pageDAO,
+		SettingDAO:  settingDAO,
+		MFAPolicy:   mfaPolicy,
+		GeoIP:       geoIPResolver,
+		Mailer:      mailSender,
+
+		WebAuthnCredentialDAO: webAuthnCredentialDAO,
+		WebAuthn:              relyingParty,
+		AccessTokenDAO:        accessTokenDAO,
+		InvitationDAO:         invitationDAO,
+		IPRuleDAO:             ipRuleDAO,
+		IPAccessControl:       ipAccessControl,
+		AuditLogDAO:           auditLogDAO,
+		Renderer:              NewRenderer(c.Security.ContentSecurity),
+		MFASecretBox:          mfaSecretBox,
+
+		ClientInfo:     middleware.NewClientInfoMiddleware(trustedProxies).Handle,
+		IPAccess:       middleware.NewIPAccessMiddleware(ipAccessControl).Handle,
+		TokenBlacklist: middleware.NewTokenBlacklistMiddleware(c.Auth.AccessSecret, redisClient, userDAO, accessTokenDAO, mfaPolicy).Handle,
+		Audit:          middleware.NewAuditMiddleware(auditLogDAO, c.Security.AuditLog.Enabled).Handle,
+		Permission:     middleware.NewPermissionMiddleware().Handle,
+	}
+}
+
+// NewRenderer 按内容安全配置创建文章和页面渲染器，关闭XSS过滤时不净化HTML
+func NewRenderer(c config.ContentSecurityConfig) *render.Renderer {
+	if !c.EnableXSSFilter {
+		return render.NewRenderer(nil)
 	}
+	return render.NewRenderer(render.NewSanitizer(render.SanitizeConfig{
+		EmbedRoles: c.EmbedRoles,
+		EmbedHosts: c.EmbedHosts,
+		URLSchemes: c.URLSchemes,
+	}))
 }
 
 // initMongoDB 初始化MongoDB连接
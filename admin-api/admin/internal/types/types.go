@@ -3,6 +3,107 @@
 
 package types
 
+type AccessTokenCreateData struct {
+	Token       string          `json:"token"` // 令牌明文，仅返回一次
+	AccessToken AccessTokenInfo `json:"accessToken"`
+}
+
+type AccessTokenCreateRequest struct {
+	Password  string   `json:"password" validate:"required"`
+	Name      string   `json:"name" validate:"required,max=50"`
+	Scopes    []string `json:"scopes" validate:"required,min=1"`
+	ExpiresIn int      `json:"expiresIn,optional" validate:"min=0,max=365"` // 有效期（天），0表示永不过期
+}
+
+type AccessTokenCreateResponse struct {
+	Code      int                   `json:"code"`
+	Message   string                `json:"message"`
+	Data      AccessTokenCreateData `json:"data"`
+	Timestamp string                `json:"timestamp"`
+}
+
+type AccessTokenInfo struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"` // 令牌开头字符，便于辨认
+	Scopes     []string `json:"scopes"`
+	Expired    bool     `json:"expired"`
+	ExpiresAt  string   `json:"expiresAt,omitempty"` // 为空表示永不过期
+	LastUsedAt string   `json:"lastUsedAt,omitempty"`
+	LastUsedIP string   `json:"lastUsedIp,omitempty"`
+	CreatedAt  string   `json:"createdAt"`
+}
+
+type AccessTokenListData struct {
+	List            []AccessTokenInfo `json:"list"`
+	AvailableScopes []string          `json:"availableScopes"` // 当前角色可用的作用域
+}
+
+type AccessTokenListResponse struct {
+	Code      int                 `json:"code"`
+	Message   string              `json:"message"`
+	Data      AccessTokenListData `json:"data"`
+	Timestamp string              `json:"timestamp"`
+}
+
+type AccessTokenRevokeRequest struct {
+	ID string `path:"id"`
+}
+
+type AccessTokenRevokeResponse struct {
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Timestamp string `json:"timestamp"`
+}
+
+type AuditChangeInfo struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type AuditLogInfo struct {
+	ID            string            `json:"id"`
+	ActorID       string            `json:"actorId"`
+	ActorName     string            `json:"actorName"`
+	ActorRole     string            `json:"actorRole"`
+	AccessTokenID string            `json:"accessTokenId,omitempty"` // 使用个人访问令牌操作时的令牌ID
+	Action        string            `json:"action"`
+	TargetType    string            `json:"targetType"`
+	TargetID      string            `json:"targetId,omitempty"`
+	Changes       []AuditChangeInfo `json:"changes"` // 变更字段的前后值，敏感字段只标记变化
+	Method        string            `json:"method"`
+	Path          string            `json:"path"`
+	StatusCode    int               `json:"statusCode"`
+	Success       bool              `json:"success"`
+	IPAddress     string            `json:"ipAddress"`
+	UserAgent     string            `json:"userAgent"`
+	CreatedAt     string            `json:"createdAt"`
+}
+
+type AuditLogsData struct {
+	List       []AuditLogInfo `json:"list"`
+	Pagination PaginationInfo `json:"pagination"`
+}
+
+type AuditLogsRequest struct {
+	Page       int    `form:"page,default=1,range=[1:]"`                                                                                      // 页码，从1开始
+	Limit      int    `form:"limit,default=20,range=[1:100]"`                                                                                 // 每页记录数，最大100
+	ActorID    string `form:"actorId,optional"`                                                                                               // 操作者用户ID过滤
+	Action     string `form:"action,optional"`                                                                                                // 操作过滤，如 post.publish；只填对象类型时匹配该对象的全部操作，如 user
+	TargetType string `form:"targetType,optional,options=user|post|page|session|access_token|webauthn_credential|invitation|ip_rule|setting"` // 操作对象类型过滤
+	TargetID   string `form:"targetId,optional"`                                                                                              // 操作对象ID过滤
+	StartTime  string `form:"startTime,optional"`                                                                                             // 开始时间（RFC3339格式）
+	EndTime    string `form:"endTime,optional"`                                                                                               // 结束时间（RFC3339格式）
+}
+
+type AuditLogsResponse struct {
+	Code      int           `json:"code"`
+	Message   string        `json:"message"`
+	Data      AuditLogsData `json:"data"`
+	Timestamp string        `json:"timestamp"`
+}
+
 type AuthorInfo struct {
 	ID           string `json:"id"`
 	Username     string `json:"username"`
@@ -17,6 +118,22 @@ type BaseResponse struct {
 	Timestamp string `json:"timestamp"`
 }
 
+type ChangePasswordData struct {
+	RevokedSessions int `json:"revokedSessions"` // 被吊销的其他会话数量
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required,min=8,max=128"`
+}
+
+type ChangePasswordResponse struct {
+	Code      int                `json:"code"`
+	Message   string             `json:"message"`
+	Data      ChangePasswordData `json:"data"`
+	Timestamp string             `json:"timestamp"`
+}
+
 type ErrorResponse struct {
 	Code      string      `json:"code"`
 	Msg       string      `json:"msg"`
@@ -24,32 +141,146 @@ type ErrorResponse struct {
 	Timestamp string      `json:"timestamp"`
 }
 
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ForgotPasswordResponse struct {
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Timestamp string `json:"timestamp"`
+}
+
+type IPRuleCreateRequest struct {
+	Type     string `json:"type,options=block|allow"`             // 规则类型：block封禁，allow白名单
+	CIDR     string `json:"cidr"`                                 // IP地址或CIDR网段
+	Reason   string `json:"reason"`                               // 添加原因
+	Duration int    `json:"duration,optional,range=[0:31536000]"` // 封禁时长（秒），大于0时为临时封禁，仅支持单个IP
+}
+
+type IPRuleIDRequest struct {
+	ID string `path:"id"`
+}
+
+type IPRuleInfo struct {
+	ID            string `json:"id"`
+	Type          string `json:"type"`
+	CIDR          string `json:"cidr"`
+	Reason        string `json:"reason"`
+	Temporary     bool   `json:"temporary"` // 是否为临时封禁
+	CreatedBy     string `json:"createdBy"` // 创建者用户ID，自动封禁为system
+	CreatedByName string `json:"createdByName,omitempty"`
+	ExpiresAt     string `json:"expiresAt,omitempty"` // 临时封禁到期时间
+	CreatedAt     string `json:"createdAt"`
+}
+
+type IPRuleListData struct {
+	List          []IPRuleInfo `json:"list"`
+	AllowlistMode bool         `json:"allowlistMode"` // 是否开启白名单模式
+}
+
+type IPRuleListRequest struct {
+	Type string `form:"type,optional,options=block|allow"` // 规则类型过滤
+}
+
+type IPRuleListResponse struct {
+	Code      int            `json:"code"`
+	Message   string         `json:"message"`
+	Data      IPRuleListData `json:"data"`
+	Timestamp string         `json:"timestamp"`
+}
+
+type IPRuleResponse struct {
+	Code      int        `json:"code"`
+	Message   string     `json:"message"`
+	Data      IPRuleInfo `json:"data"`
+	Timestamp string     `json:"timestamp"`
+}
+
+type InvitationAcceptRequest struct {
+	Token       string `json:"token" validate:"required"` // 邀请链接中的令牌
+	Username    string `json:"username" validate:"required,min=3,max=32"`
+	Password    string `json:"password" validate:"required,min=8,max=128"`
+	DisplayName string `json:"displayName,optional" validate:"max=64"` // 为空时使用用户名
+}
+
+type InvitationAcceptResponse struct {
+	Code      int      `json:"code"`
+	Message   string   `json:"message"`
+	Data      UserInfo `json:"data"`
+	Timestamp string   `json:"timestamp"`
+}
+
+type InvitationCreateRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=owner admin editor author"`
+}
+
+type InvitationIDRequest struct {
+	ID string `path:"id"`
+}
+
+type InvitationInfo struct {
+	ID         string `json:"id"`
+	Email      string `json:"email"`
+	Role       string `json:"role"`
+	Status     string `json:"status"`
+	Expired    bool   `json:"expired"`   // 链接已过期，可重新发送
+	InvitedBy  string `json:"invitedBy"` // 邀请人ID
+	SendCount  int    `json:"sendCount"`
+	LastSentAt string `json:"lastSentAt"`
+	ExpiresAt  string `json:"expiresAt"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+type InvitationListData struct {
+	List []InvitationInfo `json:"list"`
+}
+
+type InvitationListResponse struct {
+	Code      int                `json:"code"`
+	Message   string             `json:"message"`
+	Data      InvitationListData `json:"data"`
+	Timestamp string             `json:"timestamp"`
+}
+
+type InvitationResponse struct {
+	Code      int            `json:"code"`
+	Message   string         `json:"message"`
+	Data      InvitationInfo `json:"data"`
+	Timestamp string         `json:"timestamp"`
+}
+
 type LoginData struct {
-	Token        string   `json:"token"`
-	RefreshToken string   `json:"refreshToken"`
-	ExpiresIn    int      `json:"expiresIn"`
-	User         UserInfo `json:"user"`
+	Token            string   `json:"token"`
+	RefreshToken     string   `json:"refreshToken"`
+	ExpiresIn        int      `json:"expiresIn"`
+	User             UserInfo `json:"user"`
+	MFARequired      bool     `json:"mfaRequired,omitempty"`      // 需要两步验证，此时不返回令牌
+	MFAToken         string   `json:"mfaToken,omitempty"`         // 两步验证挑战令牌
+	MFASetupRequired bool     `json:"mfaSetupRequired,omitempty"` // 当前角色要求启用两步验证但尚未启用
 }
 
 type LoginLogInfo struct {
-	ID          string `json:"id"`
-	UserID      string `json:"userId,omitempty"`
-	Username    string `json:"username"`
-	LoginMethod string `json:"loginMethod"`
-	IPAddress   string `json:"ipAddress"`
-	UserAgent   string `json:"userAgent"`
-	Status      string `json:"status"`
-	FailReason  string `json:"failReason,omitempty"`
-	SessionID   string `json:"sessionId,omitempty"`
-	Country     string `json:"country,omitempty"`
-	Region      string `json:"region,omitempty"`
-	City        string `json:"city,omitempty"`
-	DeviceType  string `json:"deviceType,omitempty"`
-	Browser     string `json:"browser,omitempty"`
-	OS          string `json:"os,omitempty"`
-	LoginAt     string `json:"loginAt"`
-	LogoutAt    string `json:"logoutAt,omitempty"`
-	Duration    int64  `json:"duration,omitempty"`
+	ID          string   `json:"id"`
+	UserID      string   `json:"userId,omitempty"`
+	Username    string   `json:"username"`
+	LoginMethod string   `json:"loginMethod"`
+	IPAddress   string   `json:"ipAddress"`
+	UserAgent   string   `json:"userAgent"`
+	Status      string   `json:"status"`
+	FailReason  string   `json:"failReason,omitempty"`
+	SessionID   string   `json:"sessionId,omitempty"`
+	Country     string   `json:"country,omitempty"`
+	Region      string   `json:"region,omitempty"`
+	City        string   `json:"city,omitempty"`
+	DeviceType  string   `json:"deviceType,omitempty"`
+	Browser     string   `json:"browser,omitempty"`
+	OS          string   `json:"os,omitempty"`
+	LoginAt     string   `json:"loginAt"`
+	LogoutAt    string   `json:"logoutAt,omitempty"`
+	Duration    int64    `json:"duration,omitempty"`
+	Anomalies   []string `json:"anomalies,omitempty"`
 }
 
 type LoginLogsData struct {
@@ -67,8 +298,9 @@ type LoginLogsRequest struct {
 	StartTime  string `form:"startTime,optional"`                                               // 开始时间（RFC3339格式）
 	EndTime    string `form:"endTime,optional"`                                                 // 结束时间（RFC3339格式）
 	Country    string `form:"country,optional"`                                                 // 国家过滤
-	DeviceType string `form:"deviceType,optional"`                                              // 设备类型过滤
-	Browser    string `form:"browser,optional"`                                                 // 浏览器过滤
+	DeviceType string `form:"deviceType,optional,options=desktop|mobile|tablet|bot"`            // 设备类型过滤
+	Browser    string `form:"browser,optional"`                                                 // 浏览器过滤（如 Chrome 或 Chrome 120）
+	OS         string `form:"os,optional"`                                                      // 操作系统过滤（如 Windows 或 Windows 10）
 	SortBy     string `form:"sortBy,default=loginAt,options=loginAt|username|ipAddress|status"` // 排序字段
 	SortDesc   bool   `form:"sortDesc,default=true"`                                            // 是否降序排列
 }
@@ -80,6 +312,11 @@ type LoginLogsResponse struct {
 	Timestamp string        `json:"timestamp"`
 }
 
+type LoginMFARequest struct {
+	MFAToken string `json:"mfaToken" validate:"required"`
+	Code     string `json:"code" validate:"required"` // TOTP验证码或恢复码
+}
+
 type LoginRequest struct {
 	Username   string `json:"username" validate:"required"`
 	Password   string `json:"password" validate:"required"`
@@ -93,6 +330,55 @@ type LoginResponse struct {
 	Timestamp string    `json:"timestamp"`
 }
 
+type LoginStatsCount struct {
+	Key    string `json:"key"`
+	Total  int64  `json:"total"`
+	Failed int64  `json:"failed"`
+	LastAt string `json:"lastAt"` // 最近一次登录时间
+}
+
+type LoginStatsData struct {
+	From               string            `json:"from"`
+	To                 string            `json:"to"`
+	GroupBy            string            `json:"groupBy"`
+	Summary            LoginStatsSummary `json:"summary"`
+	Series             []LoginStatsPoint `json:"series"`             // 连续的时间序列，没有登录的时间段计数为0
+	TopFailedIPs       []LoginStatsCount `json:"topFailedIps"`       // 失败次数最多的IP
+	TopFailedUsernames []LoginStatsCount `json:"topFailedUsernames"` // 失败次数最多的用户名
+	Countries          []LoginStatsCount `json:"countries"`          // 按国家分布
+	Browsers           []LoginStatsCount `json:"browsers"`           // 按浏览器分布，不区分版本
+}
+
+type LoginStatsPoint struct {
+	Time    string `json:"time"` // 时间段起点（UTC）
+	Total   int64  `json:"total"`
+	Success int64  `json:"success"`
+	Failed  int64  `json:"failed"`
+}
+
+type LoginStatsRequest struct {
+	From    string `form:"from,optional"`                        // 开始时间（RFC3339格式），默认按天统计最近7天、按小时统计最近24小时
+	To      string `form:"to,optional"`                          // 结束时间（RFC3339格式），默认为当前时间
+	GroupBy string `form:"groupBy,default=day,options=day|hour"` // 时间序列粒度
+	Limit   int    `form:"limit,default=10,range=[1:50]"`        // 排行和分布的条数
+}
+
+type LoginStatsResponse struct {
+	Code      int            `json:"code"`
+	Message   string         `json:"message"`
+	Data      LoginStatsData `json:"data"`
+	Timestamp string         `json:"timestamp"`
+}
+
+type LoginStatsSummary struct {
+	TotalLogins   int64   `json:"totalLogins"`
+	SuccessLogins int64   `json:"successLogins"`
+	FailedLogins  int64   `json:"failedLogins"`
+	UniqueUsers   int64   `json:"uniqueUsers"`
+	UniqueIPs     int64   `json:"uniqueIps"`
+	SuccessRate   float64 `json:"successRate"` // 成功率（百分比）
+}
+
 type LogoutRequest struct {
 	RefreshToken string `json:"refreshToken,omitempty"`
 }
@@ -103,6 +389,35 @@ type LogoutResponse struct {
 	Timestamp string `json:"timestamp"`
 }
 
+type MFAPolicyData struct {
+	RequiredRoles []string `json:"requiredRoles"`
+}
+
+type MFAPolicyResponse struct {
+	Code      int           `json:"code"`
+	Message   string        `json:"message"`
+	Data      MFAPolicyData `json:"data"`
+	Timestamp string        `json:"timestamp"`
+}
+
+type MFAPolicyUpdateRequest struct {
+	RequiredRoles []string `json:"requiredRoles"`
+}
+
+type MFAStatusData struct {
+	Enabled                bool   `json:"enabled"`
+	Required               bool   `json:"required"` // 当前角色是否要求启用
+	EnabledAt              string `json:"enabledAt,omitempty"`
+	RecoveryCodesRemaining int    `json:"recoveryCodesRemaining"`
+}
+
+type MFAStatusResponse struct {
+	Code      int           `json:"code"`
+	Message   string        `json:"message"`
+	Data      MFAStatusData `json:"data"`
+	Timestamp string        `json:"timestamp"`
+}
+
 type PageCreateRequest struct {
 	Title           string `json:"title" validate:"required,min=1,max=255"`
 	Slug            string `json:"slug,optional" validate:"max=255"`
@@ -265,6 +580,8 @@ type PostCreateRequest struct {
 	MetaDescription string    `json:"metaDescription,optional" validate:"max=160"`
 	CanonicalURL    string    `json:"canonicalUrl,optional" validate:"max=255"`
 	PublishedAt     string    `json:"publishedAt,optional"`
+	DisableTOC      bool      `json:"disableToc,optional"`                   // 不生成目录
+	TOCMaxDepth     int       `json:"tocMaxDepth,optional" validate:"max=6"` // 目录层数，从最高一级标题算起，0为默认值3
 }
 
 type PostCreateResponse struct {
@@ -303,6 +620,8 @@ type PostDetailData struct {
 	ReadingTime     int        `json:"readingTime"`
 	WordCount       int        `json:"wordCount"`
 	ViewCount       int64      `json:"viewCount"`
+	DisableTOC      bool       `json:"disableToc"`  // 不生成目录
+	TOCMaxDepth     int        `json:"tocMaxDepth"` // 目录层数，0为默认值
 	PublishedAt     string     `json:"publishedAt,omitempty"`
 	CreatedAt       string     `json:"createdAt"`
 	UpdatedAt       string     `json:"updatedAt"`
@@ -400,6 +719,8 @@ type PostUpdateRequest struct {
 	MetaDescription string    `json:"metaDescription,optional" validate:"max=160"`
 	CanonicalURL    string    `json:"canonicalUrl,optional" validate:"max=255"`
 	PublishedAt     string    `json:"publishedAt,optional"`
+	DisableTOC      *bool     `json:"disableToc,optional"`                   // 不生成目录，未提供时不修改
+	TOCMaxDepth     *int      `json:"tocMaxDepth,optional" validate:"max=6"` // 目录层数，0为默认值，未提供时不修改
 }
 
 type PostUpdateResponse struct {
@@ -416,6 +737,175 @@ type ProfileResponse struct {
 	Timestamp string   `json:"timestamp"`
 }
 
+type ProfileUpdateRequest struct {
+	DisplayName  string `json:"displayName" validate:"required,max=64"`
+	Bio          string `json:"bio,optional" validate:"max=500"`
+	Location     string `json:"location,optional" validate:"max=100"`
+	Website      string `json:"website,optional" validate:"max=255"`
+	Twitter      string `json:"twitter,optional" validate:"max=50"`
+	Facebook     string `json:"facebook,optional" validate:"max=50"`
+	ProfileImage string `json:"profileImage,optional"` // 头像URL
+	CoverImage   string `json:"coverImage,optional"`   // 封面图URL
+}
+
+type ProfileUpdateResponse struct {
+	Code      int      `json:"code"`
+	Message   string   `json:"message"`
+	Data      UserInfo `json:"data"`
+	Timestamp string   `json:"timestamp"`
+}
+
+type RecoveryCodesData struct {
+	RecoveryCodes []string `json:"recoveryCodes"` // 明文恢复码，仅返回一次
+}
+
+type RecoveryCodesRegenerateRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type RecoveryCodesResponse struct {
+	Code      int               `json:"code"`
+	Message   string            `json:"message"`
+	Data      RecoveryCodesData `json:"data"`
+	Timestamp string            `json:"timestamp"`
+}
+
+type RefreshTokenData struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int    `json:"expiresIn"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type RefreshTokenResponse struct {
+	Code      int              `json:"code"`
+	Message   string           `json:"message"`
+	Data      RefreshTokenData `json:"data"`
+	Timestamp string           `json:"timestamp"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,min=8,max=128"`
+}
+
+type ResetPasswordResponse struct {
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Timestamp string `json:"timestamp"`
+}
+
+type SecurityAlertsRequest struct {
+	Page      int    `form:"page,default=1,range=[1:]"`
+	Limit     int    `form:"limit,default=20,range=[1:100]"`
+	UserID    string `form:"userId,optional"`
+	Type      string `form:"type,optional,options=new_country|new_device|impossible_travel"`
+	StartTime string `form:"startTime,optional"`
+	EndTime   string `form:"endTime,optional"`
+}
+
+type SecurityAlertsResponse struct {
+	Code      int           `json:"code"`
+	Message   string        `json:"message"`
+	Data      LoginLogsData `json:"data"`
+	Timestamp string        `json:"timestamp"`
+}
+
+type SessionBatchRevokeData struct {
+	RevokedCount int `json:"revokedCount"`
+}
+
+type SessionBatchRevokeResponse struct {
+	Code      int                    `json:"code"`
+	Message   string                 `json:"message"`
+	Data      SessionBatchRevokeData `json:"data"`
+	Timestamp string                 `json:"timestamp"`
+}
+
+type SessionInfo struct {
+	ID         string `json:"id"`
+	IPAddress  string `json:"ipAddress"`
+	UserAgent  string `json:"userAgent"`
+	DeviceType string `json:"deviceType,omitempty"`
+	Browser    string `json:"browser,omitempty"`
+	OS         string `json:"os,omitempty"`
+	CreatedAt  string `json:"createdAt"`
+	LastSeenAt string `json:"lastSeenAt"`
+	Current    bool   `json:"current"` // 是否为当前请求所属会话
+}
+
+type SessionListData struct {
+	List []SessionInfo `json:"list"`
+}
+
+type SessionListResponse struct {
+	Code      int             `json:"code"`
+	Message   string          `json:"message"`
+	Data      SessionListData `json:"data"`
+	Timestamp string          `json:"timestamp"`
+}
+
+type SessionRevokeRequest struct {
+	ID string `path:"id"`
+}
+
+type SessionRevokeResponse struct {
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Timestamp string `json:"timestamp"`
+}
+
+type SlugStrategyData struct {
+	Strategy   string   `json:"strategy"`   // 当前策略
+	Strategies []string `json:"strategies"` // 可选的策略
+}
+
+type SlugStrategyResponse struct {
+	Code      int              `json:"code"`
+	Message   string           `json:"message"`
+	Data      SlugStrategyData `json:"data"`
+	Timestamp string           `json:"timestamp"`
+}
+
+type SlugStrategyUpdateRequest struct {
+	Strategy string `json:"strategy"` // pinyin、ascii或unicode
+}
+
+type TOTPConfirmRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TOTPDisableRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"` // TOTP验证码或恢复码
+}
+
+type TOTPDisableResponse struct {
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Timestamp string `json:"timestamp"`
+}
+
+type TOTPSetupData struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauthUri"`
+	ExpiresIn  int    `json:"expiresIn"` // 需在此时间内（秒）完成确认
+}
+
+type TOTPSetupRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+type TOTPSetupResponse struct {
+	Code      int           `json:"code"`
+	Message   string        `json:"message"`
+	Data      TOTPSetupData `json:"data"`
+	Timestamp string        `json:"timestamp"`
+}
+
 type TagInfo struct {
 	Name string `json:"name"`
 	Slug string `json:"slug"`
@@ -429,6 +919,39 @@ type TestResponse struct {
 	Message string `json:"message"`
 }
 
+type UserCreateRequest struct {
+	Username    string `json:"username" validate:"required,min=3,max=32"`
+	Email       string `json:"email" validate:"required,email"`
+	Password    string `json:"password" validate:"required,min=8,max=128"`
+	DisplayName string `json:"displayName" validate:"required,max=64"`
+	Role        string `json:"role" validate:"required,oneof=owner admin editor author"`
+	Bio         string `json:"bio,optional" validate:"max=500"`
+	Location    string `json:"location,optional" validate:"max=100"`
+	Website     string `json:"website,optional" validate:"max=255"`
+	Twitter     string `json:"twitter,optional" validate:"max=50"`
+	Facebook    string `json:"facebook,optional" validate:"max=50"`
+}
+
+type UserDeleteData struct {
+	ReassignedTo string `json:"reassignedTo"` // 接收内容的用户ID
+	PostCount    int64  `json:"postCount"`    // 转移的文章数量
+	PageCount    int64  `json:"pageCount"`    // 转移的页面数量
+	Archived     bool   `json:"archived"`     // 文章是否已归档
+}
+
+type UserDeleteRequest struct {
+	ID         string `path:"id"`
+	ReassignTo string `form:"reassignTo"`                                           // 接收文章和页面的用户ID
+	PostAction string `form:"postAction,default=reassign,options=reassign|archive"` // reassign保留文章状态，archive同时归档文章
+}
+
+type UserDeleteResponse struct {
+	Code      int            `json:"code"`
+	Message   string         `json:"message"`
+	Data      UserDeleteData `json:"data"`
+	Timestamp string         `json:"timestamp"`
+}
+
 type UserDetailRequest struct {
 	ID string `path:"id"`
 }
@@ -441,21 +964,23 @@ type UserDetailResponse struct {
 }
 
 type UserInfo struct {
-	ID           string `json:"id"`
-	Username     string `json:"username"`
-	DisplayName  string `json:"displayName"`
-	Email        string `json:"email"`
-	Role         string `json:"role"`
-	ProfileImage string `json:"profileImage,omitempty"`
-	Bio          string `json:"bio,omitempty"`
-	Location     string `json:"location,omitempty"`
-	Website      string `json:"website,omitempty"`
-	Twitter      string `json:"twitter,omitempty"`
-	Facebook     string `json:"facebook,omitempty"`
-	Status       string `json:"status"`
-	LastLoginAt  string `json:"lastLoginAt,omitempty"`
-	CreatedAt    string `json:"createdAt"`
-	UpdatedAt    string `json:"updatedAt"`
+	ID               string `json:"id"`
+	Username         string `json:"username"`
+	DisplayName      string `json:"displayName"`
+	Email            string `json:"email"`
+	Role             string `json:"role"`
+	ProfileImage     string `json:"profileImage,omitempty"`
+	CoverImage       string `json:"coverImage,omitempty"`
+	Bio              string `json:"bio,omitempty"`
+	Location         string `json:"location,omitempty"`
+	Website          string `json:"website,omitempty"`
+	Twitter          string `json:"twitter,omitempty"`
+	Facebook         string `json:"facebook,omitempty"`
+	Status           string `json:"status"`
+	TwoFactorEnabled bool   `json:"twoFactorEnabled"`
+	LastLoginAt      string `json:"lastLoginAt,omitempty"`
+	CreatedAt        string `json:"createdAt"`
+	UpdatedAt        string `json:"updatedAt"`
 }
 
 type UserListData struct {
@@ -479,3 +1004,147 @@ type UserListResponse struct {
 	Data      UserListData `json:"data"`
 	Timestamp string       `json:"timestamp"`
 }
+
+type UserRoleUpdateRequest struct {
+	ID   string `path:"id"`
+	Role string `json:"role" validate:"required,oneof=owner admin editor author"`
+}
+
+type UserUpdateRequest struct {
+	ID           string `path:"id"`
+	Email        string `json:"email" validate:"required,email"`
+	DisplayName  string `json:"displayName" validate:"required,max=64"`
+	Bio          string `json:"bio,optional" validate:"max=500"`
+	Location     string `json:"location,optional" validate:"max=100"`
+	Website      string `json:"website,optional" validate:"max=255"`
+	Twitter      string `json:"twitter,optional" validate:"max=50"`
+	Facebook     string `json:"facebook,optional" validate:"max=50"`
+	ProfileImage string `json:"profileImage,optional"` // 头像URL
+	CoverImage   string `json:"coverImage,optional"`   // 封面图URL
+}
+
+type WebAuthnAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON" validate:"required"`
+	AuthenticatorData string `json:"authenticatorData" validate:"required"`
+	Signature         string `json:"signature" validate:"required"`
+	UserHandle        string `json:"userHandle,optional"`
+}
+
+type WebAuthnAttestationResponse struct {
+	ClientDataJSON    string   `json:"clientDataJSON" validate:"required"`
+	AttestationObject string   `json:"attestationObject" validate:"required"`
+	Transports        []string `json:"transports,optional"`
+}
+
+type WebAuthnAuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+type WebAuthnCreationOptions struct {
+	Challenge              string                         `json:"challenge"`
+	RP                     WebAuthnRelyingParty           `json:"rp"`
+	User                   WebAuthnUserEntity             `json:"user"`
+	PubKeyCredParams       []WebAuthnCredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int                            `json:"timeout"` // 毫秒
+	ExcludeCredentials     []WebAuthnCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection WebAuthnAuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                         `json:"attestation"`
+}
+
+type WebAuthnCredentialDeleteRequest struct {
+	ID string `path:"id"`
+}
+
+type WebAuthnCredentialDeleteResponse struct {
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Timestamp string `json:"timestamp"`
+}
+
+type WebAuthnCredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+type WebAuthnCredentialInfo struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Transports []string `json:"transports,omitempty"`
+	BackedUp   bool     `json:"backedUp"` // 是否已同步到云端
+	CreatedAt  string   `json:"createdAt"`
+	LastUsedAt string   `json:"lastUsedAt,omitempty"`
+	LastUsedIP string   `json:"lastUsedIp,omitempty"`
+}
+
+type WebAuthnCredentialListData struct {
+	List []WebAuthnCredentialInfo `json:"list"`
+}
+
+type WebAuthnCredentialListResponse struct {
+	Code      int                        `json:"code"`
+	Message   string                     `json:"message"`
+	Data      WebAuthnCredentialListData `json:"data"`
+	Timestamp string                     `json:"timestamp"`
+}
+
+type WebAuthnCredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+type WebAuthnCredentialResponse struct {
+	Code      int                    `json:"code"`
+	Message   string                 `json:"message"`
+	Data      WebAuthnCredentialInfo `json:"data"`
+	Timestamp string                 `json:"timestamp"`
+}
+
+type WebAuthnLoginOptionsResponse struct {
+	Code      int                    `json:"code"`
+	Message   string                 `json:"message"`
+	Data      WebAuthnRequestOptions `json:"data"`
+	Timestamp string                 `json:"timestamp"`
+}
+
+type WebAuthnLoginRequest struct {
+	ID       string                    `json:"id" validate:"required"`
+	Response WebAuthnAssertionResponse `json:"response"`
+}
+
+type WebAuthnRegisterOptionsRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+type WebAuthnRegisterOptionsResponse struct {
+	Code      int                     `json:"code"`
+	Message   string                  `json:"message"`
+	Data      WebAuthnCreationOptions `json:"data"`
+	Timestamp string                  `json:"timestamp"`
+}
+
+type WebAuthnRegisterRequest struct {
+	Name     string                      `json:"name,optional" validate:"max=50"`
+	ID       string                      `json:"id" validate:"required"`
+	Response WebAuthnAttestationResponse `json:"response"`
+}
+
+type WebAuthnRelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type WebAuthnRequestOptions struct {
+	Challenge        string                         `json:"challenge"`
+	Timeout          int                            `json:"timeout"` // 毫秒
+	RPID             string                         `json:"rpId"`
+	UserVerification string                         `json:"userVerification"`
+	AllowCredentials []WebAuthnCredentialDescriptor `json:"allowCredentials"` // 为空，由认证器选择可发现凭据
+}
+
+type WebAuthnUserEntity struct {
+	ID          string `json:"id"` // base64url编码的用户句柄
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
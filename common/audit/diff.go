@@ -0,0 +1,184 @@
+package audit
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/common/model"
+)
+
+// maxValueLength 审计日志中字符串值的最大长度（字符数），正文等长内容截断保存
+const maxValueLength = 500
+
+// redactedValue 敏感字段的值不写入审计日志，只记录发生了变化
+const redactedValue = "[REDACTED]"
+
+// sensitiveFieldKeywords 字段名包含这些关键字时视为敏感字段
+var sensitiveFieldKeywords = []string{"password", "secret", "token", "recovery"}
+
+// Diff 比较模型的当前值与更新内容，返回实际发生变化的字段，按字段名排序
+// 字段名使用数据库字段名，支持 a.b 形式的嵌套字段；两侧都为空值时不视为变化
+func Diff(before interface{}, updates map[string]interface{}) []model.AuditChange {
+	current := toDocument(before)
+
+	fields := make([]string, 0, len(updates))
+	for field := range updates {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	changes := make([]model.AuditChange, 0, len(fields))
+	for _, field := range fields {
+		if change, ok := diffValue(field, lookup(current, field), normalize(updates[field])); ok {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// diffValue 比较规范化后的前后值，有变化时返回脱敏、截断后的变更
+func diffValue(field string, before, after interface{}) (model.AuditChange, bool) {
+	if isEmpty(before) && isEmpty(after) || reflect.DeepEqual(before, after) {
+		return model.AuditChange{}, false
+	}
+
+	if isSensitiveField(field) {
+		return model.AuditChange{Field: field, Before: redact(before), After: redact(after)}, true
+	}
+	return model.AuditChange{Field: field, Before: truncate(before), After: truncate(after)}, true
+}
+
+// toDocument 把模型转换为以数据库字段名为键的文档
+func toDocument(value interface{}) map[string]interface{} {
+	if value == nil {
+		return nil
+	}
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil
+	}
+
+	data, err := bson.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+	document, _ := Simplify(doc).(map[string]interface{})
+	return document
+}
+
+// lookup 按 a.b 形式的路径获取嵌套字段
+func lookup(document map[string]interface{}, path string) interface{} {
+	var value interface{} = document
+	for _, key := range strings.Split(path, ".") {
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = nested[key]
+	}
+	return value
+}
+
+// normalize 按数据库中的存储形式规范化任意值，使其可以与模型字段直接比较
+func normalize(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	data, err := bson.Marshal(bson.M{"v": value})
+	if err != nil {
+		return value
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(data, &doc); err != nil || len(doc) == 0 {
+		return value
+	}
+	return Simplify(doc[0].Value)
+}
+
+// Simplify 把BSON解码类型转换为JSON友好的普通类型，用于输出从数据库读出的变更值
+func Simplify(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.D:
+		document := make(map[string]interface{}, len(v))
+		for _, element := range v {
+			document[element.Key] = Simplify(element.Value)
+		}
+		return document
+	case bson.M:
+		document := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			document[key] = Simplify(element)
+		}
+		return document
+	case bson.A:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = Simplify(item)
+		}
+		return items
+	case primitive.DateTime:
+		return v.Time().UTC()
+	case primitive.ObjectID:
+		return v.Hex()
+	case int32:
+		return int64(v)
+	default:
+		return v
+	}
+}
+
+// isEmpty 检查是否为空值，缺省字段与空字符串、空数组视为相同
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	case time.Time:
+		return v.IsZero()
+	default:
+		return false
+	}
+}
+
+// isSensitiveField 检查字段是否为密码、密钥、令牌等敏感字段
+func isSensitiveField(field string) bool {
+	lower := strings.ToLower(field)
+	for _, keyword := range sensitiveFieldKeywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact 隐藏敏感字段的值，空值保持为空以便区分设置和清除
+func redact(value interface{}) interface{} {
+	if isEmpty(value) {
+		return nil
+	}
+	return redactedValue
+}
+
+// truncate 截断过长的字符串
+func truncate(value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok || utf8.RuneCountInString(s) <= maxValueLength {
+		return value
+	}
+	return string([]rune(s)[:maxValueLength]) + "…"
+}
@@ -0,0 +1,116 @@
+package audit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/common/model"
+)
+
+func TestDiff(t *testing.T) {
+	Convey("Diff Tests", t, func() {
+		lockedUntil := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+		user := &model.User{
+			ID:           primitive.NewObjectID(),
+			Username:     "writer",
+			Email:        "writer@example.com",
+			PasswordHash: "$2a$12$old",
+			DisplayName:  "Writer",
+			Role:         "author",
+			Status:       "active",
+			LockedUntil:  &lockedUntil,
+		}
+
+		Convey("Should report only fields that actually change", func() {
+			changes := Diff(user, map[string]interface{}{
+				"displayName": "Writer",
+				"role":        "editor",
+				"bio":         "",
+				"status":      "suspended",
+			})
+			So(changes, ShouldResemble, []model.AuditChange{
+				{Field: "role", Before: "author", After: "editor"},
+				{Field: "status", Before: "active", After: "suspended"},
+			})
+		})
+
+		Convey("Should compare values in their stored form", func() {
+			So(Diff(user, map[string]interface{}{
+				"lockedUntil":    lockedUntil.Local(),
+				"loginFailCount": 0,
+			}), ShouldBeEmpty)
+
+			changes := Diff(user, map[string]interface{}{"lockedUntil": nil})
+			So(changes, ShouldHaveLength, 1)
+			So(changes[0].Before, ShouldEqual, lockedUntil)
+			So(changes[0].After, ShouldBeNil)
+		})
+
+		Convey("Should hide sensitive values", func() {
+			changes := Diff(user, map[string]interface{}{"passwordHash": "$2a$12$new"})
+			So(changes, ShouldResemble, []model.AuditChange{
+				{Field: "passwordHash", Before: redactedValue, After: redactedValue},
+			})
+		})
+
+		Convey("Should truncate long values and support nested fields", func() {
+			type seo struct {
+				Title string `bson:"title"`
+			}
+			document := struct {
+				Markdown string `bson:"markdown"`
+				SEO      seo    `bson:"seo"`
+			}{Markdown: "short", SEO: seo{Title: "Old"}}
+			changes := Diff(document, map[string]interface{}{
+				"markdown":  strings.Repeat("字", maxValueLength+10),
+				"seo.title": "New",
+			})
+			So(changes, ShouldHaveLength, 2)
+			So(changes[0].Field, ShouldEqual, "markdown")
+			So(changes[0].After, ShouldEqual, strings.Repeat("字", maxValueLength)+"…")
+			So(changes[1], ShouldResemble, model.AuditChange{Field: "seo.title", Before: "Old", After: "New"})
+		})
+
+		Convey("Nil models should be treated as empty", func() {
+			var missing *model.User
+			So(Diff(missing, map[string]interface{}{"role": "admin"}), ShouldResemble, []model.AuditChange{
+				{Field: "role", After: "admin"},
+			})
+		})
+	})
+}
+
+func TestRecorder(t *testing.T) {
+	Convey("Recorder Tests", t, func() {
+		Convey("Helpers should be no-ops without a recorder", func() {
+			ctx := context.Background()
+			SetActor(ctx, Actor{UserID: "1"})
+			SetTargetID(ctx, "target")
+			RecordChange(ctx, "role", "author", "editor")
+			So(FromContext(ctx), ShouldBeNil)
+		})
+
+		Convey("Should collect actor, target and changes", func() {
+			recorder := NewRecorder()
+			ctx := WithRecorder(context.Background(), recorder)
+
+			SetActor(ctx, Actor{UserID: "1", Username: "writer", Role: "author"})
+			SetTargetID(ctx, "target")
+			RecordUpdates(ctx, &model.User{Role: "author"}, map[string]interface{}{"role": "editor"})
+			RecordChange(ctx, "requiredRoles", []string{"owner"}, []string{"owner"})
+			RecordChange(ctx, "requiredRoles", []string{"owner"}, []string{"owner", "admin"})
+
+			So(recorder.Actor().Username, ShouldEqual, "writer")
+			So(recorder.TargetID(), ShouldEqual, "target")
+			So(recorder.Changes(), ShouldResemble, []model.AuditChange{
+				{Field: "role", Before: "author", After: "editor"},
+				{Field: "requiredRoles", Before: []interface{}{"owner"}, After: []interface{}{"owner", "admin"}},
+			})
+		})
+	})
+}
@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/heimdall-api/common/model"
+)
+
+// recorderKey 审计记录器在context中的键
+type recorderKey struct{}
+
+// Actor 操作者，未登录的公开接口由业务逻辑在确认身份后设置
+type Actor struct {
+	UserID   string
+	Username string
+	Role     string
+}
+
+// Recorder 单次请求的审计信息收集器
+// 由审计中间件创建并写入context，业务逻辑通过本包的函数补充操作对象和字段变更，请求结束后由中间件写入审计日志
+type Recorder struct {
+	mu       sync.Mutex
+	actor    *Actor
+	targetID string
+	changes  []model.AuditChange
+}
+
+// NewRecorder 创建审计记录器
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// WithRecorder 将审计记录器写入context
+func WithRecorder(ctx context.Context, recorder *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, recorder)
+}
+
+// FromContext 从context获取审计记录器，不存在时返回nil
+func FromContext(ctx context.Context) *Recorder {
+	recorder, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return recorder
+}
+
+// SetActor 设置操作者，用于在公开接口中记录通过令牌确认身份的用户
+func SetActor(ctx context.Context, actor Actor) {
+	if recorder := FromContext(ctx); recorder != nil {
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		recorder.actor = &actor
+	}
+}
+
+// SetTargetID 设置操作对象ID，用于创建操作等路径中没有对象ID的接口
+func SetTargetID(ctx context.Context, targetID string) {
+	if recorder := FromContext(ctx); recorder != nil {
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		recorder.targetID = targetID
+	}
+}
+
+// RecordUpdates 记录对象按更新内容变化的字段，before为更新前的模型，updates为写入数据库的字段
+func RecordUpdates(ctx context.Context, before interface{}, updates map[string]interface{}) {
+	if recorder := FromContext(ctx); recorder != nil {
+		recorder.addChanges(Diff(before, updates))
+	}
+}
+
+// RecordChange 记录单个字段的变化，前后值相同时忽略
+func RecordChange(ctx context.Context, field string, before, after interface{}) {
+	if recorder := FromContext(ctx); recorder != nil {
+		if change, ok := diffValue(field, normalize(before), normalize(after)); ok {
+			recorder.addChanges([]model.AuditChange{change})
+		}
+	}
+}
+
+// Actor 返回业务逻辑设置的操作者，未设置时返回nil
+func (r *Recorder) Actor() *Actor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.actor
+}
+
+// TargetID 返回业务逻辑设置的操作对象ID
+func (r *Recorder) TargetID() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.targetID
+}
+
+// Changes 返回记录的字段变更
+func (r *Recorder) Changes() []model.AuditChange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]model.AuditChange(nil), r.changes...)
+}
+
+// addChanges 追加字段变更
+func (r *Recorder) addChanges(changes []model.AuditChange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes = append(r.changes, changes...)
+}
@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/zeromicro/go-zero/core/collection"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+// ipRulesCacheExpire 永久IP规则本地缓存时间，每个请求都会检查规则
+// 规则变更后本实例立即刷新，其他实例最多延迟该时间生效
+const ipRulesCacheExpire = 30 * time.Second
+
+// ipRulesCacheKey 永久IP规则在本地缓存中的键
+const ipRulesCacheKey = "ipRules"
+
+var (
+	// ErrIPBlocked IP已被封禁
+	ErrIPBlocked = errors.New("ip blocked")
+	// ErrIPNotAllowed 白名单模式下IP不在白名单中
+	ErrIPNotAllowed = errors.New("ip not allowed")
+)
+
+// ipRuleSet 解析后的永久IP规则
+type ipRuleSet struct {
+	block []*net.IPNet
+	allow []*net.IPNet
+}
+
+// IPAccessControl IP访问控制
+// 永久规则（封禁和白名单，支持CIDR）保存在数据库中并缓存在本地；临时封禁只针对单个IP，保存在Redis中由TTL自动过期
+// 白名单中的IP不受封禁限制；开启白名单模式且白名单非空时，只允许白名单中的IP访问
+type IPAccessControl struct {
+	ipRuleDAO     *dao.IPRuleDAO
+	redis         redis.Cmdable
+	cache         *collection.Cache
+	allowlistMode bool
+}
+
+// NewIPAccessControl 创建IP访问控制
+func NewIPAccessControl(ipRuleDAO *dao.IPRuleDAO, rdb redis.Cmdable, allowlistMode bool) (*IPAccessControl, error) {
+	cache, err := collection.NewCache(ipRulesCacheExpire)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ip rules cache: %w", err)
+	}
+
+	return &IPAccessControl{
+		ipRuleDAO:     ipRuleDAO,
+		redis:         rdb,
+		cache:         cache,
+		allowlistMode: allowlistMode,
+	}, nil
+}
+
+// AllowlistMode 是否开启白名单模式
+func (c *IPAccessControl) AllowlistMode() bool {
+	return c.allowlistMode
+}
+
+// Check 检查IP是否允许访问，被拒绝时返回 ErrIPBlocked 或 ErrIPNotAllowed
+// 无法解析的IP不做封禁检查，但白名单模式下白名单非空时一律拒绝
+func (c *IPAccessControl) Check(ctx context.Context, ip string) error {
+	parsed := net.ParseIP(ip)
+	if parsed == nil && !c.allowlistMode {
+		return nil
+	}
+
+	rules, err := c.rules(ctx)
+	if err != nil {
+		return err
+	}
+	if parsed == nil {
+		if len(rules.allow) > 0 {
+			return ErrIPNotAllowed
+		}
+		return nil
+	}
+	if containsIP(rules.allow, parsed) {
+		return nil
+	}
+	if c.allowlistMode && len(rules.allow) > 0 {
+		return ErrIPNotAllowed
+	}
+	if containsIP(rules.block, parsed) {
+		return ErrIPBlocked
+	}
+
+	blocked, err := c.redis.Exists(ctx, ipBlockKey(parsed)).Result()
+	if err != nil {
+		return fmt.Errorf("查询IP临时封禁失败: %w", err)
+	}
+	if blocked > 0 {
+		return ErrIPBlocked
+	}
+	return nil
+}
+
+// IsAllowlisted 检查IP是否在白名单中，白名单中的IP不会被自动封禁
+func (c *IPAccessControl) IsAllowlisted(ctx context.Context, ip string) (bool, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false, nil
+	}
+
+	rules, err := c.rules(ctx)
+	if err != nil {
+		return false, err
+	}
+	return containsIP(rules.allow, parsed), nil
+}
+
+// BlockTemporarily 临时封禁单个IP，到期后由Redis自动移除
+func (c *IPAccessControl) BlockTemporarily(ctx context.Context, ip, reason, createdBy, createdByName string, duration time.Duration) (*model.IPRule, error) {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return nil, fmt.Errorf("invalid ip: %s", ip)
+	}
+	if duration <= 0 {
+		return nil, errors.New("duration must be positive")
+	}
+
+	ipNet, err := utils.ParseIPNet(parsed.String())
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	expiresAt := now.Add(duration)
+	rule := &model.IPRule{
+		Type:          constants.IPRuleTypeBlock,
+		CIDR:          ipNet.String(),
+		Reason:        reason,
+		CreatedBy:     createdBy,
+		CreatedByName: createdByName,
+		ExpiresAt:     &expiresAt,
+		CreatedAt:     now,
+	}
+
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return nil, fmt.Errorf("序列化IP临时封禁失败: %w", err)
+	}
+	if err := c.redis.Set(ctx, ipBlockKey(parsed), data, duration).Err(); err != nil {
+		return nil, fmt.Errorf("保存IP临时封禁失败: %w", err)
+	}
+	return rule, nil
+}
+
+// TemporaryBlocks 获取当前全部临时封禁
+func (c *IPAccessControl) TemporaryBlocks(ctx context.Context) ([]*model.IPRule, error) {
+	pattern := fmt.Sprintf(constants.CacheKeyIPBlock, "*")
+	var rules []*model.IPRule
+
+	iter := c.redis.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		data, err := c.redis.Get(ctx, iter.Val()).Bytes()
+		if err == redis.Nil {
+			continue // 扫描期间已过期
+		}
+		if err != nil {
+			return nil, fmt.Errorf("查询IP临时封禁失败: %w", err)
+		}
+
+		var rule model.IPRule
+		if err := json.Unmarshal(data, &rule); err != nil {
+			return nil, fmt.Errorf("解析IP临时封禁失败: %w", err)
+		}
+		rules = append(rules, &rule)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("查询IP临时封禁失败: %w", err)
+	}
+
+	return rules, nil
+}
+
+// Unblock 解除单个IP的临时封禁，同时清零该IP的登录失败次数，返回是否存在临时封禁
+func (c *IPAccessControl) Unblock(ctx context.Context, ip string) (bool, error) {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return false, fmt.Errorf("invalid ip: %s", ip)
+	}
+
+	deleted, err := c.redis.Del(ctx, ipBlockKey(parsed), fmt.Sprintf(constants.CacheKeyLoginIPFail, parsed.String())).Result()
+	if err != nil {
+		return false, fmt.Errorf("解除IP临时封禁失败: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+// Invalidate 永久规则变更后清除本地缓存
+func (c *IPAccessControl) Invalidate() {
+	c.cache.Del(ipRulesCacheKey)
+}
+
+// rules 获取解析后的永久规则，带本地缓存
+func (c *IPAccessControl) rules(ctx context.Context) (*ipRuleSet, error) {
+	value, err := c.cache.Take(ipRulesCacheKey, func() (any, error) {
+		stored, err := c.ipRuleDAO.List(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+
+		rules := &ipRuleSet{}
+		for _, rule := range stored {
+			ipNet, err := utils.ParseIPNet(rule.CIDR)
+			if err != nil {
+				continue // 忽略无法解析的历史数据
+			}
+			switch rule.Type {
+			case constants.IPRuleTypeBlock:
+				rules.block = append(rules.block, ipNet)
+			case constants.IPRuleTypeAllow:
+				rules.allow = append(rules.allow, ipNet)
+			}
+		}
+		return rules, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查询IP访问规则失败: %w", err)
+	}
+	return value.(*ipRuleSet), nil
+}
+
+// ipBlockKey 临时封禁在Redis中的键，使用规范化后的IP字符串
+func ipBlockKey(ip net.IP) string {
+	return fmt.Sprintf(constants.CacheKeyIPBlock, ip.String())
+}
+
+// containsIP 检查IP是否属于任一网段
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bytedance/mockey"
+	"github.com/go-redis/redis/v8"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+)
+
+func TestIPAccessControl(t *testing.T) {
+	mockey.PatchConvey("Test IPAccessControl", t, func() {
+		ctx := context.Background()
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+
+		stored := []*model.IPRule{
+			{Type: constants.IPRuleTypeBlock, CIDR: "203.0.113.0/24"},
+			{Type: constants.IPRuleTypeAllow, CIDR: "203.0.113.10/32"},
+		}
+		reads := 0
+		mockey.Mock((*dao.IPRuleDAO).List).To(func(_ *dao.IPRuleDAO, _ context.Context, _ string) ([]*model.IPRule, error) {
+			reads++
+			return stored, nil
+		}).Build()
+
+		access, err := NewIPAccessControl(&dao.IPRuleDAO{}, rdb, false)
+		So(err, ShouldBeNil)
+
+		Convey("Should block CIDR ranges but let allowlisted IPs through", func() {
+			So(access.Check(ctx, "203.0.113.55"), ShouldEqual, ErrIPBlocked)
+			So(access.Check(ctx, "203.0.113.10"), ShouldBeNil)
+			So(access.Check(ctx, "198.51.100.1"), ShouldBeNil)
+			So(access.Check(ctx, ""), ShouldBeNil)
+			So(reads, ShouldEqual, 1)
+		})
+
+		Convey("Allowlist mode should reject IPs outside the allowlist", func() {
+			strict, err := NewIPAccessControl(&dao.IPRuleDAO{}, rdb, true)
+			So(err, ShouldBeNil)
+			So(strict.Check(ctx, "198.51.100.1"), ShouldEqual, ErrIPNotAllowed)
+			So(strict.Check(ctx, "203.0.113.10"), ShouldBeNil)
+			So(strict.Check(ctx, ""), ShouldEqual, ErrIPNotAllowed)
+			So(strict.Check(ctx, "unknown"), ShouldEqual, ErrIPNotAllowed)
+
+			// 白名单为空时不生效，避免锁死管理后台
+			stored = stored[:1]
+			So(strict.Check(ctx, "198.51.100.1"), ShouldEqual, ErrIPNotAllowed)
+			strict.Invalidate()
+			So(strict.Check(ctx, "198.51.100.1"), ShouldBeNil)
+		})
+
+		Convey("Temporary blocks should expire and be listed", func() {
+			rule, err := access.BlockTemporarily(ctx, "198.51.100.7", "登录失败次数过多", constants.IPRuleCreatorSystem, "", time.Minute)
+			So(err, ShouldBeNil)
+			So(rule.CIDR, ShouldEqual, "198.51.100.7/32")
+			So(access.Check(ctx, "198.51.100.7"), ShouldEqual, ErrIPBlocked)
+
+			blocks, err := access.TemporaryBlocks(ctx)
+			So(err, ShouldBeNil)
+			So(blocks, ShouldHaveLength, 1)
+			So(blocks[0].Reason, ShouldEqual, "登录失败次数过多")
+			So(blocks[0].IsTemporary(), ShouldBeTrue)
+
+			mr.FastForward(time.Minute)
+			So(access.Check(ctx, "198.51.100.7"), ShouldBeNil)
+		})
+
+		Convey("Unblock should also clear the login failure counter", func() {
+			_, err := access.BlockTemporarily(ctx, "2001:db8::1", "manual", "u1", "admin", time.Hour)
+			So(err, ShouldBeNil)
+			mr.Set(fmt.Sprintf(constants.CacheKeyLoginIPFail, "2001:db8::1"), "20")
+
+			unblocked, err := access.Unblock(ctx, "2001:0db8::1")
+			So(err, ShouldBeNil)
+			So(unblocked, ShouldBeTrue)
+			So(access.Check(ctx, "2001:db8::1"), ShouldBeNil)
+			So(mr.Exists(fmt.Sprintf(constants.CacheKeyLoginIPFail, "2001:db8::1")), ShouldBeFalse)
+
+			unblocked, err = access.Unblock(ctx, "2001:db8::1")
+			So(err, ShouldBeNil)
+			So(unblocked, ShouldBeFalse)
+		})
+	})
+}
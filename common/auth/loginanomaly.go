@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"github.com/heimdall-api/common/client/geoip"
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+// minImpossibleTravelKm 判定异地旅行的最小距离，GeoIP坐标误差可达数百公里，过近的距离不做判断
+const minImpossibleTravelKm = 500
+
+// DetectLoginAnomalies 将本次成功登录与用户最近的成功登录比较，返回异常类型
+// history 按登录时间倒序排列；没有历史记录的首次登录无法比较，不做标记
+// maxTravelSpeed 为正常出行的最大速度（公里/小时），为0时不检测异地旅行
+func DetectLoginAnomalies(current *model.LoginLog, history []*model.LoginLog, maxTravelSpeed float64) []string {
+	if current == nil || len(history) == 0 {
+		return nil
+	}
+
+	var anomalies []string
+	if isNewCountry(current, history) {
+		anomalies = append(anomalies, constants.LoginAnomalyNewCountry)
+	}
+	if isNewDevice(current, history) {
+		anomalies = append(anomalies, constants.LoginAnomalyNewDevice)
+	}
+	if maxTravelSpeed > 0 && isImpossibleTravel(current, history, maxTravelSpeed) {
+		anomalies = append(anomalies, constants.LoginAnomalyImpossibleTravel)
+	}
+	return anomalies
+}
+
+// isNewCountry 本次登录的国家从未出现在历史记录中
+// 历史记录都没有国家信息时（如刚启用GeoIP）不做判断
+func isNewCountry(current *model.LoginLog, history []*model.LoginLog) bool {
+	if current.Country == "" {
+		return false
+	}
+
+	known := false
+	for _, log := range history {
+		if log.Country == "" {
+			continue
+		}
+		if log.Country == current.Country {
+			return false
+		}
+		known = true
+	}
+	return known
+}
+
+// isNewDevice 本次登录的设备类型、浏览器和操作系统组合从未出现在历史记录中
+// 只比较名称不比较版本，浏览器和系统升级不算新设备
+func isNewDevice(current *model.LoginLog, history []*model.LoginLog) bool {
+	key := deviceKey(current.UserAgent)
+	if key == "" {
+		return false
+	}
+
+	for _, log := range history {
+		if deviceKey(log.UserAgent) == key {
+			return false
+		}
+	}
+	return true
+}
+
+// deviceKey 由User-Agent生成设备标识，无法识别浏览器和操作系统时返回空
+func deviceKey(userAgent string) string {
+	ua := utils.ParseUserAgent(userAgent)
+	if ua.Browser == "" && ua.OS == "" {
+		return ""
+	}
+	return ua.DeviceType + "|" + ua.Browser + "|" + ua.OS
+}
+
+// isImpossibleTravel 与上一次有坐标的登录相比，移动速度超出正常出行速度
+func isImpossibleTravel(current *model.LoginLog, history []*model.LoginLog, maxTravelSpeed float64) bool {
+	if !current.HasCoordinates() {
+		return false
+	}
+
+	for _, previous := range history {
+		if !previous.HasCoordinates() || previous.LoginAt.After(current.LoginAt) {
+			continue
+		}
+
+		distance := geoip.DistanceKm(previous.Latitude, previous.Longitude, current.Latitude, current.Longitude)
+		if distance < minImpossibleTravelKm {
+			return false
+		}
+		hours := current.LoginAt.Sub(previous.LoginAt).Hours()
+		return hours <= 0 || distance/hours > maxTravelSpeed
+	}
+	return false
+}
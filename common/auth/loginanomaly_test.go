@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+)
+
+const (
+	chromeWindowsUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	chromeWindowsV2 = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"
+	safariIPhoneUA  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+)
+
+func TestDetectLoginAnomalies(t *testing.T) {
+	Convey("DetectLoginAnomalies Tests", t, func() {
+		now := time.Now()
+		london := &model.LoginLog{Country: "GB", UserAgent: chromeWindowsUA, Latitude: 51.5074, Longitude: -0.1278, LoginAt: now.Add(-2 * time.Hour)}
+		history := []*model.LoginLog{london}
+
+		Convey("First login has nothing to compare with", func() {
+			So(DetectLoginAnomalies(london, nil, 900), ShouldBeEmpty)
+		})
+
+		Convey("A familiar login should not be flagged", func() {
+			current := &model.LoginLog{Country: "GB", UserAgent: chromeWindowsV2, Latitude: 51.45, Longitude: -0.97, LoginAt: now}
+			So(DetectLoginAnomalies(current, history, 900), ShouldBeEmpty)
+		})
+
+		Convey("Should flag a new country and a new device", func() {
+			current := &model.LoginLog{Country: "FR", UserAgent: safariIPhoneUA, LoginAt: now}
+			So(DetectLoginAnomalies(current, history, 900), ShouldResemble, []string{
+				constants.LoginAnomalyNewCountry,
+				constants.LoginAnomalyNewDevice,
+			})
+		})
+
+		Convey("Should flag travel faster than the configured speed", func() {
+			// 伦敦到北京约8140公里，2小时内无法到达
+			beijing := &model.LoginLog{Country: "CN", UserAgent: chromeWindowsUA, Latitude: 39.9042, Longitude: 116.4074, LoginAt: now}
+			So(DetectLoginAnomalies(beijing, history, 900), ShouldResemble, []string{
+				constants.LoginAnomalyNewCountry,
+				constants.LoginAnomalyImpossibleTravel,
+			})
+
+			// 间隔足够长时不算异常
+			beijing.LoginAt = now.Add(12 * time.Hour)
+			So(DetectLoginAnomalies(beijing, history, 900), ShouldResemble, []string{constants.LoginAnomalyNewCountry})
+
+			// 速度为0时不检测
+			beijing.LoginAt = now
+			So(DetectLoginAnomalies(beijing, history, 0), ShouldResemble, []string{constants.LoginAnomalyNewCountry})
+		})
+
+		Convey("Missing location data should not produce alerts", func() {
+			history := []*model.LoginLog{{UserAgent: chromeWindowsUA, LoginAt: now.Add(-time.Hour)}}
+			current := &model.LoginLog{Country: "US", UserAgent: chromeWindowsUA, Latitude: 37.77, Longitude: -122.42, LoginAt: now}
+			So(DetectLoginAnomalies(current, history, 900), ShouldBeEmpty)
+
+			current = &model.LoginLog{UserAgent: "unknown", LoginAt: now}
+			So(DetectLoginAnomalies(current, history, 900), ShouldBeEmpty)
+		})
+	})
+}
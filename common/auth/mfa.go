@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/collection"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+)
+
+// mfaPolicyCacheExpire 两步验证策略本地缓存时间，认证中间件每个请求都会读取策略
+const mfaPolicyCacheExpire = 30 * time.Second
+
+// MFAPolicy 两步验证策略，记录哪些角色必须启用两步验证
+// 策略保存在站点设置中，由所有者维护
+type MFAPolicy struct {
+	settingDAO *dao.SettingDAO
+	cache      *collection.Cache
+}
+
+// NewMFAPolicy 创建两步验证策略
+func NewMFAPolicy(settingDAO *dao.SettingDAO) (*MFAPolicy, error) {
+	cache, err := collection.NewCache(mfaPolicyCacheExpire)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mfa policy cache: %w", err)
+	}
+
+	return &MFAPolicy{
+		settingDAO: settingDAO,
+		cache:      cache,
+	}, nil
+}
+
+// RequiredRoles 获取必须启用两步验证的角色列表
+func (p *MFAPolicy) RequiredRoles(ctx context.Context) ([]string, error) {
+	value, err := p.cache.Take(constants.SettingKeyMFARequiredRoles, func() (any, error) {
+		setting, err := p.settingDAO.GetByKey(ctx, constants.SettingKeyMFARequiredRoles)
+		if err != nil {
+			return nil, err
+		}
+		if setting == nil {
+			return []string{}, nil
+		}
+		return constants.SplitSettingList(setting.Value), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查询两步验证策略失败: %w", err)
+	}
+	return value.([]string), nil
+}
+
+// Requires 检查角色是否必须启用两步验证
+func (p *MFAPolicy) Requires(ctx context.Context, role string) (bool, error) {
+	roles, err := p.RequiredRoles(ctx)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(roles, role), nil
+}
+
+// SetRequiredRoles 更新必须启用两步验证的角色列表
+func (p *MFAPolicy) SetRequiredRoles(ctx context.Context, roles []string) error {
+	value := constants.JoinSettingList(roles)
+	if err := p.settingDAO.Set(ctx, constants.SettingKeyMFARequiredRoles, value, constants.SettingGroupSecurity); err != nil {
+		return fmt.Errorf("保存两步验证策略失败: %w", err)
+	}
+	p.cache.Del(constants.SettingKeyMFARequiredRoles)
+	return nil
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/model"
+)
+
+func TestMFAPolicy(t *testing.T) {
+	mockey.PatchConvey("Test MFAPolicy", t, func() {
+		ctx := context.Background()
+		policy, err := NewMFAPolicy(&dao.SettingDAO{})
+		So(err, ShouldBeNil)
+
+		stored := "owner, admin"
+		reads := 0
+		var readErr error
+		mockey.Mock((*dao.SettingDAO).GetByKey).To(func(_ *dao.SettingDAO, _ context.Context, key string) (*model.Setting, error) {
+			reads++
+			if readErr != nil {
+				return nil, readErr
+			}
+			So(key, ShouldEqual, constants.SettingKeyMFARequiredRoles)
+			if stored == "" {
+				return nil, nil
+			}
+			return &model.Setting{Key: key, Value: stored}, nil
+		}).Build()
+		mockey.Mock((*dao.SettingDAO).Set).To(func(_ *dao.SettingDAO, _ context.Context, key, value, group string) error {
+			So(group, ShouldEqual, constants.SettingGroupSecurity)
+			stored = value
+			return nil
+		}).Build()
+
+		Convey("Should parse and cache required roles", func() {
+			roles, err := policy.RequiredRoles(ctx)
+			So(err, ShouldBeNil)
+			So(roles, ShouldResemble, []string{constants.UserRoleOwner, constants.UserRoleAdmin})
+
+			required, err := policy.Requires(ctx, constants.UserRoleAdmin)
+			So(err, ShouldBeNil)
+			So(required, ShouldBeTrue)
+
+			required, err = policy.Requires(ctx, constants.UserRoleAuthor)
+			So(err, ShouldBeNil)
+			So(required, ShouldBeFalse)
+			So(reads, ShouldEqual, 1)
+		})
+
+		Convey("Should invalidate cache on update", func() {
+			_, _ = policy.RequiredRoles(ctx)
+			So(policy.SetRequiredRoles(ctx, []string{constants.UserRoleEditor}), ShouldBeNil)
+
+			roles, err := policy.RequiredRoles(ctx)
+			So(err, ShouldBeNil)
+			So(roles, ShouldResemble, []string{constants.UserRoleEditor})
+			So(reads, ShouldEqual, 2)
+		})
+
+		Convey("Should treat missing setting as no requirement", func() {
+			stored = ""
+			required, err := policy.Requires(ctx, constants.UserRoleOwner)
+			So(err, ShouldBeNil)
+			So(required, ShouldBeFalse)
+		})
+
+		Convey("Should return error when storage fails", func() {
+			readErr = errors.New("db down")
+			_, err := policy.Requires(ctx, constants.UserRoleOwner)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+// ErrUnauthenticated 请求上下文中没有认证主体
+var ErrUnauthenticated = errors.New("用户未认证")
+
+// principalKey 认证主体在context中的键
+type principalKey struct{}
+
+// Principal 已认证的请求主体，由认证中间件从访问令牌解析后写入context
+type Principal struct {
+	UserID        string    // 用户ID
+	Username      string    // 用户名
+	Role          string    // 用户角色
+	TokenID       string    // 访问令牌ID (jti)
+	SessionID     string    // 登录会话ID (sid)
+	ExpiresAt     time.Time // 访问令牌过期时间
+	AccessTokenID string    // 个人访问令牌ID，使用JWT登录时为空
+	Scopes        []string  // 个人访问令牌作用域
+}
+
+// NewPrincipal 根据JWT声明创建认证主体
+func NewPrincipal(claims *utils.JWTClaims) *Principal {
+	principal := &Principal{
+		UserID:    claims.UserID,
+		Username:  claims.Username,
+		Role:      claims.Role,
+		TokenID:   claims.TokenID,
+		SessionID: claims.SessionID,
+	}
+	if claims.ExpiresAt != nil {
+		principal.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return principal
+}
+
+// NewAccessTokenPrincipal 根据个人访问令牌创建认证主体，角色取用户当前角色
+func NewAccessTokenPrincipal(user *model.User, token *model.AccessToken) *Principal {
+	principal := &Principal{
+		UserID:        user.ID.Hex(),
+		Username:      user.Username,
+		Role:          user.Role,
+		AccessTokenID: token.ID.Hex(),
+		Scopes:        token.Scopes,
+	}
+	if token.ExpiresAt != nil {
+		principal.ExpiresAt = *token.ExpiresAt
+	}
+	return principal
+}
+
+// WithPrincipal 将认证主体写入context
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext 从context获取认证主体，不存在时返回 ErrUnauthenticated
+func FromContext(ctx context.Context) (*Principal, error) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	if !ok || principal == nil || principal.UserID == "" {
+		return nil, ErrUnauthenticated
+	}
+	return principal, nil
+}
+
+// IsAccessToken 是否通过个人访问令牌认证
+func (p *Principal) IsAccessToken() bool {
+	return p.AccessTokenID != ""
+}
+
+// HasPermission 检查是否可以执行该动作（不考虑资源归属）
+func (p *Principal) HasPermission(permission string) bool {
+	return p.scopeAllows(permission) && constants.HasPermission(p.Role, permission)
+}
+
+// CanAccess 检查是否可以对归属于ownerID的资源执行该动作
+func (p *Principal) CanAccess(permission, ownerID string) bool {
+	return p.scopeAllows(permission) && constants.HasPermissionOn(p.Role, permission, p.UserID, ownerID)
+}
+
+// scopeAllows 个人访问令牌只能执行作用域内的动作，JWT登录不受作用域限制
+func (p *Principal) scopeAllows(permission string) bool {
+	if !p.IsAccessToken() {
+		return true
+	}
+	return constants.ScopesAllow(p.Scopes, permission)
+}
+
+// TokenRemaining 访问令牌剩余有效时间
+func (p *Principal) TokenRemaining() time.Duration {
+	if p.ExpiresAt.IsZero() {
+		return 0
+	}
+	if remaining := time.Until(p.ExpiresAt); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/utils"
+)
+
+func TestPrincipal(t *testing.T) {
+	Convey("Test Principal", t, func() {
+		expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+		claims := &utils.JWTClaims{
+			UserID:    "64b7f0c2a1b2c3d4e5f60718",
+			Username:  "author",
+			Role:      constants.UserRoleAuthor,
+			TokenID:   "token-id",
+			SessionID: "session-id",
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+			},
+		}
+
+		Convey("Should build principal from claims", func() {
+			principal := NewPrincipal(claims)
+
+			So(principal.UserID, ShouldEqual, claims.UserID)
+			So(principal.Username, ShouldEqual, "author")
+			So(principal.Role, ShouldEqual, constants.UserRoleAuthor)
+			So(principal.TokenID, ShouldEqual, "token-id")
+			So(principal.SessionID, ShouldEqual, "session-id")
+			So(principal.ExpiresAt, ShouldEqual, expiresAt)
+			So(principal.TokenRemaining(), ShouldBeGreaterThan, 59*time.Minute)
+		})
+
+		Convey("Should round trip through context", func() {
+			ctx := WithPrincipal(context.Background(), NewPrincipal(claims))
+
+			principal, err := FromContext(ctx)
+			So(err, ShouldBeNil)
+			So(principal.UserID, ShouldEqual, claims.UserID)
+		})
+
+		Convey("Missing or empty principal should be unauthenticated", func() {
+			_, err := FromContext(context.Background())
+			So(err, ShouldEqual, ErrUnauthenticated)
+
+			_, err = FromContext(WithPrincipal(context.Background(), &Principal{}))
+			So(err, ShouldEqual, ErrUnauthenticated)
+		})
+
+		Convey("Should check permissions with ownership", func() {
+			principal := NewPrincipal(claims)
+
+			So(principal.HasPermission(constants.PermissionPostDelete), ShouldBeTrue)
+			So(principal.HasPermission(constants.PermissionUserList), ShouldBeFalse)
+			So(principal.CanAccess(constants.PermissionPostDelete, claims.UserID), ShouldBeTrue)
+			So(principal.CanAccess(constants.PermissionPostDelete, "someone-else"), ShouldBeFalse)
+		})
+
+		Convey("Access token principal should be limited to its scopes", func() {
+			user := &model.User{ID: primitive.NewObjectID(), Username: "deploy", Role: constants.UserRoleEditor}
+			token := &model.AccessToken{ID: primitive.NewObjectID(), Scopes: []string{constants.ScopeReadPosts}}
+			principal := NewAccessTokenPrincipal(user, token)
+
+			So(principal.IsAccessToken(), ShouldBeTrue)
+			So(principal.UserID, ShouldEqual, user.ID.Hex())
+			So(principal.HasPermission(constants.PermissionPostRead), ShouldBeTrue)
+			So(principal.HasPermission(constants.PermissionPostUpdate), ShouldBeFalse)
+			So(principal.CanAccess(constants.PermissionPostUpdate, user.ID.Hex()), ShouldBeFalse)
+			So(principal.HasPermission(constants.PermissionAuthSelf), ShouldBeFalse)
+
+			// 作用域不能超出角色权限
+			principal.Scopes = []string{constants.ScopeAdminUsers}
+			So(principal.HasPermission(constants.PermissionUserList), ShouldBeFalse)
+		})
+
+		Convey("Expired token should have no remaining time", func() {
+			principal := &Principal{UserID: "u1", ExpiresAt: time.Now().Add(-time.Minute)}
+			So(principal.TokenRemaining(), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/utils"
+)
+
+// tokenRevokedKey 生成用户令牌失效水位线缓存键
+func tokenRevokedKey(userID string) string {
+	return fmt.Sprintf(constants.CacheKeyTokenRevoked, userID)
+}
+
+// RevokeUserTokens 设置用户令牌失效水位线（Unix毫秒），此前签发的所有访问令牌和刷新令牌立即失效
+func RevokeUserTokens(ctx context.Context, rdb redis.Cmdable, userID string) error {
+	// 水位线只需保留到此前签发的刷新令牌全部过期
+	if err := rdb.Set(ctx, tokenRevokedKey(userID), time.Now().UnixMilli(), utils.RefreshTokenExpiration).Err(); err != nil {
+		return fmt.Errorf("设置令牌失效水位线失败: %w", err)
+	}
+	return nil
+}
+
+// TokensRevokedBefore 获取用户令牌失效水位线（Unix毫秒），未设置时返回0
+func TokensRevokedBefore(ctx context.Context, rdb redis.Cmdable, userID string) (int64, error) {
+	val, err := rdb.Get(ctx, tokenRevokedKey(userID)).Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("查询令牌失效水位线失败: %w", err)
+	}
+
+	revokedBefore, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("解析令牌失效水位线失败: %w", err)
+	}
+	return revokedBefore, nil
+}
+
+// IsTokenRevoked 检查令牌是否在水位线之前签发，按毫秒比较
+// 与水位线同一毫秒内签发的令牌无法区分先后，按已失效处理
+func IsTokenRevoked(claims *utils.JWTClaims, revokedBefore int64) bool {
+	if revokedBefore == 0 {
+		return false
+	}
+	issuedAt := claims.IssuedAtMillis()
+	if issuedAt == 0 {
+		return true
+	}
+	return issuedAt <= revokedBefore
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/golang-jwt/jwt/v4"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/utils"
+)
+
+func TestTokenRevocation(t *testing.T) {
+	Convey("Token revocation watermark", t, func() {
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		defer rdb.Close()
+		ctx := context.Background()
+
+		Convey("Unset watermark should not revoke anything", func() {
+			revokedBefore, err := TokensRevokedBefore(ctx, rdb, "u1")
+			So(err, ShouldBeNil)
+			So(revokedBefore, ShouldEqual, 0)
+			So(IsTokenRevoked(&utils.JWTClaims{IssuedMs: time.Now().UnixMilli()}, revokedBefore), ShouldBeFalse)
+		})
+
+		Convey("Tokens issued up to the watermark should be revoked", func() {
+			So(RevokeUserTokens(ctx, rdb, "u1"), ShouldBeNil)
+
+			revokedBefore, err := TokensRevokedBefore(ctx, rdb, "u1")
+			So(err, ShouldBeNil)
+			So(IsTokenRevoked(&utils.JWTClaims{IssuedMs: revokedBefore}, revokedBefore), ShouldBeTrue)
+			So(IsTokenRevoked(&utils.JWTClaims{IssuedMs: revokedBefore - time.Hour.Milliseconds()}, revokedBefore), ShouldBeTrue)
+			So(IsTokenRevoked(&utils.JWTClaims{}, revokedBefore), ShouldBeTrue)
+		})
+
+		Convey("Tokens issued later in the same second as the watermark should stay valid", func() {
+			watermark := time.Now().Truncate(time.Second).Add(100 * time.Millisecond)
+			issued := watermark.Add(500 * time.Millisecond)
+			claims := &utils.JWTClaims{
+				IssuedMs:         issued.UnixMilli(),
+				RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(issued)},
+			}
+
+			So(IsTokenRevoked(claims, watermark.UnixMilli()), ShouldBeFalse)
+		})
+
+		Convey("Tokens without the millisecond claim should fall back to the whole second", func() {
+			issued := time.Now().Truncate(time.Second).Add(500 * time.Millisecond)
+			claims := &utils.JWTClaims{RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(issued)}}
+
+			So(IsTokenRevoked(claims, issued.Add(-400*time.Millisecond).UnixMilli()), ShouldBeTrue)
+			So(IsTokenRevoked(claims, issued.Add(-time.Second).UnixMilli()), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,121 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// COSE算法标识（RFC 9053），注册时按此顺序声明偏好
+const (
+	AlgES256 int64 = -7   // ECDSA P-256 + SHA-256
+	AlgEdDSA int64 = -8   // Ed25519
+	AlgRS256 int64 = -257 // RSASSA-PKCS1-v1_5 + SHA-256
+)
+
+// SupportedAlgorithms 支持的公钥算法
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+// COSE密钥参数标签
+const (
+	coseKeyType   int64 = 1
+	coseAlgorithm int64 = 3
+	coseCurve     int64 = -1 // EC2/OKP曲线；RSA时为模数n
+	coseX         int64 = -2 // EC2/OKP的x坐标；RSA时为指数e
+	coseY         int64 = -3
+
+	coseKeyTypeOKP int64 = 1
+	coseKeyTypeEC2 int64 = 2
+	coseKeyTypeRSA int64 = 3
+
+	coseCurveP256    int64 = 1
+	coseCurveEd25519 int64 = 6
+)
+
+// ErrUnsupportedAlgorithm 不支持的公钥算法
+var ErrUnsupportedAlgorithm = errors.New("webauthn: unsupported public key algorithm")
+
+// publicKey 从COSE密钥解析出的公钥
+type publicKey struct {
+	algorithm int64
+	key       crypto.PublicKey
+}
+
+// parsePublicKey 解析COSE_Key编码的公钥
+func parsePublicKey(data []byte) (*publicKey, error) {
+	value, rest, err := decodeCBOR(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("webauthn: trailing data after public key")
+	}
+	return publicKeyFromMap(value)
+}
+
+// publicKeyFromMap 从已解码的COSE_Key映射构造公钥
+func publicKeyFromMap(value any) (*publicKey, error) {
+	m, ok := value.(map[any]any)
+	if !ok {
+		return nil, errors.New("webauthn: public key is not a map")
+	}
+	kty, _ := m[coseKeyType].(int64)
+	alg, _ := m[coseAlgorithm].(int64)
+
+	switch {
+	case alg == AlgES256 && kty == coseKeyTypeEC2:
+		crv, _ := m[coseCurve].(int64)
+		x, _ := m[coseX].([]byte)
+		y, _ := m[coseY].([]byte)
+		if crv != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("webauthn: invalid ES256 public key")
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("webauthn: ES256 point is not on curve")
+		}
+		return &publicKey{algorithm: alg, key: key}, nil
+
+	case alg == AlgEdDSA && kty == coseKeyTypeOKP:
+		crv, _ := m[coseCurve].(int64)
+		x, _ := m[coseX].([]byte)
+		if crv != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("webauthn: invalid EdDSA public key")
+		}
+		return &publicKey{algorithm: alg, key: ed25519.PublicKey(x)}, nil
+
+	case alg == AlgRS256 && kty == coseKeyTypeRSA:
+		n, _ := m[coseCurve].([]byte)
+		e, _ := m[coseX].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("webauthn: invalid RS256 public key")
+		}
+		exponent := int(new(big.Int).SetBytes(e).Int64())
+		return &publicKey{algorithm: alg, key: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: kty=%d alg=%d", ErrUnsupportedAlgorithm, kty, alg)
+	}
+}
+
+// verify 校验签名
+func (k *publicKey) verify(data, signature []byte) bool {
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return ecdsa.VerifyASN1(key, digest[:], signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, data, signature)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	default:
+		return false
+	}
+}
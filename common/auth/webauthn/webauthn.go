@@ -0,0 +1,391 @@
+// Package webauthn 实现WebAuthn（通行密钥）注册和断言仪式的服务端校验
+// 只请求 "none" 证明（attestation），不校验认证器的证明声明，
+// 支持ES256、EdDSA和RS256公钥，不依赖第三方库
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// 仪式类型（CollectedClientData.type）
+const (
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+)
+
+// 认证器数据标志位
+const (
+	flagUserPresent       byte = 0x01 // UP
+	flagUserVerified      byte = 0x04 // UV
+	flagBackupEligible    byte = 0x08 // BE
+	flagBackupState       byte = 0x10 // BS
+	flagAttestedCredsData byte = 0x40 // AT
+	flagExtensionData     byte = 0x80 // ED
+)
+
+const (
+	// challengeSize 挑战随机字节数
+	challengeSize = 32
+	// authDataMinLength 认证器数据最小长度：rpIdHash(32) + flags(1) + signCount(4)
+	authDataMinLength = 37
+	// maxCredentialIDLength 凭据ID最大长度
+	maxCredentialIDLength = 1023
+)
+
+var (
+	ErrInvalidResponse    = errors.New("webauthn: malformed response")
+	ErrCeremonyMismatch   = errors.New("webauthn: unexpected ceremony type")
+	ErrChallengeMismatch  = errors.New("webauthn: challenge mismatch")
+	ErrOriginMismatch     = errors.New("webauthn: origin not allowed")
+	ErrRPIDMismatch       = errors.New("webauthn: relying party id mismatch")
+	ErrUserNotPresent     = errors.New("webauthn: user presence flag not set")
+	ErrUserNotVerified    = errors.New("webauthn: user verification required")
+	ErrInvalidSignature   = errors.New("webauthn: invalid signature")
+	ErrSignCountRegressed = errors.New("webauthn: signature counter did not increase")
+)
+
+// Config 依赖方配置
+type Config struct {
+	RPID                    string   // 依赖方ID，通常为管理后台域名
+	RPName                  string   // 依赖方显示名称
+	Origins                 []string // 允许的来源，如 https://admin.example.com
+	RequireUserVerification bool     // 是否要求用户验证（PIN、生物识别）
+}
+
+// RelyingParty WebAuthn依赖方
+type RelyingParty struct {
+	config   Config
+	rpIDHash [32]byte
+}
+
+// New 创建依赖方
+func New(c Config) (*RelyingParty, error) {
+	if c.RPID == "" {
+		return nil, errors.New("webauthn: rp id is required")
+	}
+	if len(c.Origins) == 0 {
+		return nil, errors.New("webauthn: at least one origin is required")
+	}
+	return &RelyingParty{
+		config:   c,
+		rpIDHash: sha256.Sum256([]byte(c.RPID)),
+	}, nil
+}
+
+// ID 依赖方ID
+func (rp *RelyingParty) ID() string {
+	return rp.config.RPID
+}
+
+// Name 依赖方显示名称
+func (rp *RelyingParty) Name() string {
+	if rp.config.RPName == "" {
+		return rp.config.RPID
+	}
+	return rp.config.RPName
+}
+
+// UserVerification 返回选项中的userVerification取值
+func (rp *RelyingParty) UserVerification() string {
+	if rp.config.RequireUserVerification {
+		return "required"
+	}
+	return "preferred"
+}
+
+// NewChallenge 生成base64url编码的随机挑战
+func NewChallenge() (string, error) {
+	raw := make([]byte, challengeSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("webauthn: failed to generate challenge: %w", err)
+	}
+	return EncodeBase64URL(raw), nil
+}
+
+// EncodeBase64URL 按WebAuthn约定编码为无填充的base64url
+func EncodeBase64URL(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeBase64URL 解码base64url，兼容带填充的输入
+func DecodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// clientData 客户端收集的数据（CollectedClientData）
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// parseClientData 解码clientDataJSON
+func parseClientData(encoded string) (*clientData, []byte, error) {
+	raw, err := DecodeBase64URL(encoded)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: clientDataJSON", ErrInvalidResponse)
+	}
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, nil, fmt.Errorf("%w: clientDataJSON", ErrInvalidResponse)
+	}
+	return &data, raw, nil
+}
+
+// ClientChallenge 读取clientDataJSON中的挑战，用于在校验前查找服务端保存的挑战
+func ClientChallenge(clientDataJSON string) (string, error) {
+	data, _, err := parseClientData(clientDataJSON)
+	if err != nil {
+		return "", err
+	}
+	return data.Challenge, nil
+}
+
+// verifyClientData 校验仪式类型、挑战和来源
+func (rp *RelyingParty) verifyClientData(data *clientData, ceremony, challenge string) error {
+	if data.Type != ceremony {
+		return ErrCeremonyMismatch
+	}
+	if challenge == "" || strings.TrimRight(data.Challenge, "=") != strings.TrimRight(challenge, "=") {
+		return ErrChallengeMismatch
+	}
+	if !slices.Contains(rp.config.Origins, data.Origin) {
+		return ErrOriginMismatch
+	}
+	return nil
+}
+
+// authenticatorData 认证器数据
+type authenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte // COSE_Key原始编码
+}
+
+// parseAuthenticatorData 解析认证器数据，包含凭据数据时一并解析
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < authDataMinLength {
+		return nil, fmt.Errorf("%w: authenticator data too short", ErrInvalidResponse)
+	}
+	ad := &authenticatorData{
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	rest := data[authDataMinLength:]
+
+	if ad.flags&flagAttestedCredsData != 0 {
+		if len(rest) < 18 {
+			return nil, fmt.Errorf("%w: attested credential data too short", ErrInvalidResponse)
+		}
+		ad.aaguid = rest[:16]
+		idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLength == 0 || idLength > maxCredentialIDLength || len(rest) < idLength {
+			return nil, fmt.Errorf("%w: invalid credential id", ErrInvalidResponse)
+		}
+		ad.credentialID = rest[:idLength]
+		rest = rest[idLength:]
+
+		// 公钥之后可能紧跟扩展数据，根据解码消耗的长度截取公钥原始编码
+		_, after, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: credential public key", ErrInvalidResponse)
+		}
+		ad.publicKey = rest[:len(rest)-len(after)]
+		rest = after
+	}
+
+	if ad.flags&flagExtensionData != 0 {
+		_, after, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: extension data", ErrInvalidResponse)
+		}
+		rest = after
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing authenticator data", ErrInvalidResponse)
+	}
+	return ad, nil
+}
+
+// verifyAuthenticatorData 校验依赖方ID哈希和用户在场、用户验证标志
+func (rp *RelyingParty) verifyAuthenticatorData(ad *authenticatorData) error {
+	if !bytes.Equal(ad.rpIDHash, rp.rpIDHash[:]) {
+		return ErrRPIDMismatch
+	}
+	if ad.flags&flagUserPresent == 0 {
+		return ErrUserNotPresent
+	}
+	if rp.config.RequireUserVerification && ad.flags&flagUserVerified == 0 {
+		return ErrUserNotVerified
+	}
+	return nil
+}
+
+// RegistrationResponse 注册仪式中客户端返回的数据（AuthenticatorAttestationResponse）
+type RegistrationResponse struct {
+	ClientDataJSON    string // base64url
+	AttestationObject string // base64url
+}
+
+// Credential 注册成功的凭据
+type Credential struct {
+	ID                []byte
+	PublicKey         []byte // COSE_Key原始编码
+	Algorithm         int64
+	SignCount         uint32
+	AAGUID            []byte
+	AttestationFormat string
+	UserVerified      bool
+	BackupEligible    bool
+	BackupState       bool
+}
+
+// VerifyRegistration 校验注册仪式并返回新凭据
+// 依赖方请求的是 "none" 证明，因此只校验认证器数据，不信任也不校验证明声明
+func (rp *RelyingParty) VerifyRegistration(challenge string, resp *RegistrationResponse) (*Credential, error) {
+	if resp == nil {
+		return nil, ErrInvalidResponse
+	}
+
+	// 1. 校验客户端数据
+	data, _, err := parseClientData(resp.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.verifyClientData(data, ceremonyCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	// 2. 解码证明对象
+	raw, err := DecodeBase64URL(resp.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: attestationObject", ErrInvalidResponse)
+	}
+	decoded, _, err := decodeCBOR(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: attestationObject", ErrInvalidResponse)
+	}
+	attestation, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: attestationObject", ErrInvalidResponse)
+	}
+	format, _ := attestation["fmt"].(string)
+	authData, _ := attestation["authData"].([]byte)
+	if format == "" || authData == nil {
+		return nil, fmt.Errorf("%w: attestationObject", ErrInvalidResponse)
+	}
+
+	// 3. 校验认证器数据
+	ad, err := parseAuthenticatorData(authData)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.verifyAuthenticatorData(ad); err != nil {
+		return nil, err
+	}
+	if ad.credentialID == nil {
+		return nil, fmt.Errorf("%w: missing attested credential data", ErrInvalidResponse)
+	}
+
+	// 4. 解析公钥，只接受支持的算法
+	key, err := parsePublicKey(ad.publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:                append([]byte(nil), ad.credentialID...),
+		PublicKey:         append([]byte(nil), ad.publicKey...),
+		Algorithm:         key.algorithm,
+		SignCount:         ad.signCount,
+		AAGUID:            append([]byte(nil), ad.aaguid...),
+		AttestationFormat: format,
+		UserVerified:      ad.flags&flagUserVerified != 0,
+		BackupEligible:    ad.flags&flagBackupEligible != 0,
+		BackupState:       ad.flags&flagBackupState != 0,
+	}, nil
+}
+
+// AssertionResponse 断言仪式中客户端返回的数据（AuthenticatorAssertionResponse）
+type AssertionResponse struct {
+	ClientDataJSON    string // base64url
+	AuthenticatorData string // base64url
+	Signature         string // base64url
+}
+
+// AssertionResult 断言校验结果
+type AssertionResult struct {
+	SignCount    uint32
+	UserVerified bool
+	BackupState  bool
+}
+
+// VerifyAssertion 使用已保存的公钥校验断言
+// storedSignCount 为上次记录的签名计数，认证器支持计数时新计数必须大于旧值，否则可能是被克隆的认证器
+func (rp *RelyingParty) VerifyAssertion(challenge string, publicKeyCOSE []byte, storedSignCount uint32, resp *AssertionResponse) (*AssertionResult, error) {
+	if resp == nil {
+		return nil, ErrInvalidResponse
+	}
+
+	// 1. 校验客户端数据
+	data, clientDataRaw, err := parseClientData(resp.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.verifyClientData(data, ceremonyGet, challenge); err != nil {
+		return nil, err
+	}
+
+	// 2. 校验认证器数据
+	authDataRaw, err := DecodeBase64URL(resp.AuthenticatorData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: authenticatorData", ErrInvalidResponse)
+	}
+	ad, err := parseAuthenticatorData(authDataRaw)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.verifyAuthenticatorData(ad); err != nil {
+		return nil, err
+	}
+
+	// 3. 校验签名：签名对象为 authenticatorData || SHA-256(clientDataJSON)
+	signature, err := DecodeBase64URL(resp.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature", ErrInvalidResponse)
+	}
+	key, err := parsePublicKey(publicKeyCOSE)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(clientDataRaw)
+	signed := append(append([]byte(nil), authDataRaw...), clientDataHash[:]...)
+	if !key.verify(signed, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	// 4. 校验签名计数
+	if (ad.signCount != 0 || storedSignCount != 0) && ad.signCount <= storedSignCount {
+		return nil, ErrSignCountRegressed
+	}
+
+	return &AssertionResult{
+		SignCount:    ad.signCount,
+		UserVerified: ad.flags&flagUserVerified != 0,
+		BackupState:  ad.flags&flagBackupState != 0,
+	}, nil
+}
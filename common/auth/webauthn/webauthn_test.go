@@ -0,0 +1,224 @@
+package webauthn
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testdata 下的夹具由软件认证器录制，RP ID 为 admin.heimdall.test：
+//
+//	registration_*.json  注册仪式（attestation "none"），标志位 UP|UV|BE|AT
+//	assertion_*.json     同一凭据的断言仪式，签名计数为1
+type fixture struct {
+	RPID              string `json:"rpId"`
+	Origin            string `json:"origin"`
+	Challenge         string `json:"challenge"`
+	CredentialID      string `json:"credentialId"`
+	PublicKey         string `json:"publicKey"`
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AttestationObject string `json:"attestationObject"`
+	AuthenticatorData string `json:"authenticatorData"`
+	Signature         string `json:"signature"`
+	UserHandle        string `json:"userHandle"`
+	SignCount         uint32 `json:"signCount"`
+}
+
+func loadFixture(t *testing.T, name string) *fixture {
+	data, err := os.ReadFile("testdata/" + name + ".json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatalf("parse fixture: %v", err)
+	}
+	return &f
+}
+
+func newTestRP(f *fixture, requireUV bool) *RelyingParty {
+	rp, err := New(Config{RPID: f.RPID, RPName: "Heimdall", Origins: []string{f.Origin}, RequireUserVerification: requireUV})
+	So(err, ShouldBeNil)
+	return rp
+}
+
+func TestVerifyRegistration(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		alg  int64
+	}{{"es256", AlgES256}, {"eddsa", AlgEdDSA}} {
+		Convey("Verify registration "+tc.name, t, func() {
+			f := loadFixture(t, "registration_"+tc.name)
+			rp := newTestRP(f, true)
+			resp := &RegistrationResponse{ClientDataJSON: f.ClientDataJSON, AttestationObject: f.AttestationObject}
+
+			Convey("Should accept the recorded ceremony", func() {
+				cred, err := rp.VerifyRegistration(f.Challenge, resp)
+				So(err, ShouldBeNil)
+				So(EncodeBase64URL(cred.ID), ShouldEqual, f.CredentialID)
+				So(cred.Algorithm, ShouldEqual, tc.alg)
+				So(cred.AttestationFormat, ShouldEqual, "none")
+				So(cred.UserVerified, ShouldBeTrue)
+				So(cred.BackupEligible, ShouldBeTrue)
+				So(cred.SignCount, ShouldEqual, 0)
+
+				// 保存的公钥可以直接用于断言校验
+				_, err = parsePublicKey(cred.PublicKey)
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Should reject a different challenge", func() {
+				_, err := rp.VerifyRegistration("b3RoZXItY2hhbGxlbmdl", resp)
+				So(err, ShouldEqual, ErrChallengeMismatch)
+			})
+
+			Convey("Should reject an unknown origin", func() {
+				other, err := New(Config{RPID: f.RPID, Origins: []string{"https://evil.example"}})
+				So(err, ShouldBeNil)
+				_, err = other.VerifyRegistration(f.Challenge, resp)
+				So(err, ShouldEqual, ErrOriginMismatch)
+			})
+
+			Convey("Should reject a different RP ID", func() {
+				other, err := New(Config{RPID: "heimdall.test", Origins: []string{f.Origin}})
+				So(err, ShouldBeNil)
+				_, err = other.VerifyRegistration(f.Challenge, resp)
+				So(err, ShouldEqual, ErrRPIDMismatch)
+			})
+
+			Convey("Should reject assertion client data", func() {
+				assertion := loadFixture(t, "assertion_"+tc.name)
+				_, err := rp.VerifyRegistration(assertion.Challenge, &RegistrationResponse{
+					ClientDataJSON:    assertion.ClientDataJSON,
+					AttestationObject: f.AttestationObject,
+				})
+				So(err, ShouldEqual, ErrCeremonyMismatch)
+			})
+
+			Convey("Should reject a truncated attestation object", func() {
+				raw, _ := DecodeBase64URL(f.AttestationObject)
+				resp.AttestationObject = EncodeBase64URL(raw[:len(raw)-10])
+				_, err := rp.VerifyRegistration(f.Challenge, resp)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	}
+}
+
+func TestVerifyAssertion(t *testing.T) {
+	for _, name := range []string{"es256", "eddsa"} {
+		Convey("Verify assertion "+name, t, func() {
+			f := loadFixture(t, "assertion_"+name)
+			rp := newTestRP(f, true)
+			publicKey, err := DecodeBase64URL(f.PublicKey)
+			So(err, ShouldBeNil)
+			resp := &AssertionResponse{
+				ClientDataJSON:    f.ClientDataJSON,
+				AuthenticatorData: f.AuthenticatorData,
+				Signature:         f.Signature,
+			}
+
+			Convey("Should accept the recorded ceremony", func() {
+				challenge, err := ClientChallenge(f.ClientDataJSON)
+				So(err, ShouldBeNil)
+				So(challenge, ShouldEqual, f.Challenge)
+
+				assertion, err := rp.VerifyAssertion(f.Challenge, publicKey, 0, resp)
+				So(err, ShouldBeNil)
+				So(assertion.SignCount, ShouldEqual, f.SignCount)
+				So(assertion.UserVerified, ShouldBeTrue)
+			})
+
+			Convey("Should reject a replayed sign count", func() {
+				_, err := rp.VerifyAssertion(f.Challenge, publicKey, f.SignCount, resp)
+				So(err, ShouldEqual, ErrSignCountRegressed)
+			})
+
+			Convey("Should reject a tampered signature", func() {
+				sig, _ := DecodeBase64URL(f.Signature)
+				sig[len(sig)-1] ^= 0xff
+				resp.Signature = EncodeBase64URL(sig)
+				_, err := rp.VerifyAssertion(f.Challenge, publicKey, 0, resp)
+				So(err, ShouldEqual, ErrInvalidSignature)
+			})
+
+			Convey("Should reject tampered authenticator data", func() {
+				authData, _ := DecodeBase64URL(f.AuthenticatorData)
+				authData[len(authData)-1]++ // 修改签名计数
+				resp.AuthenticatorData = EncodeBase64URL(authData)
+				_, err := rp.VerifyAssertion(f.Challenge, publicKey, 0, resp)
+				So(err, ShouldEqual, ErrInvalidSignature)
+			})
+
+			Convey("Should reject a key from another credential", func() {
+				other := loadFixture(t, "assertion_es256")
+				if name == "es256" {
+					other = loadFixture(t, "assertion_eddsa")
+				}
+				otherKey, _ := DecodeBase64URL(other.PublicKey)
+				_, err := rp.VerifyAssertion(f.Challenge, otherKey, 0, resp)
+				So(err, ShouldEqual, ErrInvalidSignature)
+			})
+
+			Convey("Should reject a different challenge", func() {
+				_, err := rp.VerifyAssertion(strings.Repeat("A", 43), publicKey, 0, resp)
+				So(err, ShouldEqual, ErrChallengeMismatch)
+			})
+		})
+	}
+}
+
+func TestRelyingParty(t *testing.T) {
+	Convey("RelyingParty Tests", t, func() {
+		Convey("Should require RP ID and origins", func() {
+			_, err := New(Config{Origins: []string{"https://admin.heimdall.test"}})
+			So(err, ShouldNotBeNil)
+			_, err = New(Config{RPID: "admin.heimdall.test"})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Should expose options values", func() {
+			rp, err := New(Config{RPID: "admin.heimdall.test", Origins: []string{"https://admin.heimdall.test"}})
+			So(err, ShouldBeNil)
+			So(rp.Name(), ShouldEqual, "admin.heimdall.test")
+			So(rp.UserVerification(), ShouldEqual, "preferred")
+		})
+
+		Convey("Should generate unique challenges", func() {
+			a, err := NewChallenge()
+			So(err, ShouldBeNil)
+			b, _ := NewChallenge()
+			So(a, ShouldNotEqual, b)
+			raw, err := DecodeBase64URL(a)
+			So(err, ShouldBeNil)
+			So(raw, ShouldHaveLength, challengeSize)
+		})
+	})
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	Convey("CBOR decoder Tests", t, func() {
+		Convey("Should decode nested structures", func() {
+			// {"a": [1, -2, h'0102'], 3: true}
+			data := []byte{0xa2, 0x61, 'a', 0x83, 0x01, 0x21, 0x42, 0x01, 0x02, 0x03, 0xf5}
+			value, rest, err := decodeCBOR(data)
+			So(err, ShouldBeNil)
+			So(rest, ShouldBeEmpty)
+			m := value.(map[any]any)
+			So(m["a"], ShouldResemble, []any{int64(1), int64(-2), []byte{1, 2}})
+			So(m[int64(3)], ShouldEqual, true)
+		})
+
+		Convey("Should reject truncated and oversized input", func() {
+			_, _, err := decodeCBOR([]byte{0x5a, 0xff, 0xff, 0xff, 0xff})
+			So(err, ShouldNotBeNil)
+			_, _, err = decodeCBOR([]byte{0x9f}) // 不定长数组
+			So(err, ShouldNotBeNil)
+			_, _, err = decodeCBOR([]byte{0xa2, 0x01, 0x01, 0x01, 0x02}) // 重复键
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
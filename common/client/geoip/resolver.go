@@ -0,0 +1,174 @@
+package geoip
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/zeromicro/go-zero/core/collection"
+)
+
+const (
+	// defaultCacheSize 默认缓存的IP数量
+	defaultCacheSize = 10000
+	// defaultCacheExpire 缓存有效期，数据库更新后最多延迟一天生效
+	defaultCacheExpire = 24 * time.Hour
+	// defaultLanguage 默认地名语言
+	defaultLanguage = "en"
+	// earthRadiusKm 地球平均半径（公里）
+	earthRadiusKm = 6371.0
+)
+
+// Config GeoIP配置
+type Config struct {
+	DatabasePath string // MaxMind格式(.mmdb)数据库文件路径，为空时不启用
+	CacheSize    int    // LRU缓存容量
+	Language     string // 地名语言，如 en、zh-CN
+}
+
+// Location IP地理位置
+type Location struct {
+	Country string // 国家ISO代码，如 CN、US
+	Region  string // 省/州
+	City    string // 城市
+
+	Latitude  float64 // 纬度，数据库中没有坐标时为0
+	Longitude float64 // 经度，数据库中没有坐标时为0
+}
+
+// HasCoordinates 是否包含经纬度
+func (l Location) HasCoordinates() bool {
+	return l.Latitude != 0 || l.Longitude != 0
+}
+
+// cityRecord GeoIP2/GeoLite2 City 数据库记录中需要的字段
+type cityRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	Subdivisions []struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Location struct {
+		Latitude  float64 `maxminddb:"latitude"`
+		Longitude float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+}
+
+// Resolver 基于本地MaxMind数据库的离线IP地理位置解析器
+// 未配置数据库时返回空位置，nil Resolver同样可以安全调用
+type Resolver struct {
+	reader   *maxminddb.Reader
+	cache    *collection.Cache
+	language string
+}
+
+// NewResolver 创建GeoIP解析器，未配置数据库路径时返回不做解析的解析器
+func NewResolver(c Config) (*Resolver, error) {
+	if c.DatabasePath == "" {
+		return &Resolver{}, nil
+	}
+
+	reader, err := maxminddb.Open(c.DatabasePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open geoip database: %w", err)
+	}
+
+	cacheSize := c.CacheSize
+	if cacheSize <= 0 {
+		cacheSize = defaultCacheSize
+	}
+	cache, err := collection.NewCache(defaultCacheExpire, collection.WithLimit(cacheSize))
+	if err != nil {
+		reader.Close()
+		return nil, fmt.Errorf("failed to create geoip cache: %w", err)
+	}
+
+	language := c.Language
+	if language == "" {
+		language = defaultLanguage
+	}
+
+	return &Resolver{
+		reader:   reader,
+		cache:    cache,
+		language: language,
+	}, nil
+}
+
+// Enabled 是否已加载数据库
+func (r *Resolver) Enabled() bool {
+	return r != nil && r.reader != nil
+}
+
+// Lookup 查询IP地理位置，无法解析时返回空位置
+func (r *Resolver) Lookup(ip string) Location {
+	if !r.Enabled() {
+		return Location{}
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil || parsed.IsPrivate() || parsed.IsLoopback() || parsed.IsUnspecified() {
+		return Location{}
+	}
+
+	if cached, ok := r.cache.Get(ip); ok {
+		return cached.(Location)
+	}
+
+	var record cityRecord
+	location := Location{}
+	if err := r.reader.Lookup(parsed, &record); err == nil {
+		location = r.toLocation(&record)
+	}
+
+	// 未命中的结果同样缓存，避免重复查询
+	r.cache.Set(ip, location)
+	return location
+}
+
+// Close 关闭数据库
+func (r *Resolver) Close() error {
+	if !r.Enabled() {
+		return nil
+	}
+	return r.reader.Close()
+}
+
+// toLocation 将数据库记录转换为位置信息
+func (r *Resolver) toLocation(record *cityRecord) Location {
+	location := Location{
+		Country:   record.Country.ISOCode,
+		City:      r.localizedName(record.City.Names),
+		Latitude:  record.Location.Latitude,
+		Longitude: record.Location.Longitude,
+	}
+	if len(record.Subdivisions) > 0 {
+		location.Region = r.localizedName(record.Subdivisions[0].Names)
+	}
+	return location
+}
+
+// localizedName 按配置语言取地名，缺失时回退到英文
+func (r *Resolver) localizedName(names map[string]string) string {
+	if name, ok := names[r.language]; ok {
+		return name
+	}
+	return names[defaultLanguage]
+}
+
+// DistanceKm 使用半正矢公式计算两个经纬度之间的球面距离（公里）
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadians := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
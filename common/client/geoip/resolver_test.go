@@ -0,0 +1,93 @@
+package geoip
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testdata/GeoIP2-City-Test.mmdb 仅包含以下网段：
+//
+//	81.2.69.0/24    -> GB / England / London
+//	175.16.199.0/24 -> CN / Jilin Sheng / Changchun
+//	2001:480::/32   -> US / California / San Diego
+const testDatabase = "testdata/GeoIP2-City-Test.mmdb"
+
+func TestResolver_Lookup(t *testing.T) {
+	Convey("Resolver Lookup Tests", t, func() {
+		Convey("Should resolve IPv4 and IPv6 addresses", func() {
+			resolver, err := NewResolver(Config{DatabasePath: testDatabase})
+			So(err, ShouldBeNil)
+			defer resolver.Close()
+
+			So(resolver.Enabled(), ShouldBeTrue)
+			So(resolver.Lookup("81.2.69.142"), ShouldResemble, Location{Country: "GB", Region: "England", City: "London"})
+			So(resolver.Lookup("2001:480::1"), ShouldResemble, Location{Country: "US", Region: "California", City: "San Diego"})
+		})
+
+		Convey("Should use configured language with English fallback", func() {
+			resolver, err := NewResolver(Config{DatabasePath: testDatabase, Language: "zh-CN"})
+			So(err, ShouldBeNil)
+			defer resolver.Close()
+
+			So(resolver.Lookup("175.16.199.10"), ShouldResemble, Location{Country: "CN", Region: "吉林省", City: "长春"})
+
+			resolver.language = "ja"
+			So(resolver.Lookup("175.16.199.20").City, ShouldEqual, "Changchun")
+		})
+
+		Convey("Should cache lookup results", func() {
+			resolver, err := NewResolver(Config{DatabasePath: testDatabase, CacheSize: 2})
+			So(err, ShouldBeNil)
+			defer resolver.Close()
+
+			location := resolver.Lookup("81.2.69.142")
+			cached, ok := resolver.cache.Get("81.2.69.142")
+			So(ok, ShouldBeTrue)
+			So(cached, ShouldResemble, location)
+
+			resolver.Lookup("8.8.8.8")
+			cached, ok = resolver.cache.Get("8.8.8.8")
+			So(ok, ShouldBeTrue)
+			So(cached, ShouldResemble, Location{})
+		})
+
+		Convey("Should return empty location for unknown, private and invalid addresses", func() {
+			resolver, err := NewResolver(Config{DatabasePath: testDatabase})
+			So(err, ShouldBeNil)
+			defer resolver.Close()
+
+			So(resolver.Lookup("8.8.8.8"), ShouldResemble, Location{})
+			So(resolver.Lookup("192.168.1.1"), ShouldResemble, Location{})
+			So(resolver.Lookup("127.0.0.1"), ShouldResemble, Location{})
+			So(resolver.Lookup("unknown"), ShouldResemble, Location{})
+		})
+
+		Convey("Should degrade to empty location without database", func() {
+			resolver, err := NewResolver(Config{})
+			So(err, ShouldBeNil)
+			So(resolver.Enabled(), ShouldBeFalse)
+			So(resolver.Lookup("81.2.69.142"), ShouldResemble, Location{})
+			So(resolver.Close(), ShouldBeNil)
+
+			var nilResolver *Resolver
+			So(nilResolver.Lookup("81.2.69.142"), ShouldResemble, Location{})
+		})
+
+		Convey("Should return error for missing database file", func() {
+			resolver, err := NewResolver(Config{DatabasePath: "testdata/missing.mmdb"})
+			So(err, ShouldNotBeNil)
+			So(resolver, ShouldBeNil)
+		})
+	})
+}
+
+func TestDistanceKm(t *testing.T) {
+	Convey("DistanceKm Tests", t, func() {
+		// 伦敦 -> 北京 约8140公里
+		So(DistanceKm(51.5074, -0.1278, 39.9042, 116.4074), ShouldAlmostEqual, 8140, 20)
+		So(DistanceKm(51.5074, -0.1278, 51.5074, -0.1278), ShouldEqual, 0)
+		So(Location{}.HasCoordinates(), ShouldBeFalse)
+		So(Location{Latitude: 51.5, Longitude: -0.12}.HasCoordinates(), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,79 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// ErrInvalidMessage 邮件内容不完整或包含非法字符
+var ErrInvalidMessage = errors.New("invalid mail message")
+
+// Message 待发送的邮件
+type Message struct {
+	To      []string // 收件人地址
+	Subject string   // 主题
+	Text    string   // 纯文本正文
+	HTML    string   // HTML正文，可选；同时提供时以multipart/alternative发送
+}
+
+// Mailer 邮件发送接口，业务代码只依赖该接口，便于替换实现和测试
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Config 邮件发送配置
+type Config struct {
+	Host      string // SMTP服务器地址，为空时不发送邮件
+	Port      int    // SMTP端口，465使用隐式TLS，其他端口在服务器支持时使用STARTTLS
+	Username  string // 认证用户名，为空时不认证
+	Password  string // 认证密码
+	FromName  string // 发件人名称
+	FromEmail string // 发件人地址
+}
+
+// NewMailer 根据配置创建邮件发送器，未配置SMTP服务器时返回只记录日志的发送器
+func NewMailer(c Config) (Mailer, error) {
+	if c.Host == "" {
+		return NopMailer{}, nil
+	}
+	return NewSMTPMailer(c)
+}
+
+// NopMailer 不发送邮件，只记录日志，用于未配置SMTP的开发环境
+type NopMailer struct{}
+
+// Send 记录邮件收件人和主题后丢弃
+func (NopMailer) Send(ctx context.Context, msg *Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+	logx.WithContext(ctx).Infof("SMTP未配置，邮件未发送: to=%v, subject=%s", msg.To, msg.Subject)
+	return nil
+}
+
+// Validate 校验邮件收件人、主题和正文，拒绝可能导致头部注入的换行符
+func (m *Message) Validate() error {
+	if m == nil || len(m.To) == 0 {
+		return fmt.Errorf("%w: no recipients", ErrInvalidMessage)
+	}
+	for _, to := range m.To {
+		if strings.ContainsAny(to, "\r\n") {
+			return fmt.Errorf("%w: invalid recipient", ErrInvalidMessage)
+		}
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("%w: invalid recipient %q", ErrInvalidMessage, to)
+		}
+	}
+	if m.Subject == "" || strings.ContainsAny(m.Subject, "\r\n") {
+		return fmt.Errorf("%w: invalid subject", ErrInvalidMessage)
+	}
+	if m.Text == "" && m.HTML == "" {
+		return fmt.Errorf("%w: empty body", ErrInvalidMessage)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultSMTPPort 默认SMTP提交端口
+	defaultSMTPPort = 587
+	// implicitTLSPort 使用隐式TLS的SMTPS端口
+	implicitTLSPort = 465
+	// defaultSendTimeout 未设置截止时间时单封邮件的发送超时
+	defaultSendTimeout = 30 * time.Second
+)
+
+// SMTPMailer 基于SMTP协议的邮件发送器
+type SMTPMailer struct {
+	addr      string
+	host      string
+	port      int
+	username  string
+	password  string
+	from      mail.Address
+	tlsConfig *tls.Config
+}
+
+// NewSMTPMailer 创建SMTP邮件发送器
+func NewSMTPMailer(c Config) (*SMTPMailer, error) {
+	if c.Host == "" {
+		return nil, errors.New("smtp host cannot be empty")
+	}
+	if _, err := mail.ParseAddress(c.FromEmail); err != nil {
+		return nil, fmt.Errorf("invalid smtp from email: %w", err)
+	}
+
+	port := c.Port
+	if port <= 0 {
+		port = defaultSMTPPort
+	}
+
+	return &SMTPMailer{
+		addr:      net.JoinHostPort(c.Host, strconv.Itoa(port)),
+		host:      c.Host,
+		port:      port,
+		username:  c.Username,
+		password:  c.Password,
+		from:      mail.Address{Name: c.FromName, Address: c.FromEmail},
+		tlsConfig: &tls.Config{ServerName: c.Host, MinVersion: tls.VersionTLS12},
+	}, nil
+}
+
+// Send 发送邮件，ctx的截止时间同时作用于连接和整个SMTP会话
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	body, err := m.buildMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultSendTimeout)
+	}
+
+	conn, err := m.dial(ctx, deadline)
+	if err != nil {
+		return fmt.Errorf("failed to connect smtp server: %w", err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		return fmt.Errorf("failed to create smtp client: %w", err)
+	}
+	defer client.Close()
+
+	if err := m.send(client, msg.To, body); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// dial 建立到SMTP服务器的连接，465端口直接使用TLS
+func (m *SMTPMailer) dial(ctx context.Context, deadline time.Time) (net.Conn, error) {
+	dialer := &net.Dialer{Deadline: deadline}
+	if m.port == implicitTLSPort {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: m.tlsConfig}
+		return tlsDialer.DialContext(ctx, "tcp", m.addr)
+	}
+	return dialer.DialContext(ctx, "tcp", m.addr)
+}
+
+// send 执行SMTP会话：STARTTLS、认证、投递
+func (m *SMTPMailer) send(client *smtp.Client, to []string, body []byte) error {
+	if m.port != implicitTLSPort {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(m.tlsConfig); err != nil {
+				return fmt.Errorf("smtp starttls failed: %w", err)
+			}
+		}
+	}
+
+	if m.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.from.Address); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	for _, addr := range to {
+		parsed, _ := mail.ParseAddress(addr)
+		if err := client.Rcpt(parsed.Address); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s failed: %w", parsed.Address, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write mail body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp server rejected message: %w", err)
+	}
+	return nil
+}
+
+// buildMessage 构建MIME格式的邮件内容
+func (m *SMTPMailer) buildMessage(msg *Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", m.from.String())
+	header.Set("To", strings.Join(msg.To, ", "))
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Message-ID", m.messageID())
+	header.Set("MIME-Version", "1.0")
+
+	if msg.Text != "" && msg.HTML != "" {
+		mw := multipart.NewWriter(&buf)
+		header.Set("Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": mw.Boundary()}))
+		headerBytes := formatHeader(header)
+
+		if err := writePart(mw, "text/plain", msg.Text); err != nil {
+			return nil, err
+		}
+		if err := writePart(mw, "text/html", msg.HTML); err != nil {
+			return nil, err
+		}
+		if err := mw.Close(); err != nil {
+			return nil, err
+		}
+		return append(headerBytes, buf.Bytes()...), nil
+	}
+
+	contentType, content := "text/plain", msg.Text
+	if msg.HTML != "" {
+		contentType, content = "text/html", msg.HTML
+	}
+	header.Set("Content-Type", contentType+"; charset=UTF-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	buf.Write(formatHeader(header))
+	if err := writeQuotedPrintable(&buf, content); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// messageID 生成邮件的Message-ID
+func (m *SMTPMailer) messageID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	domain := m.host
+	if at := strings.LastIndex(m.from.Address, "@"); at >= 0 {
+		domain = m.from.Address[at+1:]
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
+
+// formatHeader 按固定顺序输出邮件头
+func formatHeader(header textproto.MIMEHeader) []byte {
+	var buf bytes.Buffer
+	for _, key := range []string{"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type", "Content-Transfer-Encoding"} {
+		if value := header.Get(key); value != "" {
+			fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+		}
+	}
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
+
+// writePart 写入multipart的一个正文部分
+func writePart(mw *multipart.Writer, contentType, content string) error {
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	return writeQuotedPrintable(part, content)
+}
+
+// writeQuotedPrintable 以quoted-printable编码写入正文
+func writeQuotedPrintable(w io.Writer, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
@@ -0,0 +1,244 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// receivedMail 假SMTP服务器收到的邮件
+type receivedMail struct {
+	Auth string // AUTH PLAIN 解码后的凭据
+	From string
+	To   []string
+	Data string
+}
+
+// fakeSMTPServer 本地假SMTP服务器，只实现发送邮件所需的最小命令集
+type fakeSMTPServer struct {
+	listener   net.Listener
+	rejectRcpt string // 拒绝该收件人
+
+	mu       sync.Mutex
+	received []receivedMail
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &fakeSMTPServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) config() Config {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return Config{
+		Host:      host,
+		Port:      p,
+		Username:  "mailer",
+		Password:  "secret",
+		FromName:  "Heimdall 博客",
+		FromEmail: "noreply@example.com",
+	}
+}
+
+func (s *fakeSMTPServer) messages() []receivedMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]receivedMail(nil), s.received...)
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	var current receivedMail
+	reply("220 localhost ESMTP fake")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(cmd, "AUTH PLAIN "):
+			decoded, _ := base64.StdEncoding.DecodeString(line[len("AUTH PLAIN "):])
+			current.Auth = string(decoded)
+			reply("235 2.7.0 Authentication successful")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			current.From = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			rcpt := strings.Trim(line[len("RCPT TO:"):], "<> ")
+			if rcpt == s.rejectRcpt {
+				reply("550 5.1.1 User unknown")
+				continue
+			}
+			current.To = append(current.To, rcpt)
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			current.Data = data.String()
+			s.mu.Lock()
+			s.received = append(s.received, current)
+			s.mu.Unlock()
+			current = receivedMail{Auth: current.Auth}
+			reply("250 OK: queued")
+		case cmd == "RSET", cmd == "NOOP":
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	Convey("SMTPMailer Send Tests", t, func() {
+		server := newFakeSMTPServer(t)
+		m, err := NewSMTPMailer(server.config())
+		So(err, ShouldBeNil)
+
+		Convey("Should deliver plain text mail with authentication", func() {
+			err := m.Send(context.Background(), &Message{
+				To:      []string{"writer@example.com"},
+				Subject: "重置密码",
+				Text:    "请点击链接重置密码",
+			})
+			So(err, ShouldBeNil)
+
+			received := server.messages()
+			So(len(received), ShouldEqual, 1)
+			So(received[0].Auth, ShouldEqual, "\x00mailer\x00secret")
+			So(received[0].From, ShouldEqual, "noreply@example.com")
+			So(received[0].To, ShouldResemble, []string{"writer@example.com"})
+
+			parsed, err := mail.ReadMessage(strings.NewReader(received[0].Data))
+			So(err, ShouldBeNil)
+			subject, _ := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+			So(subject, ShouldEqual, "重置密码")
+			from, _ := parsed.Header.AddressList("From")
+			So(from[0].Name, ShouldEqual, "Heimdall 博客")
+			So(parsed.Header.Get("Content-Type"), ShouldStartWith, "text/plain")
+		})
+
+		Convey("Should send multipart alternative when both bodies are set", func() {
+			err := m.Send(context.Background(), &Message{
+				To:      []string{"writer@example.com"},
+				Subject: "Welcome",
+				Text:    "plain body",
+				HTML:    "<p>html body</p>",
+			})
+			So(err, ShouldBeNil)
+
+			parsed, err := mail.ReadMessage(strings.NewReader(server.messages()[0].Data))
+			So(err, ShouldBeNil)
+			mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+			So(err, ShouldBeNil)
+			So(mediaType, ShouldEqual, "multipart/alternative")
+
+			reader := multipart.NewReader(parsed.Body, params["boundary"])
+			var bodies []string
+			for {
+				part, err := reader.NextPart()
+				if err != nil {
+					break
+				}
+				content, _ := io.ReadAll(part)
+				bodies = append(bodies, string(content))
+			}
+			So(bodies, ShouldResemble, []string{"plain body", "<p>html body</p>"})
+		})
+
+		Convey("Should surface recipient rejection", func() {
+			server.rejectRcpt = "ghost@example.com"
+			err := m.Send(context.Background(), &Message{
+				To:      []string{"ghost@example.com"},
+				Subject: "Hello",
+				Text:    "body",
+			})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "RCPT TO")
+			So(len(server.messages()), ShouldEqual, 0)
+		})
+
+		Convey("Should reject header injection before connecting", func() {
+			err := m.Send(context.Background(), &Message{
+				To:      []string{"writer@example.com"},
+				Subject: "Hello\r\nBcc: victim@example.com",
+				Text:    "body",
+			})
+			So(errors.Is(err, ErrInvalidMessage), ShouldBeTrue)
+
+			err = m.Send(context.Background(), &Message{To: []string{"not-an-email"}, Subject: "Hello", Text: "body"})
+			So(errors.Is(err, ErrInvalidMessage), ShouldBeTrue)
+		})
+
+		Convey("Should honour context deadline", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+			defer cancel()
+			time.Sleep(time.Millisecond)
+
+			err := m.Send(ctx, &Message{To: []string{"writer@example.com"}, Subject: "Hello", Text: "body"})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestNewMailer(t *testing.T) {
+	Convey("NewMailer should fall back to NopMailer without SMTP host", t, func() {
+		m, err := NewMailer(Config{})
+		So(err, ShouldBeNil)
+		So(m, ShouldHaveSameTypeAs, NopMailer{})
+		So(m.Send(context.Background(), &Message{To: []string{"a@example.com"}, Subject: "s", Text: "t"}), ShouldBeNil)
+
+		_, err = NewMailer(Config{Host: "smtp.example.com"})
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,42 @@
+package constants
+
+// AuditTarget 审计日志操作对象类型常量
+const (
+	AuditTargetUser               = "user"                // 用户（含当前用户自己的账号）
+	AuditTargetPost               = "post"                // 文章
+	AuditTargetPage               = "page"                // 页面
+	AuditTargetSession            = "session"             // 登录会话
+	AuditTargetAccessToken        = "access_token"        // 个人访问令牌
+	AuditTargetWebAuthnCredential = "webauthn_credential" // 通行密钥
+	AuditTargetInvitation         = "invitation"          // 邀请
+	AuditTargetIPRule             = "ip_rule"             // IP访问规则
+	AuditTargetSetting            = "setting"             // 站点设置
+)
+
+// AuditLogTTLIndexName 审计日志保留期限TTL索引名称，修改保留期限时按名称更新
+const AuditLogTTLIndexName = "createdAt_ttl"
+
+// GetAllAuditTargets 获取所有审计对象类型
+func GetAllAuditTargets() []string {
+	return []string{
+		AuditTargetUser,
+		AuditTargetPost,
+		AuditTargetPage,
+		AuditTargetSession,
+		AuditTargetAccessToken,
+		AuditTargetWebAuthnCredential,
+		AuditTargetInvitation,
+		AuditTargetIPRule,
+		AuditTargetSetting,
+	}
+}
+
+// IsValidAuditTarget 检查审计对象类型是否有效
+func IsValidAuditTarget(targetType string) bool {
+	for _, valid := range GetAllAuditTargets() {
+		if targetType == valid {
+			return true
+		}
+	}
+	return false
+}
@@ -45,6 +45,7 @@ const (
 	CacheKeyJWTBlacklist = "heimdall:auth:jwt:blacklist:%s" // JWT黑名单
 	CacheKeyRefreshToken = "heimdall:auth:refresh:%s"       // 刷新令牌
 	CacheKeyTokenUser    = "heimdall:auth:token:user:%s"    // 令牌对应的用户信息
+	CacheKeyTokenRevoked = "heimdall:auth:token:revoked:%s" // 用户令牌失效水位线，早于该时间签发的令牌全部失效
 
 	// 会话相关
 	CacheKeyUserSession  = "heimdall:session:user:%s:%s" // 用户会话: user_id:session_id
@@ -55,6 +56,18 @@ const (
 	CacheKeyLoginIPFail    = "heimdall:security:login:ip:%s"   // IP登录失败计数
 	CacheKeyUserLock       = "heimdall:security:lock:%s"       // 用户锁定状态
 	CacheKeyIPBlock        = "heimdall:security:block:ip:%s"   // IP封禁
+
+	// 两步验证与通行密钥相关
+	CacheKeyMFAChallenge     = "heimdall:auth:mfa:challenge:%s"     // 登录两步验证挑战: token_hash -> user_id
+	CacheKeyMFAEnroll        = "heimdall:auth:mfa:enroll:%s"        // 待确认的TOTP密钥: user_id -> secret
+	CacheKeyWebAuthnRegister = "heimdall:auth:webauthn:register:%s" // 通行密钥注册挑战: user_id -> challenge
+	CacheKeyWebAuthnLogin    = "heimdall:auth:webauthn:login:%s"    // 通行密钥登录挑战: challenge -> 1
+
+	// 密码重置相关
+	CacheKeyPasswordReset        = "heimdall:auth:password:reset:%s"            // 密码重置令牌: token_hash -> user_id
+	CacheKeyPasswordResetUser    = "heimdall:auth:password:reset:user:%s"       // 用户当前有效的重置令牌哈希
+	CacheKeyPasswordResetByEmail = "heimdall:security:ratelimit:reset:email:%s" // 按邮箱的重置请求计数
+	CacheKeyPasswordResetByIP    = "heimdall:security:ratelimit:reset:ip:%s"    // 按IP的重置请求计数
 )
 
 // ====================
@@ -199,6 +212,14 @@ const (
 	CacheTTLUserLock  = 24 * time.Hour   // 用户锁定状态缓存时间
 	CacheTTLIPBlock   = 1 * time.Hour    // IP封禁缓存时间
 	CacheTTLRateLimit = 1 * time.Minute  // 限流缓存时间
+
+	// 两步验证与通行密钥TTL
+	CacheTTLMFAChallenge      = 5 * time.Minute  // 登录两步验证挑战有效期
+	CacheTTLMFAEnroll         = 10 * time.Minute // TOTP绑定确认有效期
+	CacheTTLWebAuthnChallenge = 5 * time.Minute  // 通行密钥注册/登录挑战有效期
+
+	// 密码重置TTL
+	CacheTTLPasswordResetLimit = 1 * time.Hour // 密码重置请求限流窗口
 )
 
 // ====================
@@ -259,6 +280,8 @@ func GeneratePostCacheKey(keyType, identifier string) string {
 		return CacheKeyPostBySlug
 	case "html":
 		return CacheKeyPostHTML
+	case "toc":
+		return CacheKeyPostTOC
 	default:
 		return ""
 	}
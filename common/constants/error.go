@@ -68,6 +68,7 @@ const (
 	ErrInsufficientPermission = "E010012" // 权限不足
 	ErrCannotDeleteSelf       = "E010013" // 不能删除自己
 	ErrLastOwner              = "E010014" // 不能删除最后一个所有者
+	ErrMFASetupRequired       = "E010015" // 当前角色要求先启用两步验证
 
 	// 文章相关错误
 	ErrPostNotFound         = "E010101" // 文章不存在
@@ -186,18 +187,22 @@ var ErrorCodeToHTTPStatus = map[string]int{
 	ErrLocked:          423,
 	ErrRateLimit:       429,
 	ErrTooManyRequests: 429,
+	ErrIPBlocked:       403,
 
 	// Admin API错误
-	ErrUserNotFound:    404,
-	ErrInvalidPassword: 401,
-	ErrUserLocked:      423,
-	ErrUsernameExists:  409,
-	ErrEmailExists:     409,
-	ErrPostNotFound:    404,
-	ErrPostSlugExists:  409,
-	ErrCommentNotFound: 404,
-	ErrMediaNotFound:   404,
-	ErrFileTooLarge:    413,
+	ErrUserNotFound:     404,
+	ErrInvalidPassword:  401,
+	ErrUserLocked:       423,
+	ErrMFASetupRequired: 403,
+	ErrUsernameExists:   409,
+	ErrEmailExists:      409,
+	ErrCannotDeleteSelf: 403,
+	ErrLastOwner:        409,
+	ErrPostNotFound:     404,
+	ErrPostSlugExists:   409,
+	ErrCommentNotFound:  404,
+	ErrMediaNotFound:    404,
+	ErrFileTooLarge:     413,
 
 	// Public API错误
 	ErrPostNotPublished:  404,
@@ -0,0 +1,142 @@
+package constants
+
+// Permission 权限动作常量，格式为 资源:操作
+const (
+	// 认证相关，所有已登录用户均可访问
+	PermissionAuthSelf = "auth:self" // 访问自己的账号信息、登出等
+
+	// 文章管理
+	PermissionPostList      = "post:list"      // 查看文章列表
+	PermissionPostRead      = "post:read"      // 查看文章详情
+	PermissionPostCreate    = "post:create"    // 创建文章
+	PermissionPostUpdate    = "post:update"    // 更新文章
+	PermissionPostDelete    = "post:delete"    // 删除文章
+	PermissionPostPublish   = "post:publish"   // 发布文章
+	PermissionPostUnpublish = "post:unpublish" // 取消发布文章
+
+	// 页面管理
+	PermissionPageList      = "page:list"      // 查看页面列表
+	PermissionPageRead      = "page:read"      // 查看页面详情
+	PermissionPageCreate    = "page:create"    // 创建页面
+	PermissionPageUpdate    = "page:update"    // 更新页面
+	PermissionPageDelete    = "page:delete"    // 删除页面
+	PermissionPagePublish   = "page:publish"   // 发布页面
+	PermissionPageUnpublish = "page:unpublish" // 取消发布页面
+
+	// 用户管理
+	PermissionUserList          = "user:list"           // 查看用户列表
+	PermissionUserRead          = "user:read"           // 查看用户详情
+	PermissionUserCreate        = "user:create"         // 创建用户
+	PermissionUserUpdate        = "user:update"         // 更新用户资料
+	PermissionUserRoleChange    = "user:role"           // 修改用户角色
+	PermissionUserStatusChange  = "user:status"         // 暂停、激活、解锁用户
+	PermissionUserDelete        = "user:delete"         // 删除用户
+	PermissionUserInvite        = "user:invite"         // 邀请用户、管理邀请
+	PermissionUserSessionRevoke = "user:session:revoke" // 吊销用户会话
+
+	// 安全管理
+	PermissionLoginLogList      = "security:login-log:list"    // 查看登录日志
+	PermissionMFAPolicyManage   = "security:mfa-policy:manage" // 管理两步验证策略
+	PermissionIPRuleList        = "security:ip-rule:list"      // 查看IP封禁和白名单
+	PermissionIPRuleManage      = "security:ip-rule:manage"    // 添加、删除IP封禁和白名单
+	PermissionSecurityAlertList = "security:alert:list"        // 查看异常登录告警
+	PermissionAuditLogList      = "security:audit-log:list"    // 查看操作审计日志
+
+	// 站点设置
+	PermissionSettingManage = "setting:manage" // 管理站点设置
+)
+
+// PermissionRule 权限规则
+// AllRoles 中的角色可以操作任意资源，OwnRoles 中的角色只能操作归属于自己的资源
+type PermissionRule struct {
+	AllRoles []string // 不受资源归属限制的角色
+	OwnRoles []string // 仅能操作自己资源的角色
+}
+
+var (
+	// adminRoles 管理员及以上角色
+	adminRoles = []string{UserRoleOwner, UserRoleAdmin}
+	// editorRoles 编辑及以上角色
+	editorRoles = []string{UserRoleOwner, UserRoleAdmin, UserRoleEditor}
+	// authorRoles 作者角色
+	authorRoles = []string{UserRoleAuthor}
+	// allRoles 所有角色
+	allRoles = []string{UserRoleOwner, UserRoleAdmin, UserRoleEditor, UserRoleAuthor}
+)
+
+// permissionRules 权限表：权限动作 -> 允许的角色及归属规则
+var permissionRules = map[string]PermissionRule{
+	PermissionAuthSelf: {AllRoles: allRoles},
+
+	PermissionPostList:      {AllRoles: allRoles},
+	PermissionPostRead:      {AllRoles: allRoles},
+	PermissionPostCreate:    {AllRoles: allRoles},
+	PermissionPostUpdate:    {AllRoles: editorRoles, OwnRoles: authorRoles},
+	PermissionPostDelete:    {AllRoles: editorRoles, OwnRoles: authorRoles},
+	PermissionPostPublish:   {AllRoles: editorRoles, OwnRoles: authorRoles},
+	PermissionPostUnpublish: {AllRoles: editorRoles, OwnRoles: authorRoles},
+
+	PermissionPageList:      {AllRoles: allRoles},
+	PermissionPageRead:      {AllRoles: allRoles},
+	PermissionPageCreate:    {AllRoles: allRoles},
+	PermissionPageUpdate:    {AllRoles: editorRoles, OwnRoles: authorRoles},
+	PermissionPageDelete:    {AllRoles: editorRoles, OwnRoles: authorRoles},
+	PermissionPagePublish:   {AllRoles: editorRoles, OwnRoles: authorRoles},
+	PermissionPageUnpublish: {AllRoles: editorRoles, OwnRoles: authorRoles},
+
+	PermissionUserList:          {AllRoles: adminRoles},
+	PermissionUserRead:          {AllRoles: adminRoles, OwnRoles: []string{UserRoleEditor, UserRoleAuthor}},
+	PermissionUserCreate:        {AllRoles: adminRoles},
+	PermissionUserUpdate:        {AllRoles: adminRoles},
+	PermissionUserRoleChange:    {AllRoles: adminRoles},
+	PermissionUserStatusChange:  {AllRoles: adminRoles},
+	PermissionUserDelete:        {AllRoles: adminRoles},
+	PermissionUserInvite:        {AllRoles: adminRoles},
+	PermissionUserSessionRevoke: {AllRoles: adminRoles},
+
+	PermissionLoginLogList:      {AllRoles: adminRoles},
+	PermissionMFAPolicyManage:   {AllRoles: []string{UserRoleOwner}},
+	PermissionIPRuleList:        {AllRoles: adminRoles},
+	PermissionIPRuleManage:      {AllRoles: adminRoles},
+	PermissionSecurityAlertList: {AllRoles: adminRoles},
+	PermissionAuditLogList:      {AllRoles: adminRoles},
+
+	PermissionSettingManage: {AllRoles: adminRoles},
+}
+
+// GetPermissionRule 获取权限规则
+func GetPermissionRule(permission string) (PermissionRule, bool) {
+	rule, ok := permissionRules[permission]
+	return rule, ok
+}
+
+// HasPermission 检查角色是否可以执行该动作（不考虑资源归属）
+func HasPermission(role, permission string) bool {
+	rule, ok := permissionRules[permission]
+	if !ok {
+		return false
+	}
+	return containsRole(rule.AllRoles, role) || containsRole(rule.OwnRoles, role)
+}
+
+// HasPermissionOn 检查角色是否可以对归属于ownerID的资源执行该动作
+func HasPermissionOn(role, permission, userID, ownerID string) bool {
+	rule, ok := permissionRules[permission]
+	if !ok {
+		return false
+	}
+	if containsRole(rule.AllRoles, role) {
+		return true
+	}
+	return containsRole(rule.OwnRoles, role) && userID != "" && userID == ownerID
+}
+
+// containsRole 检查角色是否在列表中
+func containsRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
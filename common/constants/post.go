@@ -39,6 +39,7 @@ const (
 	PostSlugMinLength         = 1       // Slug最小长度
 	PostSlugMaxLength         = 255     // Slug最大长度
 	PostExcerptMaxLength      = 500     // 摘要最大长度
+	PostExcerptDefaultLength  = 200     // 自动生成摘要的长度
 	PostContentMaxLength      = 1000000 // 内容最大长度（1MB）
 	PostMetaTitleMaxLength    = 70      // SEO标题最大长度
 	PostMetaDescMaxLength     = 160     // SEO描述最大长度
@@ -49,9 +50,16 @@ const (
 
 // ReadingTime 阅读时间相关常量
 const (
-	ReadingSpeedWordsPerMinute = 200 // 平均阅读速度（每分钟字数）
-	ReadingTimeMin             = 1   // 最小阅读时间（分钟）
-	ReadingTimeMax             = 999 // 最大阅读时间（分钟）
+	ReadingSpeedWordsPerMinute    = 200 // 平均阅读速度（每分钟字数）
+	ReadingSpeedCJKCharsPerMinute = 300 // 中日文平均阅读速度（每分钟字数）
+	ReadingTimeMin                = 1   // 最小阅读时间（分钟）
+	ReadingTimeMax                = 999 // 最大阅读时间（分钟）
+)
+
+// TOC 文章目录相关常量
+const (
+	TOCMaxDepthDefault = 3 // 默认目录层数，从文章中最高一级标题算起
+	TOCMaxDepthLimit   = 6 // 目录层数上限，与标题级别数一致
 )
 
 // PostLimits 文章数量限制常量
@@ -165,20 +173,3 @@ func IsPublishedStatus(status string) bool {
 func IsPublicVisible(visibility string) bool {
 	return visibility == PostVisibilityPublic
 }
-
-// CalculateReadingTime 计算阅读时间（分钟）
-func CalculateReadingTime(wordCount int) int {
-	if wordCount <= 0 {
-		return ReadingTimeMin
-	}
-
-	readingTime := wordCount / ReadingSpeedWordsPerMinute
-	if readingTime < ReadingTimeMin {
-		return ReadingTimeMin
-	}
-	if readingTime > ReadingTimeMax {
-		return ReadingTimeMax
-	}
-
-	return readingTime
-}
@@ -0,0 +1,46 @@
+package constants
+
+// IPRuleType IP访问规则类型常量
+const (
+	IPRuleTypeBlock = "block" // 封禁
+	IPRuleTypeAllow = "allow" // 白名单
+)
+
+// IPRuleCreatorSystem 系统自动添加的IP规则的创建者
+const IPRuleCreatorSystem = "system"
+
+// IPRuleMaxReasonLength IP规则原因的最大长度
+const IPRuleMaxReasonLength = 200
+
+// IsValidIPRuleType 验证IP访问规则类型是否有效
+func IsValidIPRuleType(ruleType string) bool {
+	return ruleType == IPRuleTypeBlock || ruleType == IPRuleTypeAllow
+}
+
+// LoginAnomaly 登录异常类型常量
+const (
+	LoginAnomalyNewCountry       = "new_country"       // 从未登录过的国家
+	LoginAnomalyNewDevice        = "new_device"        // 从未使用过的设备和浏览器组合
+	LoginAnomalyImpossibleTravel = "impossible_travel" // 与上次登录的距离和间隔超出正常出行速度
+)
+
+// GetAllLoginAnomalies 获取所有登录异常类型
+func GetAllLoginAnomalies() []string {
+	return []string{LoginAnomalyNewCountry, LoginAnomalyNewDevice, LoginAnomalyImpossibleTravel}
+}
+
+// IsValidLoginAnomaly 验证登录异常类型是否有效
+func IsValidLoginAnomaly(anomaly string) bool {
+	for _, valid := range GetAllLoginAnomalies() {
+		if anomaly == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// LoginStatsGroupBy 登录统计时间序列的分组粒度
+const (
+	LoginStatsGroupByDay  = "day"  // 按天
+	LoginStatsGroupByHour = "hour" // 按小时
+)
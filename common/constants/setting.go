@@ -0,0 +1,51 @@
+package constants
+
+import "strings"
+
+// SettingGroup 站点设置分组常量
+const (
+	SettingGroupGeneral  = "general"  // 基本设置
+	SettingGroupDisplay  = "display"  // 显示设置
+	SettingGroupSEO      = "seo"      // SEO设置
+	SettingGroupSocial   = "social"   // 社交媒体
+	SettingGroupComments = "comments" // 评论设置
+	SettingGroupSecurity = "security" // 安全设置
+)
+
+// SettingKey 站点设置键常量
+const (
+	SettingKeyMFARequiredRoles = "mfaRequiredRoles" // 要求启用两步验证的角色，逗号分隔
+	SettingKeySlugStrategy     = "slugStrategy"     // 文章、页面和标签的slug生成策略
+)
+
+// SlugStrategy slug生成策略常量
+const (
+	SlugStrategyPinyin  = "pinyin"  // 汉字转为拼音，拉丁字母去除变音符号
+	SlugStrategyASCII   = "ascii"   // 拉丁字母去除变音符号，其他文字丢弃
+	SlugStrategyUnicode = "unicode" // 保留各种文字，URL中按百分号编码
+
+	SlugStrategyDefault = SlugStrategyPinyin // 未设置时使用的策略
+)
+
+// SlugFallbackPrefix 文本中没有可用字符时，生成的slug使用的前缀，后接Unix时间戳
+const (
+	SlugFallbackPrefixPost = "post" // 文章
+	SlugFallbackPrefixPage = "page" // 页面
+	SlugFallbackPrefixTag  = "tag"  // 标签
+)
+
+// SplitSettingList 解析逗号分隔的设置值，去除空白和空项
+func SplitSettingList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// JoinSettingList 将列表编码为逗号分隔的设置值
+func JoinSettingList(items []string) string {
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,90 @@
+package constants
+
+// AccessTokenPrefix 个人访问令牌前缀，中间件据此区分个人访问令牌和JWT
+const AccessTokenPrefix = "hpat_"
+
+// AccessToken 个人访问令牌限制常量
+const (
+	AccessTokenMaxPerUser       = 20  // 单用户最多持有的有效令牌数
+	AccessTokenMaxExpireDays    = 365 // 令牌最长有效期(天)
+	AccessTokenNameMaxLength    = 50  // 令牌名称最大长度
+	AccessTokenUsageIntervalSec = 60  // 最后使用信息的最小更新间隔(秒)
+)
+
+// AccessTokenScope 个人访问令牌作用域常量，写权限包含对应的读权限
+const (
+	ScopeReadPosts    = "read:posts"    // 查看文章
+	ScopeWritePosts   = "write:posts"   // 创建、修改、发布文章
+	ScopeReadPages    = "read:pages"    // 查看页面
+	ScopeWritePages   = "write:pages"   // 创建、修改、发布页面
+	ScopeReadUsers    = "read:users"    // 查看用户
+	ScopeAdminUsers   = "admin:users"   // 管理用户
+	ScopeReadSecurity = "read:security" // 查看登录日志等安全信息
+)
+
+// scopePermissions 作用域 -> 允许的权限动作
+// 令牌的实际权限是用户角色权限与令牌作用域的交集，auth:self 不属于任何作用域，令牌不能管理账号自身
+var scopePermissions = map[string][]string{
+	ScopeReadPosts: {PermissionPostList, PermissionPostRead},
+	ScopeWritePosts: {
+		PermissionPostList, PermissionPostRead, PermissionPostCreate, PermissionPostUpdate,
+		PermissionPostDelete, PermissionPostPublish, PermissionPostUnpublish,
+	},
+	ScopeReadPages: {PermissionPageList, PermissionPageRead},
+	ScopeWritePages: {
+		PermissionPageList, PermissionPageRead, PermissionPageCreate, PermissionPageUpdate,
+		PermissionPageDelete, PermissionPagePublish, PermissionPageUnpublish,
+	},
+	ScopeReadUsers: {PermissionUserList, PermissionUserRead},
+	ScopeAdminUsers: {
+		PermissionUserList, PermissionUserRead, PermissionUserCreate, PermissionUserUpdate,
+		PermissionUserRoleChange, PermissionUserStatusChange, PermissionUserDelete, PermissionUserInvite,
+		PermissionUserSessionRevoke,
+	},
+	ScopeReadSecurity: {PermissionLoginLogList, PermissionIPRuleList, PermissionSecurityAlertList, PermissionAuditLogList},
+}
+
+// GetAllAccessTokenScopes 返回所有作用域
+func GetAllAccessTokenScopes() []string {
+	return []string{
+		ScopeReadPosts,
+		ScopeWritePosts,
+		ScopeReadPages,
+		ScopeWritePages,
+		ScopeReadUsers,
+		ScopeAdminUsers,
+		ScopeReadSecurity,
+	}
+}
+
+// IsValidAccessTokenScope 检查作用域是否有效
+func IsValidAccessTokenScope(scope string) bool {
+	_, ok := scopePermissions[scope]
+	return ok
+}
+
+// ScopesAllow 检查作用域集合是否包含该权限动作
+func ScopesAllow(scopes []string, permission string) bool {
+	for _, scope := range scopes {
+		for _, p := range scopePermissions[scope] {
+			if p == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RoleHasScope 检查角色能否使用该作用域，角色需拥有作用域中的全部权限（含仅限自己资源的权限）
+func RoleHasScope(role, scope string) bool {
+	permissions, ok := scopePermissions[scope]
+	if !ok {
+		return false
+	}
+	for _, permission := range permissions {
+		if !HasPermission(role, permission) {
+			return false
+		}
+	}
+	return true
+}
@@ -8,6 +8,14 @@ const (
 	UserRoleAuthor = "author" // 作者，只能管理自己创建的内容
 )
 
+// roleLevels 角色等级，数值越大权限越高
+var roleLevels = map[string]int{
+	UserRoleOwner:  4,
+	UserRoleAdmin:  3,
+	UserRoleEditor: 2,
+	UserRoleAuthor: 1,
+}
+
 // UserStatus 用户状态常量
 const (
 	UserStatusActive    = "active"    // 正常状态
@@ -16,12 +24,37 @@ const (
 	UserStatusSuspended = "suspended" // 暂停状态
 )
 
+// InvitationStatus 邀请状态常量，过期由 expiresAt 判断，不单独记录状态
+const (
+	InvitationStatusPending  = "pending"  // 待接受
+	InvitationStatusAccepted = "accepted" // 已接受
+	InvitationStatusRevoked  = "revoked"  // 已撤销
+)
+
 // LoginStatus 登录状态常量
 const (
 	LoginStatusSuccess = "success" // 登录成功
 	LoginStatusFailed  = "failed"  // 登录失败
 )
 
+// LoginMethod 登录方式常量
+const (
+	LoginMethodUsername     = "username"      // 用户名密码登录
+	LoginMethodEmail        = "email"         // 邮箱密码登录
+	LoginMethodRefreshToken = "refresh_token" // 刷新令牌续期
+	LoginMethodTOTP         = "totp"          // 密码+TOTP两步验证登录
+	LoginMethodRecoveryCode = "recovery_code" // 密码+恢复码两步验证登录
+	LoginMethodWebAuthn     = "webauthn"      // 通行密钥无密码登录
+)
+
+// DeviceType 登录设备类型常量
+const (
+	DeviceTypeDesktop = "desktop" // 桌面设备
+	DeviceTypeMobile  = "mobile"  // 手机
+	DeviceTypeTablet  = "tablet"  // 平板
+	DeviceTypeBot     = "bot"     // 爬虫或自动化工具
+)
+
 // LoginFailReason 登录失败原因常量
 const (
 	LoginFailReasonInvalidPassword = "invalid_password"  // 密码错误
@@ -30,6 +63,7 @@ const (
 	LoginFailReasonUserInactive    = "user_inactive"     // 账号未激活
 	LoginFailReasonUserSuspended   = "user_suspended"    // 账号被暂停
 	LoginFailReasonTooManyAttempts = "too_many_attempts" // 尝试次数过多
+	LoginFailReasonTokenReused     = "token_reused"      // 刷新令牌被重复使用
 )
 
 // AccountLockDuration 账号锁定时长（分钟）
@@ -48,6 +82,22 @@ const (
 	PasswordExpireDays   = 90 // 密码过期天数
 )
 
+// MFA 两步验证限制常量
+const (
+	MFAChallengeMaxAttempts = 5 // 单个两步验证挑战最多尝试次数
+)
+
+// PasswordReset 密码重置限制常量
+const (
+	PasswordResetMaxPerEmail = 3  // 每个邮箱每小时最多请求次数
+	PasswordResetMaxPerIP    = 10 // 每个IP每小时最多请求次数
+)
+
+// Invitation 邀请限制常量
+const (
+	InvitationResendIntervalSec = 60 // 同一邀请两次发送的最小间隔（秒）
+)
+
 // SessionLimits 会话限制常量
 const (
 	MaxConcurrentSessions = 3     // 单用户最大并发会话数
@@ -86,6 +136,18 @@ func GetAllUserStatuses() []string {
 	}
 }
 
+// GetAllLoginMethods 返回所有登录方式
+func GetAllLoginMethods() []string {
+	return []string{
+		LoginMethodUsername,
+		LoginMethodEmail,
+		LoginMethodRefreshToken,
+		LoginMethodTOTP,
+		LoginMethodRecoveryCode,
+		LoginMethodWebAuthn,
+	}
+}
+
 // IsValidUserRole 验证用户角色是否有效
 func IsValidUserRole(role string) bool {
 	validRoles := GetAllUserRoles()
@@ -108,6 +170,24 @@ func IsValidUserStatus(status string) bool {
 	return false
 }
 
+// GetRoleLevel 获取角色等级，无效角色返回0
+func GetRoleLevel(role string) int {
+	return roleLevels[role]
+}
+
+// CanManageRole 检查操作者角色能否管理目标角色的用户或授予目标角色
+// 所有者可以管理包括所有者在内的全部角色，其他角色只能管理等级低于自己的角色
+func CanManageRole(actorRole, targetRole string) bool {
+	actor, target := GetRoleLevel(actorRole), GetRoleLevel(targetRole)
+	if actor == 0 || target == 0 {
+		return false
+	}
+	if actorRole == UserRoleOwner {
+		return true
+	}
+	return actor > target
+}
+
 // GetLockDurationByFailCount 根据失败次数获取锁定时长（分钟）
 func GetLockDurationByFailCount(failCount int) int {
 	switch {
@@ -0,0 +1,130 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/common/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AccessTokenDAO 个人访问令牌数据访问层
+type AccessTokenDAO struct {
+	collection *mongo.Collection
+}
+
+// NewAccessTokenDAO 创建个人访问令牌DAO实例
+func NewAccessTokenDAO(database *mongo.Database) *AccessTokenDAO {
+	return &AccessTokenDAO{
+		collection: database.Collection("accessTokens"),
+	}
+}
+
+// Create 保存新令牌
+func (d *AccessTokenDAO) Create(ctx context.Context, token *model.AccessToken) error {
+	if token.UserID.IsZero() || token.TokenHash == "" {
+		return errors.New("access token is incomplete")
+	}
+
+	token.ID = primitive.NewObjectID()
+	token.CreatedAt = time.Now()
+
+	_, err := d.collection.InsertOne(ctx, token)
+	return err
+}
+
+// GetByHash 根据令牌哈希获取令牌，不存在时返回nil
+func (d *AccessTokenDAO) GetByHash(ctx context.Context, tokenHash string) (*model.AccessToken, error) {
+	if tokenHash == "" {
+		return nil, errors.New("token hash cannot be empty")
+	}
+
+	var token model.AccessToken
+	err := d.collection.FindOne(ctx, bson.M{"tokenHash": tokenHash}).Decode(&token)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// ListByUserID 获取用户未吊销的令牌，按创建时间倒序
+func (d *AccessTokenDAO) ListByUserID(ctx context.Context, userID primitive.ObjectID) ([]*model.AccessToken, error) {
+	filter := bson.M{"userId": userID, "revokedAt": bson.M{"$exists": false}}
+	opts := options.Find().SetSort(bson.D{bson.E{Key: "createdAt", Value: -1}})
+	cursor, err := d.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tokens []*model.AccessToken
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// CountActiveByUserID 统计用户当前有效（未吊销且未过期）的令牌数
+func (d *AccessTokenDAO) CountActiveByUserID(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	filter := bson.M{
+		"userId":    userID,
+		"revokedAt": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"expiresAt": bson.M{"$exists": false}},
+			bson.M{"expiresAt": bson.M{"$gt": time.Now()}},
+		},
+	}
+	return d.collection.CountDocuments(ctx, filter)
+}
+
+// Revoke 吊销用户的令牌，返回是否吊销成功
+func (d *AccessTokenDAO) Revoke(ctx context.Context, id, userID primitive.ObjectID) (bool, error) {
+	filter := bson.M{"_id": id, "userId": userID, "revokedAt": bson.M{"$exists": false}}
+	result, err := d.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"revokedAt": time.Now()}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// RecordUsage 记录最后使用时间和IP，距上次记录不足interval时跳过，避免每个请求都写库
+func (d *AccessTokenDAO) RecordUsage(ctx context.Context, id primitive.ObjectID, ip string, interval time.Duration) error {
+	now := time.Now()
+	filter := bson.M{
+		"_id": id,
+		"$or": bson.A{
+			bson.M{"lastUsedAt": bson.M{"$exists": false}},
+			bson.M{"lastUsedAt": bson.M{"$lte": now.Add(-interval)}},
+			bson.M{"lastUsedIp": bson.M{"$ne": ip}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"lastUsedAt": now, "lastUsedIp": ip}}
+
+	_, err := d.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// CreateIndexes 创建索引
+func (d *AccessTokenDAO) CreateIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{bson.E{Key: "tokenHash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{bson.E{Key: "userId", Value: 1}, bson.E{Key: "createdAt", Value: -1}},
+		},
+	}
+
+	_, err := d.collection.Indexes().CreateMany(ctx, indexes)
+	return err
+}
@@ -0,0 +1,70 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/heimdall-api/common/model"
+)
+
+func TestAccessTokenDAO(t *testing.T) {
+	Convey("AccessTokenDAO Tests", t, func() {
+		tokenDAO := &AccessTokenDAO{
+			collection: &mongo.Collection{},
+		}
+		ctx := context.Background()
+		userID := primitive.NewObjectID()
+
+		Convey("Create should reject token without hash", func() {
+			err := tokenDAO.Create(ctx, &model.AccessToken{UserID: userID})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("GetByHash should return nil when not found", func() {
+			mock1 := mockey.Mock((*mongo.Collection).FindOne).Return(&mongo.SingleResult{}).Build()
+			defer mock1.UnPatch()
+			mock2 := mockey.Mock((*mongo.SingleResult).Decode).Return(mongo.ErrNoDocuments).Build()
+			defer mock2.UnPatch()
+
+			token, err := tokenDAO.GetByHash(ctx, "missing")
+			So(err, ShouldBeNil)
+			So(token, ShouldBeNil)
+		})
+
+		Convey("Revoke should only touch the owner's active token", func() {
+			var gotFilter interface{}
+			mock := mockey.Mock((*mongo.Collection).UpdateOne).To(func(c *mongo.Collection, ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+				gotFilter = filter
+				return &mongo.UpdateResult{ModifiedCount: 0}, nil
+			}).Build()
+			defer mock.UnPatch()
+
+			id := primitive.NewObjectID()
+			revoked, err := tokenDAO.Revoke(ctx, id, userID)
+			So(err, ShouldBeNil)
+			So(revoked, ShouldBeFalse)
+			So(gotFilter, ShouldResemble, bson.M{"_id": id, "userId": userID, "revokedAt": bson.M{"$exists": false}})
+		})
+
+		Convey("RecordUsage should throttle by interval and IP", func() {
+			var gotFilter bson.M
+			mock := mockey.Mock((*mongo.Collection).UpdateOne).To(func(c *mongo.Collection, ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+				gotFilter = filter.(bson.M)
+				return &mongo.UpdateResult{}, nil
+			}).Build()
+			defer mock.UnPatch()
+
+			err := tokenDAO.RecordUsage(ctx, primitive.NewObjectID(), "203.0.113.10", time.Minute)
+			So(err, ShouldBeNil)
+			So(gotFilter["$or"], ShouldHaveLength, 3)
+		})
+	})
+}
@@ -0,0 +1,205 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoDB错误码：索引不存在、同名索引选项冲突、集合不存在
+const (
+	mongoErrIndexNotFound        = 27
+	mongoErrIndexOptionsConflict = 85
+	mongoErrNamespaceNotFound    = 26
+)
+
+// AuditLogDAO 审计日志数据访问层
+type AuditLogDAO struct {
+	collection *mongo.Collection
+}
+
+// NewAuditLogDAO 创建审计日志DAO实例
+func NewAuditLogDAO(database *mongo.Database) *AuditLogDAO {
+	return &AuditLogDAO{
+		collection: database.Collection("auditLogs"),
+	}
+}
+
+// Create 创建审计日志
+func (d *AuditLogDAO) Create(ctx context.Context, log *model.AuditLog) error {
+	if log == nil {
+		return errors.New("log cannot be nil")
+	}
+	if log.Action == "" || log.ActorID.IsZero() {
+		return errors.New("audit log is incomplete")
+	}
+
+	log.ID = primitive.NewObjectID()
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now()
+	}
+
+	_, err := d.collection.InsertOne(ctx, log)
+	return err
+}
+
+// List 分页查询审计日志，最新的在前
+func (d *AuditLogDAO) List(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*model.AuditLog, int64, error) {
+	// 参数验证
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	query := d.buildQueryFilter(filter)
+
+	total, err := d.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{bson.E{Key: "createdAt", Value: -1}})
+
+	cursor, err := d.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var logs []*model.AuditLog
+	if err := cursor.All(ctx, &logs); err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
+
+// buildQueryFilter 构建查询过滤条件
+// action 不含完整操作名时按前缀匹配，如 "user" 匹配 user.suspend、user.role.change
+func (d *AuditLogDAO) buildQueryFilter(filter map[string]interface{}) bson.M {
+	query := bson.M{}
+
+	for key, value := range filter {
+		switch key {
+		case "actorId":
+			if strID, ok := value.(string); ok && strID != "" {
+				if objectID, err := primitive.ObjectIDFromHex(strID); err == nil {
+					query["actorId"] = objectID
+				}
+			}
+		case "action":
+			if action, ok := value.(string); ok && action != "" {
+				query["action"] = bson.M{"$regex": "^" + regexp.QuoteMeta(action) + `(\.|$)`}
+			}
+		case "targetType", "targetId":
+			if s, ok := value.(string); ok && s != "" {
+				query[key] = s
+			}
+		case "startTime":
+			if startTime, ok := value.(time.Time); ok {
+				if query["createdAt"] == nil {
+					query["createdAt"] = bson.M{}
+				}
+				query["createdAt"].(bson.M)["$gte"] = startTime
+			}
+		case "endTime":
+			if endTime, ok := value.(time.Time); ok {
+				if query["createdAt"] == nil {
+					query["createdAt"] = bson.M{}
+				}
+				query["createdAt"].(bson.M)["$lte"] = endTime
+			}
+		}
+	}
+
+	return query
+}
+
+// CreateIndexes 创建审计日志集合的查询索引，保留期限的TTL索引由 EnsureRetention 维护
+func (d *AuditLogDAO) CreateIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				bson.E{Key: "actorId", Value: 1},
+				bson.E{Key: "createdAt", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				bson.E{Key: "action", Value: 1},
+				bson.E{Key: "createdAt", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				bson.E{Key: "targetType", Value: 1},
+				bson.E{Key: "targetId", Value: 1},
+				bson.E{Key: "createdAt", Value: -1},
+			},
+		},
+	}
+
+	_, err := d.collection.Indexes().CreateMany(ctx, indexes)
+	return err
+}
+
+// EnsureRetention 按保留期限维护createdAt上的TTL索引，retention不大于0时删除TTL索引永久保留
+// 索引已存在但期限不同时通过collMod修改，无需重建索引
+func (d *AuditLogDAO) EnsureRetention(ctx context.Context, retention time.Duration) error {
+	if retention <= 0 {
+		_, err := d.collection.Indexes().DropOne(ctx, constants.AuditLogTTLIndexName)
+		if isMongoError(err, mongoErrIndexNotFound, mongoErrNamespaceNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	seconds := int32(retention / time.Second)
+	_, err := d.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{bson.E{Key: "createdAt", Value: 1}},
+		Options: options.Index().
+			SetName(constants.AuditLogTTLIndexName).
+			SetExpireAfterSeconds(seconds),
+	})
+	if !isMongoError(err, mongoErrIndexOptionsConflict) {
+		return err
+	}
+
+	return d.collection.Database().RunCommand(ctx, bson.D{
+		bson.E{Key: "collMod", Value: d.collection.Name()},
+		bson.E{Key: "index", Value: bson.D{
+			bson.E{Key: "name", Value: constants.AuditLogTTLIndexName},
+			bson.E{Key: "expireAfterSeconds", Value: seconds},
+		}},
+	}).Err()
+}
+
+// isMongoError 检查是否为指定错误码的MongoDB命令错误
+func isMongoError(err error, codes ...int32) bool {
+	var cmdErr mongo.CommandError
+	if !errors.As(err, &cmdErr) {
+		return false
+	}
+	for _, code := range codes {
+		if cmdErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/heimdall-api/common/model"
+)
+
+func TestAuditLogDAO(t *testing.T) {
+	mockey.PatchConvey("AuditLogDAO Tests", t, func() {
+		auditDAO := &AuditLogDAO{
+			collection: &mongo.Collection{},
+		}
+		ctx := context.Background()
+
+		Convey("Create should reject incomplete logs", func() {
+			So(auditDAO.Create(ctx, nil), ShouldNotBeNil)
+			So(auditDAO.Create(ctx, &model.AuditLog{Action: "post.publish"}), ShouldNotBeNil)
+		})
+
+		Convey("buildQueryFilter should map supported filters", func() {
+			actorID := primitive.NewObjectID()
+			start := time.Now().Add(-time.Hour)
+			end := time.Now()
+
+			query := auditDAO.buildQueryFilter(map[string]interface{}{
+				"actorId":    actorID.Hex(),
+				"action":     "user",
+				"targetType": "user",
+				"targetId":   "abc",
+				"startTime":  start,
+				"endTime":    end,
+				"unknown":    "ignored",
+			})
+			So(query["actorId"], ShouldEqual, actorID)
+			So(query["action"], ShouldResemble, bson.M{"$regex": `^user(\.|$)`})
+			So(query["targetType"], ShouldEqual, "user")
+			So(query["targetId"], ShouldEqual, "abc")
+			So(query["createdAt"], ShouldResemble, bson.M{"$gte": start, "$lte": end})
+			So(query, ShouldNotContainKey, "unknown")
+
+			So(auditDAO.buildQueryFilter(map[string]interface{}{"actorId": "invalid"}), ShouldBeEmpty)
+		})
+
+		Convey("EnsureRetention should create the TTL index", func() {
+			var expire int32
+			mockey.Mock(mongo.IndexView.CreateOne).To(func(_ mongo.IndexView, _ context.Context, index mongo.IndexModel, _ ...*options.CreateIndexesOptions) (string, error) {
+				expire = *index.Options.ExpireAfterSeconds
+				return *index.Options.Name, nil
+			}).Build()
+
+			So(auditDAO.EnsureRetention(ctx, 180*24*time.Hour), ShouldBeNil)
+			So(expire, ShouldEqual, 180*24*3600)
+		})
+
+		Convey("EnsureRetention should update an existing TTL index", func() {
+			mockey.Mock(mongo.IndexView.CreateOne).Return("", mongo.CommandError{Code: mongoErrIndexOptionsConflict}).Build()
+			var command interface{}
+			mockey.Mock((*mongo.Database).RunCommand).To(func(_ *mongo.Database, _ context.Context, runCommand interface{}, _ ...*options.RunCmdOptions) *mongo.SingleResult {
+				command = runCommand
+				return mongo.NewSingleResultFromDocument(bson.D{bson.E{Key: "ok", Value: 1}}, nil, nil)
+			}).Build()
+
+			So(auditDAO.EnsureRetention(ctx, time.Hour), ShouldBeNil)
+			So(command.(bson.D)[0].Key, ShouldEqual, "collMod")
+		})
+
+		Convey("EnsureRetention should drop the TTL index when retention is disabled", func() {
+			mockey.Mock(mongo.IndexView.DropOne).Return(nil, mongo.CommandError{Code: mongoErrIndexNotFound}).Build()
+			So(auditDAO.EnsureRetention(ctx, 0), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,192 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InvitationDAO 用户邀请数据访问层
+type InvitationDAO struct {
+	collection *mongo.Collection
+}
+
+// NewInvitationDAO 创建用户邀请DAO实例
+func NewInvitationDAO(database *mongo.Database) *InvitationDAO {
+	return &InvitationDAO{
+		collection: database.Collection("invitations"),
+	}
+}
+
+// Create 保存新邀请
+func (d *InvitationDAO) Create(ctx context.Context, invitation *model.Invitation) error {
+	if invitation.Email == "" || invitation.Role == "" {
+		return errors.New("invitation is incomplete")
+	}
+
+	now := time.Now()
+	if invitation.ID.IsZero() {
+		invitation.ID = primitive.NewObjectID()
+	}
+	invitation.Status = constants.InvitationStatusPending
+	invitation.CreatedAt = now
+	invitation.UpdatedAt = now
+
+	_, err := d.collection.InsertOne(ctx, invitation)
+	return err
+}
+
+// GetByID 根据ID获取邀请，不存在时返回nil
+func (d *InvitationDAO) GetByID(ctx context.Context, id primitive.ObjectID) (*model.Invitation, error) {
+	var invitation model.Invitation
+	err := d.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&invitation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+// GetPendingByEmail 获取邮箱未过期的待接受邀请，不存在时返回nil
+func (d *InvitationDAO) GetPendingByEmail(ctx context.Context, email string) (*model.Invitation, error) {
+	if email == "" {
+		return nil, errors.New("email cannot be empty")
+	}
+
+	filter := bson.M{
+		"email":     email,
+		"status":    constants.InvitationStatusPending,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+
+	var invitation model.Invitation
+	err := d.collection.FindOne(ctx, filter).Decode(&invitation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+// ListPending 获取全部待接受的邀请（含已过期但未撤销的），最新的在前
+func (d *InvitationDAO) ListPending(ctx context.Context) ([]*model.Invitation, error) {
+	opts := options.Find().SetSort(bson.D{bson.E{Key: "createdAt", Value: -1}})
+	cursor, err := d.collection.Find(ctx, bson.M{"status": constants.InvitationStatusPending}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var invitations []*model.Invitation
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// Resend 为待接受的邀请更换令牌并延长有效期，返回是否更新成功
+func (d *InvitationDAO) Resend(ctx context.Context, id primitive.ObjectID, tokenHash string, expiresAt time.Time) (bool, error) {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"tokenHash":  tokenHash,
+			"expiresAt":  expiresAt,
+			"lastSentAt": now,
+			"updatedAt":  now,
+		},
+		"$inc": bson.M{"sendCount": 1},
+	}
+
+	result, err := d.collection.UpdateOne(ctx, bson.M{"_id": id, "status": constants.InvitationStatusPending}, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// Revoke 撤销待接受的邀请，返回是否撤销成功
+func (d *InvitationDAO) Revoke(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	now := time.Now()
+	update := bson.M{"$set": bson.M{
+		"status":    constants.InvitationStatusRevoked,
+		"revokedAt": now,
+		"updatedAt": now,
+	}}
+
+	result, err := d.collection.UpdateOne(ctx, bson.M{"_id": id, "status": constants.InvitationStatusPending}, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// MarkAccepted 以当前令牌接受邀请，只有令牌匹配且未过期的待接受邀请能更新成功
+func (d *InvitationDAO) MarkAccepted(ctx context.Context, id primitive.ObjectID, tokenHash string, userID primitive.ObjectID) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id":       id,
+		"tokenHash": tokenHash,
+		"status":    constants.InvitationStatusPending,
+		"expiresAt": bson.M{"$gt": now},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":         constants.InvitationStatusAccepted,
+		"acceptedAt":     now,
+		"acceptedUserId": userID,
+		"updatedAt":      now,
+	}}
+
+	result, err := d.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ReleaseAccepted 撤回MarkAccepted的占用，账号创建失败时让邀请恢复为待接受状态
+func (d *InvitationDAO) ReleaseAccepted(ctx context.Context, id primitive.ObjectID, userID primitive.ObjectID) (bool, error) {
+	filter := bson.M{
+		"_id":            id,
+		"status":         constants.InvitationStatusAccepted,
+		"acceptedUserId": userID,
+	}
+	update := bson.M{
+		"$set":   bson.M{"status": constants.InvitationStatusPending, "updatedAt": time.Now()},
+		"$unset": bson.M{"acceptedAt": "", "acceptedUserId": ""},
+	}
+
+	result, err := d.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// CreateIndexes 创建索引
+func (d *InvitationDAO) CreateIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{bson.E{Key: "email", Value: 1}, bson.E{Key: "status", Value: 1}},
+		},
+		{
+			Keys: bson.D{bson.E{Key: "status", Value: 1}, bson.E{Key: "createdAt", Value: -1}},
+		},
+	}
+
+	_, err := d.collection.Indexes().CreateMany(ctx, indexes)
+	return err
+}
@@ -0,0 +1,98 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+)
+
+func TestInvitationDAO(t *testing.T) {
+	Convey("InvitationDAO Tests", t, func() {
+		invitationDAO := &InvitationDAO{
+			collection: &mongo.Collection{},
+		}
+		ctx := context.Background()
+
+		Convey("Create should default to pending", func() {
+			mock := mockey.Mock((*mongo.Collection).InsertOne).Return(&mongo.InsertOneResult{}, nil).Build()
+			defer mock.UnPatch()
+
+			invitation := &model.Invitation{Email: "new@example.com", Role: constants.UserRoleAuthor}
+			err := invitationDAO.Create(ctx, invitation)
+			So(err, ShouldBeNil)
+			So(invitation.ID.IsZero(), ShouldBeFalse)
+			So(invitation.Status, ShouldEqual, constants.InvitationStatusPending)
+			So(invitation.CreatedAt.IsZero(), ShouldBeFalse)
+		})
+
+		Convey("GetByID should return nil when not found", func() {
+			mock1 := mockey.Mock((*mongo.Collection).FindOne).Return(&mongo.SingleResult{}).Build()
+			defer mock1.UnPatch()
+			mock2 := mockey.Mock((*mongo.SingleResult).Decode).Return(mongo.ErrNoDocuments).Build()
+			defer mock2.UnPatch()
+
+			invitation, err := invitationDAO.GetByID(ctx, primitive.NewObjectID())
+			So(err, ShouldBeNil)
+			So(invitation, ShouldBeNil)
+		})
+
+		Convey("Revoke should only touch pending invitations", func() {
+			var gotFilter interface{}
+			mock := mockey.Mock((*mongo.Collection).UpdateOne).To(func(c *mongo.Collection, ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+				gotFilter = filter
+				return &mongo.UpdateResult{ModifiedCount: 0}, nil
+			}).Build()
+			defer mock.UnPatch()
+
+			id := primitive.NewObjectID()
+			revoked, err := invitationDAO.Revoke(ctx, id)
+			So(err, ShouldBeNil)
+			So(revoked, ShouldBeFalse)
+			So(gotFilter, ShouldResemble, bson.M{"_id": id, "status": constants.InvitationStatusPending})
+		})
+
+		Convey("MarkAccepted should require the current unexpired token", func() {
+			var gotFilter bson.M
+			mock := mockey.Mock((*mongo.Collection).UpdateOne).To(func(c *mongo.Collection, ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+				gotFilter = filter.(bson.M)
+				return &mongo.UpdateResult{ModifiedCount: 1}, nil
+			}).Build()
+			defer mock.UnPatch()
+
+			accepted, err := invitationDAO.MarkAccepted(ctx, primitive.NewObjectID(), "hash", primitive.NewObjectID())
+			So(err, ShouldBeNil)
+			So(accepted, ShouldBeTrue)
+			So(gotFilter["tokenHash"], ShouldEqual, "hash")
+			So(gotFilter["status"], ShouldEqual, constants.InvitationStatusPending)
+			So(gotFilter["expiresAt"].(bson.M)["$gt"], ShouldHappenWithin, time.Second, time.Now())
+		})
+
+		Convey("ReleaseAccepted should only revert the claim held by the given user", func() {
+			var gotFilter, gotUpdate bson.M
+			mock := mockey.Mock((*mongo.Collection).UpdateOne).To(func(c *mongo.Collection, ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+				gotFilter, gotUpdate = filter.(bson.M), update.(bson.M)
+				return &mongo.UpdateResult{ModifiedCount: 1}, nil
+			}).Build()
+			defer mock.UnPatch()
+
+			userID := primitive.NewObjectID()
+			released, err := invitationDAO.ReleaseAccepted(ctx, primitive.NewObjectID(), userID)
+			So(err, ShouldBeNil)
+			So(released, ShouldBeTrue)
+			So(gotFilter["status"], ShouldEqual, constants.InvitationStatusAccepted)
+			So(gotFilter["acceptedUserId"], ShouldEqual, userID)
+			So(gotUpdate["$set"].(bson.M)["status"], ShouldEqual, constants.InvitationStatusPending)
+			So(gotUpdate["$unset"], ShouldContainKey, "acceptedUserId")
+		})
+	})
+}
@@ -0,0 +1,114 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/common/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IPRuleDAO 永久IP访问规则数据访问层
+type IPRuleDAO struct {
+	collection *mongo.Collection
+}
+
+// NewIPRuleDAO 创建IP访问规则DAO实例
+func NewIPRuleDAO(database *mongo.Database) *IPRuleDAO {
+	return &IPRuleDAO{
+		collection: database.Collection("ipRules"),
+	}
+}
+
+// Create 保存新规则
+func (d *IPRuleDAO) Create(ctx context.Context, rule *model.IPRule) error {
+	if rule.Type == "" || rule.CIDR == "" {
+		return errors.New("ip rule is incomplete")
+	}
+
+	rule.ID = primitive.NewObjectID()
+	rule.ExpiresAt = nil
+	rule.CreatedAt = time.Now()
+
+	_, err := d.collection.InsertOne(ctx, rule)
+	return err
+}
+
+// GetByID 根据ID获取规则，不存在时返回nil
+func (d *IPRuleDAO) GetByID(ctx context.Context, id primitive.ObjectID) (*model.IPRule, error) {
+	var rule model.IPRule
+	err := d.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&rule)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// GetByCIDR 根据类型和网段获取规则，不存在时返回nil
+func (d *IPRuleDAO) GetByCIDR(ctx context.Context, ruleType, cidr string) (*model.IPRule, error) {
+	var rule model.IPRule
+	err := d.collection.FindOne(ctx, bson.M{"type": ruleType, "cidr": cidr}).Decode(&rule)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// List 获取规则列表，ruleType为空时返回全部类型，最新的在前
+func (d *IPRuleDAO) List(ctx context.Context, ruleType string) ([]*model.IPRule, error) {
+	filter := bson.M{}
+	if ruleType != "" {
+		filter["type"] = ruleType
+	}
+
+	opts := options.Find().SetSort(bson.D{bson.E{Key: "createdAt", Value: -1}})
+	cursor, err := d.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rules []*model.IPRule
+	if err := cursor.All(ctx, &rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// Delete 删除规则，返回是否删除成功
+func (d *IPRuleDAO) Delete(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	result, err := d.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// CreateIndexes 创建索引
+func (d *IPRuleDAO) CreateIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{bson.E{Key: "type", Value: 1}, bson.E{Key: "cidr", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{bson.E{Key: "createdAt", Value: -1}},
+		},
+	}
+
+	_, err := d.collection.Indexes().CreateMany(ctx, indexes)
+	return err
+}
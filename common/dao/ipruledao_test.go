@@ -0,0 +1,67 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+)
+
+func TestIPRuleDAO(t *testing.T) {
+	Convey("IPRuleDAO Tests", t, func() {
+		ruleDAO := &IPRuleDAO{
+			collection: &mongo.Collection{},
+		}
+		ctx := context.Background()
+
+		Convey("Create should reject incomplete rules", func() {
+			err := ruleDAO.Create(ctx, &model.IPRule{Type: constants.IPRuleTypeBlock})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("GetByCIDR should return nil when not found", func() {
+			mock1 := mockey.Mock((*mongo.Collection).FindOne).Return(&mongo.SingleResult{}).Build()
+			defer mock1.UnPatch()
+			mock2 := mockey.Mock((*mongo.SingleResult).Decode).Return(mongo.ErrNoDocuments).Build()
+			defer mock2.UnPatch()
+
+			rule, err := ruleDAO.GetByCIDR(ctx, constants.IPRuleTypeBlock, "203.0.113.0/24")
+			So(err, ShouldBeNil)
+			So(rule, ShouldBeNil)
+		})
+
+		Convey("List should filter by type", func() {
+			var gotFilter interface{}
+			mock1 := mockey.Mock((*mongo.Collection).Find).To(func(c *mongo.Collection, ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+				gotFilter = filter
+				return &mongo.Cursor{}, nil
+			}).Build()
+			defer mock1.UnPatch()
+			mock2 := mockey.Mock((*mongo.Cursor).All).Return(nil).Build()
+			defer mock2.UnPatch()
+			mock3 := mockey.Mock((*mongo.Cursor).Close).Return(nil).Build()
+			defer mock3.UnPatch()
+
+			_, err := ruleDAO.List(ctx, constants.IPRuleTypeAllow)
+			So(err, ShouldBeNil)
+			So(gotFilter, ShouldResemble, bson.M{"type": constants.IPRuleTypeAllow})
+		})
+
+		Convey("Delete should report missing rules", func() {
+			mock := mockey.Mock((*mongo.Collection).DeleteOne).Return(&mongo.DeleteResult{DeletedCount: 0}, nil).Build()
+			defer mock.UnPatch()
+
+			deleted, err := ruleDAO.Delete(ctx, primitive.NewObjectID())
+			So(err, ShouldBeNil)
+			So(deleted, ShouldBeFalse)
+		})
+	})
+}
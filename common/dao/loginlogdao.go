@@ -3,8 +3,11 @@ package dao
 import (
 	"context"
 	"errors"
+	"regexp"
+	"sort"
 	"time"
 
+	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -173,6 +176,263 @@ func (d *LoginLogDAO) GetRecentFailedLogins(ctx context.Context, since time.Time
 	return logs, nil
 }
 
+// GetRecentSuccessfulLogins 获取用户最近的成功登录记录，不包含登出记录，用于登录异常检测
+func (d *LoginLogDAO) GetRecentSuccessfulLogins(ctx context.Context, userID primitive.ObjectID, limit int) ([]*model.LoginLog, error) {
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	query := bson.M{
+		"userId":   userID,
+		"status":   constants.LoginStatusSuccess,
+		"logoutAt": bson.M{"$exists": false},
+	}
+
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{bson.E{Key: "loginAt", Value: -1}})
+
+	cursor, err := d.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var logs []*model.LoginLog
+	if err := cursor.All(ctx, &logs); err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// versionSuffixPattern 浏览器和操作系统名称末尾的版本号，如 "Chrome 120" 中的 " 120"
+var versionSuffixPattern = regexp.MustCompile(`\s+[\d.]+$`)
+
+// loginStatsTimeFormats 时间序列分组粒度对应的 $dateToString 格式
+var loginStatsTimeFormats = map[string]string{
+	constants.LoginStatsGroupByDay:  "%Y-%m-%d",
+	constants.LoginStatsGroupByHour: "%Y-%m-%dT%H",
+}
+
+// GetStatistics 统计时间范围内的登录总数、成功失败次数、独立用户数和独立IP数
+func (d *LoginLogDAO) GetStatistics(ctx context.Context, from, to time.Time) (*model.LoginStatistics, error) {
+	var results []*model.LoginStatistics
+	if err := d.aggregate(ctx, buildStatisticsPipeline(from, to), &results); err != nil {
+		return nil, err
+	}
+
+	stats := &model.LoginStatistics{}
+	if len(results) > 0 {
+		stats = results[0]
+	}
+	stats.CalculateSuccessRate()
+	return stats, nil
+}
+
+// GetTimeSeries 按天或小时（UTC）统计登录次数，没有登录的时间段不返回
+func (d *LoginLogDAO) GetTimeSeries(ctx context.Context, from, to time.Time, groupBy string) ([]*model.LoginTimeBucket, error) {
+	format, ok := loginStatsTimeFormats[groupBy]
+	if !ok {
+		return nil, errors.New("invalid groupBy")
+	}
+
+	var buckets []*model.LoginTimeBucket
+	if err := d.aggregate(ctx, buildTimeSeriesPipeline(from, to, format), &buckets); err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// GetTopFailedIPs 获取登录失败次数最多的IP
+func (d *LoginLogDAO) GetTopFailedIPs(ctx context.Context, from, to time.Time, limit int) ([]*model.LoginCountItem, error) {
+	return d.countBy(ctx, buildCountPipeline(from, to, "ipAddress", true, limit))
+}
+
+// GetTopFailedUsernames 获取登录失败次数最多的用户名，包括不存在的用户名
+func (d *LoginLogDAO) GetTopFailedUsernames(ctx context.Context, from, to time.Time, limit int) ([]*model.LoginCountItem, error) {
+	return d.countBy(ctx, buildCountPipeline(from, to, "username", true, limit))
+}
+
+// GetBreakdown 按国家、设备类型、浏览器或操作系统统计登录次数
+// 浏览器和操作系统按名称合并不同版本
+func (d *LoginLogDAO) GetBreakdown(ctx context.Context, from, to time.Time, field string, limit int) ([]*model.LoginCountItem, error) {
+	switch field {
+	case "country", "deviceType":
+		return d.countBy(ctx, buildCountPipeline(from, to, field, false, limit))
+	case "browser", "os":
+		// 不同版本的数量有限，先全部取出再按名称合并
+		items, err := d.countBy(ctx, buildCountPipeline(from, to, field, false, 0))
+		if err != nil {
+			return nil, err
+		}
+		return mergeVersionedCounts(items, limit), nil
+	default:
+		return nil, errors.New("invalid breakdown field")
+	}
+}
+
+// countBy 执行分组计数聚合
+func (d *LoginLogDAO) countBy(ctx context.Context, pipeline mongo.Pipeline) ([]*model.LoginCountItem, error) {
+	var items []*model.LoginCountItem
+	if err := d.aggregate(ctx, pipeline, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// aggregate 执行聚合并解码全部结果
+func (d *LoginLogDAO) aggregate(ctx context.Context, pipeline mongo.Pipeline, results interface{}) error {
+	cursor, err := d.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, results)
+}
+
+// buildStatsMatch 统计时间范围 [from, to) 的匹配条件，登出记录不是登录，不参与统计
+func buildStatsMatch(from, to time.Time) bson.D {
+	return bson.D{bson.E{Key: "$match", Value: bson.M{
+		"loginAt":  bson.M{"$gte": from, "$lt": to},
+		"logoutAt": bson.M{"$exists": false},
+	}}}
+}
+
+// statusCount 统计指定状态的次数
+func statusCount(status string) bson.M {
+	return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", status}}, 1, 0}}}
+}
+
+// buildStatisticsPipeline 构建汇总统计聚合
+// 独立用户数和独立IP数先按字段分组再计数，不把所有取值收集进同一个文档
+func buildStatisticsPipeline(from, to time.Time) mongo.Pipeline {
+	return mongo.Pipeline{
+		buildStatsMatch(from, to),
+		{bson.E{Key: "$facet", Value: bson.M{
+			"totals": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":           nil,
+					"totalLogins":   bson.M{"$sum": 1},
+					"successLogins": statusCount(constants.LoginStatusSuccess),
+					"failedLogins":  statusCount(constants.LoginStatusFailed),
+				}},
+			},
+			// 登录失败且用户不存在时没有 userId，不计入独立用户
+			"users": buildDistinctCount(bson.M{"$match": bson.M{"userId": bson.M{"$exists": true}}}, "$userId"),
+			"ips":   buildDistinctCount(nil, "$ipAddress"),
+		}}},
+		{bson.E{Key: "$project", Value: bson.M{
+			"totalLogins":   firstOrZero("$totals.totalLogins"),
+			"successLogins": firstOrZero("$totals.successLogins"),
+			"failedLogins":  firstOrZero("$totals.failedLogins"),
+			"uniqueUsers":   firstOrZero("$users.count"),
+			"uniqueIPs":     firstOrZero("$ips.count"),
+		}}},
+	}
+}
+
+// buildDistinctCount 构建 $facet 子管道：按 key 分组后用 $count 统计分组数，match 为空时不过滤
+func buildDistinctCount(match bson.M, key string) bson.A {
+	stages := bson.A{}
+	if match != nil {
+		stages = append(stages, match)
+	}
+	return append(stages, bson.M{"$group": bson.M{"_id": key}}, bson.M{"$count": "count"})
+}
+
+// firstOrZero 取 $facet 结果数组的第一个值，时间范围内没有记录时为0
+func firstOrZero(path string) bson.M {
+	return bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{path, 0}}, 0}}
+}
+
+// buildTimeSeriesPipeline 构建时间序列聚合，按UTC时间格式化后分组
+func buildTimeSeriesPipeline(from, to time.Time, format string) mongo.Pipeline {
+	return mongo.Pipeline{
+		buildStatsMatch(from, to),
+		{bson.E{Key: "$group", Value: bson.M{
+			"_id": bson.M{"$dateToString": bson.M{
+				"format":   format,
+				"date":     "$loginAt",
+				"timezone": "UTC",
+			}},
+			"total":   bson.M{"$sum": 1},
+			"success": statusCount(constants.LoginStatusSuccess),
+			"failed":  statusCount(constants.LoginStatusFailed),
+		}}},
+		{bson.E{Key: "$sort", Value: bson.D{bson.E{Key: "_id", Value: 1}}}},
+	}
+}
+
+// buildCountPipeline 构建按字段分组计数的聚合，忽略字段为空的记录
+// failedOnly 为 true 时只保留有失败记录的分组并按失败次数排序，否则按登录次数排序；limit 为0时不限制数量
+func buildCountPipeline(from, to time.Time, field string, failedOnly bool, limit int) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		buildStatsMatch(from, to),
+		{bson.E{Key: "$match", Value: bson.M{field: bson.M{"$nin": bson.A{nil, ""}}}}},
+		{bson.E{Key: "$group", Value: bson.M{
+			"_id":    "$" + field,
+			"total":  bson.M{"$sum": 1},
+			"failed": statusCount(constants.LoginStatusFailed),
+			"lastAt": bson.M{"$max": "$loginAt"},
+		}}},
+	}
+
+	sortBy := "total"
+	if failedOnly {
+		sortBy = "failed"
+		pipeline = append(pipeline, bson.D{bson.E{Key: "$match", Value: bson.M{"failed": bson.M{"$gt": 0}}}})
+	}
+	pipeline = append(pipeline, bson.D{bson.E{Key: "$sort", Value: bson.D{
+		bson.E{Key: sortBy, Value: -1},
+		bson.E{Key: "_id", Value: 1},
+	}}})
+	if limit > 0 {
+		pipeline = append(pipeline, bson.D{bson.E{Key: "$limit", Value: limit}})
+	}
+
+	return pipeline
+}
+
+// mergeVersionedCounts 去掉名称末尾的版本号后合并计数，按登录次数排序并截取前 limit 项
+func mergeVersionedCounts(items []*model.LoginCountItem, limit int) []*model.LoginCountItem {
+	merged := make(map[string]*model.LoginCountItem)
+	var keys []string
+	for _, item := range items {
+		name := versionSuffixPattern.ReplaceAllString(item.Key, "")
+		existing, ok := merged[name]
+		if !ok {
+			merged[name] = &model.LoginCountItem{Key: name, Total: item.Total, Failed: item.Failed, LastAt: item.LastAt}
+			keys = append(keys, name)
+			continue
+		}
+		existing.Total += item.Total
+		existing.Failed += item.Failed
+		if item.LastAt.After(existing.LastAt) {
+			existing.LastAt = item.LastAt
+		}
+	}
+
+	result := make([]*model.LoginCountItem, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, merged[key])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].Key < result[j].Key
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
 // CreateIndexes 创建登录日志集合的索引
 func (d *LoginLogDAO) CreateIndexes(ctx context.Context) error {
 	indexes := []mongo.IndexModel{
@@ -200,6 +460,13 @@ func (d *LoginLogDAO) CreateIndexes(ctx context.Context) error {
 		{
 			Keys: bson.D{bson.E{Key: "username", Value: 1}},
 		},
+		{
+			Keys: bson.D{
+				bson.E{Key: "anomalies", Value: 1},
+				bson.E{Key: "loginAt", Value: -1},
+			},
+			Options: options.Index().SetSparse(true),
+		},
 	}
 
 	_, err := d.collection.Indexes().CreateMany(ctx, indexes)
@@ -260,9 +527,21 @@ func (d *LoginLogDAO) buildQueryFilter(filter map[string]interface{}) bson.M {
 			if value != nil && value != "" {
 				query["deviceType"] = value
 			}
-		case "browser":
-			if value != nil && value != "" {
-				query["browser"] = value
+		case "anomalous":
+			// 只查询被标记为异常的登录
+			if anomalous, ok := value.(bool); ok && anomalous {
+				if _, exists := query["anomalies"]; !exists {
+					query["anomalies"] = bson.M{"$exists": true, "$ne": bson.A{}}
+				}
+			}
+		case "anomaly":
+			if anomaly, ok := value.(string); ok && anomaly != "" {
+				query["anomalies"] = anomaly
+			}
+		case "browser", "os":
+			// 按名称匹配时同时命中所有版本，如 "Chrome" 匹配 "Chrome 120"
+			if name, ok := value.(string); ok && name != "" {
+				query[key] = bson.M{"$regex": "^" + regexp.QuoteMeta(name) + "( |$)"}
 			}
 		}
 	}
@@ -8,8 +8,10 @@ import (
 
 	"github.com/bytedance/mockey"
 	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
@@ -368,6 +370,45 @@ func TestLoginLogDAO_GetRecentFailedLogins(t *testing.T) {
 	})
 }
 
+func TestLoginLogDAO_GetRecentSuccessfulLogins(t *testing.T) {
+	Convey("LoginLogDAO GetRecentSuccessfulLogins Tests", t, func() {
+		loginLogDAO := &LoginLogDAO{
+			collection: &mongo.Collection{}, // Mock collection
+		}
+
+		Convey("Should query the user's successful logins without logout records", func() {
+			var gotFilter interface{}
+			var gotOpts *options.FindOptions
+			mock1 := mockey.Mock((*mongo.Collection).Find).To(func(c *mongo.Collection, ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+				gotFilter = filter
+				gotOpts = opts[0]
+				return &mongo.Cursor{}, nil
+			}).Build()
+			defer mock1.UnPatch()
+
+			mock2 := mockey.Mock((*mongo.Cursor).All).To(func(c *mongo.Cursor, ctx context.Context, results interface{}) error {
+				*results.(*[]*model.LoginLog) = []*model.LoginLog{{Status: constants.LoginStatusSuccess}}
+				return nil
+			}).Build()
+			defer mock2.UnPatch()
+
+			mock3 := mockey.Mock((*mongo.Cursor).Close).Return(nil).Build()
+			defer mock3.UnPatch()
+
+			userID := primitive.NewObjectID()
+			logs, err := loginLogDAO.GetRecentSuccessfulLogins(context.Background(), userID, 500)
+			So(err, ShouldBeNil)
+			So(logs, ShouldHaveLength, 1)
+			So(gotFilter, ShouldResemble, bson.M{
+				"userId":   userID,
+				"status":   constants.LoginStatusSuccess,
+				"logoutAt": bson.M{"$exists": false},
+			})
+			So(*gotOpts.Limit, ShouldEqual, 100)
+		})
+	})
+}
+
 func TestLoginLogDAO_CreateIndexes(t *testing.T) {
 	Convey("LoginLogDAO CreateIndexes Tests", t, func() {
 		loginLogDAO := &LoginLogDAO{
@@ -434,6 +475,27 @@ func TestLoginLogDAO_BuildQueryFilter(t *testing.T) {
 			query := loginLogDAO.buildQueryFilter(filter)
 			So(query["loginAt"], ShouldNotBeNil)
 		})
+
+		Convey("Should match browser and os by name prefix", func() {
+			filter := map[string]interface{}{
+				"browser": "Chrome",
+				"os":      "Windows 10",
+			}
+			query := loginLogDAO.buildQueryFilter(filter)
+			So(query["browser"], ShouldResemble, bson.M{"$regex": "^Chrome( |$)"})
+			So(query["os"], ShouldResemble, bson.M{"$regex": "^Windows 10( |$)"})
+		})
+
+		Convey("Should filter anomalous logins", func() {
+			query := loginLogDAO.buildQueryFilter(map[string]interface{}{"anomalous": true})
+			So(query["anomalies"], ShouldResemble, bson.M{"$exists": true, "$ne": bson.A{}})
+
+			query = loginLogDAO.buildQueryFilter(map[string]interface{}{
+				"anomalous": true,
+				"anomaly":   constants.LoginAnomalyNewCountry,
+			})
+			So(query["anomalies"], ShouldEqual, constants.LoginAnomalyNewCountry)
+		})
 	})
 }
 
@@ -466,3 +528,113 @@ func TestLoginLogDAO_BuildSortCondition(t *testing.T) {
 		})
 	})
 }
+
+func TestLoginLogDAO_Statistics(t *testing.T) {
+	Convey("LoginLogDAO Statistics Tests", t, func() {
+		loginLogDAO := &LoginLogDAO{
+			collection: &mongo.Collection{}, // Mock collection
+		}
+		from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		to := from.Add(7 * 24 * time.Hour)
+
+		Convey("Should compute success rate from aggregated totals", func() {
+			var gotPipeline interface{}
+			mock1 := mockey.Mock((*mongo.Collection).Aggregate).To(func(c *mongo.Collection, ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+				gotPipeline = pipeline
+				return &mongo.Cursor{}, nil
+			}).Build()
+			defer mock1.UnPatch()
+			mock2 := mockey.Mock((*mongo.Cursor).All).To(func(c *mongo.Cursor, ctx context.Context, results interface{}) error {
+				*results.(*[]*model.LoginStatistics) = []*model.LoginStatistics{{TotalLogins: 3, SuccessLogins: 2, FailedLogins: 1, UniqueUsers: 1, UniqueIPs: 2}}
+				return nil
+			}).Build()
+			defer mock2.UnPatch()
+			mock3 := mockey.Mock((*mongo.Cursor).Close).Return(nil).Build()
+			defer mock3.UnPatch()
+
+			stats, err := loginLogDAO.GetStatistics(context.Background(), from, to)
+			So(err, ShouldBeNil)
+			So(stats.SuccessRate, ShouldEqual, 66.67)
+			So(gotPipeline.(mongo.Pipeline)[0], ShouldResemble, buildStatsMatch(from, to))
+		})
+
+		Convey("Should return zero statistics for an empty range", func() {
+			mock1 := mockey.Mock((*mongo.Collection).Aggregate).Return(&mongo.Cursor{}, nil).Build()
+			defer mock1.UnPatch()
+			mock2 := mockey.Mock((*mongo.Cursor).All).Return(nil).Build()
+			defer mock2.UnPatch()
+			mock3 := mockey.Mock((*mongo.Cursor).Close).Return(nil).Build()
+			defer mock3.UnPatch()
+
+			stats, err := loginLogDAO.GetStatistics(context.Background(), from, to)
+			So(err, ShouldBeNil)
+			So(stats.TotalLogins, ShouldEqual, 0)
+			So(stats.SuccessRate, ShouldEqual, 0)
+		})
+
+		Convey("Should reject unknown groupBy and breakdown fields", func() {
+			_, err := loginLogDAO.GetTimeSeries(context.Background(), from, to, "week")
+			So(err, ShouldNotBeNil)
+			_, err = loginLogDAO.GetBreakdown(context.Background(), from, to, "passwordHash", 10)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Should exclude logout records from every pipeline", func() {
+			match := buildStatsMatch(from, to)[0].Value.(bson.M)
+			So(match["logoutAt"], ShouldResemble, bson.M{"$exists": false})
+			So(match["loginAt"], ShouldResemble, bson.M{"$gte": from, "$lt": to})
+		})
+
+		Convey("Should count unique users and IPs by grouping on the key", func() {
+			pipeline := buildStatisticsPipeline(from, to)
+			So(pipeline, ShouldHaveLength, 3)
+			facet := pipeline[1][0].Value.(bson.M)
+			So(facet["users"], ShouldResemble, bson.A{
+				bson.M{"$match": bson.M{"userId": bson.M{"$exists": true}}},
+				bson.M{"$group": bson.M{"_id": "$userId"}},
+				bson.M{"$count": "count"},
+			})
+			So(facet["ips"], ShouldResemble, bson.A{
+				bson.M{"$group": bson.M{"_id": "$ipAddress"}},
+				bson.M{"$count": "count"},
+			})
+			project := pipeline[2][0].Value.(bson.M)
+			So(project["uniqueUsers"], ShouldResemble, firstOrZero("$users.count"))
+			So(project["uniqueIPs"], ShouldResemble, firstOrZero("$ips.count"))
+		})
+
+		Convey("Should group time series by formatted UTC time", func() {
+			pipeline := buildTimeSeriesPipeline(from, to, loginStatsTimeFormats[constants.LoginStatsGroupByHour])
+			group := pipeline[1][0].Value.(bson.M)
+			So(group["_id"], ShouldResemble, bson.M{"$dateToString": bson.M{"format": "%Y-%m-%dT%H", "date": "$loginAt", "timezone": "UTC"}})
+			So(pipeline[2][0].Key, ShouldEqual, "$sort")
+		})
+
+		Convey("Should rank failing groups by failure count", func() {
+			pipeline := buildCountPipeline(from, to, "ipAddress", true, 10)
+			So(pipeline, ShouldHaveLength, 6)
+			So(pipeline[2][0].Value.(bson.M)["_id"], ShouldEqual, "$ipAddress")
+			So(pipeline[3][0].Value, ShouldResemble, bson.M{"failed": bson.M{"$gt": 0}})
+			So(pipeline[4][0].Value, ShouldResemble, bson.D{bson.E{Key: "failed", Value: -1}, bson.E{Key: "_id", Value: 1}})
+			So(pipeline[5][0].Value, ShouldEqual, 10)
+
+			pipeline = buildCountPipeline(from, to, "country", false, 0)
+			So(pipeline, ShouldHaveLength, 4)
+			So(pipeline[3][0].Value, ShouldResemble, bson.D{bson.E{Key: "total", Value: -1}, bson.E{Key: "_id", Value: 1}})
+		})
+
+		Convey("Should merge browser versions", func() {
+			older := time.Now().Add(-time.Hour)
+			newer := time.Now()
+			items := mergeVersionedCounts([]*model.LoginCountItem{
+				{Key: "Chrome 120", Total: 5, Failed: 1, LastAt: older},
+				{Key: "Firefox 121", Total: 6, LastAt: older},
+				{Key: "Chrome 121", Total: 4, Failed: 2, LastAt: newer},
+				{Key: "Safari", Total: 1, LastAt: older},
+			}, 2)
+			So(items, ShouldHaveLength, 2)
+			So(*items[0], ShouldResemble, model.LoginCountItem{Key: "Chrome", Total: 9, Failed: 3, LastAt: newer})
+			So(items[1].Key, ShouldEqual, "Firefox")
+		})
+	})
+}
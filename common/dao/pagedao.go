@@ -369,6 +369,66 @@ func (d *PageDAO) GetRecentPages(ctx context.Context, limit int) ([]*model.Page,
 	return pages, nil
 }
 
+// ReassignAuthor 将作者的全部页面转移给另一位用户，返回转移的数量
+func (d *PageDAO) ReassignAuthor(ctx context.Context, fromAuthorID, toAuthorID string) (int64, error) {
+	fromID, err := primitive.ObjectIDFromHex(fromAuthorID)
+	if err != nil {
+		return 0, errors.New("invalid author id format")
+	}
+	toID, err := primitive.ObjectIDFromHex(toAuthorID)
+	if err != nil {
+		return 0, errors.New("invalid author id format")
+	}
+
+	updates := bson.M{
+		"authorId":  toID,
+		"updatedAt": time.Now(),
+	}
+
+	result, err := d.collection.UpdateMany(ctx, bson.M{"authorId": fromID}, bson.M{"$set": updates})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// ForEach 按ID顺序遍历全部页面（包括草稿和已归档的），用于数据迁移，fn返回错误时停止遍历
+func (d *PageDAO) ForEach(ctx context.Context, fn func(page *model.Page) error) error {
+	opts := options.Find().SetSort(bson.D{bson.E{Key: "_id", Value: 1}})
+	cursor, err := d.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var page model.Page
+		if err := cursor.Decode(&page); err != nil {
+			return err
+		}
+		if err := fn(&page); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// SetHTML 保存重新渲染的HTML，内容本身未变化，不修改更新时间
+func (d *PageDAO) SetHTML(ctx context.Context, id primitive.ObjectID, html string) error {
+	result, err := d.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"html": html}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("page not found")
+	}
+
+	return nil
+}
+
 // CreateIndexes 创建页面集合的索引
 func (d *PageDAO) CreateIndexes(ctx context.Context) error {
 	indexes := []mongo.IndexModel{
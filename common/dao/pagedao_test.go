@@ -8,8 +8,10 @@ import (
 	"github.com/bytedance/mockey"
 	"github.com/heimdall-api/common/model"
 	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func TestPageDAO_Create(t *testing.T) {
@@ -705,6 +707,38 @@ func TestPageDAO_QueryBuilders(t *testing.T) {
 	})
 }
 
+func TestPageDAO_SetHTML(t *testing.T) {
+	Convey("PageDAO SetHTML Tests", t, func() {
+		pageDAO := &PageDAO{
+			collection: &mongo.Collection{}, // Mock collection
+		}
+		id := primitive.NewObjectID()
+
+		Convey("Should only set html without touching updatedAt", func() {
+			var gotFilter, gotUpdate bson.M
+			mock := mockey.Mock((*mongo.Collection).UpdateOne).To(func(c *mongo.Collection, ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+				gotFilter, gotUpdate = filter.(bson.M), update.(bson.M)
+				return &mongo.UpdateResult{MatchedCount: 1}, nil
+			}).Build()
+			defer mock.UnPatch()
+
+			err := pageDAO.SetHTML(context.Background(), id, "<p>内容</p>")
+			So(err, ShouldBeNil)
+			So(gotFilter, ShouldResemble, bson.M{"_id": id})
+			So(gotUpdate, ShouldResemble, bson.M{"$set": bson.M{"html": "<p>内容</p>"}})
+		})
+
+		Convey("Should return error when page does not exist", func() {
+			mock := mockey.Mock((*mongo.Collection).UpdateOne).Return(&mongo.UpdateResult{MatchedCount: 0}, nil).Build()
+			defer mock.UnPatch()
+
+			err := pageDAO.SetHTML(context.Background(), id, "<p>内容</p>")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "page not found")
+		})
+	})
+}
+
 func TestPageDAO_CreateIndexes(t *testing.T) {
 	Convey("PageDAO CreateIndexes Tests", t, func() {
 		pageDAO := &PageDAO{
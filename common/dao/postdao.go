@@ -450,6 +450,76 @@ func (d *PostDAO) GetRecentPosts(ctx context.Context, limit int) ([]*model.Post,
 	return posts, nil
 }
 
+// ReassignAuthor 将作者的全部文章转移给另一位用户，status不为空时同时更新状态，返回转移的数量
+func (d *PostDAO) ReassignAuthor(ctx context.Context, fromAuthorID, toAuthorID, status string) (int64, error) {
+	fromID, err := primitive.ObjectIDFromHex(fromAuthorID)
+	if err != nil {
+		return 0, errors.New("invalid author id format")
+	}
+	toID, err := primitive.ObjectIDFromHex(toAuthorID)
+	if err != nil {
+		return 0, errors.New("invalid author id format")
+	}
+
+	updates := bson.M{
+		"authorId":  toID,
+		"updatedAt": time.Now(),
+	}
+	if status != "" {
+		updates["status"] = status
+	}
+
+	result, err := d.collection.UpdateMany(ctx, bson.M{"authorId": fromID}, bson.M{"$set": updates})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// ForEach 按ID顺序遍历全部文章（包括草稿和已归档的），用于数据迁移，fn返回错误时停止遍历
+func (d *PostDAO) ForEach(ctx context.Context, fn func(post *model.Post) error) error {
+	opts := options.Find().SetSort(bson.D{bson.E{Key: "_id", Value: 1}})
+	cursor, err := d.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var post model.Post
+		if err := cursor.Decode(&post); err != nil {
+			return err
+		}
+		if err := fn(&post); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// SetRendered 保存重新渲染的HTML、目录、摘要和内容指标，内容本身未变化，不修改更新时间
+func (d *PostDAO) SetRendered(ctx context.Context, post *model.Post) error {
+	result, err := d.collection.UpdateOne(ctx, bson.M{"_id": post.ID}, bson.M{"$set": bson.M{
+		"html":        post.HTML,
+		"toc":         post.TOC,
+		"excerpt":     post.Excerpt,
+		"autoExcerpt": post.AutoExcerpt,
+		"wordCount":   post.WordCount,
+		"readingTime": post.ReadingTime,
+	}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("post not found")
+	}
+
+	return nil
+}
+
 // CreateIndexes 创建文章集合的索引
 func (d *PostDAO) CreateIndexes(ctx context.Context) error {
 	indexes := []mongo.IndexModel{
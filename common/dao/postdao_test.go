@@ -9,8 +9,10 @@ import (
 	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
 	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func TestPostDAO_Create(t *testing.T) {
@@ -35,6 +37,7 @@ func TestPostDAO_Create(t *testing.T) {
 			authorID := primitive.NewObjectID()
 			post := &model.Post{
 				Title:      "测试文章",
+				Slug:       "ce-shi-wen-zhang",
 				Markdown:   "测试内容",
 				Type:       constants.PostTypePost,
 				Status:     constants.PostStatusDraft,
@@ -45,7 +48,7 @@ func TestPostDAO_Create(t *testing.T) {
 			err := postDAO.Create(context.Background(), post)
 			So(err, ShouldBeNil)
 			So(post.ID, ShouldNotEqual, primitive.NilObjectID)
-			So(post.Slug, ShouldNotBeEmpty)
+			So(post.Slug, ShouldEqual, "ce-shi-wen-zhang")
 		})
 
 		Convey("Should return error when slug already exists", func() {
@@ -725,6 +728,78 @@ func TestPostDAO_QueryBuilders(t *testing.T) {
 	})
 }
 
+func TestPostDAO_ReassignAuthor(t *testing.T) {
+	Convey("PostDAO ReassignAuthor Tests", t, func() {
+		postDAO := &PostDAO{
+			collection: &mongo.Collection{}, // Mock collection
+		}
+		from, to := primitive.NewObjectID(), primitive.NewObjectID()
+
+		Convey("Should return error when author ID format is invalid", func() {
+			_, err := postDAO.ReassignAuthor(context.Background(), "invalid", to.Hex(), "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Should move posts and optionally update status", func() {
+			var gotFilter, gotUpdate bson.M
+			mock := mockey.Mock((*mongo.Collection).UpdateMany).To(func(c *mongo.Collection, ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+				gotFilter, gotUpdate = filter.(bson.M), update.(bson.M)
+				return &mongo.UpdateResult{ModifiedCount: 4}, nil
+			}).Build()
+			defer mock.UnPatch()
+
+			count, err := postDAO.ReassignAuthor(context.Background(), from.Hex(), to.Hex(), constants.PostStatusArchived)
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 4)
+			So(gotFilter, ShouldResemble, bson.M{"authorId": from})
+			set := gotUpdate["$set"].(bson.M)
+			So(set["authorId"], ShouldEqual, to)
+			So(set["status"], ShouldEqual, constants.PostStatusArchived)
+
+			_, err = postDAO.ReassignAuthor(context.Background(), from.Hex(), to.Hex(), "")
+			So(err, ShouldBeNil)
+			So(gotUpdate["$set"].(bson.M), ShouldNotContainKey, "status")
+		})
+	})
+}
+
+func TestPostDAO_SetRendered(t *testing.T) {
+	Convey("PostDAO SetRendered Tests", t, func() {
+		postDAO := &PostDAO{
+			collection: &mongo.Collection{}, // Mock collection
+		}
+		id := primitive.NewObjectID()
+
+		toc := []model.TOCItem{{Level: 2, Text: "标题", ID: "标题"}}
+		post := &model.Post{ID: id, HTML: "<p>内容</p>", TOC: toc, Excerpt: "内容", AutoExcerpt: true, WordCount: 2, ReadingTime: 1}
+
+		Convey("Should only set rendered fields without touching updatedAt", func() {
+			var gotFilter, gotUpdate bson.M
+			mock := mockey.Mock((*mongo.Collection).UpdateOne).To(func(c *mongo.Collection, ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+				gotFilter, gotUpdate = filter.(bson.M), update.(bson.M)
+				return &mongo.UpdateResult{MatchedCount: 1}, nil
+			}).Build()
+			defer mock.UnPatch()
+
+			err := postDAO.SetRendered(context.Background(), post)
+			So(err, ShouldBeNil)
+			So(gotFilter, ShouldResemble, bson.M{"_id": id})
+			So(gotUpdate, ShouldResemble, bson.M{"$set": bson.M{
+				"html": "<p>内容</p>", "toc": toc, "excerpt": "内容", "autoExcerpt": true, "wordCount": 2, "readingTime": 1,
+			}})
+		})
+
+		Convey("Should return error when post does not exist", func() {
+			mock := mockey.Mock((*mongo.Collection).UpdateOne).Return(&mongo.UpdateResult{MatchedCount: 0}, nil).Build()
+			defer mock.UnPatch()
+
+			err := postDAO.SetRendered(context.Background(), post)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "post not found")
+		})
+	})
+}
+
 func TestPostDAO_CreateIndexes(t *testing.T) {
 	Convey("PostDAO CreateIndexes Tests", t, func() {
 		postDAO := &PostDAO{
@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/common/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SettingDAO 站点设置数据访问层
+type SettingDAO struct {
+	collection *mongo.Collection
+}
+
+// NewSettingDAO 创建站点设置DAO实例
+func NewSettingDAO(database *mongo.Database) *SettingDAO {
+	return &SettingDAO{
+		collection: database.Collection("settings"),
+	}
+}
+
+// GetByKey 根据键获取设置，不存在时返回nil
+func (d *SettingDAO) GetByKey(ctx context.Context, key string) (*model.Setting, error) {
+	if key == "" {
+		return nil, errors.New("key cannot be empty")
+	}
+
+	var setting model.Setting
+	err := d.collection.FindOne(ctx, bson.M{"key": key}).Decode(&setting)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &setting, nil
+}
+
+// ListByGroup 获取分组下的全部设置
+func (d *SettingDAO) ListByGroup(ctx context.Context, group string) ([]*model.Setting, error) {
+	opts := options.Find().SetSort(bson.D{bson.E{Key: "key", Value: 1}})
+	cursor, err := d.collection.Find(ctx, bson.M{"group": group}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var settings []*model.Setting
+	if err := cursor.All(ctx, &settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// Set 写入设置，不存在时创建
+func (d *SettingDAO) Set(ctx context.Context, key, value, group string) error {
+	if key == "" {
+		return errors.New("key cannot be empty")
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"value":     value,
+			"group":     group,
+			"updatedAt": now,
+		},
+		"$setOnInsert": bson.M{
+			"createdAt": now,
+		},
+	}
+
+	_, err := d.collection.UpdateOne(ctx, bson.M{"key": key}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// CreateIndexes 创建索引
+func (d *SettingDAO) CreateIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{bson.E{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{bson.E{Key: "group", Value: 1}},
+		},
+	}
+
+	_, err := d.collection.Indexes().CreateMany(ctx, indexes)
+	return err
+}
@@ -0,0 +1,77 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+)
+
+func TestSettingDAO(t *testing.T) {
+	Convey("SettingDAO Tests", t, func() {
+		settingDAO := &SettingDAO{
+			collection: &mongo.Collection{},
+		}
+		ctx := context.Background()
+
+		Convey("GetByKey should reject empty key", func() {
+			setting, err := settingDAO.GetByKey(ctx, "")
+			So(err, ShouldNotBeNil)
+			So(setting, ShouldBeNil)
+		})
+
+		Convey("GetByKey should return setting when found", func() {
+			mock1 := mockey.Mock((*mongo.Collection).FindOne).Return(&mongo.SingleResult{}).Build()
+			defer mock1.UnPatch()
+			mock2 := mockey.Mock((*mongo.SingleResult).Decode).To(func(sr *mongo.SingleResult, v interface{}) error {
+				if settingPtr, ok := v.(*model.Setting); ok {
+					settingPtr.Key = constants.SettingKeyMFARequiredRoles
+					settingPtr.Value = "owner,admin"
+				}
+				return nil
+			}).Build()
+			defer mock2.UnPatch()
+
+			setting, err := settingDAO.GetByKey(ctx, constants.SettingKeyMFARequiredRoles)
+			So(err, ShouldBeNil)
+			So(setting.Value, ShouldEqual, "owner,admin")
+		})
+
+		Convey("GetByKey should return nil when not found", func() {
+			mock1 := mockey.Mock((*mongo.Collection).FindOne).Return(&mongo.SingleResult{}).Build()
+			defer mock1.UnPatch()
+			mock2 := mockey.Mock((*mongo.SingleResult).Decode).Return(mongo.ErrNoDocuments).Build()
+			defer mock2.UnPatch()
+
+			setting, err := settingDAO.GetByKey(ctx, "missing")
+			So(err, ShouldBeNil)
+			So(setting, ShouldBeNil)
+		})
+
+		Convey("Set should upsert by key", func() {
+			var gotFilter, gotUpdate interface{}
+			var upsert bool
+			mock := mockey.Mock((*mongo.Collection).UpdateOne).To(func(c *mongo.Collection, ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+				gotFilter, gotUpdate = filter, update
+				upsert = len(opts) > 0 && opts[0].Upsert != nil && *opts[0].Upsert
+				return &mongo.UpdateResult{UpsertedCount: 1}, nil
+			}).Build()
+			defer mock.UnPatch()
+
+			err := settingDAO.Set(ctx, constants.SettingKeyMFARequiredRoles, "owner", constants.SettingGroupSecurity)
+			So(err, ShouldBeNil)
+			So(upsert, ShouldBeTrue)
+			So(gotFilter, ShouldResemble, bson.M{"key": constants.SettingKeyMFARequiredRoles})
+			set := gotUpdate.(bson.M)["$set"].(bson.M)
+			So(set["value"], ShouldEqual, "owner")
+			So(set["group"], ShouldEqual, constants.SettingGroupSecurity)
+		})
+	})
+}
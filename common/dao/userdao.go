@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -269,6 +270,46 @@ func (d *UserDAO) List(ctx context.Context, filter map[string]interface{}, page,
 	return users, total, nil
 }
 
+// ErrLastOwner 操作会移除最后一个正常状态的所有者
+var ErrLastOwner = errors.New("cannot remove the last active owner")
+
+// RemoveOwner 在事务中执行移除正常状态所有者的写操作（降级、暂停、删除），fn需使用传入的ctx写入才能加入事务
+// 事务先给其余正常状态的所有者递增ownerGuard，匹配数即剩余所有者数量，为0时返回ErrLastOwner且不执行fn；
+// 并发移除所有者的事务会写入同一批文档而发生写冲突并重试，检查和写入之间不会被穿插
+func (d *UserDAO) RemoveOwner(ctx context.Context, id string, fn func(ctx context.Context) error) error {
+	if id == "" {
+		return errors.New("id cannot be empty")
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid id format")
+	}
+
+	session, err := d.collection.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		filter := bson.M{
+			"_id":    bson.M{"$ne": objectID},
+			"role":   constants.UserRoleOwner,
+			"status": constants.UserStatusActive,
+		}
+		result, err := d.collection.UpdateMany(sc, filter, bson.M{"$inc": bson.M{"ownerGuard": 1}})
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			return nil, ErrLastOwner
+		}
+		return nil, fn(sc)
+	})
+	return err
+}
+
 // UpdateLoginInfo 更新登录信息
 func (d *UserDAO) UpdateLoginInfo(ctx context.Context, id string, ipAddress string) error {
 	if id == "" {
@@ -300,21 +341,61 @@ func (d *UserDAO) UpdateLoginInfo(ctx context.Context, id string, ipAddress stri
 	return nil
 }
 
-// IncrementLoginFailCount 增加登录失败次数
-func (d *UserDAO) IncrementLoginFailCount(ctx context.Context, id string) error {
+// IncrementLoginFailCount 增加登录失败次数，返回累加后的次数
+func (d *UserDAO) IncrementLoginFailCount(ctx context.Context, id string) (int, error) {
 	if id == "" {
-		return errors.New("id cannot be empty")
+		return 0, errors.New("id cannot be empty")
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid id format")
+		return 0, errors.New("invalid id format")
 	}
 
 	updates := bson.M{
 		"$inc": bson.M{"loginFailCount": 1},
 		"$set": bson.M{"updatedAt": time.Now()},
 	}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"loginFailCount": 1})
+
+	var user model.User
+	err = d.collection.FindOneAndUpdate(ctx, bson.M{"_id": objectID}, updates, opts).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, errors.New("user not found")
+		}
+		return 0, err
+	}
+
+	return user.LoginFailCount, nil
+}
+
+// ResetLoginFailCount 清零登录失败次数并解除到期的锁定，与 model.User.ResetLoginFailCount 保持一致
+func (d *UserDAO) ResetLoginFailCount(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("id cannot be empty")
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid id format")
+	}
+
+	// 使用聚合管道更新，只有锁定状态才恢复为活跃，不影响被暂停或禁用的账户
+	updates := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"loginFailCount": 0,
+			"status": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$status", constants.UserStatusLocked}},
+				constants.UserStatusActive,
+				"$status",
+			}},
+			"updatedAt": time.Now(),
+		}}},
+		{{Key: "$unset", Value: "lockedUntil"}},
+	}
 
 	result, err := d.collection.UpdateOne(ctx, bson.M{"_id": objectID}, updates)
 	if err != nil {
@@ -392,6 +473,64 @@ func (d *UserDAO) UnlockUser(ctx context.Context, id string) error {
 	return nil
 }
 
+// UseTOTPStep 记录已使用的TOTP时间步，只有时间步大于上次记录时才更新成功，用于防止验证码重放
+func (d *UserDAO) UseTOTPStep(ctx context.Context, id string, step int64) (bool, error) {
+	if id == "" {
+		return false, errors.New("id cannot be empty")
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, errors.New("invalid id format")
+	}
+
+	filter := bson.M{
+		"_id": objectID,
+		"$or": bson.A{
+			bson.M{"twoFactorLastStep": bson.M{"$lt": step}},
+			bson.M{"twoFactorLastStep": bson.M{"$exists": false}},
+		},
+	}
+	updates := bson.M{
+		"$set": bson.M{
+			"twoFactorLastStep": step,
+			"updatedAt":         time.Now(),
+		},
+	}
+
+	result, err := d.collection.UpdateOne(ctx, filter, updates)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+// ConsumeRecoveryCode 消费一个恢复码，恢复码不存在或已被使用时返回false
+func (d *UserDAO) ConsumeRecoveryCode(ctx context.Context, id string, codeHash string) (bool, error) {
+	if id == "" {
+		return false, errors.New("id cannot be empty")
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, errors.New("invalid id format")
+	}
+
+	filter := bson.M{"_id": objectID, "recoveryCodes": codeHash}
+	updates := bson.M{
+		"$pull": bson.M{"recoveryCodes": codeHash},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+
+	result, err := d.collection.UpdateOne(ctx, filter, updates)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
 // GetLockedUsers 获取被锁定的用户列表
 func (d *UserDAO) GetLockedUsers(ctx context.Context) ([]*model.User, error) {
 	query := bson.M{
@@ -7,8 +7,10 @@ import (
 
 	"github.com/bytedance/mockey"
 	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
@@ -391,6 +393,71 @@ func TestUserDAO_List(t *testing.T) {
 	})
 }
 
+// fakeSession 不连接数据库的会话，WithTransaction直接执行回调
+type fakeSession struct {
+	mongo.Session
+}
+
+func (s *fakeSession) WithTransaction(ctx context.Context, fn func(ctx mongo.SessionContext) (interface{}, error), _ ...*options.TransactionOptions) (interface{}, error) {
+	return fn(mongo.NewSessionContext(ctx, s))
+}
+
+func (s *fakeSession) EndSession(context.Context) {}
+
+func TestUserDAO_RemoveOwner(t *testing.T) {
+	mockey.PatchConvey("UserDAO RemoveOwner Tests", t, func() {
+		userDAO := &UserDAO{
+			collection: &mongo.Collection{}, // Mock collection
+		}
+		mockey.Mock((*mongo.Collection).Database).Return(&mongo.Database{}).Build()
+		mockey.Mock((*mongo.Database).Client).Return(&mongo.Client{}).Build()
+		mockey.Mock((*mongo.Client).StartSession).Return(&fakeSession{}, nil).Build()
+
+		var gotFilter, gotUpdate bson.M
+		var remaining int64
+		mockey.Mock((*mongo.Collection).UpdateMany).To(func(c *mongo.Collection, ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+			gotFilter, gotUpdate = filter.(bson.M), update.(bson.M)
+			return &mongo.UpdateResult{MatchedCount: remaining, ModifiedCount: remaining}, nil
+		}).Build()
+
+		objectID := primitive.NewObjectID()
+		written := false
+		write := func(ctx context.Context) error {
+			_, inTransaction := ctx.(mongo.SessionContext)
+			written = inTransaction
+			return nil
+		}
+
+		Convey("Should return error when ID format is invalid", func() {
+			err := userDAO.RemoveOwner(context.Background(), "invalid-id", write)
+			So(err, ShouldNotBeNil)
+			So(written, ShouldBeFalse)
+		})
+
+		Convey("Should write inside the transaction while other active owners remain", func() {
+			remaining = 1
+
+			err := userDAO.RemoveOwner(context.Background(), objectID.Hex(), write)
+			So(err, ShouldBeNil)
+			So(written, ShouldBeTrue)
+			So(gotFilter, ShouldResemble, bson.M{
+				"_id":    bson.M{"$ne": objectID},
+				"role":   constants.UserRoleOwner,
+				"status": constants.UserStatusActive,
+			})
+			So(gotUpdate, ShouldResemble, bson.M{"$inc": bson.M{"ownerGuard": 1}})
+		})
+
+		Convey("Should refuse to remove the last active owner", func() {
+			remaining = 0
+
+			err := userDAO.RemoveOwner(context.Background(), objectID.Hex(), write)
+			So(err, ShouldEqual, ErrLastOwner)
+			So(written, ShouldBeFalse)
+		})
+	})
+}
+
 func TestUserDAO_LoginMethods(t *testing.T) {
 	Convey("UserDAO Login Methods Tests", t, func() {
 		userDAO := &UserDAO{
@@ -410,7 +477,23 @@ func TestUserDAO_LoginMethods(t *testing.T) {
 			So(err, ShouldBeNil)
 		})
 
-		Convey("IncrementLoginFailCount should work correctly", func() {
+		Convey("IncrementLoginFailCount should return the new count", func() {
+			// Mock MongoDB FindOneAndUpdate method
+			mock1 := mockey.Mock((*mongo.Collection).FindOneAndUpdate).Return(&mongo.SingleResult{}).Build()
+			defer mock1.UnPatch()
+			mock2 := mockey.Mock((*mongo.SingleResult).Decode).To(func(_ *mongo.SingleResult, v interface{}) error {
+				v.(*model.User).LoginFailCount = 3
+				return nil
+			}).Build()
+			defer mock2.UnPatch()
+
+			objectID := primitive.NewObjectID()
+			count, err := userDAO.IncrementLoginFailCount(context.Background(), objectID.Hex())
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("ResetLoginFailCount should work correctly", func() {
 			// Mock MongoDB UpdateOne method
 			mock := mockey.Mock((*mongo.Collection).UpdateOne).Return(&mongo.UpdateResult{
 				MatchedCount:  1,
@@ -419,7 +502,7 @@ func TestUserDAO_LoginMethods(t *testing.T) {
 			defer mock.UnPatch()
 
 			objectID := primitive.NewObjectID()
-			err := userDAO.IncrementLoginFailCount(context.Background(), objectID.Hex())
+			err := userDAO.ResetLoginFailCount(context.Background(), objectID.Hex())
 			So(err, ShouldBeNil)
 		})
 
@@ -485,6 +568,55 @@ func TestUserDAO_LoginMethods(t *testing.T) {
 	})
 }
 
+func TestUserDAO_TwoFactorMethods(t *testing.T) {
+	Convey("UserDAO Two Factor Methods Tests", t, func() {
+		userDAO := &UserDAO{
+			collection: &mongo.Collection{}, // Mock collection
+		}
+		objectID := primitive.NewObjectID()
+
+		Convey("UseTOTPStep should accept a newer step", func() {
+			mock := mockey.Mock((*mongo.Collection).UpdateOne).Return(&mongo.UpdateResult{
+				MatchedCount:  1,
+				ModifiedCount: 1,
+			}, nil).Build()
+			defer mock.UnPatch()
+
+			ok, err := userDAO.UseTOTPStep(context.Background(), objectID.Hex(), 100)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("UseTOTPStep should reject a replayed step", func() {
+			mock := mockey.Mock((*mongo.Collection).UpdateOne).Return(&mongo.UpdateResult{}, nil).Build()
+			defer mock.UnPatch()
+
+			ok, err := userDAO.UseTOTPStep(context.Background(), objectID.Hex(), 100)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("ConsumeRecoveryCode should report whether a code was removed", func() {
+			mock := mockey.Mock((*mongo.Collection).UpdateOne).Return(&mongo.UpdateResult{
+				MatchedCount:  1,
+				ModifiedCount: 1,
+			}, nil).Build()
+			defer mock.UnPatch()
+
+			ok, err := userDAO.ConsumeRecoveryCode(context.Background(), objectID.Hex(), "hash")
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("Should reject invalid id", func() {
+			_, err := userDAO.UseTOTPStep(context.Background(), "invalid", 1)
+			So(err, ShouldNotBeNil)
+			_, err = userDAO.ConsumeRecoveryCode(context.Background(), "", "hash")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
 func TestUserDAO_CreateIndexes(t *testing.T) {
 	Convey("UserDAO CreateIndexes Tests", t, func() {
 		userDAO := &UserDAO{
@@ -0,0 +1,119 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/heimdall-api/common/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebAuthnCredentialDAO 通行密钥数据访问层
+type WebAuthnCredentialDAO struct {
+	collection *mongo.Collection
+}
+
+// NewWebAuthnCredentialDAO 创建通行密钥DAO实例
+func NewWebAuthnCredentialDAO(database *mongo.Database) *WebAuthnCredentialDAO {
+	return &WebAuthnCredentialDAO{
+		collection: database.Collection("webauthnCredentials"),
+	}
+}
+
+// Create 保存新凭据
+func (d *WebAuthnCredentialDAO) Create(ctx context.Context, credential *model.WebAuthnCredential) error {
+	if credential.UserID.IsZero() || credential.CredentialID == "" || len(credential.PublicKey) == 0 {
+		return errors.New("credential is incomplete")
+	}
+
+	credential.ID = primitive.NewObjectID()
+	credential.CreatedAt = time.Now()
+
+	_, err := d.collection.InsertOne(ctx, credential)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("credential already registered")
+		}
+		return err
+	}
+	return nil
+}
+
+// GetByCredentialID 根据凭据ID获取凭据，不存在时返回nil
+func (d *WebAuthnCredentialDAO) GetByCredentialID(ctx context.Context, credentialID string) (*model.WebAuthnCredential, error) {
+	if credentialID == "" {
+		return nil, errors.New("credential id cannot be empty")
+	}
+
+	var credential model.WebAuthnCredential
+	err := d.collection.FindOne(ctx, bson.M{"credentialId": credentialID}).Decode(&credential)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &credential, nil
+}
+
+// ListByUserID 获取用户的全部凭据，按创建时间排序
+func (d *WebAuthnCredentialDAO) ListByUserID(ctx context.Context, userID primitive.ObjectID) ([]*model.WebAuthnCredential, error) {
+	opts := options.Find().SetSort(bson.D{bson.E{Key: "createdAt", Value: 1}})
+	cursor, err := d.collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var credentials []*model.WebAuthnCredential
+	if err := cursor.All(ctx, &credentials); err != nil {
+		return nil, err
+	}
+
+	return credentials, nil
+}
+
+// UpdateUsage 登录成功后记录签名计数和使用信息
+func (d *WebAuthnCredentialDAO) UpdateUsage(ctx context.Context, id primitive.ObjectID, signCount uint32, backedUp bool, ip string) error {
+	update := bson.M{
+		"$set": bson.M{
+			"signCount":  signCount,
+			"backedUp":   backedUp,
+			"lastUsedAt": time.Now(),
+			"lastUsedIp": ip,
+		},
+	}
+
+	_, err := d.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// Delete 删除用户的凭据，返回是否删除成功
+func (d *WebAuthnCredentialDAO) Delete(ctx context.Context, id, userID primitive.ObjectID) (bool, error) {
+	result, err := d.collection.DeleteOne(ctx, bson.M{"_id": id, "userId": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// CreateIndexes 创建索引
+func (d *WebAuthnCredentialDAO) CreateIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{bson.E{Key: "credentialId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{bson.E{Key: "userId", Value: 1}},
+		},
+	}
+
+	_, err := d.collection.Indexes().CreateMany(ctx, indexes)
+	return err
+}
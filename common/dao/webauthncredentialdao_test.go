@@ -0,0 +1,67 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bytedance/mockey"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/heimdall-api/common/model"
+)
+
+func TestWebAuthnCredentialDAO(t *testing.T) {
+	Convey("WebAuthnCredentialDAO Tests", t, func() {
+		credentialDAO := &WebAuthnCredentialDAO{
+			collection: &mongo.Collection{},
+		}
+		ctx := context.Background()
+		userID := primitive.NewObjectID()
+
+		Convey("Create should reject incomplete credential", func() {
+			err := credentialDAO.Create(ctx, &model.WebAuthnCredential{UserID: userID})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Create should assign id and creation time", func() {
+			mock := mockey.Mock((*mongo.Collection).InsertOne).Return(&mongo.InsertOneResult{}, nil).Build()
+			defer mock.UnPatch()
+
+			credential := &model.WebAuthnCredential{UserID: userID, CredentialID: "Y3JlZA", PublicKey: []byte{1}}
+			err := credentialDAO.Create(ctx, credential)
+			So(err, ShouldBeNil)
+			So(credential.ID.IsZero(), ShouldBeFalse)
+			So(credential.CreatedAt.IsZero(), ShouldBeFalse)
+		})
+
+		Convey("GetByCredentialID should return nil when not found", func() {
+			mock1 := mockey.Mock((*mongo.Collection).FindOne).Return(&mongo.SingleResult{}).Build()
+			defer mock1.UnPatch()
+			mock2 := mockey.Mock((*mongo.SingleResult).Decode).Return(mongo.ErrNoDocuments).Build()
+			defer mock2.UnPatch()
+
+			credential, err := credentialDAO.GetByCredentialID(ctx, "missing")
+			So(err, ShouldBeNil)
+			So(credential, ShouldBeNil)
+		})
+
+		Convey("Delete should be scoped to the owner", func() {
+			var gotFilter interface{}
+			mock := mockey.Mock((*mongo.Collection).DeleteOne).To(func(c *mongo.Collection, ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+				gotFilter = filter
+				return &mongo.DeleteResult{DeletedCount: 1}, nil
+			}).Build()
+			defer mock.UnPatch()
+
+			id := primitive.NewObjectID()
+			deleted, err := credentialDAO.Delete(ctx, id, userID)
+			So(err, ShouldBeNil)
+			So(deleted, ShouldBeTrue)
+			So(gotFilter, ShouldResemble, bson.M{"_id": id, "userId": userID})
+		})
+	})
+}
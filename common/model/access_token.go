@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AccessToken 个人访问令牌模型，只保存令牌哈希，明文仅在创建时返回一次
+type AccessToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID `bson:"userId" json:"userId"`                             // 所属用户
+	Name       string             `bson:"name" json:"name"`                                 // 令牌名称
+	TokenHash  string             `bson:"tokenHash" json:"-"`                               // 令牌SHA-256哈希
+	Prefix     string             `bson:"prefix" json:"prefix"`                             // 令牌开头字符，便于用户辨认
+	Scopes     []string           `bson:"scopes" json:"scopes"`                             // 作用域
+	ExpiresAt  *time.Time         `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"`   // 过期时间，为空表示永不过期
+	LastUsedAt *time.Time         `bson:"lastUsedAt,omitempty" json:"lastUsedAt,omitempty"` // 最后使用时间
+	LastUsedIP string             `bson:"lastUsedIp,omitempty" json:"lastUsedIp,omitempty"` // 最后使用IP
+	RevokedAt  *time.Time         `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`   // 吊销时间
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`                       // 创建时间
+}
+
+// IsExpired 检查令牌是否已过期
+func (t *AccessToken) IsExpired() bool {
+	return t.ExpiresAt != nil && !time.Now().Before(*t.ExpiresAt)
+}
+
+// IsRevoked 检查令牌是否已吊销
+func (t *AccessToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// IsUsable 检查令牌当前是否可用于认证
+func (t *AccessToken) IsUsable() bool {
+	return !t.IsRevoked() && !t.IsExpired()
+}
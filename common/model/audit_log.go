@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditLog 管理操作审计日志模型
+// 每个修改数据的管理接口请求（含被拒绝的请求）记录一条，由TTL索引按保留期限自动清理
+type AuditLog struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ActorID       primitive.ObjectID `bson:"actorId" json:"actorId"`                                 // 操作者用户ID
+	ActorName     string             `bson:"actorName" json:"actorName"`                             // 操作者用户名
+	ActorRole     string             `bson:"actorRole" json:"actorRole"`                             // 操作时的角色
+	AccessTokenID string             `bson:"accessTokenId,omitempty" json:"accessTokenId,omitempty"` // 使用个人访问令牌操作时的令牌ID
+	Action        string             `bson:"action" json:"action"`                                   // 操作，如 post.publish、user.suspend
+	TargetType    string             `bson:"targetType" json:"targetType"`                           // 操作对象类型
+	TargetID      string             `bson:"targetId,omitempty" json:"targetId,omitempty"`           // 操作对象ID
+	Changes       []AuditChange      `bson:"changes,omitempty" json:"changes,omitempty"`             // 变更字段的前后值
+	Method        string             `bson:"method" json:"method"`                                   // HTTP方法
+	Path          string             `bson:"path" json:"path"`                                       // 请求路径
+	StatusCode    int                `bson:"statusCode" json:"statusCode"`                           // 响应状态码
+	IPAddress     string             `bson:"ipAddress" json:"ipAddress"`                             // 客户端IP
+	UserAgent     string             `bson:"userAgent" json:"userAgent"`                             // 用户代理
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`                             // 操作时间
+}
+
+// AuditChange 单个字段的变更
+type AuditChange struct {
+	Field  string      `bson:"field" json:"field"`                       // 字段名
+	Before interface{} `bson:"before,omitempty" json:"before,omitempty"` // 变更前的值
+	After  interface{} `bson:"after,omitempty" json:"after,omitempty"`   // 变更后的值
+}
+
+// IsSuccess 检查操作是否成功
+func (l *AuditLog) IsSuccess() bool {
+	return l.StatusCode > 0 && l.StatusCode < 400
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/heimdall-api/common/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Invitation 用户邀请模型，邀请链接为签名令牌，库中只保存最近一次发送的令牌哈希
+type Invitation struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Email          string              `bson:"email" json:"email"`                                       // 受邀邮箱
+	Role           string              `bson:"role" json:"role"`                                         // 接受后授予的角色
+	TokenHash      string              `bson:"tokenHash" json:"-"`                                       // 当前有效链接令牌的SHA-256哈希，重发后旧链接失效
+	Status         string              `bson:"status" json:"status"`                                     // 邀请状态
+	InvitedBy      primitive.ObjectID  `bson:"invitedBy" json:"invitedBy"`                               // 邀请人
+	SendCount      int                 `bson:"sendCount" json:"sendCount"`                               // 发送次数
+	LastSentAt     time.Time           `bson:"lastSentAt" json:"lastSentAt"`                             // 最后发送时间
+	ExpiresAt      time.Time           `bson:"expiresAt" json:"expiresAt"`                               // 当前链接过期时间
+	AcceptedAt     *time.Time          `bson:"acceptedAt,omitempty" json:"acceptedAt,omitempty"`         // 接受时间
+	AcceptedUserID *primitive.ObjectID `bson:"acceptedUserId,omitempty" json:"acceptedUserId,omitempty"` // 接受后创建的用户
+	RevokedAt      *time.Time          `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`           // 撤销时间
+	CreatedAt      time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time           `bson:"updatedAt" json:"updatedAt"`
+}
+
+// IsExpired 检查邀请链接是否已过期
+func (i *Invitation) IsExpired() bool {
+	return !time.Now().Before(i.ExpiresAt)
+}
+
+// IsPending 检查邀请是否仍可接受
+func (i *Invitation) IsPending() bool {
+	return i.Status == constants.InvitationStatusPending && !i.IsExpired()
+}
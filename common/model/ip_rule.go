@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IPRule IP访问规则模型
+// 永久规则保存在数据库中，支持单个IP和CIDR网段；临时封禁只针对单个IP，以相同结构序列化保存在Redis中并由TTL自动过期
+type IPRule struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Type          string             `bson:"type" json:"type"`                               // 规则类型：block/allow
+	CIDR          string             `bson:"cidr" json:"cidr"`                               // 规范化后的网段，单个IP保存为/32或/128
+	Reason        string             `bson:"reason" json:"reason"`                           // 添加原因
+	CreatedBy     string             `bson:"createdBy" json:"createdBy"`                     // 创建者用户ID，系统自动添加时为system
+	CreatedByName string             `bson:"createdByName" json:"createdByName"`             // 创建者用户名
+	ExpiresAt     *time.Time         `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"` // 过期时间，仅临时封禁有值
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`                     // 创建时间
+}
+
+// IsTemporary 检查是否为临时封禁
+func (r *IPRule) IsTemporary() bool {
+	return r.ExpiresAt != nil
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"math"
 	"time"
 
 	"github.com/heimdall-api/common/constants"
@@ -27,6 +28,9 @@ type LoginLog struct {
 	DeviceType  string              `bson:"deviceType,omitempty" json:"deviceType,omitempty"` // 设备类型
 	Browser     string              `bson:"browser,omitempty" json:"browser,omitempty"`       // 浏览器
 	OS          string              `bson:"os,omitempty" json:"os,omitempty"`                 // 操作系统
+	Latitude    float64             `bson:"latitude,omitempty" json:"latitude,omitempty"`     // 纬度
+	Longitude   float64             `bson:"longitude,omitempty" json:"longitude,omitempty"`   // 经度
+	Anomalies   []string            `bson:"anomalies,omitempty" json:"anomalies,omitempty"`   // 登录异常标记，仅成功登录检测
 	CreatedAt   time.Time           `bson:"createdAt" json:"createdAt"`                       // 记录创建时间
 }
 
@@ -83,6 +87,7 @@ type LoginLogListItem struct {
 	DeviceType  string     `json:"deviceType,omitempty"`
 	Browser     string     `json:"browser,omitempty"`
 	OS          string     `json:"os,omitempty"`
+	Anomalies   []string   `json:"anomalies,omitempty"`
 	LoginAt     time.Time  `json:"loginAt"`
 	LogoutAt    *time.Time `json:"logoutAt,omitempty"`
 	Duration    *int64     `json:"duration,omitempty"`
@@ -90,12 +95,37 @@ type LoginLogListItem struct {
 
 // LoginStatistics 登录统计信息
 type LoginStatistics struct {
-	TotalLogins   int64   `json:"totalLogins"`   // 总登录次数
-	SuccessLogins int64   `json:"successLogins"` // 成功登录次数
-	FailedLogins  int64   `json:"failedLogins"`  // 失败登录次数
-	UniqueUsers   int64   `json:"uniqueUsers"`   // 独立用户数
-	UniqueIPs     int64   `json:"uniqueIPs"`     // 独立IP数
-	SuccessRate   float64 `json:"successRate"`   // 成功率
+	TotalLogins   int64   `bson:"totalLogins" json:"totalLogins"`     // 总登录次数
+	SuccessLogins int64   `bson:"successLogins" json:"successLogins"` // 成功登录次数
+	FailedLogins  int64   `bson:"failedLogins" json:"failedLogins"`   // 失败登录次数
+	UniqueUsers   int64   `bson:"uniqueUsers" json:"uniqueUsers"`     // 独立用户数
+	UniqueIPs     int64   `bson:"uniqueIPs" json:"uniqueIPs"`         // 独立IP数
+	SuccessRate   float64 `bson:"-" json:"successRate"`               // 成功率
+}
+
+// LoginTimeBucket 登录统计时间序列中的一个时间段
+type LoginTimeBucket struct {
+	Bucket  string `bson:"_id" json:"bucket"`      // 时间段起点（UTC），按天为2006-01-02，按小时为2006-01-02T15
+	Total   int64  `bson:"total" json:"total"`     // 登录次数
+	Success int64  `bson:"success" json:"success"` // 成功次数
+	Failed  int64  `bson:"failed" json:"failed"`   // 失败次数
+}
+
+// LoginCountItem 按字段（IP、用户名、国家等）分组的登录次数
+type LoginCountItem struct {
+	Key    string    `bson:"_id" json:"key"`       // 分组值
+	Total  int64     `bson:"total" json:"total"`   // 登录次数
+	Failed int64     `bson:"failed" json:"failed"` // 失败次数
+	LastAt time.Time `bson:"lastAt" json:"lastAt"` // 最近一次登录时间
+}
+
+// CalculateSuccessRate 计算成功率（百分比，保留两位小数）
+func (s *LoginStatistics) CalculateSuccessRate() {
+	if s.TotalLogins == 0 {
+		s.SuccessRate = 0
+		return
+	}
+	s.SuccessRate = math.Round(float64(s.SuccessLogins)/float64(s.TotalLogins)*10000) / 100
 }
 
 // ===============================
@@ -178,6 +208,16 @@ func (l *LoginLog) IsFailed() bool {
 	return l.Status == constants.LoginStatusFailed
 }
 
+// IsAnomalous 检查是否被标记为异常登录
+func (l *LoginLog) IsAnomalous() bool {
+	return len(l.Anomalies) > 0
+}
+
+// HasCoordinates 检查是否包含经纬度
+func (l *LoginLog) HasCoordinates() bool {
+	return l.Latitude != 0 || l.Longitude != 0
+}
+
 // IsActiveSession 检查会话是否仍然活跃
 func (l *LoginLog) IsActiveSession() bool {
 	return l.IsSuccess() && l.LogoutAt == nil
@@ -220,6 +260,7 @@ func (l *LoginLog) ToListItem() *LoginLogListItem {
 		DeviceType:  l.DeviceType,
 		Browser:     l.Browser,
 		OS:          l.OS,
+		Anomalies:   l.Anomalies,
 		LoginAt:     l.LoginAt,
 		LogoutAt:    l.LogoutAt,
 		Duration:    l.Duration,
@@ -320,6 +361,12 @@ func (l *LoginLog) UpdateLocation(country, region, city string) {
 	l.City = city
 }
 
+// UpdateCoordinates 更新经纬度
+func (l *LoginLog) UpdateCoordinates(latitude, longitude float64) {
+	l.Latitude = latitude
+	l.Longitude = longitude
+}
+
 // UpdateDeviceInfo 更新设备信息
 func (l *LoginLog) UpdateDeviceInfo(deviceType, browser, os string) {
 	l.DeviceType = deviceType
@@ -333,7 +380,7 @@ func (l *LoginLog) UpdateDeviceInfo(deviceType, browser, os string) {
 
 // isValidLoginMethod 验证登录方式是否有效
 func isValidLoginMethod(method string) bool {
-	validMethods := []string{"username", "email"}
+	validMethods := constants.GetAllLoginMethods()
 	for _, validMethod := range validMethods {
 		if method == validMethod {
 			return true
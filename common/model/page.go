@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/slug"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -150,7 +151,7 @@ func (p *Page) ValidateForCreate() error {
 
 	// 验证slug格式
 	if p.Slug != "" && !IsValidSlug(p.Slug) {
-		return NewPageValidationError("slug", "slug格式无效，只能包含小写字母、数字和连字符")
+		return NewPageValidationError("slug", "slug格式无效，只能包含小写字母、其他文字、数字和连字符")
 	}
 
 	return nil
@@ -167,7 +168,7 @@ func (p *Page) ValidateForUpdate() error {
 			return NewPageValidationError("slug", "页面slug长度不能超过255字符")
 		}
 		if !IsValidSlug(p.Slug) {
-			return NewPageValidationError("slug", "slug格式无效，只能包含小写字母、数字和连字符")
+			return NewPageValidationError("slug", "slug格式无效，只能包含小写字母、其他文字、数字和连字符")
 		}
 	}
 	if p.Content != "" && len(p.Content) > constants.PostContentMaxLength {
@@ -227,18 +228,18 @@ func (p *Page) ShouldBePublishedNow() bool {
 // Slug处理方法
 // ===============================
 
-// GenerateSlug 自动生成slug
-func (p *Page) GenerateSlug() string {
+// GenerateSlug 按站点选择的slug策略从标题生成slug
+func (p *Page) GenerateSlug(strategy slug.Strategy) string {
 	if p.Title == "" {
 		return ""
 	}
-	return GenerateSlugFromText(p.Title)
+	return GenerateSlugWith(strategy, p.Title, constants.SlugFallbackPrefixPage)
 }
 
 // EnsureSlug 确保页面有有效的slug
-func (p *Page) EnsureSlug() {
+func (p *Page) EnsureSlug(strategy slug.Strategy) {
 	if p.Slug == "" {
-		p.Slug = p.GenerateSlug()
+		p.Slug = p.GenerateSlug(strategy)
 	}
 }
 
@@ -287,8 +288,8 @@ func (p *Page) ToListItem(author *AuthorInfo) *PageListItem {
 // 工厂方法
 // ===============================
 
-// NewPage 创建新页面
-func NewPage(title, content, status string, authorID primitive.ObjectID) *Page {
+// NewPage 创建新页面，按指定的slug策略生成slug
+func NewPage(title, content, status string, authorID primitive.ObjectID, strategy slug.Strategy) *Page {
 	now := time.Now()
 
 	page := &Page{
@@ -303,13 +304,13 @@ func NewPage(title, content, status string, authorID primitive.ObjectID) *Page {
 	}
 
 	// 自动生成slug
-	page.EnsureSlug()
+	page.EnsureSlug(strategy)
 
 	return page
 }
 
-// NewPageFromCreateRequest 从创建请求创建页面
-func NewPageFromCreateRequest(req *PageCreateRequest, authorID primitive.ObjectID) *Page {
+// NewPageFromCreateRequest 从创建请求创建页面，未提供slug时按指定策略生成
+func NewPageFromCreateRequest(req *PageCreateRequest, authorID primitive.ObjectID, strategy slug.Strategy) *Page {
 	now := time.Now()
 
 	page := &Page{
@@ -335,7 +336,7 @@ func NewPageFromCreateRequest(req *PageCreateRequest, authorID primitive.ObjectI
 	}
 
 	// 自动生成slug
-	page.EnsureSlug()
+	page.EnsureSlug(strategy)
 
 	return page
 }
@@ -344,7 +345,7 @@ func NewPageFromCreateRequest(req *PageCreateRequest, authorID primitive.ObjectI
 // 准备方法
 // ===============================
 
-// PrepareForInsert 准备插入数据库
+// PrepareForInsert 准备插入数据库，slug由调用方按站点的slug策略生成
 func (p *Page) PrepareForInsert() {
 	now := time.Now()
 	if p.ID.IsZero() {
@@ -357,9 +358,6 @@ func (p *Page) PrepareForInsert() {
 	if p.Template == "" {
 		p.Template = "default"
 	}
-
-	// 确保有slug
-	p.EnsureSlug()
 }
 
 // PrepareForUpdate 准备更新数据库
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/slug"
 	. "github.com/smartystreets/goconvey/convey"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -15,7 +16,7 @@ func TestPageModel(t *testing.T) {
 
 		Convey("页面创建", func() {
 			Convey("使用NewPage工厂方法", func() {
-				page := NewPage("测试页面", "测试内容", constants.PostStatusDraft, authorID)
+				page := NewPage("测试页面", "测试内容", constants.PostStatusDraft, authorID, slug.Get(constants.SlugStrategyDefault))
 
 				So(page, ShouldNotBeNil)
 				So(page.Title, ShouldEqual, "测试页面")
@@ -39,7 +40,7 @@ func TestPageModel(t *testing.T) {
 					MetaDescription: "SEO描述",
 				}
 
-				page := NewPageFromCreateRequest(req, authorID)
+				page := NewPageFromCreateRequest(req, authorID, slug.Get(constants.SlugStrategyDefault))
 
 				So(page, ShouldNotBeNil)
 				So(page.Title, ShouldEqual, req.Title)
@@ -59,7 +60,7 @@ func TestPageModel(t *testing.T) {
 					Status:  constants.PostStatusDraft,
 				}
 
-				page := NewPageFromCreateRequest(req, authorID)
+				page := NewPageFromCreateRequest(req, authorID, slug.Get(constants.SlugStrategyDefault))
 
 				So(page.Template, ShouldEqual, "default")
 			})
@@ -346,16 +347,15 @@ func TestPageModel(t *testing.T) {
 		Convey("Slug处理", func() {
 			Convey("自动生成slug", func() {
 				page := &Page{Title: "测试页面标题"}
-				slug := page.GenerateSlug()
-
-				So(slug, ShouldNotBeEmpty)
-				So(slug, ShouldNotContainSubstring, " ")
+				So(page.GenerateSlug(slug.Get(constants.SlugStrategyPinyin)), ShouldEqual, "ce-shi-ye-mian-biao-ti")
+				So(page.GenerateSlug(slug.Get(constants.SlugStrategyUnicode)), ShouldEqual, "测试页面标题")
+				So(page.GenerateSlug(slug.Get(constants.SlugStrategyASCII)), ShouldStartWith, "page-")
 			})
 
 			Convey("确保有slug", func() {
 				Convey("没有slug时自动生成", func() {
 					page := &Page{Title: "测试页面"}
-					page.EnsureSlug()
+					page.EnsureSlug(slug.Get(constants.SlugStrategyDefault))
 
 					So(page.Slug, ShouldNotBeEmpty)
 				})
@@ -366,7 +366,7 @@ func TestPageModel(t *testing.T) {
 						Title: "测试页面",
 						Slug:  existingSlug,
 					}
-					page.EnsureSlug()
+					page.EnsureSlug(slug.Get(constants.SlugStrategyDefault))
 
 					So(page.Slug, ShouldEqual, existingSlug)
 				})
@@ -438,7 +438,6 @@ func TestPageModel(t *testing.T) {
 				So(page.CreatedAt, ShouldNotBeZeroValue)
 				So(page.UpdatedAt, ShouldNotBeZeroValue)
 				So(page.Template, ShouldEqual, "default")
-				So(page.Slug, ShouldNotBeEmpty)
 			})
 
 			Convey("准备更新", func() {
@@ -2,13 +2,11 @@ package model
 
 import (
 	"fmt"
-	"regexp"
 	"strconv"
-	"strings"
 	"time"
-	"unicode/utf8"
 
 	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/slug"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -18,6 +16,7 @@ type Post struct {
 	Title           string             `bson:"title" json:"title"`
 	Slug            string             `bson:"slug" json:"slug"`
 	Excerpt         string             `bson:"excerpt" json:"excerpt"`
+	AutoExcerpt     bool               `bson:"autoExcerpt" json:"-"` // 摘要由正文自动生成，内容修改或重新渲染时随之更新
 	Markdown        string             `bson:"markdown" json:"markdown"`
 	HTML            string             `bson:"html" json:"html"`
 	FeaturedImage   string             `bson:"featuredImage" json:"featuredImage"`
@@ -32,11 +31,22 @@ type Post struct {
 	ReadingTime     int                `bson:"readingTime" json:"readingTime"`
 	WordCount       int                `bson:"wordCount" json:"wordCount"`
 	ViewCount       int64              `bson:"viewCount" json:"viewCount"`
+	TOC             []TOCItem          `bson:"toc,omitempty" json:"toc,omitempty"`                 // 由Markdown标题生成的目录
+	DisableTOC      bool               `bson:"disableToc" json:"disableToc"`                       // 不生成目录
+	TOCMaxDepth     int                `bson:"tocMaxDepth,omitempty" json:"tocMaxDepth,omitempty"` // 目录层数，0为默认值
 	PublishedAt     *time.Time         `bson:"publishedAt,omitempty" json:"publishedAt,omitempty"`
 	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt       time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
 
+// TOCItem 文章目录项，下级标题嵌套在Children中
+type TOCItem struct {
+	Level    int       `bson:"level" json:"level"` // 标题级别，1-6
+	Text     string    `bson:"text" json:"text"`   // 标题纯文本
+	ID       string    `bson:"id" json:"id"`       // 标题锚点ID
+	Children []TOCItem `bson:"children,omitempty" json:"children,omitempty"`
+}
+
 // Tag 内嵌标签结构
 type Tag struct {
 	Name string `bson:"name" json:"name"`
@@ -218,7 +228,7 @@ func (p *Post) ValidateForCreate() error {
 
 	// 验证slug格式
 	if p.Slug != "" && !IsValidSlug(p.Slug) {
-		return NewPostValidationError("slug", "slug格式无效，只能包含小写字母、数字和连字符")
+		return NewPostValidationError("slug", "slug格式无效，只能包含小写字母、其他文字、数字和连字符")
 	}
 
 	return nil
@@ -235,7 +245,7 @@ func (p *Post) ValidateForUpdate() error {
 			return NewPostValidationError("slug", "文章slug长度不能超过255字符")
 		}
 		if !IsValidSlug(p.Slug) {
-			return NewPostValidationError("slug", "slug格式无效，只能包含小写字母、数字和连字符")
+			return NewPostValidationError("slug", "slug格式无效，只能包含小写字母、其他文字、数字和连字符")
 		}
 	}
 	if len(p.Excerpt) > constants.PostExcerptMaxLength {
@@ -321,98 +331,19 @@ func (p *Post) ShouldBePublishedNow() bool {
 // Slug处理方法
 // ===============================
 
-// GenerateSlug 自动生成slug
-func (p *Post) GenerateSlug() string {
+// GenerateSlug 按站点选择的slug策略从标题生成slug
+func (p *Post) GenerateSlug(strategy slug.Strategy) string {
 	if p.Title == "" {
 		return ""
 	}
 
-	slug := GenerateSlugFromText(p.Title)
-	return slug
+	return GenerateSlugWith(strategy, p.Title, constants.SlugFallbackPrefixPost)
 }
 
 // EnsureSlug 确保文章有有效的slug
-func (p *Post) EnsureSlug() {
+func (p *Post) EnsureSlug(strategy slug.Strategy) {
 	if p.Slug == "" {
-		p.Slug = p.GenerateSlug()
-	}
-}
-
-// ===============================
-// 内容处理方法
-// ===============================
-
-// CalculateWordCount 计算字数
-func (p *Post) CalculateWordCount() int {
-	if p.Markdown == "" {
-		return 0
-	}
-
-	// 简单的字数统计（按空格分割）
-	text := strings.TrimSpace(p.Markdown)
-	if text == "" {
-		return 0
-	}
-
-	// 移除markdown语法标记的简单处理
-	text = regexp.MustCompile(`#+ `).ReplaceAllString(text, "")
-	text = regexp.MustCompile(`\*\*([^*]+)\*\*`).ReplaceAllString(text, "$1")
-	text = regexp.MustCompile(`\*([^*]+)\*`).ReplaceAllString(text, "$1")
-	text = regexp.MustCompile(`\[([^\]]+)\]\([^)]+\)`).ReplaceAllString(text, "$1")
-
-	// 按空格分割计算单词数
-	words := strings.Fields(text)
-	return len(words)
-}
-
-// CalculateReadingTime 计算阅读时间
-func (p *Post) CalculateReadingTime() int {
-	return constants.CalculateReadingTime(p.WordCount)
-}
-
-// UpdateContentMetrics 更新内容指标
-func (p *Post) UpdateContentMetrics() {
-	p.WordCount = p.CalculateWordCount()
-	p.ReadingTime = p.CalculateReadingTime()
-}
-
-// GenerateExcerpt 自动生成摘要
-func (p *Post) GenerateExcerpt(maxLength int) string {
-	if p.Markdown == "" {
-		return ""
-	}
-
-	// 移除markdown标记
-	text := regexp.MustCompile(`#+ `).ReplaceAllString(p.Markdown, "")
-	text = regexp.MustCompile(`\*\*([^*]+)\*\*`).ReplaceAllString(text, "$1")
-	text = regexp.MustCompile(`\*([^*]+)\*`).ReplaceAllString(text, "$1")
-	text = regexp.MustCompile(`\[([^\]]+)\]\([^)]+\)`).ReplaceAllString(text, "$1")
-
-	// 移除多余的空白字符
-	text = regexp.MustCompile(`\s+`).ReplaceAllString(text, " ")
-	text = strings.TrimSpace(text)
-
-	// 截取指定长度
-	if maxLength <= 0 {
-		maxLength = 200
-	}
-
-	if utf8.RuneCountInString(text) <= maxLength {
-		return text
-	}
-
-	runes := []rune(text)
-	if len(runes) > maxLength {
-		return string(runes[:maxLength]) + "..."
-	}
-
-	return text
-}
-
-// EnsureExcerpt 确保文章有摘要
-func (p *Post) EnsureExcerpt() {
-	if p.Excerpt == "" {
-		p.Excerpt = p.GenerateExcerpt(200)
+		p.Slug = p.GenerateSlug(strategy)
 	}
 }
 
@@ -472,8 +403,8 @@ func (p *Post) ToListItem(author *AuthorInfo) *PostListItem {
 // 工厂方法
 // ===============================
 
-// NewPost 创建新文章
-func NewPost(title, markdown, postType, status, visibility string, authorID primitive.ObjectID) *Post {
+// NewPost 创建新文章，按指定的slug策略生成slug
+func NewPost(title, markdown, postType, status, visibility string, authorID primitive.ObjectID, strategy slug.Strategy) *Post {
 	now := time.Now()
 
 	post := &Post{
@@ -490,17 +421,15 @@ func NewPost(title, markdown, postType, status, visibility string, authorID prim
 		UpdatedAt:  now,
 	}
 
-	// 自动生成slug和摘要
-	post.EnsureSlug()
-	post.EnsureExcerpt()
-	post.UpdateContentMetrics()
+	// 自动生成slug，摘要和内容指标由渲染结果生成
+	post.EnsureSlug(strategy)
 
 	return post
 }
 
-// NewPostFromCreateRequest 从创建请求创建文章
-func NewPostFromCreateRequest(req *PostCreateRequest, authorID primitive.ObjectID) *Post {
-	post := NewPost(req.Title, req.Markdown, req.Type, req.Status, req.Visibility, authorID)
+// NewPostFromCreateRequest 从创建请求创建文章，未提供的文章和标签slug按指定策略生成
+func NewPostFromCreateRequest(req *PostCreateRequest, authorID primitive.ObjectID, strategy slug.Strategy) *Post {
+	post := NewPost(req.Title, req.Markdown, req.Type, req.Status, req.Visibility, authorID, strategy)
 
 	// 设置可选字段
 	if req.Slug != "" {
@@ -522,15 +451,10 @@ func NewPostFromCreateRequest(req *PostCreateRequest, authorID primitive.ObjectI
 		}
 		// 如果没有提供slug，自动生成
 		if post.Tags[i].Slug == "" {
-			post.Tags[i].Slug = GenerateSlugFromText(tagInfo.Name)
+			post.Tags[i].Slug = GenerateSlugWith(strategy, tagInfo.Name, constants.SlugFallbackPrefixTag)
 		}
 	}
 
-	// 确保摘要存在
-	if post.Excerpt == "" {
-		post.EnsureExcerpt()
-	}
-
 	return post
 }
 
@@ -538,7 +462,7 @@ func NewPostFromCreateRequest(req *PostCreateRequest, authorID primitive.ObjectI
 // 数据库操作辅助方法
 // ===============================
 
-// PrepareForInsert 准备插入数据库
+// PrepareForInsert 准备插入数据库，slug由调用方按站点的slug策略生成
 func (p *Post) PrepareForInsert() {
 	now := time.Now()
 	if p.ID.IsZero() {
@@ -547,11 +471,6 @@ func (p *Post) PrepareForInsert() {
 	p.CreatedAt = now
 	p.UpdatedAt = now
 
-	// 确保必要字段有值
-	p.EnsureSlug()
-	p.EnsureExcerpt()
-	p.UpdateContentMetrics()
-
 	// 设置默认值
 	if p.Type == "" {
 		p.Type = constants.PostTypePost
@@ -570,7 +489,6 @@ func (p *Post) PrepareForInsert() {
 // PrepareForUpdate 准备更新数据库
 func (p *Post) PrepareForUpdate() {
 	p.UpdatedAt = time.Now()
-	p.UpdateContentMetrics()
 }
 
 // IncrementViewCount 增加浏览量
@@ -599,46 +517,17 @@ func (p *Post) Unpublish() {
 // 工具函数
 // ===============================
 
-// IsValidSlug 验证slug格式
-func IsValidSlug(slug string) bool {
-	if slug == "" {
-		return false
-	}
-
-	// slug只能包含小写字母、数字和连字符，不能以连字符开头或结尾
-	matched, _ := regexp.MatchString(`^[a-z0-9]+(-[a-z0-9]+)*$`, slug)
-	return matched
+// IsValidSlug 验证slug格式，接受所有内置slug策略生成的结果
+func IsValidSlug(s string) bool {
+	return slug.IsValid(s)
 }
 
-// GenerateSlugFromText 从文本生成slug
-func GenerateSlugFromText(text string) string {
-	if text == "" {
-		return "post-" + strconv.FormatInt(time.Now().Unix(), 10)
+// GenerateSlugWith 按指定策略从文本生成slug，结果为空时生成“前缀-时间戳”形式的默认值
+func GenerateSlugWith(strategy slug.Strategy, text, fallbackPrefix string) string {
+	if s := strategy.Make(text); s != "" {
+		return s
 	}
-
-	// 转换为小写
-	slug := strings.ToLower(text)
-
-	// 替换空格和特殊字符为连字符
-	slug = regexp.MustCompile(`[^a-z0-9\s-]`).ReplaceAllString(slug, "")
-	slug = regexp.MustCompile(`\s+`).ReplaceAllString(slug, "-")
-	slug = regexp.MustCompile(`-+`).ReplaceAllString(slug, "-")
-
-	// 移除首尾的连字符
-	slug = strings.Trim(slug, "-")
-
-	// 限制长度
-	if len(slug) > 100 {
-		slug = slug[:100]
-		slug = strings.Trim(slug, "-")
-	}
-
-	// 如果结果为空，生成一个默认值
-	if slug == "" {
-		slug = "post-" + strconv.FormatInt(time.Now().Unix(), 10)
-	}
-
-	return slug
+	return fallbackPrefix + "-" + strconv.FormatInt(time.Now().Unix(), 10)
 }
 
 // ===============================
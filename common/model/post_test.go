@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/slug"
 	. "github.com/smartystreets/goconvey/convey"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -182,20 +183,21 @@ func TestPostModel(t *testing.T) {
 
 			Convey("从标题生成slug", func() {
 				post.Title = "这是一个测试标题 With English"
-				slug := post.GenerateSlug()
-				So(slug, ShouldNotBeEmpty)
-				So(IsValidSlug(slug), ShouldBeTrue)
+				So(post.GenerateSlug(slug.Get(constants.SlugStrategyPinyin)), ShouldEqual, "zhe-shi-yi-ge-ce-shi-biao-ti-with-english")
+				So(post.GenerateSlug(slug.Get(constants.SlugStrategyUnicode)), ShouldEqual, "这是一个测试标题-with-english")
 			})
 
 			Convey("确保文章有slug", func() {
 				post.Title = "测试标题"
-				post.EnsureSlug()
+				post.EnsureSlug(slug.Get(constants.SlugStrategyDefault))
 				So(post.Slug, ShouldNotBeEmpty)
 				So(IsValidSlug(post.Slug), ShouldBeTrue)
 			})
 
 			Convey("slug格式验证", func() {
 				So(IsValidSlug("valid-slug-123"), ShouldBeTrue)
+				So(IsValidSlug("go-并发模式"), ShouldBeTrue)
+				So(IsValidSlug("привет-мир"), ShouldBeTrue)
 				So(IsValidSlug("invalid_slug"), ShouldBeFalse)
 				So(IsValidSlug("Invalid-Slug"), ShouldBeFalse)
 				So(IsValidSlug("-invalid"), ShouldBeFalse)
@@ -204,42 +206,6 @@ func TestPostModel(t *testing.T) {
 			})
 		})
 
-		Convey("内容处理", func() {
-			post := &Post{}
-
-			Convey("字数统计", func() {
-				post.Markdown = "# 标题\n\n这是一个 **粗体** 和 *斜体* 的测试文章。[链接](http://example.com)"
-				wordCount := post.CalculateWordCount()
-				So(wordCount, ShouldBeGreaterThan, 0)
-			})
-
-			Convey("阅读时间计算", func() {
-				post.WordCount = 400
-				readingTime := post.CalculateReadingTime()
-				So(readingTime, ShouldEqual, 2) // 400字 / 200字每分钟 = 2分钟
-			})
-
-			Convey("更新内容指标", func() {
-				post.Markdown = "这是一篇测试文章，包含多个单词用于测试字数统计功能。"
-				post.UpdateContentMetrics()
-				So(post.WordCount, ShouldBeGreaterThan, 0)
-				So(post.ReadingTime, ShouldBeGreaterThan, 0)
-			})
-
-			Convey("自动生成摘要", func() {
-				post.Markdown = "# 标题\n\n这是一篇很长的文章内容，用于测试自动生成摘要的功能。文章包含多个段落和各种markdown格式。"
-				excerpt := post.GenerateExcerpt(50)
-				So(excerpt, ShouldNotBeEmpty)
-				So(len([]rune(excerpt)), ShouldBeLessThanOrEqualTo, 53) // 50 + "..."
-			})
-
-			Convey("确保文章有摘要", func() {
-				post.Markdown = "这是文章内容"
-				post.EnsureExcerpt()
-				So(post.Excerpt, ShouldNotBeEmpty)
-			})
-		})
-
 		Convey("文章转换方法", func() {
 			authorID := primitive.NewObjectID()
 			author := &AuthorInfo{
@@ -285,14 +251,11 @@ func TestPostModel(t *testing.T) {
 			authorID := primitive.NewObjectID()
 
 			Convey("创建新文章", func() {
-				post := NewPost("测试标题", "测试内容", constants.PostTypePost, constants.PostStatusDraft, constants.PostVisibilityPublic, authorID)
+				post := NewPost("测试标题", "测试内容", constants.PostTypePost, constants.PostStatusDraft, constants.PostVisibilityPublic, authorID, slug.Get(constants.SlugStrategyDefault))
 				So(post, ShouldNotBeNil)
 				So(post.ID, ShouldNotEqual, primitive.NilObjectID)
 				So(post.Title, ShouldEqual, "测试标题")
 				So(post.Slug, ShouldNotBeEmpty)
-				So(post.Excerpt, ShouldNotBeEmpty)
-				So(post.WordCount, ShouldBeGreaterThan, 0)
-				So(post.ReadingTime, ShouldBeGreaterThan, 0)
 			})
 
 			Convey("从创建请求创建文章", func() {
@@ -308,12 +271,12 @@ func TestPostModel(t *testing.T) {
 					},
 				}
 
-				post := NewPostFromCreateRequest(req, authorID)
+				post := NewPostFromCreateRequest(req, authorID, slug.Get(constants.SlugStrategyUnicode))
 				So(post, ShouldNotBeNil)
 				So(post.Title, ShouldEqual, req.Title)
 				So(len(post.Tags), ShouldEqual, 2)
 				So(post.Tags[0].Slug, ShouldEqual, "go")
-				So(post.Tags[1].Slug, ShouldNotBeEmpty) // 自动生成
+				So(post.Tags[1].Slug, ShouldEqual, "测试") // 按指定策略自动生成
 			})
 		})
 
@@ -368,15 +331,16 @@ func TestPostModel(t *testing.T) {
 					expected string
 				}{
 					{"Hello World", "hello-world"},
-					{"Go 语言编程", "go"},
+					{"Go 语言编程", "go-yu-yan-bian-cheng"},
+					{"Crème Brûlée", "creme-brulee"},
 					{"Test123", "test123"},
 					{"", ""},
 				}
 
 				for _, tc := range testCases {
-					result := GenerateSlugFromText(tc.input)
+					result := GenerateSlugWith(slug.Get(constants.SlugStrategyPinyin), tc.input, constants.SlugFallbackPrefixTag)
 					if tc.expected == "" {
-						So(result, ShouldStartWith, "post-")
+						So(result, ShouldStartWith, "tag-")
 					} else {
 						So(result, ShouldEqual, tc.expected)
 					}
@@ -9,26 +9,32 @@ import (
 
 // User 用户模型
 type User struct {
-	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Username       string             `bson:"username" json:"username"`
-	Email          string             `bson:"email" json:"email"`
-	PasswordHash   string             `bson:"passwordHash" json:"-"` // 不在JSON中返回
-	DisplayName    string             `bson:"displayName" json:"displayName"`
-	Role           string             `bson:"role" json:"role"`
-	ProfileImage   string             `bson:"profileImage" json:"profileImage"`
-	CoverImage     string             `bson:"coverImage" json:"coverImage"`
-	Bio            string             `bson:"bio" json:"bio"`
-	Location       string             `bson:"location" json:"location"`
-	Website        string             `bson:"website" json:"website"`
-	Twitter        string             `bson:"twitter" json:"twitter"`
-	Facebook       string             `bson:"facebook" json:"facebook"`
-	Status         string             `bson:"status" json:"status"`
-	LoginFailCount int                `bson:"loginFailCount" json:"loginFailCount"`
-	LockedUntil    *time.Time         `bson:"lockedUntil,omitempty" json:"lockedUntil,omitempty"`
-	LastLoginAt    *time.Time         `bson:"lastLoginAt,omitempty" json:"lastLoginAt,omitempty"`
-	LastLoginIP    string             `bson:"lastLoginIP" json:"lastLoginIP"`
-	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID                 primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Username           string             `bson:"username" json:"username"`
+	Email              string             `bson:"email" json:"email"`
+	PasswordHash       string             `bson:"passwordHash" json:"-"`              // 不在JSON中返回
+	PasswordHistory    []string           `bson:"passwordHistory,omitempty" json:"-"` // 最近使用过的密码哈希，最新的在前
+	DisplayName        string             `bson:"displayName" json:"displayName"`
+	Role               string             `bson:"role" json:"role"`
+	ProfileImage       string             `bson:"profileImage" json:"profileImage"`
+	CoverImage         string             `bson:"coverImage" json:"coverImage"`
+	Bio                string             `bson:"bio" json:"bio"`
+	Location           string             `bson:"location" json:"location"`
+	Website            string             `bson:"website" json:"website"`
+	Twitter            string             `bson:"twitter" json:"twitter"`
+	Facebook           string             `bson:"facebook" json:"facebook"`
+	Status             string             `bson:"status" json:"status"`
+	LoginFailCount     int                `bson:"loginFailCount" json:"loginFailCount"`
+	LockedUntil        *time.Time         `bson:"lockedUntil,omitempty" json:"lockedUntil,omitempty"`
+	LastLoginAt        *time.Time         `bson:"lastLoginAt,omitempty" json:"lastLoginAt,omitempty"`
+	LastLoginIP        string             `bson:"lastLoginIP" json:"lastLoginIP"`
+	TwoFactorEnabled   bool               `bson:"twoFactorEnabled" json:"twoFactorEnabled"`
+	TwoFactorSecret    string             `bson:"twoFactorSecret,omitempty" json:"-"`   // 加密后的TOTP密钥(AES-GCM)
+	TwoFactorLastStep  int64              `bson:"twoFactorLastStep,omitempty" json:"-"` // 最近一次使用的TOTP时间步，防止验证码重放
+	TwoFactorEnabledAt *time.Time         `bson:"twoFactorEnabledAt,omitempty" json:"twoFactorEnabledAt,omitempty"`
+	RecoveryCodes      []string           `bson:"recoveryCodes,omitempty" json:"-"` // 未使用的恢复码哈希
+	CreatedAt          time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt          time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
 
 // UserCreateRequest 用户创建请求
@@ -224,11 +230,25 @@ func (u *User) IsLocked() bool {
 	return false
 }
 
+// IsLockExpired 检查登录失败导致的临时锁定是否已到期，到期后允许再次尝试登录
+func (u *User) IsLockExpired() bool {
+	return u.Status == constants.UserStatusLocked && u.LockedUntil != nil && !u.LockedUntil.After(time.Now())
+}
+
 // CanLogin 检查用户是否可以登录
 func (u *User) CanLogin() bool {
+	if u.IsLockExpired() {
+		return true
+	}
 	return u.IsActive() && !u.IsLocked()
 }
 
+// CanResetPassword 检查用户是否可以通过邮件重置密码
+// 因登录失败被锁定的账户也可以重置，重置后解除锁定；被暂停或禁用的账户不能重置
+func (u *User) CanResetPassword() bool {
+	return u.IsActive() || u.Status == constants.UserStatusLocked
+}
+
 // IsOwner 检查是否为所有者
 func (u *User) IsOwner() bool {
 	return u.Role == constants.UserRoleOwner
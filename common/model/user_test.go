@@ -149,6 +149,20 @@ func TestUserModel(t *testing.T) {
 				So(user.IsLocked(), ShouldBeFalse)
 				So(user.CanLogin(), ShouldBeTrue)
 			})
+
+			Convey("锁定到期检查", func() {
+				user.Status = constants.UserStatusLocked
+				past := time.Now().Add(-time.Minute)
+				user.LockedUntil = &past
+
+				So(user.IsLockExpired(), ShouldBeTrue)
+				So(user.CanLogin(), ShouldBeTrue)
+
+				future := time.Now().Add(time.Minute)
+				user.LockedUntil = &future
+				So(user.IsLockExpired(), ShouldBeFalse)
+				So(user.CanLogin(), ShouldBeFalse)
+			})
 		})
 
 		Convey("用户权限检查", func() {
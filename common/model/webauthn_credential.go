@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WebAuthnCredential 通行密钥（WebAuthn凭据）模型
+type WebAuthnCredential struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID            primitive.ObjectID `bson:"userId" json:"userId"`                             // 所属用户
+	CredentialID      string             `bson:"credentialId" json:"credentialId"`                 // 凭据ID（base64url）
+	PublicKey         []byte             `bson:"publicKey" json:"-"`                               // COSE编码的公钥
+	Algorithm         int64              `bson:"algorithm" json:"algorithm"`                       // COSE算法标识
+	SignCount         uint32             `bson:"signCount" json:"signCount"`                       // 签名计数
+	AAGUID            string             `bson:"aaguid,omitempty" json:"aaguid,omitempty"`         // 认证器型号标识
+	Name              string             `bson:"name" json:"name"`                                 // 用户设置的名称
+	AttestationFormat string             `bson:"attestationFormat" json:"attestationFormat"`       // 证明格式
+	Transports        []string           `bson:"transports,omitempty" json:"transports,omitempty"` // 传输方式：usb, nfc, ble, internal, hybrid
+	BackupEligible    bool               `bson:"backupEligible" json:"backupEligible"`             // 是否可同步备份
+	BackedUp          bool               `bson:"backedUp" json:"backedUp"`                         // 是否已同步备份
+	LastUsedAt        *time.Time         `bson:"lastUsedAt,omitempty" json:"lastUsedAt,omitempty"` // 最后使用时间
+	LastUsedIP        string             `bson:"lastUsedIp,omitempty" json:"lastUsedIp,omitempty"` // 最后使用IP
+	CreatedAt         time.Time          `bson:"createdAt" json:"createdAt"`                       // 创建时间
+}
@@ -0,0 +1,62 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// headingIDs 生成文档内唯一的标题锚点ID
+// goldmark默认会丢弃非ASCII字符，中文标题都会变成 heading，因此保留所有语言的字母和数字
+type headingIDs struct {
+	used map[string]bool
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: make(map[string]bool)}
+}
+
+// Generate 根据标题文本生成锚点ID，重复时依次追加 -1、-2 后缀
+func (s *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	base := Anchor(string(value))
+	if base == "" {
+		if kind == ast.KindHeading {
+			base = "heading"
+		} else {
+			base = "id"
+		}
+	}
+
+	id := base
+	for i := 1; s.used[id]; i++ {
+		id = base + "-" + strconv.Itoa(i)
+	}
+	s.used[id] = true
+	return []byte(id)
+}
+
+// Put 登记文档中手动指定的ID，避免生成的ID与其冲突
+func (s *headingIDs) Put(value []byte) {
+	s.used[string(value)] = true
+}
+
+// Anchor 把标题文本转换为锚点：字母转小写，保留各语言的字母和数字，空白、连字符和下划线合并为单个连字符，其余字符丢弃
+func Anchor(text string) string {
+	var b strings.Builder
+	pendingDash := false
+	for _, r := range strings.TrimSpace(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			if pendingDash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingDash = false
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsSpace(r) || r == '-' || r == '_':
+			pendingDash = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,83 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// markdown 共享的Markdown转换器，goldmark的转换器可并发使用
+var markdown = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM, // 表格、任务列表、删除线、自动链接
+		extension.Footnote,
+	),
+	goldmark.WithParserOptions(
+		parser.WithAutoHeadingID(),
+	),
+	goldmark.WithRendererOptions(
+		// 保留内嵌的HTML（如嵌入视频），由 Sanitizer 按作者角色净化
+		html.WithUnsafe(),
+	),
+)
+
+// Document Markdown渲染结果
+type Document struct {
+	HTML     string
+	Headings []Heading // 按出现顺序排列的标题，用于生成目录
+	Text     string    // 正文纯文本，用于统计字数和阅读时间
+
+	prose string // 正文段落纯文本，用于生成摘要
+}
+
+// Parse 按CommonMark和GFM规范渲染Markdown，同时提取标题
+// 支持表格、任务列表、删除线、自动链接、脚注，代码块按语言输出 language-xxx 类名，标题带锚点ID
+func Parse(source string) (*Document, error) {
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	root := markdown.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := markdown.Renderer().Render(&buf, src, root); err != nil {
+		return nil, fmt.Errorf("渲染Markdown失败: %w", err)
+	}
+	doc := &Document{HTML: buf.String(), Headings: collectHeadings(root, src)}
+	doc.Text, doc.prose = collectText(root, src)
+	return doc, nil
+}
+
+// Markdown 把Markdown渲染为HTML
+func Markdown(source string) (string, error) {
+	doc, err := Parse(source)
+	if err != nil {
+		return "", err
+	}
+	return doc.HTML, nil
+}
+
+// Renderer 文章和页面内容渲染器，渲染Markdown后按作者角色净化HTML
+type Renderer struct {
+	sanitizer *Sanitizer
+}
+
+// NewRenderer 创建内容渲染器，sanitizer为nil时不净化（关闭XSS过滤）
+func NewRenderer(sanitizer *Sanitizer) *Renderer {
+	return &Renderer{sanitizer: sanitizer}
+}
+
+// Render 渲染Markdown内容，role为内容作者的角色，决定允许的内嵌HTML
+func (r *Renderer) Render(source, role string) (*Document, error) {
+	doc, err := Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	if r.sanitizer != nil {
+		doc.HTML = r.sanitizer.Sanitize(doc.HTML, role)
+	}
+	return doc, nil
+}
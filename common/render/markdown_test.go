@@ -0,0 +1,70 @@
+package render
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMarkdown(t *testing.T) {
+	Convey("Markdown rendering", t, func() {
+		render := func(source string) string {
+			html, err := Markdown(source)
+			So(err, ShouldBeNil)
+			return html
+		}
+
+		Convey("Should render block elements with closed tags", func() {
+			So(render("# 标题\n\n第一段\n第二行\n\n> 引用"), ShouldEqual,
+				"<h1 id=\"标题\">标题</h1>\n<p>第一段\n第二行</p>\n<blockquote>\n<p>引用</p>\n</blockquote>\n")
+		})
+
+		Convey("Should render GFM tables", func() {
+			html := render("| 名称 | 数量 |\n|------|-----:|\n| 苹果 | 3 |")
+			So(html, ShouldContainSubstring, "<table>")
+			So(html, ShouldContainSubstring, "<th>名称</th>")
+			So(html, ShouldContainSubstring, "<td style=\"text-align:right\">3</td>")
+		})
+
+		Convey("Should render task lists, strikethrough and autolinks", func() {
+			html := render("- [x] 完成\n- [ ] 待办\n\n~~旧内容~~ https://example.com")
+			So(html, ShouldContainSubstring, "<li><input checked=\"\" disabled=\"\" type=\"checkbox\"> 完成</li>")
+			So(html, ShouldContainSubstring, "<li><input disabled=\"\" type=\"checkbox\"> 待办</li>")
+			So(html, ShouldContainSubstring, "<del>旧内容</del>")
+			So(html, ShouldContainSubstring, "<a href=\"https://example.com\">https://example.com</a>")
+		})
+
+		Convey("Should render fenced code with language class and escape its content", func() {
+			So(render("```go\nfmt.Println(\"<b>\")\n```"), ShouldEqual,
+				"<pre><code class=\"language-go\">fmt.Println(&quot;&lt;b&gt;&quot;)\n</code></pre>\n")
+		})
+
+		Convey("Should render footnotes", func() {
+			html := render("正文[^1]\n\n[^1]: 注释")
+			So(html, ShouldContainSubstring, "<a href=\"#fn:1\" class=\"footnote-ref\" role=\"doc-noteref\">1</a>")
+			So(html, ShouldContainSubstring, "<li id=\"fn:1\">")
+		})
+
+		Convey("Heading anchors should keep non-ASCII text and be unique", func() {
+			html := render("## 安装 Guide\n\n## 安装 Guide\n\n## **Q&A**: `go get`")
+			So(html, ShouldContainSubstring, "<h2 id=\"安装-guide\">")
+			So(html, ShouldContainSubstring, "<h2 id=\"安装-guide-1\">")
+			So(html, ShouldContainSubstring, "<h2 id=\"qa-go-get\">")
+		})
+
+		Convey("Anchors should restart for each document", func() {
+			So(render("# 简介"), ShouldContainSubstring, "id=\"简介\"")
+			So(render("# 简介"), ShouldContainSubstring, "id=\"简介\"")
+		})
+	})
+}
+
+func TestAnchor(t *testing.T) {
+	Convey("Anchor", t, func() {
+		So(Anchor("Hello, World!"), ShouldEqual, "hello-world")
+		So(Anchor("  Go 1.24 发布说明  "), ShouldEqual, "go-124-发布说明")
+		So(Anchor("snake_case -- 名称"), ShouldEqual, "snake-case-名称")
+		So(Anchor("Ünïcödé"), ShouldEqual, "ünïcödé")
+		So(Anchor("!!!"), ShouldEqual, "")
+	})
+}
@@ -0,0 +1,153 @@
+package render
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+
+	"github.com/heimdall-api/common/constants"
+)
+
+// DefaultEmbedHosts 未配置时允许嵌入iframe的常见视频、音乐和代码片段站点
+var DefaultEmbedHosts = []string{
+	"www.youtube.com",
+	"www.youtube-nocookie.com",
+	"player.vimeo.com",
+	"player.bilibili.com",
+	"open.spotify.com",
+	"w.soundcloud.com",
+	"codepen.io",
+	"codesandbox.io",
+}
+
+// DefaultURLSchemes 未配置时链接和图片允许的URL协议，相对地址始终允许
+var DefaultURLSchemes = []string{"http", "https", "mailto"}
+
+var (
+	// anchorIDPattern 标题锚点和脚注的ID，与 Anchor 生成的字符集一致
+	anchorIDPattern = regexp.MustCompile(`^[\p{L}\p{N}_:-]+$`)
+	// languageClassPattern 代码块的语言类名
+	languageClassPattern = regexp.MustCompile(`^language-[\w+#.-]+$`)
+	// dimensionPattern 宽高只允许数字或百分比
+	dimensionPattern = regexp.MustCompile(`^\d+%?$`)
+	// iframeAllowPattern iframe的allow属性，只允许权限策略名称列表
+	iframeAllowPattern = regexp.MustCompile(`^[a-z-]+(;\s*[a-z-]+)*;?$`)
+	// iframeWithoutSrcPattern 来源不在白名单中的iframe只会去掉src，其余属性仍保留，需要整体移除
+	iframeWithoutSrcPattern = regexp.MustCompile(`<iframe(\s+(width|height|title|allowfullscreen|frameborder|allow|loading)="[^"]*")*\s*>\s*</iframe>`)
+)
+
+// SanitizeConfig HTML净化配置
+type SanitizeConfig struct {
+	EmbedRoles []string // 允许嵌入iframe和音视频的角色，为空时只允许所有者
+	EmbedHosts []string // 允许作为iframe来源的主机，为空时使用 DefaultEmbedHosts
+	URLSchemes []string // 链接和图片允许的URL协议，为空时使用 DefaultURLSchemes
+}
+
+// Sanitizer 按白名单净化渲染后的HTML，去除脚本、事件属性和危险链接
+// 不同角色使用不同策略：所有角色都可使用Markdown生成的标签和常用排版标签，只有允许嵌入的角色可以使用iframe和音视频
+type Sanitizer struct {
+	strict     *bluemonday.Policy
+	embed      *bluemonday.Policy
+	embedRoles map[string]bool
+}
+
+// NewSanitizer 创建HTML净化器
+func NewSanitizer(c SanitizeConfig) *Sanitizer {
+	embedRoles := c.EmbedRoles
+	if len(embedRoles) == 0 {
+		embedRoles = []string{constants.UserRoleOwner}
+	}
+	embedHosts := c.EmbedHosts
+	if len(embedHosts) == 0 {
+		embedHosts = DefaultEmbedHosts
+	}
+	schemes := c.URLSchemes
+	if len(schemes) == 0 {
+		schemes = DefaultURLSchemes
+	}
+
+	s := &Sanitizer{
+		strict:     newContentPolicy(schemes),
+		embed:      newContentPolicy(schemes),
+		embedRoles: make(map[string]bool, len(embedRoles)),
+	}
+	allowEmbeds(s.embed, embedHosts)
+	for _, role := range embedRoles {
+		s.embedRoles[role] = true
+	}
+	return s
+}
+
+// Sanitize 按作者角色对应的策略净化HTML
+func (s *Sanitizer) Sanitize(html, role string) string {
+	if s.embedRoles[role] {
+		return iframeWithoutSrcPattern.ReplaceAllString(s.embed.Sanitize(html), "")
+	}
+	return s.strict.Sanitize(html)
+}
+
+// newContentPolicy 创建所有角色共用的基础策略：Markdown渲染结果和常用排版标签
+func newContentPolicy(schemes []string) *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+
+	// 链接和图片：只允许指定协议，外部链接在新窗口打开并带 rel="noopener"
+	p.RequireParseableURLs(true)
+	p.AllowRelativeURLs(true)
+	p.AllowURLSchemes(schemes...)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowAttrs("src", "alt").OnElements("img")
+	p.AllowAttrs("width", "height").Matching(dimensionPattern).OnElements("img")
+	p.AllowAttrs("loading").Matching(regexp.MustCompile(`^(lazy|eager)$`)).OnElements("img")
+	p.AllowAttrs("title").OnElements("a", "img", "abbr")
+
+	// 块级和行内排版
+	p.AllowElements("p", "br", "hr", "blockquote", "pre", "div", "span",
+		"em", "strong", "b", "i", "u", "del", "s", "ins", "mark", "sub", "sup", "small", "kbd", "abbr", "code",
+		"ul", "ol", "li", "dl", "dt", "dd", "figure", "figcaption", "details", "summary")
+	p.AllowElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("id").Matching(anchorIDPattern).OnElements("h1", "h2", "h3", "h4", "h5", "h6", "li", "sup")
+	p.AllowAttrs("start").Matching(regexp.MustCompile(`^\d+$`)).OnElements("ol")
+	p.AllowAttrs("open").OnElements("details")
+	p.AllowAttrs("class").Matching(languageClassPattern).OnElements("code")
+
+	// 表格及列对齐
+	p.AllowElements("table", "thead", "tbody", "tfoot", "tr", "th", "td")
+	p.AllowStyles("text-align").MatchingEnum("left", "center", "right").OnElements("th", "td")
+	p.AllowAttrs("colspan", "rowspan").Matching(regexp.MustCompile(`^\d+$`)).OnElements("th", "td")
+
+	// 任务列表的只读复选框
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+
+	// 脚注
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^(footnotes|footnote-ref|footnote-backref)$`)).OnElements("a", "div")
+	p.AllowAttrs("role").Matching(regexp.MustCompile(`^doc-(noteref|backlink|endnotes)$`)).OnElements("a", "div")
+
+	return p
+}
+
+// allowEmbeds 在策略中加入iframe和音视频，iframe只允许来自指定主机的HTTPS地址
+func allowEmbeds(p *bluemonday.Policy, hosts []string) {
+	quoted := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			quoted = append(quoted, regexp.QuoteMeta(host))
+		}
+	}
+	if len(quoted) > 0 {
+		srcPattern := regexp.MustCompile(`^https://(` + strings.Join(quoted, "|") + `)([/?#]|$)`)
+		p.AllowAttrs("src").Matching(srcPattern).OnElements("iframe")
+		p.AllowAttrs("width", "height").Matching(dimensionPattern).OnElements("iframe")
+		p.AllowAttrs("title", "allowfullscreen", "frameborder").OnElements("iframe")
+		p.AllowAttrs("allow").Matching(iframeAllowPattern).OnElements("iframe")
+		p.AllowAttrs("loading").Matching(regexp.MustCompile(`^(lazy|eager)$`)).OnElements("iframe")
+	}
+
+	p.AllowAttrs("src").OnElements("video", "audio", "source")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^(video|audio)/[\w.+-]+$`)).OnElements("source")
+	p.AllowAttrs("poster").OnElements("video")
+	p.AllowAttrs("width", "height").Matching(dimensionPattern).OnElements("video")
+	p.AllowAttrs("controls", "loop", "muted", "preload").OnElements("video", "audio")
+}
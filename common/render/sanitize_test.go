@@ -0,0 +1,84 @@
+package render
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/constants"
+)
+
+func TestSanitizer(t *testing.T) {
+	Convey("Sanitizer", t, func() {
+		s := NewSanitizer(SanitizeConfig{})
+		youtube := `<iframe src="https://www.youtube.com/embed/abc" width="560" height="315" allowfullscreen></iframe>`
+
+		Convey("Should strip scripts, event handlers and unsafe URLs for every role", func() {
+			for _, role := range []string{constants.UserRoleOwner, constants.UserRoleAuthor} {
+				So(s.Sanitize(`<p onclick="alert(1)">正文</p><script>alert(1)</script>`, role), ShouldEqual, "<p>正文</p>")
+				So(s.Sanitize(`<a href="javascript:alert(1)">链接</a>`, role), ShouldEqual, "链接")
+				So(s.Sanitize(`<img src="data:image/svg+xml;base64,PHN2Zz4=" alt="图">`, role), ShouldEqual, `<img alt="图">`)
+			}
+		})
+
+		Convey("Should keep markup produced by the Markdown renderer", func() {
+			html, err := Markdown("# 标题\n\n| a |\n|:-:|\n| 1 |\n\n- [x] 完成\n\n```go\nx\n```\n\n正文[^1]\n\n[^1]: 注释")
+			So(err, ShouldBeNil)
+			So(s.Sanitize(html, constants.UserRoleAuthor), ShouldEqual, s.Sanitize(s.Sanitize(html, constants.UserRoleAuthor), constants.UserRoleAuthor))
+
+			clean := s.Sanitize(html, constants.UserRoleAuthor)
+			So(clean, ShouldContainSubstring, `<h1 id="标题">`)
+			So(clean, ShouldContainSubstring, `<th style="text-align: center">`)
+			So(clean, ShouldContainSubstring, `<input checked="" disabled="" type="checkbox">`)
+			So(clean, ShouldContainSubstring, `<code class="language-go">`)
+			So(clean, ShouldContainSubstring, `<a href="#fn:1" class="footnote-ref" role="doc-noteref">`)
+			So(clean, ShouldContainSubstring, `<li id="fn:1">`)
+		})
+
+		Convey("External links should open in a new window with rel=noopener", func() {
+			So(s.Sanitize(`<a href="https://example.com">外链</a>`, constants.UserRoleAuthor), ShouldEqual,
+				`<a href="https://example.com" target="_blank" rel="noopener">外链</a>`)
+			So(s.Sanitize(`<a href="/about">关于</a>`, constants.UserRoleAuthor), ShouldEqual, `<a href="/about">关于</a>`)
+		})
+
+		Convey("Only embed roles should keep iframes from allowed hosts", func() {
+			So(s.Sanitize(youtube, constants.UserRoleOwner), ShouldEqual,
+				`<iframe src="https://www.youtube.com/embed/abc" width="560" height="315" allowfullscreen=""></iframe>`)
+			So(s.Sanitize(youtube, constants.UserRoleAuthor), ShouldEqual, "")
+			So(s.Sanitize(`<iframe src="https://evil.example.com/embed"></iframe>`, constants.UserRoleOwner), ShouldEqual, "")
+			So(s.Sanitize(`<iframe src="http://www.youtube.com/embed/abc"></iframe>`, constants.UserRoleOwner), ShouldEqual, "")
+			So(s.Sanitize(`<iframe src="https://www.youtube.com.evil.com/embed"></iframe>`, constants.UserRoleOwner), ShouldEqual, "")
+		})
+
+		Convey("Configured roles, hosts and schemes should replace the defaults", func() {
+			custom := NewSanitizer(SanitizeConfig{
+				EmbedRoles: []string{constants.UserRoleEditor},
+				EmbedHosts: []string{"Video.Example.com"},
+				URLSchemes: []string{"https"},
+			})
+			So(custom.Sanitize(`<iframe src="https://video.example.com/v/1"></iframe>`, constants.UserRoleEditor), ShouldEqual,
+				`<iframe src="https://video.example.com/v/1"></iframe>`)
+			So(custom.Sanitize(youtube, constants.UserRoleEditor), ShouldEqual, "")
+			So(custom.Sanitize(`<iframe src="https://video.example.com/v/1"></iframe>`, constants.UserRoleOwner), ShouldEqual, "")
+			So(custom.Sanitize(`<a href="mailto:a@example.com">邮件</a>`, constants.UserRoleEditor), ShouldEqual, "邮件")
+		})
+	})
+}
+
+func TestRenderer(t *testing.T) {
+	Convey("Renderer", t, func() {
+		source := "正文\n\n<script>alert(1)</script>"
+
+		Convey("Should sanitize rendered HTML with the author's policy", func() {
+			doc, err := NewRenderer(NewSanitizer(SanitizeConfig{})).Render(source, constants.UserRoleAuthor)
+			So(err, ShouldBeNil)
+			So(doc.HTML, ShouldEqual, "<p>正文</p>\n")
+		})
+
+		Convey("Should keep raw HTML when sanitizing is disabled", func() {
+			doc, err := NewRenderer(nil).Render(source, constants.UserRoleAuthor)
+			So(err, ShouldBeNil)
+			So(doc.HTML, ShouldContainSubstring, "<script>")
+		})
+	})
+}
@@ -0,0 +1,60 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+
+	"github.com/heimdall-api/common/utils"
+)
+
+// collectText 提取正文纯文本，每个标题、段落、列表项和表格单元格占一行
+// 代码块、图片、内嵌HTML不计入；prose只包含正文段落和列表项，不含标题、表格和脚注，用于生成摘要
+func collectText(root ast.Node, src []byte) (text, prose string) {
+	var all, body []string
+	_ = ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.(type) {
+		case *ast.Heading, *east.TableCell:
+			if line := plainText(n, src); line != "" {
+				all = append(all, line)
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.Paragraph, *ast.TextBlock:
+			line := plainText(n, src)
+			if line == "" {
+				return ast.WalkSkipChildren, nil
+			}
+			all = append(all, line)
+			if !inFootnote(n) {
+				body = append(body, line)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.Join(all, "\n"), strings.Join(body, "\n")
+}
+
+// inFootnote 检查节点是否位于脚注定义中
+func inFootnote(n ast.Node) bool {
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if _, ok := p.(*east.Footnote); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Stats 统计正文字数，中日文按字、其他文字按词计
+func (d *Document) Stats() utils.TextStats {
+	return utils.CountText(d.Text)
+}
+
+// Excerpt 从正文段落生成摘要，按整句截取不超过maxLength个字符
+func (d *Document) Excerpt(maxLength int) string {
+	return utils.Excerpt(d.prose, maxLength)
+}
@@ -0,0 +1,36 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/utils"
+)
+
+func TestDocumentText(t *testing.T) {
+	Convey("Document text", t, func() {
+		doc, err := Parse("# 标题\n\n第一句。第二句！Go **1.24** is [out](https://go.dev).\n\n" +
+			"![截图](a.png)\n\n```go\nfmt.Println(\"代码\")\n```\n\n<div>内嵌HTML</div>\n\n" +
+			"- 列表项\n- item\n\n| 表头 |\n|---|\n| cell |\n\n引用脚注[^1]\n\n[^1]: 脚注内容")
+		So(err, ShouldBeNil)
+
+		Convey("Should extract prose without code, images, raw HTML or footnote markers", func() {
+			So(doc.Text, ShouldEqual, "标题\n第一句。第二句！Go 1.24 is out.\n列表项\nitem\n表头\ncell\n引用脚注\n脚注内容")
+			So(doc.Stats(), ShouldResemble, utils.TextStats{CJKChars: 21, Words: 6})
+		})
+
+		Convey("Excerpts should only use body paragraphs and list items", func() {
+			So(doc.Excerpt(10), ShouldEqual, "第一句。第二句！")
+			So(doc.Excerpt(200), ShouldEqual, "第一句。第二句！Go 1.24 is out. 列表项 item 引用脚注")
+		})
+
+		Convey("Reading time should use the CJK reading speed for Chinese text", func() {
+			long, err := Parse(strings.Repeat("中文内容", 750))
+			So(err, ShouldBeNil)
+			So(long.Stats().WordCount(), ShouldEqual, 3000)
+			So(long.Stats().ReadingTime(), ShouldEqual, 10)
+		})
+	})
+}
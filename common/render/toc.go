@@ -0,0 +1,102 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+)
+
+// Heading Markdown中的标题
+type Heading struct {
+	Level int    // 标题级别，1-6
+	Text  string // 标题纯文本，去除了强调、链接等标记
+	ID    string // 锚点ID，与渲染出的HTML一致
+}
+
+// collectHeadings 按出现顺序提取文档中的标题，内嵌HTML写的标题不提取
+func collectHeadings(root ast.Node, src []byte) []Heading {
+	var headings []Heading
+	_ = ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+
+		h := Heading{Level: heading.Level, Text: plainText(heading, src)}
+		if id, ok := heading.AttributeString("id"); ok {
+			if b, ok := id.([]byte); ok {
+				h.ID = string(b)
+			}
+		}
+		headings = append(headings, h)
+		return ast.WalkSkipChildren, nil
+	})
+	return headings
+}
+
+// plainText 拼接节点下的文本，软换行替换为空格，图片、内嵌HTML和脚注引用忽略
+func plainText(n ast.Node, src []byte) string {
+	var b strings.Builder
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := child.(type) {
+		case *ast.Text:
+			b.Write(node.Segment.Value(src))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(node.Value)
+		case *ast.Image, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// BuildTOC 按标题级别生成嵌套目录
+// maxDepth为从文章中最高一级标题算起最多包含的层数，0为默认值；跳级的标题挂在最近的上级标题下
+func BuildTOC(headings []Heading, maxDepth int) []model.TOCItem {
+	if maxDepth <= 0 {
+		maxDepth = constants.TOCMaxDepthDefault
+	}
+
+	topLevel := 0
+	for _, h := range headings {
+		if h.Text != "" && (topLevel == 0 || h.Level < topLevel) {
+			topLevel = h.Level
+		}
+	}
+	if topLevel == 0 {
+		return nil
+	}
+
+	// 用栈记录当前路径上各级目录项的位置，新标题挂在栈中最近的更高级标题下
+	var toc []model.TOCItem
+	var stack []*model.TOCItem
+	for _, h := range headings {
+		if h.Text == "" || h.Level-topLevel >= maxDepth {
+			continue
+		}
+		for len(stack) > 0 && stack[len(stack)-1].Level >= h.Level {
+			stack = stack[:len(stack)-1]
+		}
+
+		item := model.TOCItem{Level: h.Level, Text: h.Text, ID: h.ID}
+		if len(stack) == 0 {
+			toc = append(toc, item)
+			stack = append(stack, &toc[len(toc)-1])
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, item)
+			stack = append(stack, &parent.Children[len(parent.Children)-1])
+		}
+	}
+	return toc
+}
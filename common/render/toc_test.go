@@ -0,0 +1,65 @@
+package render
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/model"
+)
+
+func TestParseHeadings(t *testing.T) {
+	Convey("Parse should extract headings with plain text and anchor ids", t, func() {
+		doc, err := Parse("# 安装\n\n正文\n\n## **快速** 开始 `go run`\n\n## 安装\n\nSetext标题\n---\n\n<h2>内嵌HTML</h2>\n\n```md\n# 代码块中的标题\n```")
+		So(err, ShouldBeNil)
+		So(doc.Headings, ShouldResemble, []Heading{
+			{Level: 1, Text: "安装", ID: "安装"},
+			{Level: 2, Text: "快速 开始 go run", ID: "快速-开始-go-run"},
+			{Level: 2, Text: "安装", ID: "安装-1"},
+			{Level: 2, Text: "Setext标题", ID: "setext标题"},
+		})
+		for _, h := range doc.Headings {
+			So(doc.HTML, ShouldContainSubstring, `id="`+h.ID+`"`)
+		}
+	})
+}
+
+func TestBuildTOC(t *testing.T) {
+	Convey("BuildTOC", t, func() {
+		headings := []Heading{
+			{Level: 2, Text: "简介", ID: "简介"},
+			{Level: 3, Text: "背景", ID: "背景"},
+			{Level: 4, Text: "细节", ID: "细节"},
+			{Level: 2, Text: "安装", ID: "安装"},
+			{Level: 4, Text: "跳级", ID: "跳级"},
+			{Level: 3, Text: "", ID: "heading"},
+			{Level: 3, Text: "配置", ID: "配置"},
+		}
+
+		Convey("Should nest headings relative to the top level and honour max depth", func() {
+			So(BuildTOC(headings, 2), ShouldResemble, []model.TOCItem{
+				{Level: 2, Text: "简介", ID: "简介", Children: []model.TOCItem{
+					{Level: 3, Text: "背景", ID: "背景"},
+				}},
+				{Level: 2, Text: "安装", ID: "安装", Children: []model.TOCItem{
+					{Level: 3, Text: "配置", ID: "配置"},
+				}},
+			})
+		})
+
+		Convey("Skipped levels should attach to the nearest higher heading", func() {
+			toc := BuildTOC(headings, 0)
+			So(toc, ShouldHaveLength, 2)
+			So(toc[0].Children[0].Children, ShouldResemble, []model.TOCItem{{Level: 4, Text: "细节", ID: "细节"}})
+			So(toc[1].Children, ShouldResemble, []model.TOCItem{
+				{Level: 4, Text: "跳级", ID: "跳级"},
+				{Level: 3, Text: "配置", ID: "配置"},
+			})
+		})
+
+		Convey("Should return nil without headings", func() {
+			So(BuildTOC(nil, 0), ShouldBeNil)
+			So(BuildTOC([]Heading{{Level: 2, Text: ""}}, 0), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,179 @@
+package slug
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gosimple/unidecode"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/heimdall-api/common/constants"
+)
+
+// maxLength slug最大字符数，超出时在单词边界截断
+const maxLength = 100
+
+// Strategy slug生成策略，文章、页面和标签共用站点设置中选择的策略
+type Strategy interface {
+	// Name 策略名称，保存在站点设置中
+	Name() string
+	// Make 从文本生成slug，文本中没有可用字符时返回空字符串
+	Make(text string) string
+	// Valid 检查slug是否符合该策略的字符集，用于校验手动填写的slug
+	Valid(slug string) bool
+}
+
+// strategies 已注册的策略
+var strategies = map[string]Strategy{}
+
+// Register 注册slug策略，同名策略会被替换
+func Register(s Strategy) {
+	strategies[s.Name()] = s
+}
+
+// Get 按名称获取策略，名称为空或未注册时返回默认策略
+func Get(name string) Strategy {
+	if s, ok := strategies[name]; ok {
+		return s
+	}
+	return strategies[constants.SlugStrategyDefault]
+}
+
+// Has 检查策略是否已注册
+func Has(name string) bool {
+	_, ok := strategies[name]
+	return ok
+}
+
+// Names 已注册的策略名称，按名称排序
+func Names() []string {
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	Register(strategyFunc{constants.SlugStrategyASCII, ascii, asciiPattern})
+	Register(strategyFunc{constants.SlugStrategyPinyin, pinyin, asciiPattern})
+	Register(strategyFunc{constants.SlugStrategyUnicode, unicodeSlug, validPattern})
+}
+
+// strategyFunc 以函数实现的策略，按正则校验slug
+type strategyFunc struct {
+	name  string
+	make  func(text string) string
+	valid *regexp.Regexp
+}
+
+func (s strategyFunc) Name() string { return s.name }
+
+func (s strategyFunc) Make(text string) string { return s.make(text) }
+
+func (s strategyFunc) Valid(slug string) bool { return s.valid.MatchString(slug) }
+
+// ascii 去除拉丁字母的变音符号，只保留ASCII字母和数字，如 "Crème Brûlée" -> "creme-brulee"
+func ascii(text string) string {
+	return join(fold(text), func(r rune) bool {
+		return r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+}
+
+// pinyinOverrides 转写表中缺失或有误的汉字
+var pinyinOverrides = map[rune]string{
+	'一': "yi",
+}
+
+// pinyin 汉字转为不带声调的拼音，其余按ascii策略处理，如 "Go 并发模式" -> "go-bing-fa-mo-shi"
+func pinyin(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) {
+			// 每个汉字的拼音作为一个单词
+			py, ok := pinyinOverrides[r]
+			if !ok {
+				py = unidecode.Unidecode(string(r))
+			}
+			b.WriteString(" " + py + " ")
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return ascii(b.String())
+}
+
+// unicodeSlug 保留各种文字的字母和数字，只转为小写，如 "Go 并发模式" -> "go-并发模式"
+// 非ASCII字符在URL中按百分号编码，见 Escape
+func unicodeSlug(text string) string {
+	return join(norm.NFC.String(text), func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.M, r)
+	})
+}
+
+// foldReplacer 无法通过分解去除变音符号的拉丁字母
+var foldReplacer = strings.NewReplacer(
+	"ß", "ss", "æ", "ae", "Æ", "ae", "œ", "oe", "Œ", "oe", "ø", "o", "Ø", "o",
+	"đ", "d", "Đ", "d", "ð", "d", "Ð", "d", "ł", "l", "Ł", "l", "þ", "th", "Þ", "th", "ı", "i",
+)
+
+// fold 分解字符并去除组合用变音符号
+func fold(text string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, foldReplacer.Replace(text))
+	if err != nil {
+		return text
+	}
+	return folded
+}
+
+// join 转为小写，把不满足keep的字符视为分隔符，用连字符连接各个单词并限制长度
+func join(text string, keep func(r rune) bool) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !keep(r)
+	})
+
+	var b strings.Builder
+	length := 0
+	for _, word := range words {
+		n := len([]rune(word))
+		if length > 0 {
+			n++
+		}
+		if length+n > maxLength {
+			if length == 0 {
+				return string([]rune(word)[:maxLength])
+			}
+			break
+		}
+		if length > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString(word)
+		length += n
+	}
+	return b.String()
+}
+
+// validPattern 合法的slug：以连字符分隔的小写字母、其他文字和数字，覆盖所有内置策略的输出
+var validPattern = regexp.MustCompile(`^[\p{Ll}\p{Lo}\p{Lm}\p{M}\p{Nd}]+(-[\p{Ll}\p{Lo}\p{Lm}\p{M}\p{Nd}]+)*$`)
+
+// asciiPattern ascii和pinyin策略的slug：以连字符分隔的ASCII小写字母和数字
+var asciiPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// IsValid 检查slug格式，大写字母、空白、下划线和标点都不允许
+// 已保存的slug可能由切换前的策略生成，这里接受所有内置策略的输出；新填写的slug应使用 Strategy.Valid 按当前策略校验
+func IsValid(slug string) bool {
+	return validPattern.MatchString(slug)
+}
+
+// Escape 按百分号编码slug，用于拼接URL路径
+func Escape(slug string) string {
+	return url.PathEscape(slug)
+}
@@ -0,0 +1,98 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/constants"
+)
+
+func TestStrategies(t *testing.T) {
+	Convey("Slug strategies", t, func() {
+		ascii := Get(constants.SlugStrategyASCII)
+		pinyin := Get(constants.SlugStrategyPinyin)
+		unicode := Get(constants.SlugStrategyUnicode)
+
+		Convey("ASCII should fold Latin diacritics and drop other scripts", func() {
+			So(ascii.Make("Hello, World!"), ShouldEqual, "hello-world")
+			So(ascii.Make("Crème Brûlée à la Straße"), ShouldEqual, "creme-brulee-a-la-strasse")
+			So(ascii.Make("Ærø Łódź"), ShouldEqual, "aero-lodz")
+			So(ascii.Make("Go 并发模式"), ShouldEqual, "go")
+			So(ascii.Make("并发"), ShouldEqual, "")
+		})
+
+		Convey("Pinyin should transliterate Han characters word by word", func() {
+			So(pinyin.Make("Go 并发模式"), ShouldEqual, "go-bing-fa-mo-shi")
+			So(pinyin.Make("Go语言2024"), ShouldEqual, "go-yu-yan-2024")
+			So(pinyin.Make("Café 指南"), ShouldEqual, "cafe-zhi-nan")
+			So(pinyin.Make("一个"), ShouldEqual, "yi-ge")
+		})
+
+		Convey("Unicode should keep every script in NFC lowercase", func() {
+			So(unicode.Make("Go 并发模式"), ShouldEqual, "go-并发模式")
+			So(unicode.Make("Привет, Мир"), ShouldEqual, "привет-мир")
+			So(unicode.Make("Café"), ShouldEqual, "café")
+		})
+
+		Convey("Valid should only accept the strategy's own character set", func() {
+			for _, s := range []string{"go-bing-fa", "post-123"} {
+				So(ascii.Valid(s), ShouldBeTrue)
+				So(pinyin.Valid(s), ShouldBeTrue)
+				So(unicode.Valid(s), ShouldBeTrue)
+			}
+			for _, s := range []string{"go-并发模式", "привет-мир", "café"} {
+				So(ascii.Valid(s), ShouldBeFalse)
+				So(pinyin.Valid(s), ShouldBeFalse)
+				So(unicode.Valid(s), ShouldBeTrue)
+			}
+			So(unicode.Valid("Hello"), ShouldBeFalse)
+			So(unicode.Valid("hello--world"), ShouldBeFalse)
+		})
+
+		Convey("Output should be cut at word boundaries", func() {
+			s := ascii.Make(strings.Repeat("word ", 30))
+			So(len(s), ShouldBeLessThanOrEqualTo, maxLength)
+			So(s, ShouldNotEndWith, "-")
+			So(len([]rune(unicode.Make(strings.Repeat("字", 150)))), ShouldEqual, maxLength)
+		})
+
+		Convey("Every strategy should produce valid slugs", func() {
+			for _, name := range Names() {
+				for _, text := range []string{"Go 并发模式", "Crème Brûlée", "Привет, Мир", "  --Hello__World--  "} {
+					if s := Get(name).Make(text); s != "" {
+						So(IsValid(s), ShouldBeTrue)
+						So(Get(name).Valid(s), ShouldBeTrue)
+					}
+				}
+			}
+		})
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	Convey("Strategy registry", t, func() {
+		So(Names(), ShouldResemble, []string{constants.SlugStrategyASCII, constants.SlugStrategyPinyin, constants.SlugStrategyUnicode})
+		So(Has(constants.SlugStrategyUnicode), ShouldBeTrue)
+		So(Has("unknown"), ShouldBeFalse)
+		So(Get("unknown").Name(), ShouldEqual, constants.SlugStrategyDefault)
+		So(Get("").Name(), ShouldEqual, constants.SlugStrategyDefault)
+	})
+}
+
+func TestIsValid(t *testing.T) {
+	Convey("IsValid", t, func() {
+		for _, s := range []string{"hello-world", "post-123", "go-并发模式", "привет-мир", "café"} {
+			So(IsValid(s), ShouldBeTrue)
+		}
+		for _, s := range []string{"", "Hello", "hello world", "hello_world", "-hello", "hello-", "hello--world", "a/b"} {
+			So(IsValid(s), ShouldBeFalse)
+		}
+	})
+
+	Convey("Escape should percent-encode non-ASCII characters", t, func() {
+		So(Escape("hello-world"), ShouldEqual, "hello-world")
+		So(Escape("go-并发"), ShouldEqual, "go-%E5%B9%B6%E5%8F%91")
+	})
+}
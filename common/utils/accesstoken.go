@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/heimdall-api/common/constants"
+)
+
+const (
+	accessTokenBytes     = 32 // 个人访问令牌随机部分字节数
+	accessTokenPrefixLen = 12 // 展示给用户辨认的令牌开头长度（含前缀）
+)
+
+// GenerateAccessToken 生成个人访问令牌，返回明文和用于展示的开头部分
+func GenerateAccessToken() (token, prefix string, err error) {
+	raw := make([]byte, accessTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", fmt.Errorf("生成访问令牌失败: %w", err)
+	}
+	token = constants.AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	return token, token[:accessTokenPrefixLen], nil
+}
+
+// IsAccessToken 检查令牌是否为个人访问令牌格式
+func IsAccessToken(token string) bool {
+	return strings.HasPrefix(token, constants.AccessTokenPrefix)
+}
+
+// HashAccessToken 计算个人访问令牌哈希
+// 令牌本身是高熵随机值，使用SHA-256即可，且每个请求都要校验，不适合bcrypt
+func HashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/constants"
+)
+
+func TestAccessToken(t *testing.T) {
+	Convey("Access token helpers", t, func() {
+		Convey("Should generate unique prefixed tokens", func() {
+			token, prefix, err := GenerateAccessToken()
+			So(err, ShouldBeNil)
+			So(strings.HasPrefix(token, constants.AccessTokenPrefix), ShouldBeTrue)
+			So(strings.HasPrefix(token, prefix), ShouldBeTrue)
+			So(len(prefix), ShouldBeLessThan, len(token))
+			So(IsAccessToken(token), ShouldBeTrue)
+
+			other, _, _ := GenerateAccessToken()
+			So(other, ShouldNotEqual, token)
+		})
+
+		Convey("JWTs should not look like access tokens", func() {
+			So(IsAccessToken("eyJhbGciOiJIUzI1NiJ9.e30.sig"), ShouldBeFalse)
+		})
+
+		Convey("Hash should be stable and hide the token", func() {
+			token, _, _ := GenerateAccessToken()
+			So(HashAccessToken(token), ShouldEqual, HashAccessToken(token))
+			So(HashAccessToken(token), ShouldHaveLength, 64)
+			So(HashAccessToken(token), ShouldNotContainSubstring, token)
+		})
+	})
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// UnknownClientValue 无法获取客户端信息时使用的占位值
+const UnknownClientValue = "unknown"
+
+// clientInfoKey 客户端信息在context中的键
+type clientInfoKey struct{}
+
+// ClientInfo 客户端信息
+type ClientInfo struct {
+	IP        string // 客户端真实IP
+	UserAgent string // 原始User-Agent
+}
+
+// ParseTrustedProxies 解析受信任代理列表，支持CIDR和单个IP
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		ipNet, err := ParseIPNet(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy: %s", proxy)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// ParseIPNet 解析CIDR或单个IP，单个IP转换为/32或/128网段，返回的网段已按掩码规范化
+func ParseIPNet(value string) (*net.IPNet, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid ip: %s", value)
+		}
+		if ip.To4() != nil {
+			value += "/32"
+		} else {
+			value += "/128"
+		}
+	}
+
+	_, ipNet, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cidr: %s", value)
+	}
+	return ipNet, nil
+}
+
+// GetClientIP 获取客户端真实IP
+// 仅当直连地址属于受信任代理时才解析 X-Forwarded-For / X-Real-IP，防止客户端伪造
+func GetClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	remoteIP := remoteAddrIP(r.RemoteAddr)
+	if remoteIP == "" || !isTrustedProxy(remoteIP, trustedProxies) {
+		return remoteIP
+	}
+
+	// X-Forwarded-For 从右向左查找第一个非受信任代理的地址
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				break
+			}
+			if !isTrustedProxy(hop, trustedProxies) || i == 0 {
+				return hop
+			}
+		}
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	return remoteIP
+}
+
+// WithClientInfo 将客户端信息写入context
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoFromContext 从context获取客户端信息，缺失的字段使用占位值填充
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	if info.IP == "" {
+		info.IP = UnknownClientValue
+	}
+	if info.UserAgent == "" {
+		info.UserAgent = UnknownClientValue
+	}
+	return info
+}
+
+// remoteAddrIP 从 host:port 形式的远端地址中提取IP
+func remoteAddrIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(remoteAddr))
+	if err != nil {
+		host = strings.TrimSpace(remoteAddr)
+	}
+	if net.ParseIP(host) == nil {
+		return ""
+	}
+	return host
+}
+
+// isTrustedProxy 判断IP是否属于受信任代理
+func isTrustedProxy(ip string, trustedProxies []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, ipNet := range trustedProxies {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
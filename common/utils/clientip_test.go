@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	Convey("Test ParseTrustedProxies", t, func() {
+		Convey("Should parse CIDRs and single IPs", func() {
+			nets, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.10", "::1", " "})
+
+			So(err, ShouldBeNil)
+			So(len(nets), ShouldEqual, 3)
+			So(nets[1].String(), ShouldEqual, "192.168.1.10/32")
+			So(nets[2].String(), ShouldEqual, "::1/128")
+		})
+
+		Convey("Invalid entry should return error", func() {
+			nets, err := ParseTrustedProxies([]string{"not-an-ip"})
+
+			So(err, ShouldNotBeNil)
+			So(nets, ShouldBeNil)
+		})
+	})
+}
+
+func TestParseIPNet(t *testing.T) {
+	Convey("Test ParseIPNet", t, func() {
+		Convey("Should normalize CIDRs and single IPs", func() {
+			ipNet, err := ParseIPNet("192.168.1.77/24")
+			So(err, ShouldBeNil)
+			So(ipNet.String(), ShouldEqual, "192.168.1.0/24")
+
+			ipNet, err = ParseIPNet(" 2001:db8::1 ")
+			So(err, ShouldBeNil)
+			So(ipNet.String(), ShouldEqual, "2001:db8::1/128")
+		})
+
+		Convey("Invalid values should return error", func() {
+			for _, value := range []string{"", "example.com", "10.0.0.0/33"} {
+				_, err := ParseIPNet(value)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+}
+
+func TestGetClientIP(t *testing.T) {
+	trusted, _ := ParseTrustedProxies([]string{"10.0.0.0/8"})
+
+	Convey("Test GetClientIP", t, func() {
+		req := httptest.NewRequest("GET", "/", nil)
+
+		Convey("Untrusted remote address should ignore forwarding headers", func() {
+			req.RemoteAddr = "203.0.113.5:5000"
+			req.Header.Set("X-Forwarded-For", "1.2.3.4")
+			req.Header.Set("X-Real-IP", "5.6.7.8")
+
+			So(GetClientIP(req, trusted), ShouldEqual, "203.0.113.5")
+		})
+
+		Convey("Trusted proxy should use the right-most untrusted X-Forwarded-For hop", func() {
+			req.RemoteAddr = "10.0.0.2:5000"
+			req.Header.Set("X-Forwarded-For", "6.6.6.6, 198.51.100.7, 10.0.0.3")
+
+			So(GetClientIP(req, trusted), ShouldEqual, "198.51.100.7")
+		})
+
+		Convey("Trusted proxy chain should fall back to the left-most hop", func() {
+			req.RemoteAddr = "10.0.0.2:5000"
+			req.Header.Set("X-Forwarded-For", "10.1.1.1, 10.0.0.3")
+
+			So(GetClientIP(req, trusted), ShouldEqual, "10.1.1.1")
+		})
+
+		Convey("Trusted proxy should use X-Real-IP when X-Forwarded-For is absent", func() {
+			req.RemoteAddr = "10.0.0.2:5000"
+			req.Header.Set("X-Real-IP", "198.51.100.8")
+
+			So(GetClientIP(req, trusted), ShouldEqual, "198.51.100.8")
+		})
+
+		Convey("Malformed forwarding headers should fall back to remote address", func() {
+			req.RemoteAddr = "10.0.0.2:5000"
+			req.Header.Set("X-Forwarded-For", "garbage")
+			req.Header.Set("X-Real-IP", "garbage")
+
+			So(GetClientIP(req, trusted), ShouldEqual, "10.0.0.2")
+		})
+
+		Convey("No trusted proxies should always use remote address", func() {
+			req.RemoteAddr = "[2001:db8::1]:443"
+			req.Header.Set("X-Forwarded-For", "1.2.3.4")
+
+			So(GetClientIP(req, nil), ShouldEqual, "2001:db8::1")
+		})
+	})
+}
+
+func TestClientInfoContext(t *testing.T) {
+	Convey("Test ClientInfo context helpers", t, func() {
+		Convey("Should return stored client info", func() {
+			ctx := WithClientInfo(context.Background(), ClientInfo{IP: "1.2.3.4", UserAgent: "Mozilla/5.0"})
+			info := ClientInfoFromContext(ctx)
+
+			So(info.IP, ShouldEqual, "1.2.3.4")
+			So(info.UserAgent, ShouldEqual, "Mozilla/5.0")
+		})
+
+		Convey("Missing values should use placeholder", func() {
+			info := ClientInfoFromContext(context.Background())
+
+			So(info.IP, ShouldEqual, UnknownClientValue)
+			So(info.UserAgent, ShouldEqual, UnknownClientValue)
+		})
+	})
+}
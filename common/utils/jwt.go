@@ -32,13 +32,28 @@ var (
 
 // JWTClaims JWT声明结构，遵循安全设计规范
 type JWTClaims struct {
-	UserID   string `json:"sub"`      // 用户ID (Subject)
-	Username string `json:"username"` // 用户名
-	Role     string `json:"role"`     // 用户角色
-	TokenID  string `json:"jti"`      // 令牌唯一标识 (JWT ID)
+	UserID    string `json:"sub"`              // 用户ID (Subject)
+	Username  string `json:"username"`         // 用户名
+	Role      string `json:"role"`             // 用户角色
+	TokenID   string `json:"jti"`              // 令牌唯一标识 (JWT ID)
+	FamilyID  string `json:"fid,omitempty"`    // 刷新令牌族标识，仅刷新令牌携带
+	SessionID string `json:"sid,omitempty"`    // 登录会话标识，仅访问令牌携带
+	IssuedMs  int64  `json:"iat_ms,omitempty"` // 签发时间（Unix毫秒），iat只精确到秒
 	jwt.RegisteredClaims
 }
 
+// IssuedAtMillis 获取令牌签发时间（Unix毫秒）
+// 未携带毫秒声明的令牌按iat截断到整秒计算，未设置签发时间时返回0
+func (c *JWTClaims) IssuedAtMillis() int64 {
+	if c.IssuedMs > 0 {
+		return c.IssuedMs
+	}
+	if c.IssuedAt == nil {
+		return 0
+	}
+	return c.IssuedAt.Unix() * 1000
+}
+
 // TokenPair 令牌对结构
 type TokenPair struct {
 	AccessToken  string    `json:"accessToken"`
@@ -76,6 +91,7 @@ func (j *JWTManager) GenerateToken(userID, username, role string) (*TokenPair, e
 		Username: username,
 		Role:     role,
 		TokenID:  tokenID,
+		IssuedMs: now.UnixMilli(),
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    j.issuer,
 			Subject:   userID,
@@ -99,6 +115,7 @@ func (j *JWTManager) GenerateToken(userID, username, role string) (*TokenPair, e
 		Username: username,
 		Role:     role,
 		TokenID:  refreshTokenID,
+		IssuedMs: now.UnixMilli(),
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    j.issuer,
 			Subject:   userID,
@@ -177,6 +194,44 @@ func (j *JWTManager) RefreshToken(refreshTokenString string) (*TokenPair, error)
 	return j.GenerateToken(claims.UserID, claims.Username, claims.Role)
 }
 
+// GenerateRefreshToken 生成归属于指定令牌族的刷新令牌，返回令牌及其唯一标识
+func (j *JWTManager) GenerateRefreshToken(userID, username, role, familyID string) (string, string, error) {
+	if userID == "" || username == "" || role == "" {
+		return "", "", errors.New("userID, username and role cannot be empty")
+	}
+	if familyID == "" {
+		return "", "", errors.New("familyID cannot be empty")
+	}
+
+	now := time.Now()
+	tokenID := uuid.New().String()
+
+	claims := &JWTClaims{
+		UserID:   userID,
+		Username: username,
+		Role:     role,
+		TokenID:  tokenID,
+		FamilyID: familyID,
+		IssuedMs: now.UnixMilli(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    j.issuer,
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(RefreshTokenExpiration)),
+			NotBefore: jwt.NewNumericDate(now),
+			ID:        tokenID,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(j.secretKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign refresh token: %w", err)
+	}
+
+	return tokenString, tokenID, nil
+}
+
 // ExtractUserIDFromToken 从令牌中提取用户ID
 func (j *JWTManager) ExtractUserIDFromToken(tokenString string) (string, error) {
 	claims, err := j.ValidateToken(tokenString)
@@ -382,6 +437,11 @@ func (j *JWTManager) ExtractTokenMetadata(tokenString string) (map[string]interf
 
 // GenerateGoZeroCompatibleToken 生成与go-zero JWT中间件兼容的令牌
 func (j *JWTManager) GenerateGoZeroCompatibleToken(userID, username, role string) (string, error) {
+	return j.GenerateSessionToken(userID, username, role, "")
+}
+
+// GenerateSessionToken 生成携带登录会话标识的go-zero兼容访问令牌，sessionID为空时不写入sid
+func (j *JWTManager) GenerateSessionToken(userID, username, role, sessionID string) (string, error) {
 	if userID == "" || username == "" || role == "" {
 		return "", errors.New("userID, username and role cannot be empty")
 	}
@@ -403,6 +463,10 @@ func (j *JWTManager) GenerateGoZeroCompatibleToken(userID, username, role string
 		// 自定义字段
 		"username": username,
 		"role":     role,
+		"iat_ms":   now.UnixMilli(), // 签发时间（毫秒），用于比较令牌失效水位线
+	}
+	if sessionID != "" {
+		claims["sid"] = sessionID
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
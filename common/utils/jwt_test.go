@@ -141,6 +141,78 @@ func TestRefreshToken(t *testing.T) {
 	})
 }
 
+func TestGenerateRefreshToken(t *testing.T) {
+	jwtManager := NewJWTManager("test-secret", "test-issuer")
+
+	Convey("Test GenerateRefreshToken", t, func() {
+		userID := "user123"
+		username := "testuser"
+		role := "admin"
+		familyID := "family-1"
+
+		Convey("Valid parameters should generate refresh token with family", func() {
+			token, tokenID, err := jwtManager.GenerateRefreshToken(userID, username, role, familyID)
+
+			So(err, ShouldBeNil)
+			So(token, ShouldNotBeEmpty)
+			So(tokenID, ShouldNotBeEmpty)
+
+			claims, err := jwtManager.ValidateToken(token)
+			So(err, ShouldBeNil)
+			So(claims.FamilyID, ShouldEqual, familyID)
+			So(claims.TokenID, ShouldEqual, tokenID)
+			So(claims.ExpiresAt.Time, ShouldHappenWithin, time.Minute, time.Now().Add(RefreshTokenExpiration))
+		})
+
+		Convey("Each refresh token should have a unique token ID", func() {
+			_, firstID, _ := jwtManager.GenerateRefreshToken(userID, username, role, familyID)
+			_, secondID, _ := jwtManager.GenerateRefreshToken(userID, username, role, familyID)
+
+			So(firstID, ShouldNotEqual, secondID)
+		})
+
+		Convey("Empty familyID should return error", func() {
+			token, tokenID, err := jwtManager.GenerateRefreshToken(userID, username, role, "")
+
+			So(err, ShouldNotBeNil)
+			So(token, ShouldBeEmpty)
+			So(tokenID, ShouldBeEmpty)
+		})
+
+		Convey("Empty userID should return error", func() {
+			_, _, err := jwtManager.GenerateRefreshToken("", username, role, familyID)
+
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestGenerateSessionToken(t *testing.T) {
+	jwtManager := NewJWTManager("test-secret", "test-issuer")
+
+	Convey("Test GenerateSessionToken", t, func() {
+		Convey("Session ID should be carried in sid claim", func() {
+			token, err := jwtManager.GenerateSessionToken("user123", "testuser", "admin", "session-1")
+			So(err, ShouldBeNil)
+
+			claims, err := jwtManager.ValidateToken(token)
+			So(err, ShouldBeNil)
+			So(claims.SessionID, ShouldEqual, "session-1")
+			So(claims.FamilyID, ShouldBeEmpty)
+		})
+
+		Convey("Empty session ID should omit sid claim", func() {
+			token, err := jwtManager.GenerateGoZeroCompatibleToken("user123", "testuser", "admin")
+			So(err, ShouldBeNil)
+
+			claims, err := jwtManager.ValidateGoZeroCompatibleToken(token)
+			So(err, ShouldBeNil)
+			_, exists := claims["sid"]
+			So(exists, ShouldBeFalse)
+		})
+	})
+}
+
 func TestTokenExtractionMethods(t *testing.T) {
 	jwtManager := NewJWTManager("test-secret", "test-issuer")
 	userID := "user123"
@@ -184,6 +184,31 @@ func ValidatePasswordForUser(password, username, email string) error {
 	return nil
 }
 
+// IsPasswordReused 检查明文密码是否与任意一个历史密码哈希匹配
+func IsPasswordReused(password string, hashes []string) bool {
+	for _, hash := range hashes {
+		if hash != "" && VerifyPassword(password, hash) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// PushPasswordHistory 将新密码哈希放到历史记录头部，只保留最近limit条
+func PushPasswordHistory(history []string, hash string, limit int) []string {
+	result := make([]string, 0, limit)
+	result = append(result, hash)
+	for _, h := range history {
+		if len(result) >= limit {
+			break
+		}
+		if h != "" && h != hash {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
 // GetPasswordStrengthScore 获取密码强度评分 (0-100)
 func GetPasswordStrengthScore(password string) int {
 	if len(password) < MinPasswordLength {
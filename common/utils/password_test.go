@@ -148,6 +148,32 @@ func TestValidatePasswordForUser(t *testing.T) {
 	})
 }
 
+func TestPasswordHistory(t *testing.T) {
+	Convey("Test password history", t, func() {
+		oldHash, _ := bcrypt.GenerateFromPassword([]byte("OldPass123!"), bcrypt.MinCost)
+		currentHash, _ := bcrypt.GenerateFromPassword([]byte("CurrentPass123!"), bcrypt.MinCost)
+		history := []string{string(currentHash), string(oldHash)}
+
+		Convey("Should detect reused passwords", func() {
+			So(IsPasswordReused("OldPass123!", history), ShouldBeTrue)
+			So(IsPasswordReused("CurrentPass123!", history), ShouldBeTrue)
+			So(IsPasswordReused("BrandNew123!", history), ShouldBeFalse)
+			So(IsPasswordReused("OldPass123!", nil), ShouldBeFalse)
+		})
+
+		Convey("Should keep the most recent hashes", func() {
+			result := PushPasswordHistory(history, "h3", 2)
+			So(result, ShouldResemble, []string{"h3", string(currentHash)})
+
+			result = PushPasswordHistory(nil, "h1", 5)
+			So(result, ShouldResemble, []string{"h1"})
+
+			result = PushPasswordHistory([]string{"h1", "", "h2"}, "h1", 5)
+			So(result, ShouldResemble, []string{"h1", "h2"})
+		})
+	})
+}
+
 func TestGetPasswordStrengthScore(t *testing.T) {
 	Convey("Test GetPasswordStrengthScore", t, func() {
 		Convey("Too short password should get 0 score", func() {
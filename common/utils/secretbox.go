@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// SecretBoxKeySize 加密密钥字节数（AES-256）
+const SecretBoxKeySize = 32
+
+var (
+	// ErrInvalidSecretBoxKey 加密密钥格式错误
+	ErrInvalidSecretBoxKey = errors.New("secret box key must be 32 bytes encoded in base64")
+	// ErrSecretBoxOpen 密文格式错误、被篡改或不属于该上下文
+	ErrSecretBoxOpen = errors.New("failed to open sealed secret")
+)
+
+// SecretBox 使用AES-256-GCM加密需要保存在数据库或缓存中的敏感数据（如TOTP密钥）
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewSecretBox 使用Base64编码的32字节密钥创建SecretBox
+func NewSecretBox(key string) (*SecretBox, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != SecretBoxKeySize {
+		return nil, ErrInvalidSecretBoxKey
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcm: %w", err)
+	}
+	return &SecretBox{aead: aead}, nil
+}
+
+// Seal 加密明文，返回Base64编码的随机数和密文
+// context 作为附加数据参与认证（如用户ID），密文不能挪到其他上下文中解密
+func (b *SecretBox) Seal(plaintext, context string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), []byte(context))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open 解密Seal生成的密文，context 必须与加密时一致
+func (b *SecretBox) Open(sealed, context string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < b.aead.NonceSize() {
+		return "", ErrSecretBoxOpen
+	}
+
+	nonce, ciphertext := raw[:b.aead.NonceSize()], raw[b.aead.NonceSize():]
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, []byte(context))
+	if err != nil {
+		return "", ErrSecretBoxOpen
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSecretBox(t *testing.T) {
+	Convey("Test SecretBox", t, func() {
+		key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", SecretBoxKeySize)))
+		box, err := NewSecretBox(key)
+		So(err, ShouldBeNil)
+
+		Convey("Should reject keys that are not 32 bytes of base64", func() {
+			for _, bad := range []string{"", "not-base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+				_, err := NewSecretBox(bad)
+				So(err, ShouldEqual, ErrInvalidSecretBoxKey)
+			}
+		})
+
+		Convey("Should round trip without exposing the plaintext", func() {
+			sealed, err := box.Seal("JBSWY3DPEHPK3PXP", "user-1")
+			So(err, ShouldBeNil)
+			So(sealed, ShouldNotContainSubstring, "JBSWY3DPEHPK3PXP")
+
+			again, err := box.Seal("JBSWY3DPEHPK3PXP", "user-1")
+			So(err, ShouldBeNil)
+			So(again, ShouldNotEqual, sealed)
+
+			plaintext, err := box.Open(sealed, "user-1")
+			So(err, ShouldBeNil)
+			So(plaintext, ShouldEqual, "JBSWY3DPEHPK3PXP")
+		})
+
+		Convey("Should refuse another context, a different key or tampered data", func() {
+			sealed, _ := box.Seal("JBSWY3DPEHPK3PXP", "user-1")
+
+			_, err := box.Open(sealed, "user-2")
+			So(err, ShouldEqual, ErrSecretBoxOpen)
+
+			other, _ := NewSecretBox(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("o", SecretBoxKeySize))))
+			_, err = other.Open(sealed, "user-1")
+			So(err, ShouldEqual, ErrSecretBoxOpen)
+
+			raw, _ := base64.StdEncoding.DecodeString(sealed)
+			raw[len(raw)-1] ^= 0xff
+			_, err = box.Open(base64.StdEncoding.EncodeToString(raw), "user-1")
+			So(err, ShouldEqual, ErrSecretBoxOpen)
+
+			_, err = box.Open("plain", "user-1")
+			So(err, ShouldEqual, ErrSecretBoxOpen)
+		})
+	})
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSignedToken(t *testing.T) {
+	Convey("Signed token helpers", t, func() {
+		now := time.Now()
+		expiresAt := now.Add(time.Hour)
+
+		Convey("Should round-trip the subject", func() {
+			token, err := SignToken("secret", "invitation", "64b7f0c2a1b2c3d4e5f60718", expiresAt)
+			So(err, ShouldBeNil)
+
+			subject, err := ParseSignedToken("secret", "invitation", token, now)
+			So(err, ShouldBeNil)
+			So(subject, ShouldEqual, "64b7f0c2a1b2c3d4e5f60718")
+
+			other, _ := SignToken("secret", "invitation", "64b7f0c2a1b2c3d4e5f60718", expiresAt)
+			So(other, ShouldNotEqual, token)
+		})
+
+		Convey("Should reject expired tokens", func() {
+			token, _ := SignToken("secret", "invitation", "subject", expiresAt)
+			_, err := ParseSignedToken("secret", "invitation", token, expiresAt)
+			So(err, ShouldEqual, ErrSignedTokenExpired)
+		})
+
+		Convey("Should reject another secret or purpose", func() {
+			token, _ := SignToken("secret", "invitation", "subject", expiresAt)
+			_, err := ParseSignedToken("other", "invitation", token, now)
+			So(err, ShouldEqual, ErrSignedTokenInvalid)
+			_, err = ParseSignedToken("secret", "password-reset", token, now)
+			So(err, ShouldEqual, ErrSignedTokenInvalid)
+		})
+
+		Convey("Should reject tampered payloads", func() {
+			token, _ := SignToken("secret", "invitation", "subject", expiresAt)
+			forged, _ := SignToken("secret", "invitation", "another", now.Add(365*24*time.Hour))
+			payload, _, _ := strings.Cut(forged, ".")
+			_, signature, _ := strings.Cut(token, ".")
+
+			_, err := ParseSignedToken("secret", "invitation", payload+"."+signature, now)
+			So(err, ShouldEqual, ErrSignedTokenInvalid)
+			_, err = ParseSignedToken("secret", "invitation", "not-a-token", now)
+			So(err, ShouldEqual, ErrSignedTokenInvalid)
+		})
+
+		Convey("Should refuse subjects containing the separator", func() {
+			_, err := SignToken("secret", "invitation", "a.b", expiresAt)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
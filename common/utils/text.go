@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/heimdall-api/common/constants"
+)
+
+// TextStats 正文字数统计
+type TextStats struct {
+	CJKChars int // 中日文字符数，汉字和假名逐字计数
+	Words    int // 其他文字（拉丁字母、数字、韩文等）按空白和标点分隔的词数
+}
+
+// WordCount 总字数，中日文按字、其他文字按词计
+func (s TextStats) WordCount() int {
+	return s.CJKChars + s.Words
+}
+
+// ReadingTime 按中日文和其他文字各自的阅读速度估算阅读时间（分钟），向上取整
+func (s TextStats) ReadingTime() int {
+	minutes := float64(s.CJKChars)/constants.ReadingSpeedCJKCharsPerMinute +
+		float64(s.Words)/constants.ReadingSpeedWordsPerMinute
+	readingTime := int(math.Ceil(minutes))
+	if readingTime < constants.ReadingTimeMin {
+		return constants.ReadingTimeMin
+	}
+	if readingTime > constants.ReadingTimeMax {
+		return constants.ReadingTimeMax
+	}
+	return readingTime
+}
+
+// CountText 统计纯文本字数，汉字和假名逐字计数，其余字母数字按词计数
+// 词内的撇号、连字符和小数点不拆分，如 don't、e-mail、3.14 各算一个词
+func CountText(text string) TextStats {
+	var stats TextStats
+	inWord := false
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case isCJK(r):
+			stats.CJKChars++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r):
+			if !inWord {
+				stats.Words++
+				inWord = true
+			}
+		case inWord && isWordJoiner(r) && i+1 < len(runes) && isWordRune(runes[i+1]):
+			// 词内连接符，继续当前词
+		default:
+			inWord = false
+		}
+	}
+	return stats
+}
+
+// isCJK 检查是否为逐字计数的中日文字符
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// isWordRune 检查是否为按词计数的字符
+func isWordRune(r rune) bool {
+	return !isCJK(r) && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// isWordJoiner 检查是否为词内连接符
+func isWordJoiner(r rune) bool {
+	return r == '\'' || r == '’' || r == '-' || r == '.' || r == '_'
+}
+
+// Excerpt 从纯文本生成摘要，按整句截取不超过maxLength个字符
+// 文本按行分段；第一句就超过长度时在词边界截断并加省略号
+func Excerpt(text string, maxLength int) string {
+	if maxLength <= 0 {
+		maxLength = constants.PostExcerptDefaultLength
+	}
+
+	var b strings.Builder
+	length := 0
+	for _, paragraph := range strings.Split(text, "\n") {
+		for _, sentence := range splitSentences(paragraph) {
+			sep := ""
+			if length > 0 && needsSpace(b.String(), sentence) {
+				sep = " "
+			}
+			n := utf8.RuneCountInString(sep + sentence)
+			if length+n > maxLength {
+				if length == 0 {
+					return truncateWords(sentence, maxLength)
+				}
+				return b.String()
+			}
+			b.WriteString(sep + sentence)
+			length += n
+		}
+	}
+	return b.String()
+}
+
+// closingPunctuation 句末标点后紧跟的右引号和右括号，归入当前句
+const closingPunctuation = `"'”’」』）)》]`
+
+// splitSentences 按句末标点切分一个段落，英文句点后须跟空白才算句末，避免切开小数和缩写
+func splitSentences(paragraph string) []string {
+	runes := []rune(strings.Join(strings.Fields(paragraph), " "))
+	var sentences []string
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		end := -1
+		switch runes[i] {
+		case '。', '！', '？', '!', '?', '…':
+			end = i + 1
+		case '.':
+			if i+1 == len(runes) || runes[i+1] == ' ' || strings.ContainsRune(closingPunctuation, runes[i+1]) {
+				end = i + 1
+			}
+		}
+		if end < 0 {
+			continue
+		}
+		for end < len(runes) && (strings.ContainsRune(closingPunctuation, runes[end]) || runes[end] == '…' || runes[end] == '.') {
+			end++
+		}
+		if sentence := strings.TrimSpace(string(runes[start:end])); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start, i = end, end-1
+	}
+	if rest := strings.TrimSpace(string(runes[start:])); rest != "" {
+		sentences = append(sentences, rest)
+	}
+	return sentences
+}
+
+// needsSpace 拼接两句时，全角标点之后或两个中日文字符之间不加空格，其余情况用空格分隔
+func needsSpace(prev, next string) bool {
+	last, _ := utf8.DecodeLastRuneInString(prev)
+	first, _ := utf8.DecodeRuneInString(next)
+	if isFullwidthPunct(last) {
+		return false
+	}
+	return !(isCJK(last) && isCJK(first))
+}
+
+// isFullwidthPunct 检查是否为中日文标点或全角符号
+func isFullwidthPunct(r rune) bool {
+	return (r >= 0x3000 && r <= 0x303f) || (r >= 0xff00 && r <= 0xffef)
+}
+
+// truncateWords 截断过长的句子，尽量在空白处断开，末尾加省略号
+func truncateWords(sentence string, maxLength int) string {
+	runes := []rune(sentence)
+	if len(runes) <= maxLength {
+		return sentence
+	}
+	cut := string(runes[:maxLength])
+	if i := strings.LastIndex(cut, " "); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,，、;；:：") + "..."
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/constants"
+)
+
+func TestCountText(t *testing.T) {
+	Convey("CountText", t, func() {
+		Convey("Should count CJK characters one by one and other text by words", func() {
+			So(CountText("Go语言的并发模型"), ShouldResemble, TextStats{CJKChars: 7, Words: 1})
+			So(CountText("ひらがなとカタカナ"), ShouldResemble, TextStats{CJKChars: 9})
+			So(CountText("Hello, world! 你好，世界。"), ShouldResemble, TextStats{CJKChars: 4, Words: 2})
+			So(CountText("안녕하세요 세계"), ShouldResemble, TextStats{Words: 2})
+		})
+
+		Convey("Should keep joined words together", func() {
+			So(CountText("don't use e-mail, Go 1.24 or snake_case.").Words, ShouldEqual, 7)
+			So(CountText("--- ... ,,,").Words, ShouldEqual, 0)
+		})
+
+		Convey("Should use separate reading speeds for CJK and other text", func() {
+			So(TextStats{CJKChars: 3000}.ReadingTime(), ShouldEqual, 10)
+			So(TextStats{Words: 400}.ReadingTime(), ShouldEqual, 2)
+			So(TextStats{CJKChars: 150, Words: 100}.ReadingTime(), ShouldEqual, 1)
+			So(TextStats{CJKChars: 151, Words: 100}.ReadingTime(), ShouldEqual, 2)
+			So(TextStats{}.ReadingTime(), ShouldEqual, constants.ReadingTimeMin)
+			So(TextStats{Words: 1000000}.ReadingTime(), ShouldEqual, constants.ReadingTimeMax)
+			So(TextStats{CJKChars: 3, Words: 2}.WordCount(), ShouldEqual, 5)
+		})
+	})
+}
+
+func TestExcerpt(t *testing.T) {
+	Convey("Excerpt", t, func() {
+		Convey("Should cut at sentence boundaries", func() {
+			text := "第一句话。第二句话！第三句话很长很长很长。"
+			So(Excerpt(text, 10), ShouldEqual, "第一句话。第二句话！")
+			So(Excerpt(text, 12), ShouldEqual, "第一句话。第二句话！")
+			So(Excerpt(text, 100), ShouldEqual, text)
+			So(Excerpt("Go 1.24 is out. It is fast. Really fast.", 30), ShouldEqual, "Go 1.24 is out. It is fast.")
+			So(Excerpt(`他说：“走吧。”然后离开了。`, 8), ShouldEqual, `他说：“走吧。”`)
+		})
+
+		Convey("Should join paragraphs and keep the result within the limit", func() {
+			So(Excerpt("第一段。\n第二段。", 10), ShouldEqual, "第一段。第二段。")
+			So(Excerpt("First one.\nSecond one.", 30), ShouldEqual, "First one. Second one.")
+			So(Excerpt("  多余的   空白  ", 10), ShouldEqual, "多余的 空白")
+		})
+
+		Convey("Should truncate an overlong first sentence at a word boundary", func() {
+			So(Excerpt("这是一个没有任何标点而且非常长的句子", 5), ShouldEqual, "这是一个没...")
+			So(Excerpt("a sentence without any punctuation that keeps going", 20), ShouldEqual, "a sentence without...")
+		})
+
+		Convey("Should use the default length when not specified", func() {
+			So(len([]rune(Excerpt(strings.Repeat("字", 300), 0))), ShouldEqual, constants.PostExcerptDefaultLength+3)
+			So(Excerpt("", 10), ShouldEqual, "")
+		})
+	})
+}
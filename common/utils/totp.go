@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// TOTPDigits 验证码位数
+	TOTPDigits = 6
+	// TOTPPeriod 验证码时间步长（秒）
+	TOTPPeriod = 30
+	// TOTPSkew 允许的前后时间步偏差，用于容忍客户端时钟误差
+	TOTPSkew = 1
+	// TOTPSecretSize 密钥字节数（160位，RFC 4226推荐值）
+	TOTPSecretSize = 20
+	// RecoveryCodeCount 每次生成的恢复码数量
+	RecoveryCodeCount = 10
+)
+
+var (
+	// ErrInvalidTOTPSecret TOTP密钥格式错误
+	ErrInvalidTOTPSecret = errors.New("invalid totp secret")
+
+	// totpEncoding 无填充的Base32编码，与主流身份验证器应用兼容
+	totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// GenerateTOTPSecret 生成Base32编码的随机TOTP密钥
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, TOTPSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// BuildTOTPURI 生成身份验证器应用可识别的 otpauth:// URI
+func BuildTOTPURI(issuer, account, secret string) string {
+	label := url.PathEscape(account)
+	if issuer != "" {
+		label = url.PathEscape(issuer) + ":" + label
+	}
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	if issuer != "" {
+		query.Set("issuer", issuer)
+	}
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(TOTPDigits))
+	query.Set("period", fmt.Sprint(TOTPPeriod))
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// TOTPStep 返回时间对应的时间步
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / TOTPPeriod
+}
+
+// GenerateTOTPCode 生成指定时间的TOTP验证码（RFC 6238，HMAC-SHA1）
+func GenerateTOTPCode(secret string, t time.Time) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, TOTPStep(t)), nil
+}
+
+// ValidateTOTPCode 校验验证码，允许 TOTPSkew 个时间步的偏差
+// 校验通过时返回匹配的时间步，调用方应拒绝不大于上次使用时间步的验证码以防重放
+func ValidateTOTPCode(secret, code string, t time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != TOTPDigits {
+		return 0, false
+	}
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	current := TOTPStep(t)
+	for offset := int64(-TOTPSkew); offset <= TOTPSkew; offset++ {
+		step := current + offset
+		if hmac.Equal([]byte(hotp(key, step)), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// GenerateRecoveryCodes 生成一次性恢复码，格式为 xxxxx-xxxxx
+func GenerateRecoveryCodes(count int) ([]string, error) {
+	const alphabet = "abcdefghjkmnpqrstuvwxyz23456789" // 去掉易混淆的字符
+	codes := make([]string, 0, count)
+	buf := make([]byte, 10)
+	for i := 0; i < count; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		var sb strings.Builder
+		for j, b := range buf {
+			if j == 5 {
+				sb.WriteByte('-')
+			}
+			sb.WriteByte(alphabet[int(b)%len(alphabet)])
+		}
+		codes = append(codes, sb.String())
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode 计算恢复码哈希，忽略大小写、空格和连字符
+// 恢复码本身是高熵随机值，使用SHA-256即可，无需bcrypt
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// decodeTOTPSecret 解码Base32密钥，兼容小写、空格和填充
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(secret), " ", ""))
+	normalized = strings.TrimRight(normalized, "=")
+	key, err := totpEncoding.DecodeString(normalized)
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidTOTPSecret
+	}
+	return key, nil
+}
+
+// hotp 计算HOTP值（RFC 4226）
+func hotp(key []byte, counter int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < TOTPDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", TOTPDigits, value%mod)
+}
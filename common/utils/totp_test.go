@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTOTP(t *testing.T) {
+	Convey("Test TOTP", t, func() {
+		// RFC 6238 附录B测试向量（SHA1，取后6位）
+		rfcSecret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+		vectors := map[int64]string{
+			59:          "287082",
+			1111111109:  "081804",
+			1111111111:  "050471",
+			1234567890:  "005924",
+			2000000000:  "279037",
+			20000000000: "353130",
+		}
+
+		Convey("Should match RFC 6238 test vectors", func() {
+			for ts, expected := range vectors {
+				code, err := GenerateTOTPCode(rfcSecret, time.Unix(ts, 0))
+				So(err, ShouldBeNil)
+				So(code, ShouldEqual, expected)
+			}
+		})
+
+		Convey("Should accept codes within the allowed skew", func() {
+			now := time.Unix(1111111111, 0)
+			previous, _ := GenerateTOTPCode(rfcSecret, now.Add(-TOTPPeriod*time.Second))
+			step, ok := ValidateTOTPCode(rfcSecret, previous, now)
+			So(ok, ShouldBeTrue)
+			So(step, ShouldEqual, TOTPStep(now)-1)
+
+			tooOld, _ := GenerateTOTPCode(rfcSecret, now.Add(-3*TOTPPeriod*time.Second))
+			_, ok = ValidateTOTPCode(rfcSecret, tooOld, now)
+			So(ok, ShouldBeFalse)
+
+			_, ok = ValidateTOTPCode(rfcSecret, "12345", now)
+			So(ok, ShouldBeFalse)
+			_, ok = ValidateTOTPCode("not base32!", "123456", now)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("Generated secret should round trip", func() {
+			secret, err := GenerateTOTPSecret()
+			So(err, ShouldBeNil)
+			So(secret, ShouldNotContainSubstring, "=")
+
+			code, err := GenerateTOTPCode(strings.ToLower(secret), time.Now())
+			So(err, ShouldBeNil)
+			_, ok := ValidateTOTPCode(secret, code, time.Now())
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("Should build otpauth URI", func() {
+			uri := BuildTOTPURI("Heimdall Blog", "alice@example.com", "JBSWY3DPEHPK3PXP")
+			parsed, err := url.Parse(uri)
+			So(err, ShouldBeNil)
+			So(parsed.Scheme, ShouldEqual, "otpauth")
+			So(parsed.Host, ShouldEqual, "totp")
+			So(parsed.Path, ShouldEqual, "/Heimdall Blog:alice@example.com")
+			So(parsed.Query().Get("secret"), ShouldEqual, "JBSWY3DPEHPK3PXP")
+			So(parsed.Query().Get("issuer"), ShouldEqual, "Heimdall Blog")
+			So(parsed.Query().Get("digits"), ShouldEqual, "6")
+		})
+	})
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	Convey("Test recovery codes", t, func() {
+		codes, err := GenerateRecoveryCodes(RecoveryCodeCount)
+		So(err, ShouldBeNil)
+		So(len(codes), ShouldEqual, RecoveryCodeCount)
+		So(codes[0], ShouldHaveLength, 11)
+		So(codes[0][5], ShouldEqual, '-')
+		So(codes[0], ShouldNotEqual, codes[1])
+
+		So(HashRecoveryCode(codes[0]), ShouldEqual, HashRecoveryCode(strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))))
+		So(HashRecoveryCode(codes[0]), ShouldNotEqual, HashRecoveryCode(codes[1]))
+	})
+}
@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/heimdall-api/common/constants"
+)
+
+// UserAgentInfo User-Agent解析结果
+type UserAgentInfo struct {
+	DeviceType     string // 设备类型：desktop/mobile/tablet/bot，无法识别时为空
+	Browser        string // 浏览器（或爬虫）名称
+	BrowserVersion string // 浏览器主版本号
+	OS             string // 操作系统名称
+	OSVersion      string // 操作系统主版本号
+}
+
+// uaPattern 名称与版本匹配规则，正则的第一个捕获组为版本号
+type uaPattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// botPatterns 已知爬虫和命令行工具，按顺序匹配
+var botPatterns = []uaPattern{
+	{"Googlebot", regexp.MustCompile(`Googlebot(?:-\w+)?/(\d+)`)},
+	{"Bingbot", regexp.MustCompile(`(?i)bingbot/(\d+)`)},
+	{"Baiduspider", regexp.MustCompile(`Baiduspider(?:-\w+)?/(\d+)`)},
+	{"YandexBot", regexp.MustCompile(`YandexBot/(\d+)`)},
+	{"DuckDuckBot", regexp.MustCompile(`DuckDuckBot(?:-\w+)?/(\d+)`)},
+	{"Applebot", regexp.MustCompile(`Applebot/(\d+)`)},
+	{"Facebook", regexp.MustCompile(`facebookexternalhit/(\d+)`)},
+	{"Twitterbot", regexp.MustCompile(`Twitterbot/(\d+)`)},
+	{"curl", regexp.MustCompile(`^curl/(\d+)`)},
+	{"Wget", regexp.MustCompile(`^Wget/(\d+)`)},
+	{"python-requests", regexp.MustCompile(`python-requests/(\d+)`)},
+	{"Go-http-client", regexp.MustCompile(`Go-http-client/(\d+)`)},
+	{"HeadlessChrome", regexp.MustCompile(`HeadlessChrome/(\d+)`)},
+}
+
+// genericBotPattern 未在列表中的通用爬虫特征
+var genericBotPattern = regexp.MustCompile(`(?i)bot\b|crawl|spider|slurp|scrapy|okhttp|httpclient|java/`)
+
+// browserPatterns 浏览器规则，基于Chromium的浏览器需排在Chrome之前
+var browserPatterns = []uaPattern{
+	{"Edge", regexp.MustCompile(`(?:Edg|EdgA|EdgiOS|Edge)/(\d+)`)},
+	{"Opera", regexp.MustCompile(`(?:OPR|OPT|Opera)/(\d+)`)},
+	{"Samsung Internet", regexp.MustCompile(`SamsungBrowser/(\d+)`)},
+	{"UC Browser", regexp.MustCompile(`UCBrowser/(\d+)`)},
+	{"WeChat", regexp.MustCompile(`MicroMessenger/(\d+)`)},
+	{"QQ Browser", regexp.MustCompile(`M?QQBrowser/(\d+)`)},
+	{"Firefox", regexp.MustCompile(`(?:Firefox|FxiOS)/(\d+)`)},
+	{"Chrome", regexp.MustCompile(`(?:CriOS|Chrome)/(\d+)`)},
+	{"Safari", regexp.MustCompile(`Version/(\d+)[\d.]*.*Safari/`)},
+	{"IE", regexp.MustCompile(`(?:MSIE |Trident/.*rv:)(\d+)`)},
+}
+
+// osPatterns 操作系统规则，移动系统需排在Linux/macOS之前
+var osPatterns = []uaPattern{
+	{"Windows Phone", regexp.MustCompile(`Windows Phone(?: OS)? (\d+)`)},
+	{"Windows", regexp.MustCompile(`Windows NT (\d+\.\d+)`)},
+	{"iOS", regexp.MustCompile(`(?:iPhone|iPad|iPod).*?OS (\d+)`)},
+	{"HarmonyOS", regexp.MustCompile(`HarmonyOS[ /]?(\d*)`)},
+	{"Android", regexp.MustCompile(`Android[ /]?(\d*)`)},
+	{"Chrome OS", regexp.MustCompile(`CrOS()`)},
+	{"macOS", regexp.MustCompile(`Mac OS X (\d+)`)},
+	{"Linux", regexp.MustCompile(`Linux()`)},
+}
+
+// windowsVersions Windows NT内核版本与产品版本的对应关系
+var windowsVersions = map[string]string{
+	"10.0": "10",
+	"6.3":  "8.1",
+	"6.2":  "8",
+	"6.1":  "7",
+	"6.0":  "Vista",
+	"5.2":  "XP",
+	"5.1":  "XP",
+}
+
+var (
+	tabletPattern = regexp.MustCompile(`(?i)ipad|tablet|kindle|silk/|playbook|\bSM-T\d+`)
+	mobilePattern = regexp.MustCompile(`(?i)mobi|iphone|ipod|windows phone|opera mini|blackberry`)
+)
+
+// ParseUserAgent 解析User-Agent，提取设备类型、浏览器和操作系统信息
+func ParseUserAgent(userAgent string) UserAgentInfo {
+	userAgent = strings.TrimSpace(userAgent)
+	if userAgent == "" || userAgent == UnknownClientValue {
+		return UserAgentInfo{}
+	}
+
+	var info UserAgentInfo
+	info.OS, info.OSVersion = matchPattern(userAgent, osPatterns)
+	if info.OS == "Windows" {
+		info.OSVersion = windowsVersions[info.OSVersion]
+	}
+
+	// 爬虫优先识别，避免被当作普通浏览器
+	if name, version := matchPattern(userAgent, botPatterns); name != "" {
+		info.DeviceType = constants.DeviceTypeBot
+		info.Browser, info.BrowserVersion = name, version
+		return info
+	}
+	if genericBotPattern.MatchString(userAgent) {
+		info.DeviceType = constants.DeviceTypeBot
+		return info
+	}
+
+	info.Browser, info.BrowserVersion = matchPattern(userAgent, browserPatterns)
+	info.DeviceType = detectDeviceType(userAgent, info.OS)
+	return info
+}
+
+// BrowserString 返回浏览器名称及主版本，如 "Chrome 120"
+func (i UserAgentInfo) BrowserString() string {
+	return joinNameVersion(i.Browser, i.BrowserVersion)
+}
+
+// OSString 返回操作系统名称及主版本，如 "Windows 10"
+func (i UserAgentInfo) OSString() string {
+	return joinNameVersion(i.OS, i.OSVersion)
+}
+
+// detectDeviceType 根据User-Agent特征和操作系统判断设备类型
+func detectDeviceType(userAgent, os string) string {
+	switch {
+	case tabletPattern.MatchString(userAgent):
+		return constants.DeviceTypeTablet
+	case os == "Android" && !strings.Contains(userAgent, "Mobile"):
+		// Android平板的User-Agent不包含Mobile标识
+		return constants.DeviceTypeTablet
+	case mobilePattern.MatchString(userAgent), os == "iOS", os == "Android", os == "HarmonyOS":
+		return constants.DeviceTypeMobile
+	case os == "Windows", os == "macOS", os == "Linux", os == "Chrome OS":
+		return constants.DeviceTypeDesktop
+	default:
+		return ""
+	}
+}
+
+// matchPattern 返回第一个匹配规则的名称及版本
+func matchPattern(userAgent string, patterns []uaPattern) (string, string) {
+	for _, p := range patterns {
+		if m := p.re.FindStringSubmatch(userAgent); m != nil {
+			version := ""
+			if len(m) > 1 {
+				version = m[1]
+			}
+			return p.name, version
+		}
+	}
+	return "", ""
+}
+
+// joinNameVersion 拼接名称和版本
+func joinNameVersion(name, version string) string {
+	if name == "" || version == "" {
+		return name
+	}
+	return name + " " + version
+}
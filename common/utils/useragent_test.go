@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/constants"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	Convey("Test ParseUserAgent", t, func() {
+		cases := []struct {
+			name       string
+			userAgent  string
+			deviceType string
+			browser    string
+			os         string
+		}{
+			{
+				name:       "Chrome on Windows",
+				userAgent:  "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+				deviceType: constants.DeviceTypeDesktop,
+				browser:    "Chrome 120",
+				os:         "Windows 10",
+			},
+			{
+				name:       "Edge on Windows",
+				userAgent:  "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+				deviceType: constants.DeviceTypeDesktop,
+				browser:    "Edge 120",
+				os:         "Windows 10",
+			},
+			{
+				name:       "Firefox on Linux",
+				userAgent:  "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+				deviceType: constants.DeviceTypeDesktop,
+				browser:    "Firefox 121",
+				os:         "Linux",
+			},
+			{
+				name:       "Safari on macOS",
+				userAgent:  "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+				deviceType: constants.DeviceTypeDesktop,
+				browser:    "Safari 17",
+				os:         "macOS 10",
+			},
+			{
+				name:       "Safari on iPhone",
+				userAgent:  "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1.2 Mobile/15E148 Safari/604.1",
+				deviceType: constants.DeviceTypeMobile,
+				browser:    "Safari 17",
+				os:         "iOS 17",
+			},
+			{
+				name:       "Chrome on iPad",
+				userAgent:  "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/119.0.6045.169 Mobile/15E148 Safari/604.1",
+				deviceType: constants.DeviceTypeTablet,
+				browser:    "Chrome 119",
+				os:         "iOS 16",
+			},
+			{
+				name:       "Chrome on Android phone",
+				userAgent:  "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36",
+				deviceType: constants.DeviceTypeMobile,
+				browser:    "Chrome 120",
+				os:         "Android 14",
+			},
+			{
+				name:       "Samsung Internet on Android tablet",
+				userAgent:  "Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Safari/537.36",
+				deviceType: constants.DeviceTypeTablet,
+				browser:    "Samsung Internet 23",
+				os:         "Android 13",
+			},
+			{
+				name:       "WeChat on Android",
+				userAgent:  "Mozilla/5.0 (Linux; Android 12; V2118A Build/SP1A.210812.003; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/107.0.5304.141 Mobile Safari/537.36 XWEB/5023 MMWEBSDK/20230405 MicroMessenger/8.0.35.2360(0x2800235B) WeChat/arm64 Weixin NetType/WIFI Language/zh_CN ABI/arm64",
+				deviceType: constants.DeviceTypeMobile,
+				browser:    "WeChat 8",
+				os:         "Android 12",
+			},
+			{
+				name:       "Internet Explorer 11",
+				userAgent:  "Mozilla/5.0 (Windows NT 6.1; WOW64; Trident/7.0; rv:11.0) like Gecko",
+				deviceType: constants.DeviceTypeDesktop,
+				browser:    "IE 11",
+				os:         "Windows 7",
+			},
+			{
+				name:       "Googlebot",
+				userAgent:  "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+				deviceType: constants.DeviceTypeBot,
+				browser:    "Googlebot 2",
+				os:         "",
+			},
+			{
+				name:       "curl",
+				userAgent:  "curl/8.4.0",
+				deviceType: constants.DeviceTypeBot,
+				browser:    "curl 8",
+				os:         "",
+			},
+			{
+				name:       "Unknown crawler",
+				userAgent:  "SomeCrawler/1.0 (+https://example.com/crawler)",
+				deviceType: constants.DeviceTypeBot,
+				browser:    "",
+				os:         "",
+			},
+		}
+
+		for _, c := range cases {
+			c := c
+			Convey(c.name, func() {
+				info := ParseUserAgent(c.userAgent)
+
+				So(info.DeviceType, ShouldEqual, c.deviceType)
+				So(info.BrowserString(), ShouldEqual, c.browser)
+				So(info.OSString(), ShouldEqual, c.os)
+			})
+		}
+
+		Convey("Empty or unknown User-Agent should return empty info", func() {
+			So(ParseUserAgent(""), ShouldResemble, UserAgentInfo{})
+			So(ParseUserAgent(UnknownClientValue), ShouldResemble, UserAgentInfo{})
+		})
+	})
+}
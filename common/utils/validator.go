@@ -9,6 +9,8 @@ import (
 	"strings"
 	"time"
 	"unicode"
+
+	"github.com/heimdall-api/common/slug"
 )
 
 var (
@@ -222,9 +224,8 @@ func (v *Validator) Slug(field string, value string) *Validator {
 	if value == "" {
 		return v
 	}
-	slugRegex := regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
-	if !slugRegex.MatchString(value) {
-		v.AddError(field, fmt.Sprintf("%s must be a valid slug (lowercase letters, numbers, and hyphens only)", field))
+	if !slug.IsValid(value) {
+		v.AddError(field, fmt.Sprintf("%s must be a valid slug (lowercase letters, other scripts, numbers, and hyphens only)", field))
 	}
 	return v
 }
@@ -355,13 +356,12 @@ func ValidateURL(url string) error {
 }
 
 // ValidateSlug URL友好字符串验证
-func ValidateSlug(slug string) error {
-	if slug == "" {
+func ValidateSlug(value string) error {
+	if value == "" {
 		return nil
 	}
-	slugRegex := regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
-	if !slugRegex.MatchString(slug) {
-		return errors.New("slug must contain only lowercase letters, numbers, and hyphens")
+	if !slug.IsValid(value) {
+		return errors.New("slug must contain only lowercase letters, other scripts, numbers, and hyphens")
 	}
 	return nil
 }
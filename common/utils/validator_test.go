@@ -259,6 +259,7 @@ func TestValidatorSlug(t *testing.T) {
 			"hello-world",
 			"a",
 			"123",
+			"go-并发模式",
 		}
 
 		invalidSlugs := []string{
@@ -180,9 +180,12 @@ type AntiBotConfig struct {
 
 // ContentSecurityConfig 内容安全配置
 type ContentSecurityConfig struct {
-	EnableXSSFilter bool `json:",default=true"`
-	EnableSQLFilter bool `json:",default=true"`
-	MaxURLLength    int  `json:",default=2048"`
+	EnableXSSFilter bool     `json:",default=true"`
+	EnableSQLFilter bool     `json:",default=true"`
+	MaxURLLength    int      `json:",default=2048"`
+	EmbedRoles      []string `json:",optional"` // 允许嵌入iframe和音视频的角色，需与管理服务一致
+	EmbedHosts      []string `json:",optional"` // 允许作为iframe来源的主机，需与管理服务一致
+	URLSchemes      []string `json:",optional"` // 链接和图片允许的URL协议，需与管理服务一致
 }
 
 // AnalyticsConfig 统计配置
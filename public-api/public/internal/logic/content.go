@@ -0,0 +1,20 @@
+package logic
+
+import (
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/public-api/public/internal/svc"
+)
+
+// sanitizeContent 输出前按作者角色再次净化文章或页面HTML
+// 管理服务保存时已经净化过，这里兼容净化功能上线前保存的内容和直接写入数据库的内容
+// 作者已被删除时按最严格的策略净化
+func sanitizeContent(svcCtx *svc.ServiceContext, html string, author *model.User) string {
+	if svcCtx.Sanitizer == nil {
+		return html
+	}
+	role := ""
+	if author != nil {
+		role = author.Role
+	}
+	return svcCtx.Sanitizer.Sanitize(html, role)
+}
@@ -0,0 +1,29 @@
+package logic
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/heimdall-api/common/constants"
+	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/render"
+	"github.com/heimdall-api/public-api/public/internal/svc"
+)
+
+func TestSanitizeContent(t *testing.T) {
+	Convey("sanitizeContent", t, func() {
+		html := `<p>正文</p><script>alert(1)</script><iframe src="https://www.youtube.com/embed/abc"></iframe>`
+
+		Convey("Should return stored HTML unchanged when XSS filter is disabled", func() {
+			So(sanitizeContent(&svc.ServiceContext{}, html, &model.User{Role: constants.UserRoleAuthor}), ShouldEqual, html)
+		})
+
+		Convey("Should sanitize with the author's policy", func() {
+			svcCtx := &svc.ServiceContext{Sanitizer: render.NewSanitizer(render.SanitizeConfig{})}
+			So(sanitizeContent(svcCtx, html, &model.User{Role: constants.UserRoleAuthor}), ShouldEqual, "<p>正文</p>")
+			So(sanitizeContent(svcCtx, html, &model.User{Role: constants.UserRoleOwner}), ShouldEqual,
+				`<p>正文</p><iframe src="https://www.youtube.com/embed/abc"></iframe>`)
+		})
+	})
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/slug"
 	"github.com/heimdall-api/public-api/public/internal/svc"
 	"github.com/heimdall-api/public-api/public/internal/types"
 
@@ -108,7 +109,7 @@ func (l *GetPublicPageDetailLogic) buildPageDetail(page *model.Page, author *mod
 	return types.PublicPageDetailData{
 		Title:           page.Title,
 		Slug:            page.Slug,
-		HTML:            page.HTML,
+		HTML:            sanitizeContent(l.svcCtx, page.HTML, author),
 		Template:        page.Template,
 		Author:          authorInfo,
 		MetaTitle:       page.MetaTitle,
@@ -130,10 +131,10 @@ func (l *GetPublicPageDetailLogic) buildAuthorInfo(author *model.User) types.Pub
 	}
 }
 
-// buildCanonicalURL 构建canonical URL
-func (l *GetPublicPageDetailLogic) buildCanonicalURL(slug string) string {
+// buildCanonicalURL 构建canonical URL，非ASCII字符的slug按百分号编码
+func (l *GetPublicPageDetailLogic) buildCanonicalURL(pageSlug string) string {
 	// 这里可以从配置中读取域名，暂时使用相对路径
-	return fmt.Sprintf("/pages/%s", slug)
+	return fmt.Sprintf("/pages/%s", slug.Escape(pageSlug))
 }
 
 // buildResponse 构建响应
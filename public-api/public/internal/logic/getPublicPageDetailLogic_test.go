@@ -356,6 +356,10 @@ func TestGetPublicPageDetailLogic_buildCanonicalURL(t *testing.T) {
 
 			So(result, ShouldEqual, expected)
 		})
+
+		Convey("应该对非ASCII字符的slug进行百分号编码", func() {
+			So(logic.buildCanonicalURL("关于-我们"), ShouldEqual, "/pages/%E5%85%B3%E4%BA%8E-%E6%88%91%E4%BB%AC")
+		})
 	})
 }
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/heimdall-api/common/constants"
 	"github.com/heimdall-api/common/model"
+	"github.com/heimdall-api/common/slug"
 	"github.com/heimdall-api/public-api/public/internal/svc"
 	"github.com/heimdall-api/public-api/public/internal/types"
 
@@ -128,7 +129,7 @@ func (l *GetPublicPostDetailLogic) buildPostDetail(post *model.Post, author *mod
 		Title:           post.Title,
 		Slug:            post.Slug,
 		Excerpt:         post.Excerpt,
-		HTML:            post.HTML,
+		HTML:            sanitizeContent(l.svcCtx, post.HTML, author),
 		FeaturedImage:   post.FeaturedImage,
 		Author:          authorInfo,
 		Tags:            tags,
@@ -140,9 +141,35 @@ func (l *GetPublicPostDetailLogic) buildPostDetail(post *model.Post, author *mod
 		ViewCount:       int64(post.ViewCount),
 		PublishedAt:     publishedAt,
 		UpdatedAt:       post.UpdatedAt.Format(time.RFC3339),
+		TOC:             l.buildTOC(post),
 	}
 }
 
+// buildTOC 构建文章目录，文章关闭目录时返回nil
+func (l *GetPublicPostDetailLogic) buildTOC(post *model.Post) []types.PublicTOCItem {
+	if post.DisableTOC {
+		return nil
+	}
+	return convertTOCItems(post.TOC)
+}
+
+// convertTOCItems 递归转换目录项
+func convertTOCItems(items []model.TOCItem) []types.PublicTOCItem {
+	if len(items) == 0 {
+		return nil
+	}
+	result := make([]types.PublicTOCItem, 0, len(items))
+	for _, item := range items {
+		result = append(result, types.PublicTOCItem{
+			Level:    item.Level,
+			Text:     item.Text,
+			ID:       item.ID,
+			Children: convertTOCItems(item.Children),
+		})
+	}
+	return result
+}
+
 // buildTags 构建标签信息
 func (l *GetPublicPostDetailLogic) buildTags(tags []model.Tag) []types.TagInfo {
 	tagInfos := make([]types.TagInfo, 0, len(tags))
@@ -165,10 +192,10 @@ func (l *GetPublicPostDetailLogic) buildAuthorInfo(author *model.User) types.Pub
 	}
 }
 
-// buildCanonicalURL 构建canonical URL
-func (l *GetPublicPostDetailLogic) buildCanonicalURL(slug string) string {
+// buildCanonicalURL 构建canonical URL，非ASCII字符的slug按百分号编码
+func (l *GetPublicPostDetailLogic) buildCanonicalURL(postSlug string) string {
 	// 这里可以从配置中读取域名，暂时使用相对路径
-	return fmt.Sprintf("/posts/%s", slug)
+	return fmt.Sprintf("/posts/%s", slug.Escape(postSlug))
 }
 
 // buildResponse 构建响应
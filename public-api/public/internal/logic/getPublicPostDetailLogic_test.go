@@ -57,6 +57,9 @@ func TestGetPublicPostDetailLogic_GetPublicPostDetail(t *testing.T) {
 				PublishedAt:     &now,
 				CreatedAt:       now.Add(-2 * time.Hour),
 				UpdatedAt:       now.Add(-1 * time.Hour),
+				TOC: []model.TOCItem{
+					{Level: 2, Text: "简介", ID: "简介", Children: []model.TOCItem{{Level: 3, Text: "背景", ID: "背景"}}},
+				},
 			}
 
 			mockUser := &model.User{
@@ -116,6 +119,9 @@ func TestGetPublicPostDetailLogic_GetPublicPostDetail(t *testing.T) {
 			So(post.Tags, ShouldHaveLength, 2)
 			So(post.Tags[0].Name, ShouldEqual, "技术")
 			So(post.Tags[1].Name, ShouldEqual, "Go语言")
+			So(post.TOC, ShouldResemble, []types.PublicTOCItem{
+				{Level: 2, Text: "简介", ID: "简介", Children: []types.PublicTOCItem{{Level: 3, Text: "背景", ID: "背景"}}},
+			})
 
 			// 验证作者信息
 			So(post.Author.Username, ShouldEqual, "testuser")
@@ -367,3 +373,23 @@ func TestGetPublicPostDetailLogic_GetPublicPostDetail(t *testing.T) {
 		})
 	})
 }
+
+func TestGetPublicPostDetailLogic_BuildTOC(t *testing.T) {
+	Convey("buildTOC", t, func() {
+		logic := NewGetPublicPostDetailLogic(context.Background(), &svc.ServiceContext{})
+		post := &model.Post{TOC: []model.TOCItem{{Level: 2, Text: "简介", ID: "简介"}}}
+
+		Convey("Should convert the stored outline", func() {
+			So(logic.buildTOC(post), ShouldResemble, []types.PublicTOCItem{{Level: 2, Text: "简介", ID: "简介"}})
+		})
+
+		Convey("Should omit the outline when the post opts out", func() {
+			post.DisableTOC = true
+			So(logic.buildTOC(post), ShouldBeNil)
+		})
+
+		Convey("Should omit the outline when the post has no headings", func() {
+			So(logic.buildTOC(&model.Post{}), ShouldBeNil)
+		})
+	})
+}
@@ -8,16 +8,18 @@ import (
 
 	"github.com/heimdall-api/common/client"
 	"github.com/heimdall-api/common/dao"
+	"github.com/heimdall-api/common/render"
 	"github.com/heimdall-api/public-api/public/internal/config"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type ServiceContext struct {
-	Config  config.Config
-	MongoDB *mongo.Database
-	PostDAO *dao.PostDAO
-	UserDAO *dao.UserDAO
-	PageDAO *dao.PageDAO
+	Config    config.Config
+	MongoDB   *mongo.Database
+	PostDAO   *dao.PostDAO
+	UserDAO   *dao.UserDAO
+	PageDAO   *dao.PageDAO
+	Sanitizer *render.Sanitizer // 输出前再次净化文章和页面HTML，关闭XSS过滤时为nil
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
@@ -69,11 +71,22 @@ func NewServiceContext(c config.Config) *ServiceContext {
 	userDAO := dao.NewUserDAO(database)
 	pageDAO := dao.NewPageDAO(database)
 
+	// 初始化HTML净化器，兼容净化功能上线前保存的内容
+	var sanitizer *render.Sanitizer
+	if contentSecurity := c.Security.ContentSecurity; contentSecurity.EnableXSSFilter {
+		sanitizer = render.NewSanitizer(render.SanitizeConfig{
+			EmbedRoles: contentSecurity.EmbedRoles,
+			EmbedHosts: contentSecurity.EmbedHosts,
+			URLSchemes: contentSecurity.URLSchemes,
+		})
+	}
+
 	return &ServiceContext{
-		Config:  c,
-		MongoDB: database,
-		PostDAO: postDAO,
-		UserDAO: userDAO,
-		PageDAO: pageDAO,
+		Config:    c,
+		MongoDB:   database,
+		PostDAO:   postDAO,
+		UserDAO:   userDAO,
+		PageDAO:   pageDAO,
+		Sanitizer: sanitizer,
 	}
 }
@@ -66,6 +66,7 @@ type PublicPostDetailData struct {
 	ViewCount       int64            `json:"viewCount"`
 	PublishedAt     string           `json:"publishedAt"`
 	UpdatedAt       string           `json:"updatedAt"`
+	TOC             []PublicTOCItem  `json:"toc,omitempty"` // 文章目录，文章关闭目录或没有标题时省略
 }
 
 type PublicPostDetailRequest struct {
@@ -114,6 +115,13 @@ type PublicPostListResponse struct {
 	Timestamp string             `json:"timestamp"`
 }
 
+type PublicTOCItem struct {
+	Level    int             `json:"level"`              // 标题级别
+	Text     string          `json:"text"`               // 标题文本
+	ID       string          `json:"id"`                 // 锚点ID，对应HTML中标题的id属性
+	Children []PublicTOCItem `json:"children,omitempty"` // 下级标题
+}
+
 type TagInfo struct {
 	Name string `json:"name"`
 	Slug string `json:"slug"`
@@ -48,6 +48,9 @@ db.loginLogs.createIndex({ "success": 1, "createdAt": -1 }, { "name": "idx_succe
 // 索引：用户名（用于按用户名查询登录记录）
 db.loginLogs.createIndex({ "username": 1 }, { "name": "idx_username_login_logs" });
 
+// 复合稀疏索引：异常标记和登录时间（安全告警列表）
+db.loginLogs.createIndex({ "anomalies": 1, "loginAt": -1 }, { "sparse": true, "name": "idx_anomaly_login_logs" });
+
 print("loginLogs 集合索引创建完成");
 
 // =============================================================================
@@ -162,12 +165,83 @@ db.media.createIndex({ "url": 1 }, { "name": "idx_media_url" });
 
 print("media 集合索引创建完成");
 
+// =============================================================================
+// 7. webauthnCredentials 集合索引
+// =============================================================================
+print("创建 webauthnCredentials 集合索引...");
+
+// 唯一索引：凭据ID（通行密钥登录时按凭据查找）
+db.webauthnCredentials.createIndex({ "credentialId": 1 }, { "unique": true, "name": "idx_webauthn_credential_id_unique" });
+
+// 索引：用户ID（列出用户的通行密钥）
+db.webauthnCredentials.createIndex({ "userId": 1 }, { "name": "idx_webauthn_user_id" });
+
+print("webauthnCredentials 集合索引创建完成");
+
+// =============================================================================
+// 8. accessTokens 集合索引
+// =============================================================================
+print("创建 accessTokens 集合索引...");
+
+// 唯一索引：令牌哈希（认证时按哈希查找）
+db.accessTokens.createIndex({ "tokenHash": 1 }, { "unique": true, "name": "idx_access_token_hash_unique" });
+
+// 复合索引：用户ID和创建时间（列出用户的令牌）
+db.accessTokens.createIndex({ "userId": 1, "createdAt": -1 }, { "name": "idx_access_token_user_created" });
+
+print("accessTokens 集合索引创建完成");
+
+// =============================================================================
+// 9. invitations 集合索引
+// =============================================================================
+print("创建 invitations 集合索引...");
+
+// 复合索引：邮箱和状态（创建邀请时检查重复）
+db.invitations.createIndex({ "email": 1, "status": 1 }, { "name": "idx_invitation_email_status" });
+
+// 复合索引：状态和创建时间（列出待接受的邀请）
+db.invitations.createIndex({ "status": 1, "createdAt": -1 }, { "name": "idx_invitation_status_created" });
+
+print("invitations 集合索引创建完成");
+
+// =============================================================================
+// 10. ipRules 集合索引
+// =============================================================================
+print("创建 ipRules 集合索引...");
+
+// 唯一复合索引：规则类型和网段（防止重复规则）
+db.ipRules.createIndex({ "type": 1, "cidr": 1 }, { "unique": true, "name": "idx_ip_rule_type_cidr_unique" });
+
+// 创建时间索引（规则列表排序）
+db.ipRules.createIndex({ "createdAt": -1 }, { "name": "idx_ip_rule_created" });
+
+print("ipRules 集合索引创建完成");
+
+// =============================================================================
+// 11. auditLogs 集合索引
+// =============================================================================
+print("创建 auditLogs 集合索引...");
+
+// 复合索引：操作者和时间（按操作者查询）
+db.auditLogs.createIndex({ "actorId": 1, "createdAt": -1 }, { "name": "idx_audit_actor_created" });
+
+// 复合索引：操作和时间（按操作查询，支持前缀匹配）
+db.auditLogs.createIndex({ "action": 1, "createdAt": -1 }, { "name": "idx_audit_action_created" });
+
+// 复合索引：操作对象（查看某个对象的变更历史）
+db.auditLogs.createIndex({ "targetType": 1, "targetId": 1, "createdAt": -1 }, { "name": "idx_audit_target_created" });
+
+// TTL索引：默认保留180天，服务启动时按 Security.AuditLog.RetentionDays 配置同步
+db.auditLogs.createIndex({ "createdAt": 1 }, { "expireAfterSeconds": 180 * 24 * 3600, "name": "createdAt_ttl" });
+
+print("auditLogs 集合索引创建完成");
+
 // =============================================================================
 // 显示索引创建结果
 // =============================================================================
 print("\n=== 索引创建完成统计 ===");
 
-var collections = ["users", "loginLogs", "posts", "comments", "settings", "media"];
+var collections = ["users", "loginLogs", "posts", "comments", "settings", "media", "webauthnCredentials", "accessTokens", "invitations", "ipRules", "auditLogs"];
 
 collections.forEach(function(collName) {
     var indexes = db[collName].getIndexes();
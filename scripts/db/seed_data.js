@@ -113,7 +113,10 @@ var settings = [
     // 评论设置
     { key: "enableComments", value: "true", group: "comments", createdAt: new Date(), updatedAt: new Date() },
     { key: "requireApproval", value: "true", group: "comments", createdAt: new Date(), updatedAt: new Date() },
-    { key: "allowGuestComments", value: "true", group: "comments", createdAt: new Date(), updatedAt: new Date() }
+    { key: "allowGuestComments", value: "true", group: "comments", createdAt: new Date(), updatedAt: new Date() },
+
+    // 安全设置
+    { key: "mfaRequiredRoles", value: "", group: "security", createdAt: new Date(), updatedAt: new Date() }
 ];
 
 db.settings.insertMany(settings);